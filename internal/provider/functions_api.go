@@ -0,0 +1,1000 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fieldMaskIdentifier matches path segments that need no quoting in a
+// field mask (AIP-161).
+var fieldMaskIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// fieldMaskPaths returns the sorted leaf paths of a patch object. Nested
+// objects are descended into; empty objects, lists and scalars are leaves.
+func fieldMaskPaths(data map[string]any, prefix string, paths []string) []string {
+	for key, value := range data {
+		segment := key
+		if !fieldMaskIdentifier.MatchString(key) {
+			segment = "`" + strings.ReplaceAll(key, "`", "``") + "`"
+		}
+		path := segment
+		if prefix != "" {
+			path = prefix + "." + segment
+		}
+
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			paths = fieldMaskPaths(nested, path, paths)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// splitFieldMaskPath splits a field mask path into segments, honouring
+// backtick-quoted segments with doubled backticks as escapes.
+func splitFieldMaskPath(path string) ([]string, error) {
+	var segments []string
+	var current strings.Builder
+	quoted := false
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '`' && quoted && i+1 < len(path) && path[i+1] == '`':
+			current.WriteByte('`')
+			i++
+		case c == '`':
+			quoted = !quoted
+		case c == '.' && !quoted:
+			if current.Len() == 0 {
+				return nil, fmt.Errorf("empty segment in path %q", path)
+			}
+			segments = append(segments, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in path %q", path)
+	}
+	if current.Len() == 0 {
+		return nil, fmt.Errorf("empty segment in path %q", path)
+	}
+	return append(segments, current.String()), nil
+}
+
+// applyFieldMask copies the value at segments from source into target,
+// creating intermediate objects as needed. Missing paths are ignored.
+func applyFieldMask(source, target map[string]any, segments []string) {
+	value, ok := source[segments[0]]
+	if !ok {
+		return
+	}
+	if len(segments) == 1 {
+		target[segments[0]] = value
+		return
+	}
+
+	nested, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	child, ok := target[segments[0]].(map[string]any)
+	if !ok {
+		child = map[string]any{}
+	}
+	applyFieldMask(nested, child, segments[1:])
+	if len(child) > 0 {
+		target[segments[0]] = child
+	}
+}
+
+// Field Mask Paths Function
+var _ function.Function = &FieldMaskPathsFunction{}
+
+type FieldMaskPathsFunction struct{}
+
+func NewFieldMaskPathsFunction() function.Function {
+	return &FieldMaskPathsFunction{}
+}
+
+func (f *FieldMaskPathsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "field_mask_paths"
+}
+
+func (f *FieldMaskPathsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Generates field mask paths from a patch object",
+		Description: "Takes a patch object and returns the sorted list of dot-separated leaf paths it sets, " +
+			"suitable for the update_mask of Google-style APIs. Keys that are not plain identifiers are backtick-quoted.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "object",
+				Description: "The patch object",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *FieldMaskPathsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var object types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &object))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, object)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	patch, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("expected an object, got %s", typeName(data))))
+		return
+	}
+
+	result := fieldMaskPaths(patch, "", []string{})
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Apply Field Mask Function
+var _ function.Function = &ApplyFieldMaskFunction{}
+
+type ApplyFieldMaskFunction struct{}
+
+func NewApplyFieldMaskFunction() function.Function {
+	return &ApplyFieldMaskFunction{}
+}
+
+func (f *ApplyFieldMaskFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "apply_field_mask"
+}
+
+func (f *ApplyFieldMaskFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Filters an object by field mask paths",
+		Description: "Takes an object and a list of field mask paths, returning an object containing only the selected fields. " +
+			"A path selecting a nested object keeps it whole, '*' keeps everything and paths that do not exist are ignored.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "object",
+				Description: "The object to filter",
+			},
+			function.ListParameter{
+				Name:        "paths",
+				Description: "The field mask paths to keep",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ApplyFieldMaskFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var object types.Dynamic
+	var paths []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &object, &paths))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, object)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	source, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("expected an object, got %s", typeName(data))))
+		return
+	}
+
+	masked := map[string]any{}
+	for _, path := range paths {
+		if path == "*" {
+			masked = source
+			break
+		}
+		segments, err := splitFieldMaskPath(path)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+			return
+		}
+		applyFieldMask(source, masked, segments)
+	}
+
+	result, err := toDynamic(masked)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// grpcStatusCodes lists the canonical gRPC status code names by number.
+var grpcStatusCodes = []string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND",
+	"ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION",
+	"ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS",
+	"UNAUTHENTICATED",
+}
+
+// protoDuration matches the JSON encoding of google.protobuf.Duration.
+var protoDuration = regexp.MustCompile(`^(\d+)(\.\d{1,9})?s$`)
+
+// snakeToCamel converts snake_case field names to the lowerCamelCase form
+// used in proto3 JSON, leaving other names unchanged.
+func snakeToCamel(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// camelKeys returns a copy of an object with its keys converted to
+// lowerCamelCase.
+func camelKeys(data map[string]any) map[string]any {
+	result := make(map[string]any, len(data))
+	for key, value := range data {
+		result[snakeToCamel(key)] = value
+	}
+	return result
+}
+
+// grpcConfigValidator accumulates problems found in a service config.
+type grpcConfigValidator struct {
+	problems []string
+}
+
+func (v *grpcConfigValidator) addf(path, format string, args ...any) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *grpcConfigValidator) object(path string, value any, allowed ...string) map[string]any {
+	object, ok := value.(map[string]any)
+	if !ok {
+		v.addf(path, "expected an object, got %s", typeName(value))
+		return nil
+	}
+	object = camelKeys(object)
+	for _, key := range sortedKeys(object) {
+		known := false
+		for _, name := range allowed {
+			if key == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			v.addf(path, "unknown field %q", key)
+		}
+	}
+	return object
+}
+
+func (v *grpcConfigValidator) integer(path string, value any, minimum int64) {
+	number, ok := value.(*big.Float)
+	if !ok || !number.IsInt() {
+		v.addf(path, "expected an integer, got %s", typeName(value))
+		return
+	}
+	if i, _ := number.Int64(); i < minimum {
+		v.addf(path, "must be at least %d", minimum)
+	}
+}
+
+func (v *grpcConfigValidator) duration(path string, value any) {
+	s, ok := value.(string)
+	if !ok || !protoDuration.MatchString(s) {
+		v.addf(path, "expected a duration such as \"1.5s\", got %v", value)
+		return
+	}
+	if strings.Trim(strings.TrimSuffix(s, "s"), "0.") == "" {
+		v.addf(path, "must be greater than zero")
+	}
+}
+
+func (v *grpcConfigValidator) statusCodes(path string, value any) []any {
+	codes, ok := value.([]any)
+	if !ok || len(codes) == 0 {
+		v.addf(path, "expected a non-empty list of status codes")
+		return nil
+	}
+	normalized := make([]any, len(codes))
+	for i, code := range codes {
+		normalized[i] = code
+		switch c := code.(type) {
+		case string:
+			name := strings.ToUpper(c)
+			found := false
+			for _, known := range grpcStatusCodes {
+				if name == known {
+					found = true
+				}
+			}
+			if !found {
+				v.addf(fmt.Sprintf("%s[%d]", path, i), "unknown status code %q", c)
+			}
+			normalized[i] = name
+		case *big.Float:
+			n, accuracy := c.Int64()
+			if accuracy != big.Exact || n < 0 || n >= int64(len(grpcStatusCodes)) {
+				v.addf(fmt.Sprintf("%s[%d]", path, i), "unknown status code %s", c.Text('g', -1))
+				continue
+			}
+			normalized[i] = grpcStatusCodes[n]
+		default:
+			v.addf(fmt.Sprintf("%s[%d]", path, i), "expected a status code name, got %s", typeName(code))
+		}
+	}
+	return normalized
+}
+
+// validate checks a service config against the gRPC service config schema,
+// returning a normalised copy with lowerCamelCase field names.
+func (v *grpcConfigValidator) validate(data any) map[string]any {
+	config := v.object("config", data, "loadBalancingConfig", "loadBalancingPolicy", "methodConfig", "retryThrottling", "healthCheckConfig")
+	if config == nil {
+		return nil
+	}
+
+	if policy, ok := config["loadBalancingPolicy"]; ok {
+		if s, ok := policy.(string); !ok || (s != "round_robin" && s != "pick_first") {
+			v.addf("loadBalancingPolicy", "must be \"round_robin\" or \"pick_first\"")
+		}
+	}
+
+	if lbConfigs, ok := config["loadBalancingConfig"]; ok {
+		list, ok := lbConfigs.([]any)
+		if !ok {
+			v.addf("loadBalancingConfig", "expected a list")
+		}
+		for i, entry := range list {
+			if object, ok := entry.(map[string]any); !ok || len(object) != 1 {
+				v.addf(fmt.Sprintf("loadBalancingConfig[%d]", i), "each entry must be an object with exactly one policy name")
+			}
+		}
+	}
+
+	if throttling, ok := config["retryThrottling"]; ok {
+		if object := v.object("retryThrottling", throttling, "maxTokens", "tokenRatio"); object != nil {
+			config["retryThrottling"] = object
+			if maxTokens, ok := object["maxTokens"].(*big.Float); !ok || maxTokens.Sign() <= 0 || maxTokens.Cmp(big.NewFloat(1000)) > 0 || !maxTokens.IsInt() {
+				v.addf("retryThrottling.maxTokens", "must be an integer between 1 and 1000")
+			}
+			if tokenRatio, ok := object["tokenRatio"].(*big.Float); !ok || tokenRatio.Sign() <= 0 {
+				v.addf("retryThrottling.tokenRatio", "must be a number greater than zero")
+			}
+		}
+	}
+
+	if health, ok := config["healthCheckConfig"]; ok {
+		if object := v.object("healthCheckConfig", health, "serviceName"); object != nil {
+			config["healthCheckConfig"] = object
+		}
+	}
+
+	if methodConfigs, ok := config["methodConfig"]; ok {
+		list, ok := methodConfigs.([]any)
+		if !ok {
+			v.addf("methodConfig", "expected a list")
+		}
+		seen := map[string]bool{}
+		for i, entry := range list {
+			list[i] = v.methodConfig(fmt.Sprintf("methodConfig[%d]", i), entry, seen)
+		}
+	}
+
+	return config
+}
+
+func (v *grpcConfigValidator) methodConfig(path string, data any, seen map[string]bool) any {
+	config := v.object(path, data, "name", "waitForReady", "timeout", "maxRequestMessageBytes", "maxResponseMessageBytes", "retryPolicy", "hedgingPolicy")
+	if config == nil {
+		return data
+	}
+
+	names, ok := config["name"].([]any)
+	if !ok || len(names) == 0 {
+		v.addf(path+".name", "expected a non-empty list of names")
+	}
+	for i, entry := range names {
+		namePath := fmt.Sprintf("%s.name[%d]", path, i)
+		name := v.object(namePath, entry, "service", "method")
+		if name == nil {
+			continue
+		}
+		service, _ := name["service"].(string)
+		method, _ := name["method"].(string)
+		if method != "" && service == "" {
+			v.addf(namePath, "method requires a service")
+		}
+		key := service + "/" + method
+		if seen[key] {
+			v.addf(namePath, "duplicate name %q", key)
+		}
+		seen[key] = true
+	}
+
+	if waitForReady, ok := config["waitForReady"]; ok {
+		if _, ok := waitForReady.(bool); !ok {
+			v.addf(path+".waitForReady", "expected a bool")
+		}
+	}
+	if timeout, ok := config["timeout"]; ok {
+		v.duration(path+".timeout", timeout)
+	}
+	for _, field := range []string{"maxRequestMessageBytes", "maxResponseMessageBytes"} {
+		if value, ok := config[field]; ok {
+			v.integer(path+"."+field, value, 0)
+		}
+	}
+
+	_, hasRetry := config["retryPolicy"]
+	_, hasHedging := config["hedgingPolicy"]
+	if hasRetry && hasHedging {
+		v.addf(path, "retryPolicy and hedgingPolicy are mutually exclusive")
+	}
+
+	if hasRetry {
+		retryPath := path + ".retryPolicy"
+		if retry := v.object(retryPath, config["retryPolicy"], "maxAttempts", "initialBackoff", "maxBackoff", "backoffMultiplier", "retryableStatusCodes"); retry != nil {
+			config["retryPolicy"] = retry
+			v.integer(retryPath+".maxAttempts", retry["maxAttempts"], 2)
+			v.duration(retryPath+".initialBackoff", retry["initialBackoff"])
+			v.duration(retryPath+".maxBackoff", retry["maxBackoff"])
+			if multiplier, ok := retry["backoffMultiplier"].(*big.Float); !ok || multiplier.Sign() <= 0 {
+				v.addf(retryPath+".backoffMultiplier", "must be a number greater than zero")
+			}
+			if codes := v.statusCodes(retryPath+".retryableStatusCodes", retry["retryableStatusCodes"]); codes != nil {
+				retry["retryableStatusCodes"] = codes
+			}
+		}
+	}
+
+	if hasHedging {
+		hedgingPath := path + ".hedgingPolicy"
+		if hedging := v.object(hedgingPath, config["hedgingPolicy"], "maxAttempts", "hedgingDelay", "nonFatalStatusCodes"); hedging != nil {
+			config["hedgingPolicy"] = hedging
+			v.integer(hedgingPath+".maxAttempts", hedging["maxAttempts"], 2)
+			if delay, ok := hedging["hedgingDelay"]; ok {
+				if s, ok := delay.(string); !ok || !protoDuration.MatchString(s) {
+					v.addf(hedgingPath+".hedgingDelay", "expected a duration such as \"0.5s\", got %v", delay)
+				}
+			}
+			if codes, ok := hedging["nonFatalStatusCodes"]; ok {
+				if normalized := v.statusCodes(hedgingPath+".nonFatalStatusCodes", codes); normalized != nil {
+					hedging["nonFatalStatusCodes"] = normalized
+				}
+			}
+		}
+	}
+
+	return config
+}
+
+// gRPC Service Config Function
+var _ function.Function = &GRPCServiceConfigFunction{}
+
+type GRPCServiceConfigFunction struct{}
+
+func NewGRPCServiceConfigFunction() function.Function {
+	return &GRPCServiceConfigFunction{}
+}
+
+func (f *GRPCServiceConfigFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "grpc_service_config"
+}
+
+func (f *GRPCServiceConfigFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates and renders a gRPC service config",
+		Description: "Takes a gRPC service config object (method configs, retry and hedging policies, retry throttling and load balancing) " +
+			"and returns it as JSON after validating it against the service config schema. Field names may be given in " +
+			"snake_case or lowerCamelCase and are rendered in lowerCamelCase; status codes are rendered by name.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "object",
+				Description: "The service config object",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *GRPCServiceConfigFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var object types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &object))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, object)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	validator := &grpcConfigValidator{}
+	config := validator.validate(data)
+	if len(validator.problems) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "Invalid service config:\n  - "+strings.Join(validator.problems, "\n  - ")))
+		return
+	}
+
+	result, err := encodeJSON(config)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// openAPIMethods lists the operation keys of an OpenAPI path item.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPIVersion matches the supported values of the "openapi" field.
+var openAPIVersion = regexp.MustCompile(`^3\.[01]\.\d+$`)
+
+// openAPIPathParameter matches templated segments in a path, e.g. {id}.
+var openAPIPathParameter = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// decodeOpenAPI parses an OpenAPI document given as JSON or YAML.
+func decodeOpenAPI(input string) (map[string]any, error) {
+	data, err := decodeDocument(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON or YAML: %s", err)
+	}
+	spec, ok := data.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a document object, got %s", typeName(data))
+	}
+	return spec, nil
+}
+
+// resolveJSONPointer looks up a local reference such as
+// "#/components/schemas/User" in a document.
+func resolveJSONPointer(document any, ref string) (any, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return document, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	current := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			next, ok := lookupPath(v, token)
+			if !ok {
+				return nil, false
+			}
+			current = next
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// validateOpenAPI returns the problems found in an OpenAPI 3.x document.
+func validateOpenAPI(spec map[string]any) []string {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if _, ok := spec["swagger"]; ok {
+		return []string{"Swagger 2.0 documents are not supported, convert to OpenAPI 3"}
+	}
+	version, _ := spec["openapi"].(string)
+	if !openAPIVersion.MatchString(version) {
+		addf("openapi: expected a 3.0.x or 3.1.x version string, got %v", spec["openapi"])
+	}
+	is31 := strings.HasPrefix(version, "3.1.")
+
+	info, ok := spec["info"].(map[string]any)
+	if !ok {
+		addf("info: required object is missing")
+	} else {
+		for _, field := range []string{"title", "version"} {
+			if s, ok := info[field].(string); !ok || s == "" {
+				addf("info.%s: required string is missing", field)
+			}
+		}
+	}
+
+	paths, hasPaths := spec["paths"]
+	if !hasPaths && !is31 {
+		addf("paths: required object is missing")
+	}
+	if hasPaths {
+		pathMap, ok := paths.(map[string]any)
+		if !ok {
+			addf("paths: expected an object")
+		}
+		operationIDs := map[string]string{}
+		for _, path := range sortedKeys(pathMap) {
+			if !strings.HasPrefix(path, "/") {
+				addf("paths.%s: path must begin with '/'", path)
+			}
+			item, ok := pathMap[path].(map[string]any)
+			if !ok {
+				addf("paths.%s: expected a path item object", path)
+				continue
+			}
+			if _, ok := item["$ref"]; ok {
+				continue
+			}
+
+			templated := map[string]bool{}
+			for _, match := range openAPIPathParameter.FindAllStringSubmatch(path, -1) {
+				templated[match[1]] = true
+			}
+			shared := openAPIPathParams(spec, item["parameters"])
+
+			for _, method := range openAPIMethods {
+				raw, ok := item[method]
+				if !ok {
+					continue
+				}
+				location := fmt.Sprintf("paths.%s.%s", path, method)
+				operation, ok := raw.(map[string]any)
+				if !ok {
+					addf("%s: expected an operation object", location)
+					continue
+				}
+
+				if id, ok := operation["operationId"].(string); ok {
+					if previous, exists := operationIDs[id]; exists {
+						addf("%s: operationId %q is already used by %s", location, id, previous)
+					}
+					operationIDs[id] = location
+				}
+
+				if responses, ok := operation["responses"]; ok {
+					if r, ok := responses.(map[string]any); !ok || len(r) == 0 {
+						addf("%s.responses: must contain at least one response", location)
+					}
+				} else if !is31 {
+					addf("%s.responses: required object is missing", location)
+				}
+
+				declared := map[string]bool{}
+				for name := range shared {
+					declared[name] = true
+				}
+				for name := range openAPIPathParams(spec, operation["parameters"]) {
+					declared[name] = true
+				}
+				for _, name := range sortedKeys(templated) {
+					if !declared[name] {
+						addf("%s: path parameter %q is not declared", location, name)
+					}
+				}
+				for _, name := range sortedKeys(declared) {
+					if !templated[name] {
+						addf("%s: path parameter %q does not appear in the path", location, name)
+					}
+				}
+			}
+		}
+	}
+
+	walkRefs(spec, "", func(location, ref string) {
+		if strings.HasPrefix(ref, "#") {
+			if _, ok := resolveJSONPointer(spec, ref); !ok {
+				addf("%s: reference %q cannot be resolved", location, ref)
+			}
+		}
+	})
+
+	return problems
+}
+
+// openAPIPathParams returns the names of "in: path" parameters in a
+// parameter list, following local references.
+func openAPIPathParams(spec map[string]any, parameters any) map[string]bool {
+	names := map[string]bool{}
+	list, _ := parameters.([]any)
+	for _, entry := range list {
+		parameter, _ := entry.(map[string]any)
+		if ref, ok := parameter["$ref"].(string); ok {
+			resolved, _ := resolveJSONPointer(spec, ref)
+			parameter, _ = resolved.(map[string]any)
+		}
+		if in, _ := parameter["in"].(string); in == "path" {
+			if name, ok := parameter["name"].(string); ok {
+				names[name] = true
+			}
+		}
+	}
+	return names
+}
+
+// walkRefs calls visit for every "$ref" string found in a document.
+func walkRefs(data any, location string, visit func(location, ref string)) {
+	switch v := data.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			if ref, ok := v[key].(string); ok && key == "$ref" {
+				visit(location, ref)
+				continue
+			}
+			walkRefs(v[key], strings.TrimPrefix(location+"."+key, "."), visit)
+		}
+	case []any:
+		for i, element := range v {
+			walkRefs(element, fmt.Sprintf("%s[%d]", location, i), visit)
+		}
+	}
+}
+
+// mergeOpenAPI overlays one OpenAPI document onto another. Objects such as
+// paths and components are merged recursively with the overlay winning, tags
+// are combined by name and other lists are replaced.
+func mergeOpenAPI(base, overlay map[string]any) map[string]any {
+	merged := deepMerge(base, overlay, arrayStrategyReplace).(map[string]any)
+
+	baseTags, _ := base["tags"].([]any)
+	overlayTags, _ := overlay["tags"].([]any)
+	if len(baseTags) > 0 && len(overlayTags) > 0 {
+		tags := []any{}
+		index := map[string]int{}
+		for _, tag := range append(append([]any{}, baseTags...), overlayTags...) {
+			object, _ := tag.(map[string]any)
+			name, ok := object["name"].(string)
+			if !ok {
+				tags = append(tags, tag)
+				continue
+			}
+			if i, exists := index[name]; exists {
+				tags[i] = deepMerge(tags[i], object, arrayStrategyReplace)
+				continue
+			}
+			index[name] = len(tags)
+			tags = append(tags, object)
+		}
+		merged["tags"] = tags
+	}
+
+	return merged
+}
+
+// globMatch reports whether name matches a glob pattern in which '*'
+// matches any run of characters except separator, '**' matches anything and
+// '?' matches a single character.
+func globMatch(pattern, name string, separator byte) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^" + regexp.QuoteMeta(string(separator)) + "]*")
+		case c == '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()).MatchString(name)
+}
+
+// OpenAPI Validate Function
+var _ function.Function = &OpenAPIValidateFunction{}
+
+type OpenAPIValidateFunction struct{}
+
+func NewOpenAPIValidateFunction() function.Function {
+	return &OpenAPIValidateFunction{}
+}
+
+func (f *OpenAPIValidateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "openapi_validate"
+}
+
+func (f *OpenAPIValidateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates an OpenAPI document",
+		Description: "Takes an OpenAPI 3.0 or 3.1 document as JSON or YAML and returns it as compact JSON if it is structurally valid. " +
+			"Checks the version, required info fields, path syntax, declared path parameters, responses, " +
+			"unique operation IDs and that every local $ref resolves.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "spec",
+				Description: "The OpenAPI document as JSON or YAML",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *OpenAPIValidateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	spec, err := decodeOpenAPI(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if problems := validateOpenAPI(spec); len(problems) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "Invalid OpenAPI document:\n  - "+strings.Join(problems, "\n  - ")))
+		return
+	}
+
+	result, err := encodeJSON(spec)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// OpenAPI Merge Function
+var _ function.Function = &OpenAPIMergeFunction{}
+
+type OpenAPIMergeFunction struct{}
+
+func NewOpenAPIMergeFunction() function.Function {
+	return &OpenAPIMergeFunction{}
+}
+
+func (f *OpenAPIMergeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "openapi_merge"
+}
+
+func (f *OpenAPIMergeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Merges two OpenAPI documents",
+		Description: "Takes a base and an overlay OpenAPI document as JSON or YAML and returns the merged document as compact JSON. " +
+			"Paths, operations and components are merged recursively with the overlay taking precedence, " +
+			"tags are combined by name and other lists (such as servers) are replaced by the overlay.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "base",
+				Description: "The base OpenAPI document",
+			},
+			function.StringParameter{
+				Name:        "overlay",
+				Description: "The document to merge on top of the base",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *OpenAPIMergeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var baseInput string
+	var overlayInput string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &baseInput, &overlayInput))
+	if resp.Error != nil {
+		return
+	}
+
+	base, err := decodeOpenAPI(baseInput)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	overlay, err := decodeOpenAPI(overlayInput)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	result, err := encodeJSON(mergeOpenAPI(base, overlay))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// OpenAPI Extract Paths Function
+var _ function.Function = &OpenAPIExtractPathsFunction{}
+
+type OpenAPIExtractPathsFunction struct{}
+
+func NewOpenAPIExtractPathsFunction() function.Function {
+	return &OpenAPIExtractPathsFunction{}
+}
+
+func (f *OpenAPIExtractPathsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "openapi_extract_paths"
+}
+
+func (f *OpenAPIExtractPathsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Extracts matching paths from an OpenAPI document",
+		Description: "Takes an OpenAPI document as JSON or YAML and a glob filter, returning the document as compact JSON with only the " +
+			"paths that match. In the filter '*' matches within a single path segment, '**' matches across segments and an empty " +
+			"filter keeps every path. All other top-level sections are kept as-is.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "spec",
+				Description: "The OpenAPI document as JSON or YAML",
+			},
+			function.StringParameter{
+				Name:        "filter",
+				Description: "A glob pattern matched against path keys, e.g. '/users/**'",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *OpenAPIExtractPathsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	var filter string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &filter))
+	if resp.Error != nil {
+		return
+	}
+
+	spec, err := decodeOpenAPI(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	if paths, ok := spec["paths"].(map[string]any); ok && filter != "" {
+		extracted := map[string]any{}
+		for path, item := range paths {
+			if globMatch(filter, path, '/') {
+				extracted[path] = item
+			}
+		}
+		spec["paths"] = extracted
+	}
+
+	result, err := encodeJSON(spec)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,265 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFieldMaskPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"flat", `{"display_name":"x","labels":{}}`, `["display_name","labels"]`},
+		{"nested", `{"config":{"retries":3,"backoff":{"max":10}},"tags":["a"]}`, `["config.backoff.max","config.retries","tags"]`},
+		{"quoted keys", `{"labels":{"app.kubernetes.io/name":"web"}}`, "[\"labels.`app.kubernetes.io/name`\"]"},
+		{"empty", `{}`, `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewFieldMaskPathsFunction(), dynamicOf(t, mustJSON(t, tt.input)))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := runFunction(t, NewFieldMaskPathsFunction(), dynamicOf(t, "x")); err == nil {
+		t.Error("expected error for non-object")
+	}
+}
+
+func TestApplyFieldMask(t *testing.T) {
+	object := `{"name":"n","config":{"retries":3,"backoff":{"max":10,"min":1}},"labels":{"a.b":"1","c":"2"}}`
+
+	tests := []struct {
+		name     string
+		paths    []string
+		expected string
+	}{
+		{"top level", []string{"name"}, `{"name":"n"}`},
+		{"nested", []string{"config.backoff.max", "config.retries"}, `{"config":{"backoff":{"max":10},"retries":3}}`},
+		{"whole object", []string{"config.backoff"}, `{"config":{"backoff":{"max":10,"min":1}}}`},
+		{"quoted", []string{"labels.`a.b`"}, `{"labels":{"a.b":"1"}}`},
+		{"missing", []string{"nope", "name.sub"}, `{}`},
+		{"wildcard", []string{"*"}, object},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewApplyFieldMaskFunction(), dynamicOf(t, mustJSON(t, object)), stringList(tt.paths...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got, want := jsonOf(t, result), jsonOf(t, dynamicOf(t, mustJSON(t, tt.expected))); got != want {
+				t.Errorf("expected %s, got %s", want, got)
+			}
+		})
+	}
+
+	if _, err := runFunction(t, NewApplyFieldMaskFunction(), dynamicOf(t, mustJSON(t, object)), stringList("a..b")); err == nil {
+		t.Error("expected error for empty segment")
+	}
+}
+
+func TestGRPCServiceConfig(t *testing.T) {
+	input := `{
+		"method_config": [{
+			"name": [{"service": "acme.Users"}],
+			"timeout": "1.5s",
+			"retry_policy": {
+				"max_attempts": 4,
+				"initial_backoff": "0.1s",
+				"max_backoff": "1s",
+				"backoff_multiplier": 2,
+				"retryable_status_codes": ["unavailable", 4]
+			}
+		}],
+		"retryThrottling": {"maxTokens": 10, "tokenRatio": 0.1},
+		"loadBalancingConfig": [{"round_robin": {}}]
+	}`
+	expected := `{"loadBalancingConfig":[{"round_robin":{}}],"methodConfig":[{"name":[{"service":"acme.Users"}],` +
+		`"retryPolicy":{"backoffMultiplier":2,"initialBackoff":"0.1s","maxAttempts":4,"maxBackoff":"1s",` +
+		`"retryableStatusCodes":["UNAVAILABLE","DEADLINE_EXCEEDED"]},"timeout":"1.5s"}],"retryThrottling":{"maxTokens":10,"tokenRatio":0.1}}`
+
+	result, err := runFunction(t, NewGRPCServiceConfigFunction(), dynamicOf(t, mustJSON(t, input)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := result.(types.String).ValueString(); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestGRPCServiceConfigErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown field", `{"methodConfigs": []}`},
+		{"missing name", `{"methodConfig": [{"timeout": "1s"}]}`},
+		{"bad timeout", `{"methodConfig": [{"name": [{}], "timeout": "1000ms"}]}`},
+		{"method without service", `{"methodConfig": [{"name": [{"method": "Get"}]}]}`},
+		{"duplicate names", `{"methodConfig": [{"name": [{"service": "a"}]}, {"name": [{"service": "a"}]}]}`},
+		{"retry and hedging", `{"methodConfig": [{"name": [{}], "retryPolicy": {}, "hedgingPolicy": {}}]}`},
+		{"retry attempts", `{"methodConfig": [{"name": [{}], "retryPolicy": {"maxAttempts": 1, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 1, "retryableStatusCodes": ["UNAVAILABLE"]}}]}`},
+		{"unknown status", `{"methodConfig": [{"name": [{}], "retryPolicy": {"maxAttempts": 2, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 1, "retryableStatusCodes": ["BROKEN"]}}]}`},
+		{"throttling", `{"retryThrottling": {"maxTokens": 5000, "tokenRatio": 1}}`},
+		{"lb config", `{"loadBalancingConfig": [{"a": {}, "b": {}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFunction(t, NewGRPCServiceConfigFunction(), dynamicOf(t, mustJSON(t, tt.input))); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: Users
+  version: "1.0"
+tags:
+  - name: users
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+  /users/{id}:
+    parameters:
+      - $ref: "#/components/parameters/UserID"
+    get:
+      operationId: getUser
+      responses:
+        "200":
+          description: ok
+  /health:
+    get:
+      responses:
+        "200":
+          description: ok
+components:
+  parameters:
+    UserID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: string
+  schemas:
+    User:
+      type: object
+`
+
+func TestOpenAPIValidate(t *testing.T) {
+	if _, err := runFunction(t, NewOpenAPIValidateFunction(), types.StringValue(testOpenAPISpec)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"swagger", `{"swagger": "2.0"}`},
+		{"missing info", `{"openapi": "3.0.0", "paths": {}}`},
+		{"bad path", `{"openapi": "3.0.0", "info": {"title": "a", "version": "1"}, "paths": {"users": {}}}`},
+		{"undeclared parameter", `{"openapi": "3.0.0", "info": {"title": "a", "version": "1"}, "paths": {"/u/{id}": {"get": {"responses": {"200": {"description": "ok"}}}}}}`},
+		{"missing responses", `{"openapi": "3.0.0", "info": {"title": "a", "version": "1"}, "paths": {"/u": {"get": {}}}}`},
+		{"duplicate operation id", `{"openapi": "3.0.0", "info": {"title": "a", "version": "1"}, "paths": {"/a": {"get": {"operationId": "x", "responses": {"200": {}}}}, "/b": {"get": {"operationId": "x", "responses": {"200": {}}}}}}`},
+		{"dangling ref", `{"openapi": "3.1.0", "info": {"title": "a", "version": "1"}, "components": {"schemas": {"A": {"$ref": "#/components/schemas/B"}}}}`},
+		{"not a document", `[1, 2]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFunction(t, NewOpenAPIValidateFunction(), types.StringValue(tt.spec)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestOpenAPIMerge(t *testing.T) {
+	overlay := `{
+		"tags": [{"name": "users", "description": "User operations"}, {"name": "admin"}],
+		"servers": [{"url": "https://api.example.com"}],
+		"paths": {"/users": {"post": {"responses": {"201": {"description": "created"}}}}},
+		"components": {"schemas": {"Admin": {"type": "object"}}}
+	}`
+
+	result, err := runFunction(t, NewOpenAPIMergeFunction(), types.StringValue(testOpenAPISpec), types.StringValue(overlay))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	merged := mustJSON(t, result.(types.String).ValueString())
+	checks := map[string]any{
+		"paths./users.get.operationId":    "listUsers",
+		"paths./users.post.responses":     map[string]any{"201": map[string]any{"description": "created"}},
+		"components.schemas.User.type":    "object",
+		"components.schemas.Admin.type":   "object",
+		"tags.0.description":              "User operations",
+		"tags.1.name":                     "admin",
+		"servers.0.url":                   "https://api.example.com",
+		"components.parameters.UserID.in": "path",
+	}
+	for path, expected := range checks {
+		value, ok := lookupPath(merged, path)
+		if !ok {
+			t.Errorf("missing %s in merged document", path)
+			continue
+		}
+		gotJSON, _ := encodeJSON(value)
+		wantJSON, _ := encodeJSON(expected)
+		if gotJSON != wantJSON {
+			t.Errorf("%s: expected %s, got %s", path, wantJSON, gotJSON)
+		}
+	}
+}
+
+func TestOpenAPIExtractPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   string
+		expected []string
+	}{
+		{"single segment", "/users/*", []string{"/users/{id}"}},
+		{"any depth", "/users**", []string{"/users", "/users/{id}"}},
+		{"exact", "/health", []string{"/health"}},
+		{"all", "", []string{"/health", "/users", "/users/{id}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewOpenAPIExtractPathsFunction(), types.StringValue(testOpenAPISpec), types.StringValue(tt.filter))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			spec := mustJSON(t, result.(types.String).ValueString()).(map[string]any)
+			got := sortedKeys(spec["paths"].(map[string]any))
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			if _, ok := spec["components"]; !ok {
+				t.Error("expected components to be preserved")
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const inTotoPayloadType = "application/vnd.in-toto+json"
+
+// configSourceURIPath holds the source of SLSA v0.2 provenance as a URI that
+// may end in @<ref>, such as git+https://github.com/acme/app@refs/heads/main.
+const configSourceURIPath = "predicate.invocation.configSource.uri"
+
+// provenanceAliases maps convenience field names to the locations they occupy
+// in SLSA provenance v0.2 and v1 predicates, tried in order.
+var provenanceAliases = map[string][]string{
+	"predicate_type": {"predicateType"},
+	"builder_id":     {"predicate.runDetails.builder.id", "predicate.builder.id"},
+	"build_type":     {"predicate.buildDefinition.buildType", "predicate.buildType"},
+	"source_repo": {
+		"predicate.buildDefinition.externalParameters.workflow.repository",
+		configSourceURIPath,
+		"predicate.buildDefinition.resolvedDependencies.0.uri",
+		"predicate.materials.0.uri",
+	},
+	"source_ref": {
+		"predicate.buildDefinition.externalParameters.workflow.ref",
+		configSourceURIPath,
+	},
+	"invocation_id": {"predicate.runDetails.metadata.invocationId", "predicate.metadata.buildInvocationId"},
+	"subjects":      {"subject"},
+}
+
+// splitConfigSourceURI splits a SLSA v0.2 configSource URI into the
+// repository and the ref after its last @, ignoring an @ in the user info.
+func splitConfigSourceURI(uri string) (string, string, bool) {
+	pathStart := 0
+	if scheme := strings.Index(uri, "://"); scheme >= 0 {
+		if slash := strings.Index(uri[scheme+3:], "/"); slash >= 0 {
+			pathStart = scheme + 3 + slash
+		}
+	}
+	at := strings.LastIndex(uri[pathStart:], "@")
+	if at < 0 {
+		return uri, "", false
+	}
+	return uri[:pathStart+at], uri[pathStart+at+1:], true
+}
+
+// decodeProvenance unwraps a DSSE envelope (optionally inside a Sigstore
+// bundle) and returns the decoded in-toto statement. Signatures are not
+// verified.
+func decodeProvenance(input string) (map[string]any, error) {
+	document, err := decodeJSON(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %s", err)
+	}
+
+	envelope, ok := document.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a DSSE envelope object")
+	}
+	if bundled, ok := envelope["dsseEnvelope"].(map[string]any); ok {
+		envelope = bundled
+	}
+
+	payloadType, _ := envelope["payloadType"].(string)
+	if payloadType != inTotoPayloadType {
+		return nil, fmt.Errorf("unsupported payloadType %q, expected %q", payloadType, inTotoPayloadType)
+	}
+
+	encoded, ok := envelope["payload"].(string)
+	if !ok {
+		return nil, fmt.Errorf("envelope has no payload")
+	}
+	payload, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		payload, err = base64.URLEncoding.DecodeString(encoded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("payload is not valid base64: %s", err)
+	}
+
+	decoded, err := decodeJSON(string(payload))
+	if err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %s", err)
+	}
+	statement, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("payload is not an in-toto statement")
+	}
+
+	statementType, _ := statement["_type"].(string)
+	if !strings.HasPrefix(statementType, "https://in-toto.io/Statement/") {
+		return nil, fmt.Errorf("payload is not an in-toto statement: _type %q", statementType)
+	}
+	predicateType, _ := statement["predicateType"].(string)
+	if !strings.HasPrefix(predicateType, "https://slsa.dev/provenance/") {
+		return nil, fmt.Errorf("predicateType %q is not SLSA provenance", predicateType)
+	}
+
+	return statement, nil
+}
+
+// Provenance Extract Function
+var _ function.Function = &ProvenanceExtractFunction{}
+
+type ProvenanceExtractFunction struct{}
+
+func NewProvenanceExtractFunction() function.Function {
+	return &ProvenanceExtractFunction{}
+}
+
+func (f *ProvenanceExtractFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "provenance_extract"
+}
+
+func (f *ProvenanceExtractFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Extracts fields from SLSA provenance",
+		Description: "Takes a DSSE envelope (or Sigstore bundle) containing an in-toto SLSA provenance statement and a list of fields, " +
+			"returning an object of the requested values. Fields are dot-separated paths into the statement " +
+			"(e.g. 'predicate.builder.id') or one of the aliases builder_id, build_type, source_repo, source_ref, " +
+			"invocation_id, predicate_type and subjects, which work across SLSA v0.2 and v1. Missing fields are null. " +
+			"Signatures are not verified.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "attestation_json",
+				Description: "The DSSE envelope JSON",
+			},
+			function.ListParameter{
+				Name:        "fields",
+				Description: "The fields to extract",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ProvenanceExtractFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	var fields []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &fields))
+	if resp.Error != nil {
+		return
+	}
+
+	statement, err := decodeProvenance(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid attestation: %s", err)))
+		return
+	}
+
+	result := make(map[string]any, len(fields))
+	for _, field := range fields {
+		paths, ok := provenanceAliases[field]
+		if !ok {
+			paths = []string{field}
+		}
+		result[field] = nil
+		for _, path := range paths {
+			value, ok := lookupPath(statement, path)
+			// The source aliases take their parts of the URI; the raw path
+			// returns it unchanged.
+			if uri, isString := value.(string); isString && path == configSourceURIPath && field != path {
+				repo, ref, hasRef := splitConfigSourceURI(uri)
+				if field == "source_ref" {
+					value, ok = ref, hasRef
+				} else {
+					value = repo
+				}
+			}
+			if ok {
+				result[field] = value
+				break
+			}
+		}
+	}
+
+	value, err := toDynamic(result)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, value))
+}
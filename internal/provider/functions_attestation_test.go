@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testEnvelope(t *testing.T, statement string) string {
+	t.Helper()
+	envelope, err := json.Marshal(map[string]any{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString([]byte(statement)),
+		"signatures":  []any{map[string]any{"sig": "c2ln"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(envelope)
+}
+
+func TestProvenanceExtract(t *testing.T) {
+	v1 := `{
+		"_type": "https://in-toto.io/Statement/v1",
+		"subject": [{"name": "app", "digest": {"sha256": "abc"}}],
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"predicate": {
+			"buildDefinition": {
+				"buildType": "https://slsa-framework.github.io/github-actions-buildtypes/workflow/v1",
+				"externalParameters": {"workflow": {"repository": "https://github.com/acme/app", "ref": "refs/heads/main"}}
+			},
+			"runDetails": {"builder": {"id": "https://github.com/actions/runner"}}
+		}
+	}`
+	v02 := `{
+		"_type": "https://in-toto.io/Statement/v0.1",
+		"predicateType": "https://slsa.dev/provenance/v0.2",
+		"predicate": {
+			"builder": {"id": "https://cloudbuild.googleapis.com/GoogleHostedWorker"},
+			"invocation": {"configSource": {"uri": "git+https://github.com/acme/legacy", "entryPoint": "cloudbuild.yaml"}}
+		}
+	}`
+
+	v02Ref := `{
+		"_type": "https://in-toto.io/Statement/v0.1",
+		"predicateType": "https://slsa.dev/provenance/v0.2",
+		"predicate": {
+			"builder": {"id": "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@refs/tags/v1.9.0"},
+			"invocation": {"configSource": {"uri": "git+https://github.com/acme/app@refs/heads/main", "entryPoint": ".github/workflows/release.yml"}}
+		}
+	}`
+
+	tests := []struct {
+		name      string
+		statement string
+		fields    []string
+		expected  string
+	}{
+		{
+			name:      "v1 aliases",
+			statement: v1,
+			fields:    []string{"builder_id", "source_repo", "source_ref"},
+			expected:  `{"builder_id":"https://github.com/actions/runner","source_ref":"refs/heads/main","source_repo":"https://github.com/acme/app"}`,
+		},
+		{
+			name:      "v0.2 aliases",
+			statement: v02,
+			fields:    []string{"builder_id", "source_repo", "source_ref"},
+			expected:  `{"builder_id":"https://cloudbuild.googleapis.com/GoogleHostedWorker","source_ref":null,"source_repo":"git+https://github.com/acme/legacy"}`,
+		},
+		{
+			name:      "v0.2 ref from the config source uri",
+			statement: v02Ref,
+			fields:    []string{"source_repo", "source_ref", "predicate.invocation.configSource.uri"},
+			expected: `{"predicate.invocation.configSource.uri":"git+https://github.com/acme/app@refs/heads/main",` +
+				`"source_ref":"refs/heads/main","source_repo":"git+https://github.com/acme/app"}`,
+		},
+		{
+			name:      "raw paths and missing fields",
+			statement: v1,
+			fields:    []string{"subject.0.digest.sha256", "predicate.nope"},
+			expected:  `{"predicate.nope":null,"subject.0.digest.sha256":"abc"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewProvenanceExtractFunction(), types.StringValue(testEnvelope(t, tt.statement)), stringList(tt.fields...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProvenanceExtractErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"invalid json", `{`},
+		{"wrong payload type", `{"payloadType":"text/plain","payload":"e30="}`},
+		{"bad base64", `{"payloadType":"application/vnd.in-toto+json","payload":"!!"}`},
+		{"not provenance", testEnvelope(t, `{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://spdx.dev/Document"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFunction(t, NewProvenanceExtractFunction(), types.StringValue(tt.input), stringList("builder_id")); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
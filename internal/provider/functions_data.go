@@ -0,0 +1,2224 @@
+package provider
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/itchyny/gojq"
+	"gopkg.in/yaml.v3"
+)
+
+// queryTimeout bounds how long a query expression may run, guarding against
+// expressions such as `repeat(.)` that never terminate.
+const queryTimeout = 5 * time.Second
+
+// toQueryData converts plain Go data into the value types gojq expects.
+func toQueryData(data any) any {
+	switch v := data.(type) {
+	case *big.Float:
+		if v.IsInt() {
+			if i, accuracy := v.Int64(); accuracy == big.Exact && i >= math.MinInt && i <= math.MaxInt {
+				return int(i)
+			}
+			i, _ := v.Int(nil)
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		result := make([]any, len(v))
+		for i, element := range v {
+			result[i] = toQueryData(element)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, element := range v {
+			result[key] = toQueryData(element)
+		}
+		return result
+	}
+	return data
+}
+
+// fromQueryData converts gojq results back into plain Go data.
+func fromQueryData(data any) (any, error) {
+	switch v := data.(type) {
+	case int:
+		return new(big.Float).SetInt64(int64(v)), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("result contains a non-finite number")
+		}
+		return big.NewFloat(v), nil
+	case *big.Int:
+		return new(big.Float).SetInt(v), nil
+	case []any:
+		result := make([]any, len(v))
+		for i, element := range v {
+			converted, err := fromQueryData(element)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, element := range v {
+			converted, err := fromQueryData(element)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = converted
+		}
+		return result, nil
+	case error:
+		return nil, v
+	}
+	return data, nil
+}
+
+// Query Function
+var _ function.Function = &QueryFunction{}
+
+type QueryFunction struct{}
+
+func NewQueryFunction() function.Function {
+	return &QueryFunction{}
+}
+
+func (f *QueryFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "query"
+}
+
+func (f *QueryFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Queries a document with a jq expression",
+		Description: "Takes a decoded document (object, list or scalar) and a jq expression, returning the result of evaluating the expression. " +
+			"An expression producing no results returns null; one producing several results is an error, so wrap it in [ ] to collect them.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "document",
+				Description:    "The document to query, e.g. the result of jsondecode() or yamldecode()",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "expression",
+				Description: "The jq expression to evaluate",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *QueryFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var document types.Dynamic
+	var expression string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &document, &expression))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, document)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	parsed, err := gojq.Parse(expression)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("Invalid expression: %s", err)))
+		return
+	}
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("Invalid expression: %s", err)))
+		return
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	var results []any
+	iter := code.RunWithContext(queryCtx, toQueryData(data))
+	for {
+		output, ok := iter.Next()
+		if !ok {
+			break
+		}
+		converted, err := fromQueryData(output)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("Query failed: %s", err)))
+			return
+		}
+		results = append(results, converted)
+		if len(results) > 1 {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "expression produced more than one result; wrap it in [ ] to collect the results into a list"))
+			return
+		}
+	}
+
+	var output any
+	if len(results) == 1 {
+		output = results[0]
+	}
+
+	result, err := toDynamic(output)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// writeCanonicalJSON serialises data following RFC 8785 (JSON Canonicalization
+// Scheme): object keys sorted by UTF-16 code units, ECMAScript number
+// formatting, minimal string escaping and no insignificant whitespace.
+func writeCanonicalJSON(b *strings.Builder, data any) error {
+	switch v := data.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case string:
+		writeCanonicalString(b, v)
+	case *big.Float:
+		f, _ := v.Float64()
+		if math.IsInf(f, 0) {
+			return fmt.Errorf("number %s is out of range", v.Text('g', 10))
+		}
+		b.WriteString(formatECMAScriptNumber(f))
+	case []any:
+		b.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(b, element); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeCanonicalString(b, key)
+			b.WriteByte(':')
+			if err := writeCanonicalJSON(b, v[key]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported value %T", data)
+	}
+	return nil
+}
+
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+func writeCanonicalString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
+
+// formatECMAScriptNumber renders a float64 the way ECMAScript's
+// Number.prototype.toString does, as required by RFC 8785.
+func formatECMAScriptNumber(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// Shortest round-trip digits and the decimal exponent n such that the
+	// value is 0.digits × 10^n.
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exponent)
+	n := e + 1
+	k := len(digits)
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+
+	exponentSign := "+"
+	if n-1 < 0 {
+		exponentSign = "-"
+	}
+	exponentValue := strconv.Itoa(int(math.Abs(float64(n - 1))))
+	if k == 1 {
+		return sign + digits + "e" + exponentSign + exponentValue
+	}
+	return sign + digits[:1] + "." + digits[1:] + "e" + exponentSign + exponentValue
+}
+
+// JSON Canonical Function
+var _ function.Function = &JSONCanonicalFunction{}
+
+type JSONCanonicalFunction struct{}
+
+func NewJSONCanonicalFunction() function.Function {
+	return &JSONCanonicalFunction{}
+}
+
+func (f *JSONCanonicalFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "json_canonical"
+}
+
+func (f *JSONCanonicalFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Canonicalizes a JSON document",
+		Description: "Takes a JSON string and re-serializes it following RFC 8785 (JSON Canonicalization Scheme): " +
+			"object keys are sorted, numbers use a stable shortest form and insignificant whitespace is removed, " +
+			"so semantically equal documents always produce identical strings.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The JSON document to canonicalize",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *JSONCanonicalFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := decodeJSON(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid JSON: %s", err)))
+		return
+	}
+
+	var b strings.Builder
+	if err := writeCanonicalJSON(&b, data); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, b.String()))
+}
+
+// jsonNumber matches number literals that are already valid JSON, so YAML
+// floats such as 1.0 keep their original spelling.
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// yamlEntry is a mapping key and value after merge keys are resolved.
+type yamlEntry struct {
+	key   string
+	value *yaml.Node
+}
+
+// parseSingleYAML parses input as exactly one YAML document.
+func parseSingleYAML(input string) (*yaml.Node, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(input))
+
+	var document yaml.Node
+	if err := decoder.Decode(&document); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var extra yaml.Node
+	if err := decoder.Decode(&extra); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("input contains more than one YAML document")
+	}
+	return &document, nil
+}
+
+// yamlMappingEntries lists a mapping's entries in document order. Keys from
+// "<<" merge keys appear where the merge key is, unless set explicitly.
+func yamlMappingEntries(node *yaml.Node) ([]yamlEntry, error) {
+	explicit := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if key.Tag == "!!merge" {
+			continue
+		}
+		if key.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: mapping keys must be scalars", key.Line)
+		}
+		if explicit[key.Value] {
+			return nil, fmt.Errorf("line %d: duplicate key %q", key.Line, key.Value)
+		}
+		explicit[key.Value] = true
+	}
+
+	var entries []yamlEntry
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag != "!!merge" {
+			entries = append(entries, yamlEntry{key.Value, value})
+			seen[key.Value] = true
+			continue
+		}
+
+		sources := []*yaml.Node{value}
+		if resolveYAMLAlias(value).Kind == yaml.SequenceNode {
+			sources = resolveYAMLAlias(value).Content
+		}
+		for _, source := range sources {
+			source = resolveYAMLAlias(source)
+			if source.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("line %d: merge key value must be a mapping", key.Line)
+			}
+			merged, err := yamlMappingEntries(source)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range merged {
+				if !explicit[entry.key] && !seen[entry.key] {
+					entries = append(entries, entry)
+					seen[entry.key] = true
+				}
+			}
+		}
+	}
+	return entries, nil
+}
+
+func resolveYAMLAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// writeOrderedJSON renders a YAML node as compact JSON, keeping mapping order
+// and the distinction between integers, floats and strings.
+func writeOrderedJSON(b *strings.Builder, node *yaml.Node) error {
+	node = resolveYAMLAlias(node)
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return writeOrderedJSON(b, node.Content[0])
+	case yaml.SequenceNode:
+		b.WriteByte('[')
+		for i, element := range node.Content {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeOrderedJSON(b, element); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		entries, err := yamlMappingEntries(node)
+		if err != nil {
+			return err
+		}
+		b.WriteByte('{')
+		for i, entry := range entries {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := encodeJSON(entry.key)
+			b.WriteString(key)
+			b.WriteByte(':')
+			if err := writeOrderedJSON(b, entry.value); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+		return nil
+	case yaml.ScalarNode:
+		return writeJSONScalar(b, node)
+	}
+	return fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+func writeJSONScalar(b *strings.Builder, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		b.WriteString("null")
+		return nil
+	case "!!bool", "!!int":
+		value, err := fromYAMLScalar(node)
+		if err != nil {
+			return err
+		}
+		encoded, _ := encodeJSON(value)
+		b.WriteString(encoded)
+		return nil
+	case "!!float":
+		literal := strings.ReplaceAll(node.Value, "_", "")
+		if jsonNumber.MatchString(literal) {
+			b.WriteString(literal)
+			return nil
+		}
+		value, err := fromYAMLScalar(node)
+		if err != nil {
+			return err
+		}
+		formatted := formatBigFloat(value.(*big.Float))
+		if !strings.ContainsAny(formatted, ".e") {
+			formatted += ".0"
+		}
+		b.WriteString(formatted)
+		return nil
+	}
+	encoded, _ := encodeJSON(node.Value)
+	b.WriteString(encoded)
+	return nil
+}
+
+// YAML To JSON Function
+var _ function.Function = &YAMLToJSONFunction{}
+
+type YAMLToJSONFunction struct{}
+
+func NewYAMLToJSONFunction() function.Function {
+	return &YAMLToJSONFunction{}
+}
+
+func (f *YAMLToJSONFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "yaml_to_json"
+}
+
+func (f *YAMLToJSONFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a YAML document to JSON",
+		Description: "Takes a YAML string and returns compact JSON with mapping keys in document order. Integers stay integers, " +
+			"floats keep a fractional part, quoted values stay strings, and aliases and merge keys are expanded.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The YAML document to convert",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *YAMLToJSONFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	document, err := parseSingleYAML(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid YAML: %s", err)))
+		return
+	}
+	if document == nil {
+		resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, "null"))
+		return
+	}
+
+	var b strings.Builder
+	if err := writeOrderedJSON(&b, document); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid YAML: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, b.String()))
+}
+
+// yaml11Bools are plain scalars that YAML 1.1 parsers read as booleans. The
+// encoder follows YAML 1.2 and would leave them unquoted.
+var yaml11Bools = map[string]bool{
+	"y": true, "yes": true, "n": true, "no": true, "on": true, "off": true,
+}
+
+// readYAMLNode builds a YAML node from the next JSON value in decoder,
+// keeping object key order and number spelling.
+func readYAMLNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if v == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for decoder.More() {
+			if v == '{' {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			element, err := readYAMLNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, element)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		if yaml11Bools[strings.ToLower(v)] {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+		return node, nil
+	case json.Number:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: v.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatBool(v)}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: "null"}, nil
+}
+
+// JSON To YAML Function
+var _ function.Function = &JSONToYAMLFunction{}
+
+type JSONToYAMLFunction struct{}
+
+func NewJSONToYAMLFunction() function.Function {
+	return &JSONToYAMLFunction{}
+}
+
+func (f *JSONToYAMLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "json_to_yaml"
+}
+
+func (f *JSONToYAMLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a JSON document to YAML",
+		Description: "Takes a JSON string and returns block-style YAML with object keys in their original order. " +
+			"Numbers keep their original spelling and strings that would read back as another type are quoted.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The JSON document to convert",
+			},
+			function.Int64Parameter{
+				Name:        "indent",
+				Description: "Number of spaces per indentation level, between 2 and 9",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *JSONToYAMLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	var indent int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &indent))
+	if resp.Error != nil {
+		return
+	}
+
+	if indent < 2 || indent > 9 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "indent must be between 2 and 9"))
+		return
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(input))
+	decoder.UseNumber()
+	node, err := readYAMLNode(decoder)
+	if err == nil {
+		if _, extra := decoder.Token(); extra != io.EOF {
+			err = fmt.Errorf("unexpected data after top-level value")
+		}
+	}
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid JSON: %s", err)))
+		return
+	}
+
+	var b strings.Builder
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(int(indent))
+	if err := encoder.Encode(node); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("Failed to encode YAML: %s", err)))
+		return
+	}
+	if err := encoder.Close(); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("Failed to encode YAML: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, b.String()))
+}
+
+// fromTOML converts decoded TOML values into plain Go data. Dates and times
+// become strings in their TOML spelling; the decoder marks local values with
+// named zones rather than exported locations.
+func fromTOML(data any) (any, error) {
+	switch v := data.(type) {
+	case int64:
+		return new(big.Float).SetInt64(v), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("non-finite number %v is not supported", v)
+		}
+		return big.NewFloat(v), nil
+	case time.Time:
+		switch v.Location().String() {
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999"), nil
+		case "date-local":
+			return v.Format("2006-01-02"), nil
+		case "time-local":
+			return v.Format("15:04:05.999999999"), nil
+		}
+		return v.Format(time.RFC3339Nano), nil
+	case []any:
+		result := make([]any, len(v))
+		for i, element := range v {
+			converted, err := fromTOML(element)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case []map[string]any:
+		result := make([]any, len(v))
+		for i, element := range v {
+			converted, err := fromTOML(element)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, element := range v {
+			converted, err := fromTOML(element)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			result[key] = converted
+		}
+		return result, nil
+	}
+	return data, nil
+}
+
+// toTOML converts plain Go data into values the TOML encoder accepts. TOML
+// has no null, so null object attributes are dropped.
+func toTOML(data any) (any, error) {
+	switch v := data.(type) {
+	case nil:
+		return nil, fmt.Errorf("null values are not supported in lists")
+	case *big.Float:
+		if v.IsInt() {
+			i, accuracy := v.Int64()
+			if accuracy != big.Exact {
+				return nil, fmt.Errorf("integer %s does not fit in 64 bits", formatBigFloat(v))
+			}
+			return i, nil
+		}
+		f, _ := v.Float64()
+		return f, nil
+	case []any:
+		result := make([]any, len(v))
+		for i, element := range v {
+			converted, err := toTOML(element)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, element := range v {
+			if element == nil {
+				continue
+			}
+			converted, err := toTOML(element)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			result[key] = converted
+		}
+		return result, nil
+	}
+	return data, nil
+}
+
+// TOML Decode Function
+var _ function.Function = &TOMLDecodeFunction{}
+
+type TOMLDecodeFunction struct{}
+
+func NewTOMLDecodeFunction() function.Function {
+	return &TOMLDecodeFunction{}
+}
+
+func (f *TOMLDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "toml_decode"
+}
+
+func (f *TOMLDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decodes a TOML document",
+		Description: "Parses a TOML 1.0 document and returns it as an object. Tables become objects, arrays of tables become lists " +
+			"of objects, and dates and times are returned as strings.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The TOML document to decode",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *TOMLDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	var document map[string]any
+	if _, err := toml.Decode(input, &document); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid TOML: %s", err)))
+		return
+	}
+
+	data, err := fromTOML(document)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid TOML: %s", err)))
+		return
+	}
+
+	result, err := toDynamic(data)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// TOML Encode Function
+var _ function.Function = &TOMLEncodeFunction{}
+
+type TOMLEncodeFunction struct{}
+
+func NewTOMLEncodeFunction() function.Function {
+	return &TOMLEncodeFunction{}
+}
+
+func (f *TOMLEncodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "toml_encode"
+}
+
+func (f *TOMLEncodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encodes a value as TOML",
+		Description: "Serializes an object as a TOML document. Keys are sorted, nested objects become tables, lists of objects " +
+			"become arrays of tables, and null attributes are omitted because TOML has no null.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "value",
+				Description: "The object to encode",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *TOMLEncodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if _, ok := data.(map[string]any); !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("value must be an object, got %s", typeName(data))))
+		return
+	}
+
+	document, err := toTOML(data)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	var b strings.Builder
+	encoder := toml.NewEncoder(&b)
+	encoder.Indent = ""
+	if err := encoder.Encode(document); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("Failed to encode TOML: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, b.String()))
+}
+
+// decodeINI parses an INI document into sections of key/value pairs. Keys
+// before the first section header belong to globalSection, or are an error
+// when it is nil, and indented lines continue the previous value.
+func decodeINI(input string, globalSection *string) (map[string]map[string]string, error) {
+	sections := map[string]map[string]string{}
+	section, lastKey := "", ""
+	inSection := globalSection != nil
+	if inSection {
+		section = *globalSection
+	}
+
+	for i, line := range strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if lastKey != "" && (line[0] == ' ' || line[0] == '\t') {
+			if value := sections[section][lastKey]; value != "" {
+				trimmed = value + "\n" + trimmed
+			}
+			sections[section][lastKey] = trimmed
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			if !strings.HasSuffix(trimmed, "]") || strings.TrimSpace(trimmed[1:len(trimmed)-1]) == "" {
+				return nil, fmt.Errorf("line %d: invalid section header %q", i+1, trimmed)
+			}
+			section, lastKey, inSection = strings.TrimSpace(trimmed[1:len(trimmed)-1]), "", true
+			if sections[section] == nil {
+				sections[section] = map[string]string{}
+			}
+			continue
+		}
+
+		separator := strings.IndexAny(trimmed, "=:")
+		if separator <= 0 {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", i+1, trimmed)
+		}
+		key := strings.TrimSpace(trimmed[:separator])
+		if !inSection {
+			return nil, fmt.Errorf("line %d: key %q is before the first section header", i+1, key)
+		}
+		value := strings.TrimSpace(trimmed[separator+1:])
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+
+		if sections[section] == nil {
+			sections[section] = map[string]string{}
+		}
+		if _, exists := sections[section][key]; exists {
+			return nil, fmt.Errorf("line %d: duplicate key %q in section %q", i+1, key, section)
+		}
+		sections[section][key], lastKey = value, key
+	}
+
+	return sections, nil
+}
+
+// INI Decode Function
+var _ function.Function = &INIDecodeFunction{}
+
+type INIDecodeFunction struct{}
+
+func NewINIDecodeFunction() function.Function {
+	return &INIDecodeFunction{}
+}
+
+func (f *INIDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ini_decode"
+}
+
+func (f *INIDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decodes an INI document",
+		Description: "Parses an INI document such as an AWS credentials or config file and returns a map of section names to " +
+			"key/value maps. Keys that appear before the first section header are returned under global_section, or are " +
+			"an error when global_section is null.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The INI document to decode",
+			},
+			function.StringParameter{
+				Name:           "global_section",
+				Description:    "The section name for keys before the first header, such as \"default\" or \"\", or null to reject them",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.MapReturn{
+			ElementType: types.MapType{ElemType: types.StringType},
+		},
+	}
+}
+
+func (f *INIDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	var globalSection types.String
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &globalSection))
+	if resp.Error != nil {
+		return
+	}
+
+	sections, err := decodeINI(input, globalSection.ValueStringPointer())
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid INI: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, sections))
+}
+
+// csvDecodeDefaults lists the options accepted by csv_decode.
+var csvDecodeDefaults = map[string]any{
+	"delimiter":   ",",
+	"header":      true,
+	"columns":     []any{},
+	"infer_types": false,
+	"comment":     "",
+}
+
+// csvRune validates a single-character delimiter or comment option.
+func csvRune(name, value string) (rune, error) {
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("option %q must be a single character other than a quote or newline", name)
+	}
+	return r, nil
+}
+
+// inferCSVValue converts a field to a bool, number or null where it is
+// unambiguous. Numbers with leading zeros, such as postcodes, stay strings.
+func inferCSVValue(field string) any {
+	switch field {
+	case "":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if jsonNumber.MatchString(field) {
+		if number, _, err := big.ParseFloat(field, 10, 512, big.ToNearestEven); err == nil {
+			return number
+		}
+	}
+	return field
+}
+
+// decodeCSV parses records according to csv_decode options, returning a list
+// of objects, or a list of lists when there is neither a header nor columns.
+func decodeCSV(input string, options map[string]any) (any, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(input, "\ufeff")))
+
+	delimiter, err := csvRune("delimiter", options["delimiter"].(string))
+	if err != nil {
+		return nil, err
+	}
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	if comment := options["comment"].(string); comment != "" {
+		if reader.Comment, err = csvRune("comment", comment); err != nil {
+			return nil, err
+		}
+		if reader.Comment == reader.Comma {
+			return nil, fmt.Errorf("options \"comment\" and \"delimiter\" must differ")
+		}
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for _, column := range options["columns"].([]any) {
+		name, ok := column.(string)
+		if !ok {
+			return nil, fmt.Errorf("option \"columns\" must be a list of strings")
+		}
+		columns = append(columns, name)
+	}
+	if options["header"].(bool) && len(records) > 0 {
+		if len(columns) == 0 {
+			columns = records[0]
+		}
+		records = records[1:]
+	}
+
+	seen := map[string]bool{}
+	for _, column := range columns {
+		if column == "" || seen[column] {
+			return nil, fmt.Errorf("column names must be unique and non-empty, got %q", column)
+		}
+		seen[column] = true
+	}
+
+	convert := func(field string) any { return field }
+	if options["infer_types"].(bool) {
+		convert = inferCSVValue
+	}
+
+	rows := make([]any, len(records))
+	for i, record := range records {
+		if len(columns) == 0 {
+			row := make([]any, len(record))
+			for j, field := range record {
+				row[j] = convert(field)
+			}
+			rows[i] = row
+			continue
+		}
+
+		if len(record) != len(columns) {
+			return nil, fmt.Errorf("record %d has %d fields, expected %d", i+1, len(record), len(columns))
+		}
+		row := make(map[string]any, len(columns))
+		for j, column := range columns {
+			row[column] = convert(record[j])
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// CSV Decode Function
+var _ function.Function = &CSVDecodeFunction{}
+
+type CSVDecodeFunction struct{}
+
+func NewCSVDecodeFunction() function.Function {
+	return &CSVDecodeFunction{}
+}
+
+func (f *CSVDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "csv_decode"
+}
+
+func (f *CSVDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decodes CSV with configurable parsing",
+		Description: "Parses CSV text into a list of objects keyed by column name. Options control the delimiter, whether the " +
+			"first record is a header, explicit column names, type inference and comment lines. Quoted fields may contain " +
+			"delimiters and newlines.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The CSV text",
+			},
+			function.DynamicParameter{
+				Name:           "options",
+				Description:    "An object with optional delimiter, header, columns, infer_types and comment attributes, or null for defaults",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *CSVDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	var optionsValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &optionsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	optionsData, err := fromValue(ctx, optionsValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	options, err := parseOptions(optionsData, csvDecodeDefaults)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	rows, err := decodeCSV(input, options)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid CSV: %s", err)))
+		return
+	}
+
+	result, err := toDynamic(rows)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// csvEncodeDefaults lists the options accepted by csv_encode.
+var csvEncodeDefaults = map[string]any{
+	"delimiter": ",",
+	"header":    true,
+	"crlf":      false,
+}
+
+// csvField renders a scalar for a CSV cell. Null becomes an empty field.
+func csvField(data any) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case *big.Float:
+		return formatBigFloat(v), nil
+	}
+	return "", fmt.Errorf("%s values are not supported, use jsonencode() for nested data", typeName(data))
+}
+
+// encodeCSV writes records as CSV with the given column order. When columns
+// is empty, the sorted union of all record keys is used.
+func encodeCSV(records []any, columns []string, options map[string]any) (string, error) {
+	delimiter, err := csvRune("delimiter", options["delimiter"].(string))
+	if err != nil {
+		return "", err
+	}
+
+	rows := make([]map[string]any, len(records))
+	keys := map[string]bool{}
+	for i, record := range records {
+		row, ok := record.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("record %d must be an object, got %s", i, typeName(record))
+		}
+		for key := range row {
+			keys[key] = true
+		}
+		rows[i] = row
+	}
+	if len(columns) == 0 {
+		columns = sortedKeys(keys)
+	}
+
+	var b strings.Builder
+	writer := csv.NewWriter(&b)
+	writer.Comma = delimiter
+	writer.UseCRLF = options["crlf"].(bool)
+
+	if options["header"].(bool) {
+		if err := writer.Write(columns); err != nil {
+			return "", err
+		}
+	}
+	for i, row := range rows {
+		fields := make([]string, len(columns))
+		for j, column := range columns {
+			field, err := csvField(row[column])
+			if err != nil {
+				return "", fmt.Errorf("record %d, column %q: %w", i, column, err)
+			}
+			fields[j] = field
+		}
+		if err := writer.Write(fields); err != nil {
+			return "", err
+		}
+	}
+	writer.Flush()
+	return b.String(), writer.Error()
+}
+
+// CSV Encode Function
+var _ function.Function = &CSVEncodeFunction{}
+
+type CSVEncodeFunction struct{}
+
+func NewCSVEncodeFunction() function.Function {
+	return &CSVEncodeFunction{}
+}
+
+func (f *CSVEncodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "csv_encode"
+}
+
+func (f *CSVEncodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encodes a list of objects as CSV",
+		Description: "Serializes a list of objects as CSV with the given column order, quoting fields as needed. Missing " +
+			"attributes and nulls become empty fields; options control the delimiter, header row and line endings.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "records",
+				Description: "A list of objects or maps",
+			},
+			function.ListParameter{
+				Name:        "columns",
+				Description: "The column names in output order; an empty list uses all keys, sorted",
+				ElementType: types.StringType,
+			},
+			function.DynamicParameter{
+				Name:           "options",
+				Description:    "An object with optional delimiter, header and crlf attributes, or null for defaults",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *CSVEncodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var recordsValue, optionsValue types.Dynamic
+	var columns []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &recordsValue, &columns, &optionsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, recordsValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	records, ok := data.([]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("records must be a list, got %s", typeName(data))))
+		return
+	}
+
+	optionsData, err := fromValue(ctx, optionsValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+	options, err := parseOptions(optionsData, csvEncodeDefaults)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+
+	output, err := encodeCSV(records, columns, options)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, output))
+}
+
+// xmlName matches element and attribute names, including a namespace prefix.
+var xmlName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.:-]*$`)
+
+// xmlElement collects an element's content while decoding.
+type xmlElement struct {
+	name     string
+	value    map[string]any
+	text     strings.Builder
+	children bool
+}
+
+// qualifiedXMLName renders a name with its prefix as written in the source.
+func qualifiedXMLName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// finish returns the element's decoded value: its text when it has neither
+// attributes nor children, otherwise an object with "@" attributes, child
+// elements and any non-blank text under "#text".
+func (e *xmlElement) finish() any {
+	if len(e.value) == 0 && !e.children {
+		return e.text.String()
+	}
+	if text := strings.TrimSpace(e.text.String()); text != "" {
+		e.value["#text"] = text
+	}
+	return e.value
+}
+
+// decodeXML converts an XML document into an object keyed by the root
+// element name. Repeated child elements become lists.
+func decodeXML(input string) (any, error) {
+	decoder := xml.NewDecoder(strings.NewReader(input))
+	var stack []*xmlElement
+	var root map[string]any
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, fmt.Errorf("document has more than one root element")
+			}
+			element := &xmlElement{name: qualifiedXMLName(t.Name), value: map[string]any{}}
+			for _, attr := range t.Attr {
+				key := "@" + qualifiedXMLName(attr.Name)
+				if _, exists := element.value[key]; exists {
+					return nil, fmt.Errorf("element <%s> repeats attribute %s", element.name, key[1:])
+				}
+				element.value[key] = attr.Value
+			}
+			if len(stack) > 0 {
+				stack[len(stack)-1].children = true
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			if len(stack) == 0 || stack[len(stack)-1].name != qualifiedXMLName(t.Name) {
+				line, _ := decoder.InputPos()
+				return nil, fmt.Errorf("line %d: unexpected closing tag </%s>", line, qualifiedXMLName(t.Name))
+			}
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			value := element.finish()
+
+			if len(stack) == 0 {
+				root = map[string]any{element.name: value}
+				continue
+			}
+			parent := stack[len(stack)-1].value
+			switch existing := parent[element.name].(type) {
+			case nil:
+				parent[element.name] = value
+			case []any:
+				parent[element.name] = append(existing, value)
+			default:
+				parent[element.name] = []any{existing, value}
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			} else if strings.TrimSpace(string(t)) != "" {
+				return nil, fmt.Errorf("text outside the root element")
+			}
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("unclosed element <%s>", stack[len(stack)-1].name)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("document has no root element")
+	}
+	return root, nil
+}
+
+// XML Decode Function
+var _ function.Function = &XMLDecodeFunction{}
+
+type XMLDecodeFunction struct{}
+
+func NewXMLDecodeFunction() function.Function {
+	return &XMLDecodeFunction{}
+}
+
+func (f *XMLDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "xml_decode"
+}
+
+func (f *XMLDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decodes an XML document",
+		Description: "Parses XML into an object keyed by the root element name. Attributes are prefixed with \"@\", text " +
+			"alongside attributes or children is stored under \"#text\", and repeated child elements become lists.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The XML document to decode",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *XMLDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := decodeXML(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid XML: %s", err)))
+		return
+	}
+
+	result, err := toDynamic(data)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// writeXMLElement writes name with the given decoded-form value, the inverse
+// of decodeXML. Lists repeat the element.
+func writeXMLElement(b *strings.Builder, name string, data any) error {
+	if !xmlName.MatchString(name) {
+		return fmt.Errorf("invalid element name %q", name)
+	}
+
+	if list, ok := data.([]any); ok {
+		for _, element := range list {
+			if _, nested := element.([]any); nested {
+				return fmt.Errorf("%s: nested lists cannot be represented in XML", name)
+			}
+			if err := writeXMLElement(b, name, element); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	object, isObject := data.(map[string]any)
+	if !isObject {
+		text, err := csvField(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		object = map[string]any{}
+		if text != "" {
+			object["#text"] = text
+		}
+	}
+
+	b.WriteString("<" + name)
+	var children []string
+	for _, key := range sortedKeys(object) {
+		attribute, isAttribute := strings.CutPrefix(key, "@")
+		if !isAttribute {
+			if key != "#text" {
+				children = append(children, key)
+			}
+			continue
+		}
+		if !xmlName.MatchString(attribute) {
+			return fmt.Errorf("%s: invalid attribute name %q", name, attribute)
+		}
+		value, err := csvField(object[key])
+		if err != nil {
+			return fmt.Errorf("%s: attribute %s: %w", name, attribute, err)
+		}
+		b.WriteString(" " + attribute + `="`)
+		xml.EscapeText(b, []byte(value))
+		b.WriteString(`"`)
+	}
+
+	text, err := csvField(object["#text"])
+	if err != nil {
+		return fmt.Errorf("%s: #text: %w", name, err)
+	}
+	if text == "" && len(children) == 0 {
+		b.WriteString("/>")
+		return nil
+	}
+	b.WriteString(">")
+	xml.EscapeText(b, []byte(text))
+	for _, child := range children {
+		if err := writeXMLElement(b, child, object[child]); err != nil {
+			return err
+		}
+	}
+	b.WriteString("</" + name + ">")
+	return nil
+}
+
+// XML Encode Function
+var _ function.Function = &XMLEncodeFunction{}
+
+type XMLEncodeFunction struct{}
+
+func NewXMLEncodeFunction() function.Function {
+	return &XMLEncodeFunction{}
+}
+
+func (f *XMLEncodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "xml_encode"
+}
+
+func (f *XMLEncodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encodes an object as XML",
+		Description: "Serializes an object with a single root key as compact XML, using the same conventions as xml_decode: " +
+			"\"@\" keys become attributes, \"#text\" becomes text content and lists become repeated elements.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "value",
+				Description: "An object with a single key naming the root element",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *XMLEncodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	object, ok := data.(map[string]any)
+	if !ok || len(object) != 1 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "value must be an object with exactly one key naming the root element"))
+		return
+	}
+
+	var b strings.Builder
+	for name, root := range object {
+		if _, isList := root.([]any); isList {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "the root element cannot be a list"))
+			return
+		}
+		if err := writeXMLElement(&b, name, root); err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+			return
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, b.String()))
+}
+
+// hclIdentifier matches attribute names that need no quoting.
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// hclString quotes a string as an HCL literal, escaping template sequences
+// so the value is not interpolated.
+func hclString(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range value {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			b.WriteRune(r)
+			if strings.HasPrefix(value[i+1:], "{") {
+				b.WriteRune(r)
+			}
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func hclKey(key string) string {
+	if hclIdentifier.MatchString(key) {
+		return key
+	}
+	return hclString(key)
+}
+
+// hclEncoder renders plain Go data as HCL in the layout terraform fmt uses.
+// Bodies written for object constructor values may quote keys that are not
+// identifiers; in a file or block body every name must be an identifier.
+type hclEncoder struct {
+	b      strings.Builder
+	blocks [][]string
+	object bool
+}
+
+// blockLabels reports whether the attribute at path renders as a block and
+// how many label levels it has. Labels in path are recorded as "*".
+func (e *hclEncoder) blockLabels(path []string) (int, bool) {
+	for _, spec := range e.blocks {
+		if len(spec) < len(path) {
+			continue
+		}
+		matches := true
+		for i := range path {
+			if spec[i] != path[i] {
+				matches = false
+				break
+			}
+		}
+		for _, segment := range spec[len(path):] {
+			if segment != "*" {
+				matches = false
+			}
+		}
+		if matches {
+			return len(spec) - len(path), true
+		}
+	}
+	return 0, false
+}
+
+// writeBody writes the attributes and then the blocks of an object.
+func (e *hclEncoder) writeBody(object map[string]any, path []string, indent string) error {
+	var attributes, blocks []string
+	for _, key := range sortedKeys(object) {
+		if !e.object && !hclIdentifier.MatchString(key) {
+			return fmt.Errorf("%q is not a valid HCL identifier; nest it in an object value to use it as a key", key)
+		}
+		if _, isBlock := e.blockLabels(append(path[:len(path):len(path)], key)); isBlock {
+			blocks = append(blocks, key)
+		} else {
+			attributes = append(attributes, key)
+		}
+	}
+
+	// Consecutive single-line attributes are aligned on "="; a multi-line
+	// value ends the group.
+	for start := 0; start < len(attributes); {
+		end, width := start, 0
+		var rendered []string
+		for end < len(attributes) {
+			value, err := e.value(object[attributes[end]], indent)
+			if err != nil {
+				return fmt.Errorf("%s: %w", attributes[end], err)
+			}
+			rendered = append(rendered, value)
+			if w := len(hclKey(attributes[end])); w > width {
+				width = w
+			}
+			end++
+			if strings.Contains(value, "\n") {
+				break
+			}
+		}
+		for i, value := range rendered {
+			key := hclKey(attributes[start+i])
+			fmt.Fprintf(&e.b, "%s%s%s = %s\n", indent, key, strings.Repeat(" ", width-len(key)), value)
+		}
+		start = end
+	}
+
+	for i, key := range blocks {
+		if i > 0 || len(attributes) > 0 {
+			e.b.WriteString("\n")
+		}
+		labels, _ := e.blockLabels(append(path[:len(path):len(path)], key))
+		if err := e.writeBlocks(key, nil, labels, object[key], append(path[:len(path):len(path)], key), indent); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// writeBlocks writes a block, consuming one object level per label. A list
+// writes a repeated block per element.
+func (e *hclEncoder) writeBlocks(blockType string, labels []string, remaining int, data any, path []string, indent string) error {
+	if list, ok := data.([]any); ok && remaining == 0 {
+		for i, element := range list {
+			if i > 0 {
+				e.b.WriteString("\n")
+			}
+			if err := e.writeBlocks(blockType, labels, 0, element, path, indent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	object, ok := data.(map[string]any)
+	if !ok {
+		return fmt.Errorf("blocks must be objects, got %s", typeName(data))
+	}
+
+	if remaining > 0 {
+		for i, label := range sortedKeys(object) {
+			if i > 0 {
+				e.b.WriteString("\n")
+			}
+			if err := e.writeBlocks(blockType, append(labels[:len(labels):len(labels)], label), remaining-1, object[label], append(path[:len(path):len(path)], "*"), indent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	e.b.WriteString(indent + blockType)
+	for _, label := range labels {
+		e.b.WriteString(" " + hclString(label))
+	}
+	if len(object) == 0 {
+		e.b.WriteString(" {}\n")
+		return nil
+	}
+	e.b.WriteString(" {\n")
+	if err := e.writeBody(object, path, indent+"  "); err != nil {
+		return err
+	}
+	e.b.WriteString(indent + "}\n")
+	return nil
+}
+
+// value renders an attribute value; indent is that of the attribute line.
+func (e *hclEncoder) value(data any, indent string) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return hclString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case *big.Float:
+		return formatBigFloat(v), nil
+	case []any:
+		if len(v) == 0 {
+			return "[]", nil
+		}
+		elements := make([]string, len(v))
+		multiline := false
+		for i, element := range v {
+			rendered, err := e.value(element, indent+"  ")
+			if err != nil {
+				return "", err
+			}
+			elements[i] = rendered
+			switch element.(type) {
+			case []any, map[string]any:
+				multiline = true
+			}
+		}
+		if !multiline {
+			return "[" + strings.Join(elements, ", ") + "]", nil
+		}
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, element := range elements {
+			b.WriteString(indent + "  " + element + ",\n")
+		}
+		b.WriteString(indent + "]")
+		return b.String(), nil
+	case map[string]any:
+		if len(v) == 0 {
+			return "{}", nil
+		}
+		nested := &hclEncoder{object: true}
+		if err := nested.writeBody(v, nil, indent+"  "); err != nil {
+			return "", err
+		}
+		return "{\n" + nested.b.String() + indent + "}", nil
+	}
+	return "", fmt.Errorf("unsupported value %T", data)
+}
+
+// HCL Encode Function
+var _ function.Function = &HCLEncodeFunction{}
+
+type HCLEncodeFunction struct{}
+
+func NewHCLEncodeFunction() function.Function {
+	return &HCLEncodeFunction{}
+}
+
+func (f *HCLEncodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "hcl_encode"
+}
+
+func (f *HCLEncodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encodes an object as HCL",
+		Description: "Renders an object as HCL body syntax in terraform fmt layout, suitable for .tfvars files and configuration " +
+			"snippets. Attributes are written as key = value; keys matching one of the optional block paths are written as " +
+			"blocks, where a \"*\" path segment consumes one object level as a block label.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "value",
+				Description: "The object to encode",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:        "blocks",
+			Description: "Dot-separated key paths to render as blocks, e.g. \"provider.*\" or \"provider.*.assume_role\"",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *HCLEncodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var blocks []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &blocks))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	object, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("value must be an object, got %s", typeName(data))))
+		return
+	}
+
+	encoder := &hclEncoder{}
+	for i, block := range blocks {
+		segments := strings.Split(block, ".")
+		if segments[0] == "" || segments[0] == "*" || slices.Contains(segments, "") {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(1+i), fmt.Sprintf("invalid block path %q", block)))
+			return
+		}
+		encoder.blocks = append(encoder.blocks, segments)
+	}
+
+	if err := encoder.writeBody(object, nil, ""); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, encoder.b.String()))
+}
+
+// propertiesEscapes maps the single-character escapes of the properties
+// format to the characters they represent.
+var propertiesEscapes = map[rune]rune{'t': '\t', 'n': '\n', 'r': '\r', 'f': '\f'}
+
+// unescapeProperties resolves backslash escapes in a key or value, combining
+// \u surrogate pairs into a single character.
+func unescapeProperties(text string) (string, error) {
+	runes := []rune(text)
+	var decoded []rune
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' || i+1 == len(runes) {
+			decoded = append(decoded, runes[i])
+			continue
+		}
+		i++
+		if runes[i] != 'u' {
+			if r, ok := propertiesEscapes[runes[i]]; ok {
+				decoded = append(decoded, r)
+			} else {
+				decoded = append(decoded, runes[i])
+			}
+			continue
+		}
+
+		if i+4 >= len(runes) {
+			return "", fmt.Errorf("malformed \\u escape in %q", text)
+		}
+		code, err := strconv.ParseUint(string(runes[i+1:i+5]), 16, 16)
+		if err != nil {
+			return "", fmt.Errorf("malformed \\u escape in %q", text)
+		}
+		i += 4
+		if last := len(decoded) - 1; last >= 0 && utf16.IsSurrogate(decoded[last]) {
+			if r := utf16.DecodeRune(decoded[last], rune(code)); r != utf8.RuneError {
+				decoded[last] = r
+				continue
+			}
+		}
+		decoded = append(decoded, rune(code))
+	}
+	return string(decoded), nil
+}
+
+// logicalPropertiesLines joins natural lines that end in an odd number of
+// backslashes with the next line, dropping the continuation's indentation.
+// Blank and comment lines are skipped.
+func logicalPropertiesLines(input string) []string {
+	var lines []string
+	var current strings.Builder
+	continuing := false
+
+	for _, line := range strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(input), "\n") {
+		line = strings.TrimLeft(line, " \t\f")
+		if !continuing && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		trailing := len(line) - len(strings.TrimRight(line, "\\"))
+		continuing = trailing%2 == 1
+		if continuing {
+			line = line[:len(line)-1]
+		}
+		current.WriteString(line)
+		if !continuing {
+			lines = append(lines, current.String())
+			current.Reset()
+		}
+	}
+	if current.Len() > 0 {
+		lines = append(lines, current.String())
+	}
+	return lines
+}
+
+// decodeProperties parses a Java properties document. The key ends at the
+// first unescaped "=", ":" or whitespace.
+func decodeProperties(input string) (map[string]string, error) {
+	properties := map[string]string{}
+
+	for _, line := range logicalPropertiesLines(input) {
+		end := len(line)
+		for i := 0; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+				continue
+			}
+			if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+				end = i
+				break
+			}
+		}
+
+		rest := strings.TrimLeft(line[end:], " \t\f")
+		if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+			rest = strings.TrimLeft(rest[1:], " \t\f")
+		}
+
+		key, err := unescapeProperties(line[:end])
+		if err != nil {
+			return nil, err
+		}
+		value, err := unescapeProperties(rest)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := properties[key]; exists {
+			return nil, fmt.Errorf("duplicate key %q", key)
+		}
+		properties[key] = value
+	}
+
+	return properties, nil
+}
+
+// escapeProperties escapes a key or value so it reads back unchanged. Spaces
+// are escaped everywhere in keys but only when leading in values, and
+// characters outside printable ASCII are written as \u escapes.
+func escapeProperties(text string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range text {
+		switch {
+		case r == ' ':
+			if isKey || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case strings.ContainsRune(`\=:#!`, r):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, `\u%04X`, unit)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Properties Decode Function
+var _ function.Function = &PropertiesDecodeFunction{}
+
+type PropertiesDecodeFunction struct{}
+
+func NewPropertiesDecodeFunction() function.Function {
+	return &PropertiesDecodeFunction{}
+}
+
+func (f *PropertiesDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "properties_decode"
+}
+
+func (f *PropertiesDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decodes a Java properties document",
+		Description: "Parses a Java properties document, such as a Kafka or JVM application configuration, into a map of strings. " +
+			"Supports \"=\", \":\" and whitespace separators, continuation lines, and backslash and \\uXXXX escapes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The properties document to decode",
+			},
+		},
+		Return: function.MapReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *PropertiesDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	properties, err := decodeProperties(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid properties: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, properties))
+}
+
+// Properties Encode Function
+var _ function.Function = &PropertiesEncodeFunction{}
+
+type PropertiesEncodeFunction struct{}
+
+func NewPropertiesEncodeFunction() function.Function {
+	return &PropertiesEncodeFunction{}
+}
+
+func (f *PropertiesEncodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "properties_encode"
+}
+
+func (f *PropertiesEncodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encodes a map as a Java properties document",
+		Description: "Renders a map of strings as a Java properties document with one key=value line per entry, sorted by key. " +
+			"Special characters are backslash-escaped and non-ASCII characters are written as \\uXXXX escapes.",
+		Parameters: []function.Parameter{
+			function.MapParameter{
+				Name:        "properties",
+				Description: "The properties to encode",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PropertiesEncodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var properties map[string]string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &properties))
+	if resp.Error != nil {
+		return
+	}
+
+	var b strings.Builder
+	for _, key := range sortedKeys(properties) {
+		b.WriteString(escapeProperties(key, true) + "=" + escapeProperties(properties[key], false) + "\n")
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, b.String()))
+}
@@ -0,0 +1,610 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+func TestQuery(t *testing.T) {
+	document := `{"items":[{"name":"a","size":3,"tags":{"env":"prod"}},{"name":"b","size":1.5,"tags":{"env":"dev"}}],"big":12345678901234567890}`
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{"path", `.items[0].name`, `"a"`},
+		{"collect", `[.items[] | select(.tags.env == "prod") | .name]`, `["a"]`},
+		{"reshape", `.items | map({(.name): .size}) | add`, `{"a":3,"b":1.5}`},
+		{"arithmetic", `[.items[].size] | add`, `4.5`},
+		{"large integers", `.big`, `12345678901234567890`},
+		{"no results", `.items[] | select(.size > 10)`, `null`},
+		{"missing key", `.nope.deeper`, `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewQueryFunction(), dynamicOf(t, mustJSON(t, document)), types.StringValue(tt.expression))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	document := dynamicOf(t, mustJSON(t, `{"items":[1,2]}`))
+
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"syntax", `.items[`},
+		{"unknown function", `nope(1)`},
+		{"multiple results", `.items[]`},
+		{"runtime error", `.items | keys_unsorted | .foo`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFunction(t, NewQueryFunction(), document, types.StringValue(tt.expression)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestJSONCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"sorted keys", `{ "b": 1, "a": { "d": [1, 2], "c": null } }`, `{"a":{"c":null,"d":[1,2]},"b":1}`},
+		{"numbers", `[1.0, 100, 1e21, 1e-7, 0.000001, -0, 1.5e3, 123456789012345680000]`, `[1,100,1e+21,1e-7,0.000001,0,1500,123456789012345680000]`},
+		{"strings", `["é", "\u000f", "a\"b", "\/"]`, `["é","\u000f","a\"b","/"]`},
+		{"utf16 ordering", `{"😀": 1, "ﬁ": 2}`, `{"😀":1,"ﬁ":2}`},
+		{"scalar", ` true `, `true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewJSONCanonicalFunction(), types.StringValue(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.(types.String).ValueString(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := runFunction(t, NewJSONCanonicalFunction(), types.StringValue(`{"a":`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"key order", "zeta: 1\nalpha: 2\nmid: 3\n", `{"zeta":1,"alpha":2,"mid":3}`},
+		{"number types", "int: 10\nfloat: 1.0\nhex: 0x1F\nshort: .5\nbig: 123456789012345678901234567890", `{"int":10,"float":1.0,"hex":31,"short":0.5,"big":123456789012345678901234567890}`},
+		{"quoted stays string", "version: \"1.10\"\nport: '8080'\nflag: \"true\"", `{"version":"1.10","port":"8080","flag":"true"}`},
+		{"scalars", "a: ~\nb: false\nc: 2001-12-14\nd: \"<tag>\"", `{"a":null,"b":false,"c":"2001-12-14","d":"<tag>"}`},
+		{"merge keys", "base: &base\n  x: 1\n  y: 2\nchild:\n  name: c\n  <<: *base\n  y: 3", `{"base":{"x":1,"y":2},"child":{"name":"c","x":1,"y":3}}`},
+		{"sequence", "- 1\n- [a, {b: c}]", `[1,["a",{"b":"c"}]]`},
+		{"empty", "", `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewYAMLToJSONFunction(), types.StringValue(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.(types.String).ValueString(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	for _, input := range []string{"a: [1", "a: 1\na: 2", "a: .inf", "a: 1\n---\nb: 2"} {
+		if _, err := runFunction(t, NewYAMLToJSONFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestJSONToYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		indent   int64
+		expected string
+	}{
+		{"key order", `{"zeta":1,"alpha":{"b":true,"a":null}}`, 2, "zeta: 1\nalpha:\n  b: true\n  a: null\n"},
+		{"number spelling", `{"float":1.0,"exp":1e3,"int":7}`, 2, "float: 1.0\nexp: 1e3\nint: 7\n"},
+		{"ambiguous strings quoted", `{"version":"1.10","flag":"yes","port":"8080","empty":""}`, 2, "version: \"1.10\"\nflag: \"yes\"\nport: \"8080\"\nempty: \"\"\n"},
+		{"indent", `{"a":{"b":[1,2]}}`, 4, "a:\n    b:\n        - 1\n        - 2\n"},
+		{"empty collections", `{"list":[],"map":{}}`, 2, "list: []\nmap: {}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewJSONToYAMLFunction(), types.StringValue(tt.input), types.Int64Value(tt.indent))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.(types.String).ValueString(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+
+			back, err := runFunction(t, NewYAMLToJSONFunction(), result)
+			if err != nil {
+				t.Fatalf("unexpected error converting back: %s", err)
+			}
+			if got := back.(types.String).ValueString(); got != tt.input {
+				t.Errorf("round trip: expected %s, got %s", tt.input, got)
+			}
+		})
+	}
+
+	if _, err := runFunction(t, NewJSONToYAMLFunction(), types.StringValue(`{"a":`), types.Int64Value(2)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if _, err := runFunction(t, NewJSONToYAMLFunction(), types.StringValue(`{}`), types.Int64Value(1)); err == nil {
+		t.Error("expected error for invalid indent")
+	}
+}
+
+func TestTOMLDecode(t *testing.T) {
+	input := `
+title = "traefik"
+debug = false
+ratio = 0.5
+
+[entryPoints.web]
+address = ":80"
+
+[[services]]
+name = "api"
+ports = [8080, 8443]
+started = 2024-05-01T10:00:00Z
+day = 2024-05-01
+`
+	expected := `{"debug":false,"entryPoints":{"web":{"address":":80"}},"ratio":0.5,` +
+		`"services":[{"day":"2024-05-01","name":"api","ports":[8080,8443],"started":"2024-05-01T10:00:00Z"}],"title":"traefik"}`
+
+	result, err := runFunction(t, NewTOMLDecodeFunction(), types.StringValue(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	for _, input := range []string{"a = ", "a = 1\na = 2", "a = nan"} {
+		if _, err := runFunction(t, NewTOMLDecodeFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestTOMLEncode(t *testing.T) {
+	input := `{"title":"influx","skip":null,"http":{"bind":":8086","timeout":1.5},"retention":[{"name":"a","days":7}]}`
+	expected := "title = \"influx\"\n\n[http]\nbind = \":8086\"\ntimeout = 1.5\n\n[[retention]]\ndays = 7\nname = \"a\"\n"
+
+	result, err := runFunction(t, NewTOMLEncodeFunction(), dynamicOf(t, mustJSON(t, input)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got := result.(types.String).ValueString()
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	back, err := runFunction(t, NewTOMLDecodeFunction(), types.StringValue(got))
+	if err != nil {
+		t.Fatalf("unexpected error decoding: %s", err)
+	}
+	if want := `{"http":{"bind":":8086","timeout":1.5},"retention":[{"days":7,"name":"a"}],"title":"influx"}`; jsonOf(t, back) != want {
+		t.Errorf("round trip: expected %s, got %s", want, jsonOf(t, back))
+	}
+
+	for _, input := range []string{`"x"`, `{"a":[null]}`, `{"a":123456789012345678901234567890}`} {
+		if _, err := runFunction(t, NewTOMLEncodeFunction(), dynamicOf(t, mustJSON(t, input))); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestINIDecode(t *testing.T) {
+	input := "region = us-east-1\r\n" + `
+; AWS config
+[default]
+output = json
+credential_process = "/usr/bin/helper --profile x"
+
+[profile dev]
+role_arn : arn:aws:iam::123456789012:role/dev
+s3 =
+  max_concurrent_requests = 20
+  addressing_style = path
+# trailing comment
+[empty]
+`
+	expected := `{"":{"region":"us-east-1"},"default":{"credential_process":"/usr/bin/helper --profile x","output":"json"},` +
+		`"empty":{},"profile dev":{"role_arn":"arn:aws:iam::123456789012:role/dev","s3":"max_concurrent_requests = 20\naddressing_style = path"}}`
+
+	result, err := runFunction(t, NewINIDecodeFunction(), types.StringValue(input), types.StringValue(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	result, err = runFunction(t, NewINIDecodeFunction(), types.StringValue("region = us-east-1\n[default]\noutput = json"), types.StringValue("default"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := `{"default":{"output":"json","region":"us-east-1"}}`; jsonOf(t, result) != want {
+		t.Errorf("expected %s, got %s", want, jsonOf(t, result))
+	}
+
+	result, err = runFunction(t, NewINIDecodeFunction(), types.StringValue("; globals\n[a]\nk = v"), types.StringNull())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := `{"a":{"k":"v"}}`; jsonOf(t, result) != want {
+		t.Errorf("expected %s, got %s", want, jsonOf(t, result))
+	}
+
+	for _, tc := range []struct {
+		input  string
+		global types.String
+	}{
+		{"[broken", types.StringValue("")},
+		{"[]", types.StringValue("")},
+		{"no separator", types.StringValue("")},
+		{"[a]\nk=1\nk=2", types.StringValue("")},
+		{"=value", types.StringValue("")},
+		{"region = us-east-1\n[a]\nk = v", types.StringNull()},
+		{"k = 1\n[default]\nk = 2", types.StringValue("default")},
+	} {
+		if _, err := runFunction(t, NewINIDecodeFunction(), types.StringValue(tc.input), tc.global); err == nil {
+			t.Errorf("expected error for %q", tc.input)
+		}
+	}
+}
+
+func TestCSVDecode(t *testing.T) {
+	export := "\ufeffname;zip;active;count\n# exported 2024-05-01\n\"Smith; J\";01234;true;3\n\"multi\nline\";90210;false;\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		options  string
+		expected string
+	}{
+		{"defaults", "a,b\n1,2\n", `null`, `[{"a":"1","b":"2"}]`},
+		{
+			name:     "semicolon export",
+			input:    export,
+			options:  `{"delimiter": ";", "comment": "#", "infer_types": true}`,
+			expected: `[{"active":true,"count":3,"name":"Smith; J","zip":"01234"},{"active":false,"count":null,"name":"multi\nline","zip":90210}]`,
+		},
+		{"columns override header", "x,y\n1,2\n", `{"columns": ["a", "b"]}`, `[{"a":"1","b":"2"}]`},
+		{"columns without header", "1,2\n3,4\n", `{"header": false, "columns": ["a", "b"]}`, `[{"a":"1","b":"2"},{"a":"3","b":"4"}]`},
+		{"lists without header", "1,2\n3\n", `{"header": false}`, `[["1","2"],["3"]]`},
+		{"empty", "", `{}`, `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewCSVDecodeFunction(), types.StringValue(tt.input), dynamicOf(t, mustJSON(t, tt.options)))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCSVDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options string
+	}{
+		{"ragged", "a,b\n1\n", `{}`},
+		{"unterminated quote", "a\n\"x\n", `{}`},
+		{"duplicate header", "a,a\n1,2\n", `{}`},
+		{"bad delimiter", "a\n", `{"delimiter": ";;"}`},
+		{"unknown option", "a\n", `{"separator": ";"}`},
+		{"same comment and delimiter", "a\n", `{"comment": ","}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFunction(t, NewCSVDecodeFunction(), types.StringValue(tt.input), dynamicOf(t, mustJSON(t, tt.options))); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestCSVEncode(t *testing.T) {
+	records := `[{"id": 1, "name": "Smith; J", "cost": 12.5, "active": true}, {"id": 2, "name": "multi\nline", "note": "say \"hi\"", "cost": null}]`
+
+	tests := []struct {
+		name     string
+		columns  []string
+		options  string
+		expected string
+	}{
+		{"column order", []string{"name", "id", "cost"}, `null`, "name,id,cost\nSmith; J,1,12.5\n\"multi\nline\",2,\n"},
+		{"all columns", []string{}, `{}`, "active,cost,id,name,note\ntrue,12.5,1,Smith; J,\n,,2,\"multi\nline\",\"say \"\"hi\"\"\"\n"},
+		{"delimiter and crlf", []string{"id", "name"}, `{"delimiter": ";", "crlf": true}`, "id;name\r\n1;\"Smith; J\"\r\n2;\"multi\r\nline\"\r\n"},
+		{"no header", []string{"id"}, `{"header": false}`, "1\n2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewCSVEncodeFunction(), dynamicOf(t, mustJSON(t, records)), stringList(tt.columns...), dynamicOf(t, mustJSON(t, tt.options)))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.(types.String).ValueString(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	for _, input := range []string{`{"a": 1}`, `[1]`, `[{"a": [1]}]`} {
+		if _, err := runFunction(t, NewCSVEncodeFunction(), dynamicOf(t, mustJSON(t, input)), stringList(), dynamicOf(t, nil)); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestXMLDecode(t *testing.T) {
+	input := `<?xml version="1.0"?>
+<!-- inventory export -->
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <host id="1" env="prod">web01</host>
+    <host id="2"><![CDATA[db<01>]]></host>
+    <note>  spaced  </note>
+    <empty/>
+  </soap:Body>
+</soap:Envelope>`
+	expected := `{"soap:Envelope":{"@xmlns:soap":"http://schemas.xmlsoap.org/soap/envelope/","soap:Body":{"empty":"",` +
+		`"host":[{"#text":"web01","@env":"prod","@id":"1"},{"#text":"db<01>","@id":"2"}],"note":"  spaced  "}}}`
+
+	result, err := runFunction(t, NewXMLDecodeFunction(), types.StringValue(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	for _, input := range []string{"", "<a>", "<a></b>", "<a/><b/>", "text <a/>", "<a x='1' x='2'/>"} {
+		if _, err := runFunction(t, NewXMLDecodeFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestXMLEncode(t *testing.T) {
+	input := `{"order":{"@id":7,"@note":"a \"b\" & c","item":[{"#text":"apple","@qty":2},"pear <green>"],"paid":true,"gift":null}}`
+	expected := `<order id="7" note="a &#34;b&#34; &amp; c"><gift/><item qty="2">apple</item><item>pear &lt;green&gt;</item><paid>true</paid></order>`
+
+	result, err := runFunction(t, NewXMLEncodeFunction(), dynamicOf(t, mustJSON(t, input)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got := result.(types.String).ValueString()
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	back, err := runFunction(t, NewXMLDecodeFunction(), types.StringValue(got))
+	if err != nil {
+		t.Fatalf("unexpected error decoding: %s", err)
+	}
+	want := `{"order":{"@id":"7","@note":"a \"b\" & c","gift":"","item":[{"#text":"apple","@qty":"2"},"pear <green>"],"paid":"true"}}`
+	if jsonOf(t, back) != want {
+		t.Errorf("round trip: expected %s, got %s", want, jsonOf(t, back))
+	}
+
+	for _, input := range []string{`{"a": 1, "b": 2}`, `{"a": [1, 2]}`, `{"bad name": 1}`, `{"a": {"b": [[1]]}}`, `{"a": {"@x": {"y": 1}}}`} {
+		if _, err := runFunction(t, NewXMLEncodeFunction(), dynamicOf(t, mustJSON(t, input))); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+// hclBlocks builds the variadic block paths argument of hcl_encode.
+func hclBlocks(blocks []string) types.Tuple {
+	values := make([]attr.Value, len(blocks))
+	for i, block := range blocks {
+		values[i] = types.StringValue(block)
+	}
+	return variadicOf(values...)
+}
+
+func TestHCLEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		blocks   []string
+		expected string
+	}{
+		{
+			"tfvars",
+			`{"region": "us-east-1", "instance_count": 3, "tags": {"Name": "web", "cost-center": "42", "team name": "ops"}, "zones": ["a", "b"], "enabled": true, "template": "${var} %{if}", "empty": []}`,
+			[]string{},
+			"empty          = []\nenabled        = true\ninstance_count = 3\nregion         = \"us-east-1\"\ntags           = {\n  Name        = \"web\"\n  cost-center = \"42\"\n  \"team name\" = \"ops\"\n}\ntemplate = \"$${var} %%{if}\"\nzones    = [\"a\", \"b\"]\n",
+		},
+		{
+			"list of objects",
+			`{"rules": [{"port": 443}, {"port": 80, "cidrs": ["0.0.0.0/0"]}]}`,
+			[]string{},
+			"rules = [\n  {\n    port = 443\n  },\n  {\n    cidrs = [\"0.0.0.0/0\"]\n    port  = 80\n  },\n]\n",
+		},
+		{
+			"labelled and repeated blocks",
+			`{"provider": {"aws": {"region": "eu-west-1", "assume_role": {"role_arn": "arn:aws:iam::1:role/x"}, "default_tags": [{"tags": {"a": "b"}}]}}}`,
+			[]string{"provider.*", "provider.*.assume_role", "provider.*.default_tags"},
+			"provider \"aws\" {\n  region = \"eu-west-1\"\n\n  assume_role {\n    role_arn = \"arn:aws:iam::1:role/x\"\n  }\n\n  default_tags {\n    tags = {\n      a = \"b\"\n    }\n  }\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewHCLEncodeFunction(), dynamicOf(t, mustJSON(t, tt.input)), hclBlocks(tt.blocks))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := result.(types.String).ValueString()
+			if got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+
+			file, diags := hclsyntax.ParseConfig([]byte(got), "generated.tfvars", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("output does not parse as HCL: %s", diags)
+			}
+			if len(tt.blocks) > 0 {
+				return
+			}
+			attributes, diags := file.Body.JustAttributes()
+			if diags.HasErrors() {
+				t.Fatalf("output is not a tfvars body: %s", diags)
+			}
+			decoded := map[string]cty.Value{}
+			for name, attribute := range attributes {
+				value, diags := attribute.Expr.Value(nil)
+				if diags.HasErrors() {
+					t.Fatalf("%s: %s", name, diags)
+				}
+				decoded[name] = value
+			}
+			roundTripped, marshalErr := ctyjson.Marshal(cty.ObjectVal(decoded), cty.ObjectVal(decoded).Type())
+			if marshalErr != nil {
+				t.Fatal(marshalErr)
+			}
+			var want, have any
+			_ = json.Unmarshal([]byte(tt.input), &want)
+			_ = json.Unmarshal(roundTripped, &have)
+			if !reflect.DeepEqual(want, have) {
+				t.Errorf("round trip changed the value:\nexpected %s\ngot      %s", tt.input, roundTripped)
+			}
+		})
+	}
+
+	errorCases := []struct {
+		input  string
+		blocks []string
+	}{
+		{`[1]`, []string{}},
+		{`{"provider": "aws"}`, []string{"provider"}},
+		{`{"a": {}}`, []string{"a..b"}},
+		{`{"a b": 1}`, nil},
+		{`{"block": {"bad key": 1}}`, []string{"block"}},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewHCLEncodeFunction(), dynamicOf(t, mustJSON(t, tt.input)), hclBlocks(tt.blocks)); err == nil {
+			t.Errorf("expected error for %s with blocks %v", tt.input, tt.blocks)
+		}
+	}
+}
+
+func TestPropertiesDecode(t *testing.T) {
+	input := "# Kafka broker\r\n" + `! legacy comment
+broker.id=1
+log.dirs : /var/lib/kafka
+listeners PLAINTEXT://:9092
+  zookeeper.connect = zk1:2181,\
+                      zk2:2181
+message = caf\u00e9 \u2603 \uD83D\uDE00
+path\ with\ spaces=C:\\data\tend
+escaped\=key=value\\
+empty=
+`
+	expected := `{"broker.id":"1","empty":"","escaped=key":"value\\","listeners":"PLAINTEXT://:9092",` +
+		`"log.dirs":"/var/lib/kafka","message":"café ☃ 😀","path with spaces":"C:\\data\tend","zookeeper.connect":"zk1:2181,zk2:2181"}`
+
+	result, err := runFunction(t, NewPropertiesDecodeFunction(), types.StringValue(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	for _, input := range []string{"a=1\na=2", `bad=\u12`, `bad=\uZZZZ`} {
+		if _, err := runFunction(t, NewPropertiesDecodeFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestPropertiesEncode(t *testing.T) {
+	properties := map[string]string{
+		"broker.id":   "1",
+		"key with =":  " leading space",
+		"message":     "café 😀",
+		"multi":       "a\nb\tc",
+		"url":         "http://host:8080/#x",
+		"windows.dir": `C:\data`,
+	}
+	expected := "broker.id=1\n" +
+		`key\ with\ \==\ leading space` + "\n" +
+		`message=caf\u00E9 \uD83D\uDE00` + "\n" +
+		`multi=a\nb\tc` + "\n" +
+		`url=http\://host\:8080/\#x` + "\n" +
+		`windows.dir=C\:\\data` + "\n"
+
+	value, diags := types.MapValueFrom(context.Background(), types.StringType, properties)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	result, err := runFunction(t, NewPropertiesEncodeFunction(), value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	encoded := result.(types.String).ValueString()
+	if encoded != expected {
+		t.Errorf("expected %q, got %q", expected, encoded)
+	}
+
+	decoded, decodeErr := decodeProperties(encoded)
+	if decodeErr != nil {
+		t.Fatalf("unexpected error decoding: %s", decodeErr)
+	}
+	for key, value := range properties {
+		if decoded[key] != value {
+			t.Errorf("round trip of %q: expected %q, got %q", key, value, decoded[key])
+		}
+	}
+}
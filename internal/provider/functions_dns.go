@@ -0,0 +1,943 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/text/unicode/norm"
+)
+
+// hostnameTypes lists the name types accepted by validate_hostname.
+var hostnameTypes = []string{"fqdn", "hostname", "label", "wildcard"}
+
+// checkDNSLabel reports why label is not a valid RFC 1123 hostname label:
+// 1 to 63 letters, digits and hyphens, not starting or ending with a hyphen.
+func checkDNSLabel(label string) error {
+	if label == "" {
+		return fmt.Errorf("empty label")
+	}
+	if len(label) > 63 {
+		return fmt.Errorf("label %q is %d characters long, the maximum is 63", label, len(label))
+	}
+	for i, c := range label {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return fmt.Errorf("label %q contains invalid character %q at position %d", label, c, i+1)
+		}
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return fmt.Errorf("label %q must not start or end with a hyphen", label)
+	}
+	return nil
+}
+
+// validateHostname checks name against the rules for kind and returns it
+// lowercased without a trailing dot. A label is a single DNS label, a
+// hostname is one or more labels, an fqdn needs at least two labels and a
+// top-level label that is not all digits, and a wildcard is an fqdn that may
+// start with a "*." label.
+func validateHostname(name, kind string) (string, error) {
+	name = strings.ToLower(name)
+	if kind == "label" {
+		return name, checkDNSLabel(name)
+	}
+
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return "", fmt.Errorf("name is empty")
+	}
+	if len(name) > 253 {
+		return "", fmt.Errorf("name is %d characters long, the maximum is 253", len(name))
+	}
+
+	labels := strings.Split(name, ".")
+	if kind == "wildcard" && labels[0] == "*" {
+		labels = labels[1:]
+		if len(labels) < 2 {
+			return "", fmt.Errorf("a wildcard needs at least two labels after the *")
+		}
+	}
+	for _, label := range labels {
+		if err := checkDNSLabel(label); err != nil {
+			return "", err
+		}
+	}
+
+	if kind == "fqdn" || kind == "wildcard" {
+		if len(labels) < 2 {
+			return "", fmt.Errorf("a fully qualified domain name needs at least two labels")
+		}
+		if tld := labels[len(labels)-1]; strings.Trim(tld, "0123456789") == "" {
+			return "", fmt.Errorf("top-level label %q must not be all numeric", tld)
+		}
+	}
+	return name, nil
+}
+
+// Validate Hostname Function
+var _ function.Function = &ValidateHostnameFunction{}
+
+type ValidateHostnameFunction struct{}
+
+func NewValidateHostnameFunction() function.Function {
+	return &ValidateHostnameFunction{}
+}
+
+func (f *ValidateHostnameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_hostname"
+}
+
+func (f *ValidateHostnameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates a hostname or domain name",
+		Description: "Checks a name against the RFC 952/1123 hostname rules, the 63 character label limit and the 253 " +
+			"character name limit, and returns it lowercased without a trailing dot. The error explains which rule failed.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "name",
+				Description: "The name to validate",
+			},
+			function.StringParameter{
+				Name: "type",
+				Description: "The kind of name: label (a single label), hostname (one or more labels), fqdn (at least two " +
+					"labels) or wildcard (an fqdn that may start with *.)",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ValidateHostnameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name, kind string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &name, &kind))
+	if resp.Error != nil {
+		return
+	}
+
+	if !slices.Contains(hostnameTypes, kind) {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("unsupported type %q, expected one of: %s", kind, strings.Join(hostnameTypes, ", "))))
+		return
+	}
+	normalized, err := validateHostname(name, kind)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("invalid %s %q: %s", kind, name, err)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, normalized))
+}
+
+// convertIDN converts a domain name between its Unicode and ASCII (xn--)
+// forms using the IDNA lookup profile, which also lowercases and validates
+// it. A leading "*." wildcard label is kept as is.
+func convertIDN(domain string, toASCII bool) (string, error) {
+	if domain == "" {
+		return "", fmt.Errorf("domain is empty")
+	}
+	wildcard := ""
+	if strings.HasPrefix(domain, "*.") {
+		wildcard, domain = "*.", domain[2:]
+	}
+
+	convert := idna.Lookup.ToUnicode
+	if toASCII {
+		convert = idna.Lookup.ToASCII
+	}
+	converted, err := convert(domain)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain %q: %s", wildcard+domain, strings.TrimPrefix(err.Error(), "idna: "))
+	}
+	return wildcard + converted, nil
+}
+
+// IDN To ASCII Function
+var _ function.Function = &IDNToASCIIFunction{}
+
+type IDNToASCIIFunction struct{}
+
+func NewIDNToASCIIFunction() function.Function {
+	return &IDNToASCIIFunction{}
+}
+
+func (f *IDNToASCIIFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "idn_to_ascii"
+}
+
+func (f *IDNToASCIIFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a domain name to its ASCII (punycode) form",
+		Description: "Converts an internationalized domain name to the lowercase xn-- form used in DNS, following IDNA 2008 " +
+			"with UTS #46 mapping. ASCII names are only lowercased; a leading *. wildcard label is kept.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "domain",
+				Description: "The domain name, such as bücher.example",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *IDNToASCIIFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runConvertIDN(ctx, req, resp, true)
+}
+
+// IDN To Unicode Function
+var _ function.Function = &IDNToUnicodeFunction{}
+
+type IDNToUnicodeFunction struct{}
+
+func NewIDNToUnicodeFunction() function.Function {
+	return &IDNToUnicodeFunction{}
+}
+
+func (f *IDNToUnicodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "idn_to_unicode"
+}
+
+func (f *IDNToUnicodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a domain name to its Unicode form",
+		Description: "Decodes the xn-- labels of a domain name back to Unicode for display, validating them with the same " +
+			"rules as idn_to_ascii. A leading *. wildcard label is kept.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "domain",
+				Description: "The domain name, such as xn--bcher-kva.example",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *IDNToUnicodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runConvertIDN(ctx, req, resp, false)
+}
+
+// runConvertIDN implements idn_to_ascii and idn_to_unicode.
+func runConvertIDN(ctx context.Context, req function.RunRequest, resp *function.RunResponse, toASCII bool) {
+	var domain string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &domain))
+	if resp.Error != nil {
+		return
+	}
+
+	converted, err := convertIDN(domain, toASCII)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, converted))
+}
+
+// domainParts is a hostname split at its registrable domain.
+type domainParts struct {
+	Hostname  string `tfsdk:"hostname"`
+	Subdomain string `tfsdk:"subdomain"`
+	Domain    string `tfsdk:"domain"`
+	Suffix    string `tfsdk:"suffix"`
+	ICANN     bool   `tfsdk:"icann"`
+}
+
+var domainPartsType = map[string]attr.Type{
+	"hostname":  types.StringType,
+	"subdomain": types.StringType,
+	"domain":    types.StringType,
+	"suffix":    types.StringType,
+	"icann":     types.BoolType,
+}
+
+// parseDomain splits a hostname into its subdomain, registrable domain
+// (eTLD+1) and public suffix using the public suffix list embedded in
+// golang.org/x/net/publicsuffix. Unicode names are converted to ASCII first.
+func parseDomain(hostname string) (domainParts, error) {
+	name, err := convertIDN(hostname, true)
+	if err != nil {
+		return domainParts{}, err
+	}
+	name = strings.TrimSuffix(name, ".")
+
+	suffix, icann := publicsuffix.PublicSuffix(name)
+	domain, err := publicsuffix.EffectiveTLDPlusOne(name)
+	if err != nil || strings.HasPrefix(domain, "*.") {
+		return domainParts{}, fmt.Errorf("%q is a public suffix and has no registrable domain", hostname)
+	}
+	return domainParts{
+		Hostname:  name,
+		Subdomain: strings.TrimSuffix(strings.TrimSuffix(name, domain), "."),
+		Domain:    domain,
+		Suffix:    suffix,
+		ICANN:     icann,
+	}, nil
+}
+
+// Domain Parse Function
+var _ function.Function = &DomainParseFunction{}
+
+type DomainParseFunction struct{}
+
+func NewDomainParseFunction() function.Function {
+	return &DomainParseFunction{}
+}
+
+func (f *DomainParseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "domain_parse"
+}
+
+func (f *DomainParseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Splits a hostname into subdomain, registrable domain and public suffix",
+		Description: "Uses the embedded public suffix list to find the public suffix of a hostname, such as co.uk or " +
+			"github.io, and the registrable domain (eTLD+1) below it. Names are returned in lowercase ASCII form.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "hostname",
+				Description: "The hostname, such as www.example.co.uk",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: domainPartsType,
+		},
+	}
+}
+
+func (f *DomainParseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var hostname string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &hostname))
+	if resp.Error != nil {
+		return
+	}
+
+	parts, err := parseDomain(hostname)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, parts))
+}
+
+// emailDotAtom matches local parts that need no quoting, allowing UTF-8 as
+// in RFC 6531.
+var emailDotAtom = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+/=?^_`{|}~\\x{80}-\\x{10FFFF}-]+(\\.[A-Za-z0-9!#$%&'*+/=?^_`{|}~\\x{80}-\\x{10FFFF}-]+)*$")
+
+// emailParts is the result of email_parse.
+type emailParts struct {
+	Valid       bool   `tfsdk:"valid"`
+	Reason      string `tfsdk:"reason"`
+	Address     string `tfsdk:"address"`
+	DisplayName string `tfsdk:"display_name"`
+	LocalPart   string `tfsdk:"local_part"`
+	Domain      string `tfsdk:"domain"`
+}
+
+var emailPartsType = map[string]attr.Type{
+	"valid":        types.BoolType,
+	"reason":       types.StringType,
+	"address":      types.StringType,
+	"display_name": types.StringType,
+	"local_part":   types.StringType,
+	"domain":       types.StringType,
+}
+
+// parseEmail parses a single RFC 5322 address, optionally with a display
+// name, and applies the RFC 5321 limits on top: a local part of at most 64
+// octets, a path of at most 254 octets and a domain that is either a fully
+// qualified domain name or an address literal. The domain is returned in
+// lowercase ASCII form.
+func parseEmail(text string) (emailParts, error) {
+	parsed, err := mail.ParseAddress(text)
+	if err != nil {
+		return emailParts{}, fmt.Errorf("not an RFC 5322 address: %s", strings.TrimPrefix(err.Error(), "mail: "))
+	}
+	at := strings.LastIndex(parsed.Address, "@")
+	local, domain := parsed.Address[:at], parsed.Address[at+1:]
+
+	if len(local) > 64 {
+		return emailParts{}, fmt.Errorf("local part is %d octets long, the maximum is 64", len(local))
+	}
+	if strings.HasPrefix(domain, "[") {
+		literal := strings.TrimSuffix(strings.TrimPrefix(domain, "["), "]")
+		ip, err := netip.ParseAddr(strings.TrimPrefix(literal, "IPv6:"))
+		if err != nil || ip.Zone() != "" || ip.Is6() != strings.HasPrefix(literal, "IPv6:") {
+			return emailParts{}, fmt.Errorf("invalid address literal %s", domain)
+		}
+	} else {
+		ascii, err := convertIDN(domain, true)
+		if err != nil {
+			return emailParts{}, err
+		}
+		if domain, err = validateHostname(ascii, "fqdn"); err != nil {
+			return emailParts{}, fmt.Errorf("invalid domain %q: %s", ascii, err)
+		}
+	}
+
+	address := local
+	if !emailDotAtom.MatchString(local) {
+		address = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(local) + `"`
+	}
+	address += "@" + domain
+	if len(address) > 254 {
+		return emailParts{}, fmt.Errorf("address is %d octets long, the maximum is 254", len(address))
+	}
+
+	return emailParts{
+		Valid:       true,
+		Address:     address,
+		DisplayName: parsed.Name,
+		LocalPart:   local,
+		Domain:      domain,
+	}, nil
+}
+
+// Email Parse Function
+var _ function.Function = &EmailParseFunction{}
+
+type EmailParseFunction struct{}
+
+func NewEmailParseFunction() function.Function {
+	return &EmailParseFunction{}
+}
+
+func (f *EmailParseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "email_parse"
+}
+
+func (f *EmailParseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses and validates an email address",
+		Description: "Parses an RFC 5322 address such as \"Ops Team <ops@example.com>\" and checks the RFC 5321 length and " +
+			"domain rules. Returns an object with valid, reason, the normalized address, display_name, local_part and " +
+			"domain. Invalid addresses are not an error: valid is false, reason explains why and the other fields are empty.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "address",
+				Description: "The email address, optionally with a display name",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: emailPartsType,
+		},
+	}
+}
+
+func (f *EmailParseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	parts, err := parseEmail(text)
+	if err != nil {
+		parts = emailParts{Reason: err.Error()}
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, parts))
+}
+
+// dnsLabelHashLength is the number of hex digits of the hash appended to
+// labels that dns_label truncates.
+const dnsLabelHashLength = 8
+
+// sanitizeDNSLabel turns arbitrary text into an RFC 1123 label: accents are
+// stripped, letters lowercased, every run of other characters becomes a
+// single hyphen and leading and trailing hyphens are removed. Labels longer
+// than maxLength are cut and end in a hash of the full label, so different
+// long inputs stay distinct.
+func sanitizeDNSLabel(input string, maxLength int) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFKD.String(input) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+		case r >= 'A' && r <= 'Z':
+			r = unicode.ToLower(r)
+		default:
+			hyphen = b.Len() > 0
+			continue
+		}
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteRune(r)
+	}
+
+	label := b.String()
+	if len(label) <= maxLength {
+		return label
+	}
+	sum := sha256.Sum256([]byte(label))
+	prefix := strings.TrimRight(label[:maxLength-dnsLabelHashLength-1], "-")
+	return prefix + "-" + hex.EncodeToString(sum[:])[:dnsLabelHashLength]
+}
+
+// DNS Label Function
+var _ function.Function = &DNSLabelFunction{}
+
+type DNSLabelFunction struct{}
+
+func NewDNSLabelFunction() function.Function {
+	return &DNSLabelFunction{}
+}
+
+func (f *DNSLabelFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dns_label"
+}
+
+func (f *DNSLabelFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts text into a valid DNS label",
+		Description: "Lowercases the input, strips accents, replaces runs of other characters with a hyphen and trims " +
+			"hyphens from both ends. Results longer than max_length are truncated and end in a hyphen and an 8 character " +
+			"hash of the full label, so the output is stable and distinct inputs stay distinct.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The text to convert, such as a human readable name",
+			},
+			function.Int64Parameter{
+				Name:        "max_length",
+				Description: "The maximum length of the label, between 10 and 63",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *DNSLabelFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	var maxLength int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &maxLength))
+	if resp.Error != nil {
+		return
+	}
+
+	if maxLength < dnsLabelHashLength+2 || maxLength > 63 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("max_length must be between %d and 63, got %d", dnsLabelHashLength+2, maxLength)))
+		return
+	}
+	label := sanitizeDNSLabel(input, int(maxLength))
+	if label == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("%q has no characters that can be used in a DNS label", input)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, label))
+}
+
+// maxSPFLookups is the RFC 7208 limit on DNS lookups during SPF evaluation.
+const maxSPFLookups = 10
+
+// maxTXTChunk is the maximum length of a single character string in a TXT
+// record.
+const maxTXTChunk = 255
+
+// chunkTXT splits a TXT record value into character strings of at most 255
+// octets, which resolvers concatenate without separators.
+func chunkTXT(record string) []string {
+	chunks := []string{}
+	for len(record) > maxTXTChunk {
+		chunks = append(chunks, record[:maxTXTChunk])
+		record = record[maxTXTChunk:]
+	}
+	return append(chunks, record)
+}
+
+// txtRecord is a TXT record value along with its character strings.
+type txtRecord struct {
+	Record string   `tfsdk:"record"`
+	Chunks []string `tfsdk:"chunks"`
+}
+
+var txtRecordType = map[string]attr.Type{
+	"record": types.StringType,
+	"chunks": types.ListType{ElemType: types.StringType},
+}
+
+// spfRecord is the result of spf_record.
+type spfRecord struct {
+	Record  string   `tfsdk:"record"`
+	Chunks  []string `tfsdk:"chunks"`
+	Lookups int64    `tfsdk:"lookups"`
+}
+
+var spfRecordType = map[string]attr.Type{
+	"record":  types.StringType,
+	"chunks":  types.ListType{ElemType: types.StringType},
+	"lookups": types.Int64Type,
+}
+
+// checkDomainSpec validates an SPF domain-spec. Names may contain
+// underscores, as in _spf.example.com, and specs with macros are only
+// checked for length.
+func checkDomainSpec(spec string) error {
+	if spec == "" {
+		return fmt.Errorf("missing domain")
+	}
+	if len(spec) > 253 {
+		return fmt.Errorf("domain %q is longer than 253 characters", spec)
+	}
+	if strings.Contains(spec, "%") {
+		return nil
+	}
+	labels := strings.Split(strings.TrimSuffix(spec, "."), ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("domain %q needs at least two labels", spec)
+	}
+	for _, label := range labels {
+		if err := checkDNSLabel(strings.ReplaceAll(label, "_", "a")); err != nil {
+			return fmt.Errorf("domain %q: %s", spec, err)
+		}
+	}
+	return nil
+}
+
+// checkSPFCIDRs validates the optional "/ip4-length//ip6-length" suffix of
+// an a or mx mechanism and returns the domain before it.
+func checkSPFCIDRs(spec string) (string, error) {
+	spec, ip6, hasIP6 := strings.Cut(spec, "//")
+	spec, ip4, hasIP4 := strings.Cut(spec, "/")
+	for _, length := range []struct {
+		text    string
+		set     bool
+		max     int
+		version string
+	}{{ip4, hasIP4, 32, "ip4"}, {ip6, hasIP6, 128, "ip6"}} {
+		if !length.set {
+			continue
+		}
+		n, err := strconv.Atoi(length.text)
+		if err != nil || n < 0 || n > length.max {
+			return "", fmt.Errorf("invalid %s prefix length %q", length.version, length.text)
+		}
+	}
+	return spec, nil
+}
+
+// buildSPF validates SPF terms and assembles the record, counting the
+// mechanisms and modifiers that cause DNS lookups.
+func buildSPF(terms []string) (spfRecord, error) {
+	var kept []string
+	seen := map[string]bool{}
+	lookups := 0
+	hasAll, hasRedirect := false, false
+
+	for i, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" || seen[term] || (i == 0 && strings.EqualFold(term, "v=spf1")) {
+			continue
+		}
+		seen[term] = true
+
+		if name, value, ok := strings.Cut(term, "="); ok && !strings.ContainsAny(name, ":/") {
+			switch strings.ToLower(name) {
+			case "redirect":
+				lookups++
+				hasRedirect = true
+			case "exp":
+			default:
+				return spfRecord{}, fmt.Errorf("unknown modifier %q", term)
+			}
+			if err := checkDomainSpec(value); err != nil {
+				return spfRecord{}, fmt.Errorf("%s: %s", term, err)
+			}
+			kept = append(kept, term)
+			continue
+		}
+
+		if hasAll {
+			return spfRecord{}, fmt.Errorf("mechanism %q comes after all and would never be evaluated", term)
+		}
+		mechanism := strings.TrimLeft(term, "+-~?")
+		if len(term)-len(mechanism) > 1 {
+			return spfRecord{}, fmt.Errorf("mechanism %q has more than one qualifier", term)
+		}
+		name, rest := mechanism, ""
+		if i := strings.IndexAny(mechanism, ":/"); i >= 0 {
+			name, rest = mechanism[:i], mechanism[i:]
+		}
+		value, hasValue := strings.CutPrefix(rest, ":")
+
+		var err error
+		switch strings.ToLower(name) {
+		case "all":
+			if rest != "" {
+				err = fmt.Errorf("all takes no arguments")
+			}
+			hasAll = true
+		case "include", "exists":
+			lookups++
+			err = checkDomainSpec(value)
+		case "ptr":
+			lookups++
+			if rest != "" {
+				err = checkDomainSpec(value)
+			}
+		case "ip4", "ip6":
+			var prefix netip.Prefix
+			if prefix, err = parseAddrOrCIDR(value); err == nil && prefix.Addr().Is4() != strings.EqualFold(name, "ip4") {
+				err = fmt.Errorf("%s is not an %s address", value, strings.ToLower(name))
+			}
+		case "a", "mx":
+			// The domain is optional and may be followed by prefix lengths,
+			// as in a:example.com/24 or mx//64.
+			lookups++
+			var domain string
+			if domain, err = checkSPFCIDRs(value); err == nil {
+				if hasValue {
+					err = checkDomainSpec(domain)
+				} else if domain != "" {
+					err = fmt.Errorf("expected a domain after %q", name+":")
+				}
+			}
+		default:
+			return spfRecord{}, fmt.Errorf("unknown mechanism %q", term)
+		}
+		if err != nil {
+			return spfRecord{}, fmt.Errorf("%s: %s", term, err)
+		}
+		kept = append(kept, term)
+	}
+
+	if hasAll && hasRedirect {
+		return spfRecord{}, fmt.Errorf("redirect is ignored when the record has an all mechanism")
+	}
+	if lookups > maxSPFLookups {
+		return spfRecord{}, fmt.Errorf("record needs %d DNS lookups, the limit is %d", lookups, maxSPFLookups)
+	}
+
+	record := strings.Join(append([]string{"v=spf1"}, kept...), " ")
+	return spfRecord{Record: record, Chunks: chunkTXT(record), Lookups: int64(lookups)}, nil
+}
+
+// SPF Record Function
+var _ function.Function = &SPFRecordFunction{}
+
+type SPFRecordFunction struct{}
+
+func NewSPFRecordFunction() function.Function {
+	return &SPFRecordFunction{}
+}
+
+func (f *SPFRecordFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "spf_record"
+}
+
+func (f *SPFRecordFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds an SPF TXT record value",
+		Description: "Validates SPF mechanisms and modifiers such as include:_spf.example.com, ip4:192.0.2.0/24 and ~all, " +
+			"enforces the limit of 10 DNS lookups and returns an object with the record, the record split into 255 " +
+			"character strings, and the number of lookups it needs.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "mechanisms",
+				Description: "The mechanisms and modifiers in evaluation order, without the v=spf1 prefix",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: spfRecordType,
+		},
+	}
+}
+
+func (f *SPFRecordFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var mechanisms []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &mechanisms))
+	if resp.Error != nil {
+		return
+	}
+
+	record, err := buildSPF(mechanisms)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, record))
+}
+
+// dmarcDefaults are the tags accepted by dmarc_record. Empty strings, empty
+// lists and -1 leave a tag out of the record.
+var dmarcDefaults = map[string]any{
+	"p":     "",
+	"sp":    "",
+	"pct":   big.NewFloat(-1),
+	"rua":   []any{},
+	"ruf":   []any{},
+	"adkim": "",
+	"aspf":  "",
+	"fo":    "",
+	"rf":    "",
+	"ri":    big.NewFloat(-1),
+}
+
+var (
+	dmarcPolicies   = []string{"none", "quarantine", "reject"}
+	dmarcAlignments = []string{"r", "s"}
+)
+
+// dmarcURIs turns report addresses into a comma separated list of URIs,
+// adding mailto: to bare email addresses. Commas and semicolons in URIs
+// are escaped as the record syntax requires; a trailing size limit such as
+// !10m is kept.
+func dmarcURIs(tag string, addresses []any) (string, error) {
+	uris := make([]string, len(addresses))
+	for i, address := range addresses {
+		text, ok := address.(string)
+		if !ok {
+			return "", fmt.Errorf("%s must be a list of strings, got %s", tag, typeName(address))
+		}
+		uri := text
+		if !strings.Contains(text, ":") {
+			parts, err := parseEmail(text)
+			if err != nil {
+				return "", fmt.Errorf("%s address %q: %s", tag, text, err)
+			}
+			uri = "mailto:" + parts.Address
+		}
+		uris[i] = strings.NewReplacer(",", "%2C", ";", "%3B").Replace(uri)
+	}
+	return strings.Join(uris, ","), nil
+}
+
+// buildDMARC validates DMARC tags and assembles the record with v and p
+// first, followed by the other tags in RFC 7489 order.
+func buildDMARC(tags map[string]any) (string, error) {
+	policy := tags["p"].(string)
+	if !slices.Contains(dmarcPolicies, policy) {
+		return "", fmt.Errorf("p must be one of: %s, got %q", strings.Join(dmarcPolicies, ", "), policy)
+	}
+	parts := []string{"v=DMARC1", "p=" + policy}
+
+	if sp := tags["sp"].(string); sp != "" {
+		if !slices.Contains(dmarcPolicies, sp) {
+			return "", fmt.Errorf("sp must be one of: %s, got %q", strings.Join(dmarcPolicies, ", "), sp)
+		}
+		parts = append(parts, "sp="+sp)
+	}
+	if pct, ok := toInt64(tags["pct"]); !ok || pct < -1 || pct > 100 {
+		return "", fmt.Errorf("pct must be a whole number between 0 and 100")
+	} else if pct >= 0 {
+		parts = append(parts, fmt.Sprintf("pct=%d", pct))
+	}
+	for _, tag := range []string{"rua", "ruf"} {
+		if addresses := tags[tag].([]any); len(addresses) > 0 {
+			uris, err := dmarcURIs(tag, addresses)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, tag+"="+uris)
+		}
+	}
+	for _, tag := range []string{"adkim", "aspf"} {
+		if mode := tags[tag].(string); mode != "" {
+			if !slices.Contains(dmarcAlignments, mode) {
+				return "", fmt.Errorf("%s must be r or s, got %q", tag, mode)
+			}
+			parts = append(parts, tag+"="+mode)
+		}
+	}
+	if fo := tags["fo"].(string); fo != "" {
+		for _, option := range strings.Split(fo, ":") {
+			if !slices.Contains([]string{"0", "1", "d", "s"}, option) {
+				return "", fmt.Errorf("fo must be a colon separated list of 0, 1, d and s, got %q", fo)
+			}
+		}
+		parts = append(parts, "fo="+fo)
+	}
+	if rf := tags["rf"].(string); rf != "" {
+		if rf != "afrf" {
+			return "", fmt.Errorf("rf must be afrf, got %q", rf)
+		}
+		parts = append(parts, "rf="+rf)
+	}
+	if ri, ok := toInt64(tags["ri"]); !ok || ri < -1 || ri == 0 || ri > 1<<32-1 {
+		return "", fmt.Errorf("ri must be a positive whole number of seconds")
+	} else if ri > 0 {
+		parts = append(parts, fmt.Sprintf("ri=%d", ri))
+	}
+	return strings.Join(parts, "; "), nil
+}
+
+// DMARC Record Function
+var _ function.Function = &DMARCRecordFunction{}
+
+type DMARCRecordFunction struct{}
+
+func NewDMARCRecordFunction() function.Function {
+	return &DMARCRecordFunction{}
+}
+
+func (f *DMARCRecordFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dmarc_record"
+}
+
+func (f *DMARCRecordFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds a DMARC TXT record value",
+		Description: "Validates a DMARC policy object with the RFC 7489 tags p, sp, pct, rua, ruf, adkim, aspf, fo, rf and ri " +
+			"and returns an object with the record and the record split into 255 character strings. Report addresses " +
+			"without a scheme get mailto: added.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "policy",
+				Description: "The policy, such as { p = \"reject\", rua = [\"dmarc@example.com\"] }",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: txtRecordType,
+		},
+	}
+}
+
+func (f *DMARCRecordFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if data == nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "policy must be an object, got null"))
+		return
+	}
+	tags, err := parseOptions(data, dmarcDefaults)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	record, err := buildDMARC(tags)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, txtRecord{Record: record, Chunks: chunkTXT(record)}))
+}
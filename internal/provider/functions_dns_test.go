@@ -0,0 +1,415 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateHostname(t *testing.T) {
+	valid := []struct {
+		name, kind, expected string
+	}{
+		{"web01", "label", "web01"},
+		{"Web-01", "label", "web-01"},
+		{"3com", "label", "3com"},
+		{"web01", "hostname", "web01"},
+		{"db.internal", "hostname", "db.internal"},
+		{"API.Example.com.", "fqdn", "api.example.com"},
+		{"xn--bcher-kva.example", "fqdn", "xn--bcher-kva.example"},
+		{"*.example.com", "wildcard", "*.example.com"},
+		{"www.example.com", "wildcard", "www.example.com"},
+		{strings.Repeat("a", 63) + ".com", "fqdn", strings.Repeat("a", 63) + ".com"},
+	}
+	for _, tt := range valid {
+		result, err := runFunction(t, NewValidateHostnameFunction(), types.StringValue(tt.name), types.StringValue(tt.kind))
+		if err != nil {
+			t.Fatalf("unexpected error for %s (%s): %s", tt.name, tt.kind, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%s (%s): expected %s, got %s", tt.name, tt.kind, tt.expected, got)
+		}
+	}
+
+	longName := strings.Repeat(strings.Repeat("a", 49)+".", 5) + "info"
+	invalid := []struct {
+		name, kind, reason string
+	}{
+		{"web.01", "label", `invalid character '.'`},
+		{"-web", "label", "must not start or end with a hyphen"},
+		{"web-", "hostname", "must not start or end with a hyphen"},
+		{"web_01.example.com", "fqdn", `invalid character '_' at position 4`},
+		{strings.Repeat("a", 64) + ".com", "fqdn", "is 64 characters long, the maximum is 63"},
+		{longName, "fqdn", "name is 254 characters long, the maximum is 253"},
+		{"example..com", "fqdn", "empty label"},
+		{"", "hostname", "name is empty"},
+		{"localhost", "fqdn", "at least two labels"},
+		{"10.0.0.1", "fqdn", "must not be all numeric"},
+		{"*.example.com", "fqdn", `invalid character '*'`},
+		{"*.com", "wildcard", "at least two labels after the *"},
+		{"www.*.example.com", "wildcard", `invalid character '*'`},
+	}
+	for _, tt := range invalid {
+		_, err := runFunction(t, NewValidateHostnameFunction(), types.StringValue(tt.name), types.StringValue(tt.kind))
+		if err == nil {
+			t.Errorf("expected error for %q (%s)", tt.name, tt.kind)
+		} else if !strings.Contains(err.Error(), tt.reason) {
+			t.Errorf("%q (%s): expected reason %q, got %s", tt.name, tt.kind, tt.reason, err)
+		}
+	}
+
+	if _, err := runFunction(t, NewValidateHostnameFunction(), types.StringValue("web01"), types.StringValue("domain")); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+}
+
+func TestIDNConversion(t *testing.T) {
+	tests := []struct {
+		unicode, ascii string
+	}{
+		{"bücher.example", "xn--bcher-kva.example"},
+		{"münchen.de.", "xn--mnchen-3ya.de."},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+		{"*.bücher.example", "*.xn--bcher-kva.example"},
+		{"example.com", "example.com"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewIDNToASCIIFunction(), types.StringValue(tt.unicode))
+		if err != nil {
+			t.Fatalf("idn_to_ascii(%s): unexpected error: %s", tt.unicode, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.ascii {
+			t.Errorf("idn_to_ascii(%s): expected %s, got %s", tt.unicode, tt.ascii, got)
+		}
+
+		result, err = runFunction(t, NewIDNToUnicodeFunction(), types.StringValue(tt.ascii))
+		if err != nil {
+			t.Fatalf("idn_to_unicode(%s): unexpected error: %s", tt.ascii, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.unicode {
+			t.Errorf("idn_to_unicode(%s): expected %s, got %s", tt.ascii, tt.unicode, got)
+		}
+	}
+
+	mapped := map[string]string{
+		"Bücher.Example":    "xn--bcher-kva.example",
+		"straße.de":         "xn--strae-oqa.de",
+		"XN--MNCHEN-3YA.DE": "xn--mnchen-3ya.de",
+	}
+	for input, want := range mapped {
+		result, err := runFunction(t, NewIDNToASCIIFunction(), types.StringValue(input))
+		if err != nil {
+			t.Fatalf("idn_to_ascii(%s): unexpected error: %s", input, err)
+		}
+		if got := result.(types.String).ValueString(); got != want {
+			t.Errorf("idn_to_ascii(%s): expected %s, got %s", input, want, got)
+		}
+	}
+
+	for _, input := range []string{"", "-bücher.example", "a_b.example", "www.*.example"} {
+		if _, err := runFunction(t, NewIDNToASCIIFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("idn_to_ascii(%q): expected error", input)
+		}
+		if _, err := runFunction(t, NewIDNToUnicodeFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("idn_to_unicode(%q): expected error", input)
+		}
+	}
+}
+
+func TestDomainParse(t *testing.T) {
+	tests := map[string]string{
+		"www.example.co.uk":        `{"domain":"example.co.uk","hostname":"www.example.co.uk","icann":true,"subdomain":"www","suffix":"co.uk"}`,
+		"API.Staging.Example.com.": `{"domain":"example.com","hostname":"api.staging.example.com","icann":true,"subdomain":"api.staging","suffix":"com"}`,
+		"example.com":              `{"domain":"example.com","hostname":"example.com","icann":true,"subdomain":"","suffix":"com"}`,
+		"docs.myorg.github.io":     `{"domain":"myorg.github.io","hostname":"docs.myorg.github.io","icann":false,"subdomain":"docs","suffix":"github.io"}`,
+		"shop.bücher.de":           `{"domain":"xn--bcher-kva.de","hostname":"shop.xn--bcher-kva.de","icann":true,"subdomain":"shop","suffix":"de"}`,
+		"*.example.com":            `{"domain":"example.com","hostname":"*.example.com","icann":true,"subdomain":"*","suffix":"com"}`,
+		"host.corp.internal":       `{"domain":"corp.internal","hostname":"host.corp.internal","icann":false,"subdomain":"host","suffix":"internal"}`,
+	}
+
+	for hostname, want := range tests {
+		result, err := runFunction(t, NewDomainParseFunction(), types.StringValue(hostname))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", hostname, err)
+		}
+		if got := jsonOf(t, result); got != want {
+			t.Errorf("%s:\nexpected %s\ngot      %s", hostname, want, got)
+		}
+	}
+
+	for _, hostname := range []string{"co.uk", "com", "*.co.uk", "", "bad_name.example.com"} {
+		if _, err := runFunction(t, NewDomainParseFunction(), types.StringValue(hostname)); err == nil {
+			t.Errorf("expected error for %q", hostname)
+		}
+	}
+}
+
+func TestEmailParse(t *testing.T) {
+	valid := map[string]string{
+		"ops@example.com":                 `{"address":"ops@example.com","display_name":"","domain":"example.com","local_part":"ops","reason":"","valid":true}`,
+		"Ops Team <Ops.Team@Example.COM>": `{"address":"Ops.Team@example.com","display_name":"Ops Team","domain":"example.com","local_part":"Ops.Team","reason":"","valid":true}`,
+		"  user+alerts@example.com ":      `{"address":"user+alerts@example.com","display_name":"","domain":"example.com","local_part":"user+alerts","reason":"","valid":true}`,
+		`"john doe"@example.com`:          `{"address":"\"john doe\"@example.com","display_name":"","domain":"example.com","local_part":"john doe","reason":"","valid":true}`,
+		"jörg@bücher.de":                  `{"address":"jörg@xn--bcher-kva.de","display_name":"","domain":"xn--bcher-kva.de","local_part":"jörg","reason":"","valid":true}`,
+		"root@[192.0.2.1]":                `{"address":"root@[192.0.2.1]","display_name":"","domain":"[192.0.2.1]","local_part":"root","reason":"","valid":true}`,
+		"root@[IPv6:2001:db8::1]":         `{"address":"root@[IPv6:2001:db8::1]","display_name":"","domain":"[IPv6:2001:db8::1]","local_part":"root","reason":"","valid":true}`,
+	}
+	for input, want := range valid {
+		result, err := runFunction(t, NewEmailParseFunction(), types.StringValue(input))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", input, err)
+		}
+		if got := jsonOf(t, result); got != want {
+			t.Errorf("%s:\nexpected %s\ngot      %s", input, want, got)
+		}
+	}
+
+	invalid := map[string]string{
+		"":                                       "not an RFC 5322 address",
+		"a..b@example.com":                       "not an RFC 5322 address",
+		"a@example.com, b@example.com":           "not an RFC 5322 address",
+		strings.Repeat("a", 65) + "@example.com": "local part is 65 octets long, the maximum is 64",
+		"ops@localhost":                          "at least two labels",
+		"ops@-example.com":                       `invalid domain "-example.com"`,
+		"ops@exa_mple.com":                       `invalid domain "exa_mple.com"`,
+		"ops@[IPv6:192.0.2.1]":                   "invalid address literal",
+		"ops@[2001:db8::1]":                      "not an RFC 5322 address",
+		"ops@" + strings.Repeat("a.", 125) + "com": "address is 257 octets long, the maximum is 254",
+	}
+	for input, reason := range invalid {
+		result, err := runFunction(t, NewEmailParseFunction(), types.StringValue(input))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", input, err)
+		}
+		got := jsonOf(t, result)
+		if !strings.Contains(got, `"valid":false`) || !strings.Contains(got, `"address":""`) {
+			t.Errorf("%q: expected an invalid result, got %s", input, got)
+		}
+		if !strings.Contains(got, strings.ReplaceAll(reason, `"`, `\"`)) {
+			t.Errorf("%q: expected reason containing %q, got %s", input, reason, got)
+		}
+	}
+}
+
+func TestDNSLabel(t *testing.T) {
+	tests := []struct {
+		input     string
+		maxLength int64
+		expected  string
+	}{
+		{"My App", 63, "my-app"},
+		{"Payments_API (v2)", 63, "payments-api-v2"},
+		{"--Team.Platform--", 63, "team-platform"},
+		{"Zürich Office", 63, "zurich-office"},
+		{"ﬁnance", 63, "finance"},
+		{"123 Main St.", 63, "123-main-st"},
+		{"web/../../db", 63, "web-db"},
+		{"already-valid", 13, "already-valid"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewDNSLabelFunction(), types.StringValue(tt.input), types.Int64Value(tt.maxLength))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", tt.input, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+
+	long := func(input string, maxLength int64) string {
+		t.Helper()
+		result, err := runFunction(t, NewDNSLabelFunction(), types.StringValue(input), types.Int64Value(maxLength))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", input, err)
+		}
+		return result.(types.String).ValueString()
+	}
+
+	first := long("Customer Analytics Data Pipeline Production Workers", 30)
+	if first != "customer-analytics-da-f1ca6148" {
+		t.Errorf("unexpected truncated label %q", first)
+	}
+	if err := checkDNSLabel(first); err != nil {
+		t.Errorf("truncated label %q is invalid: %s", first, err)
+	}
+	if again := long("customer analytics data pipeline production workers", 30); again != first {
+		t.Errorf("expected the same label for the same sanitized input, got %q and %q", first, again)
+	}
+	if other := long("Customer Analytics Data Pipeline Staging Workers", 30); other == first {
+		t.Errorf("expected different labels for different inputs, both %q", first)
+	}
+
+	// The cut falls after a hyphen, which must not be doubled.
+	if got := long("abcdefghij-klmnopqrstuvwxyz", 20); strings.Contains(got, "--") || len(got) > 20 {
+		t.Errorf("unexpected truncated label %q", got)
+	}
+
+	for _, tc := range []struct {
+		input     string
+		maxLength int64
+	}{
+		{"!!!", 63},
+		{"", 63},
+		{"app", 9},
+		{"app", 64},
+	} {
+		if _, err := runFunction(t, NewDNSLabelFunction(), types.StringValue(tc.input), types.Int64Value(tc.maxLength)); err == nil {
+			t.Errorf("expected error for %q with max_length %d", tc.input, tc.maxLength)
+		}
+	}
+}
+
+func TestSPFRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		mechanisms []string
+		expected   string
+	}{
+		{
+			"typical",
+			[]string{"include:_spf.google.com", "include:sendgrid.net", "ip4:192.0.2.0/24", "ip6:2001:db8::/32", "mx", "~all"},
+			`{"chunks":["v=spf1 include:_spf.google.com include:sendgrid.net ip4:192.0.2.0/24 ip6:2001:db8::/32 mx ~all"],"lookups":3,"record":"v=spf1 include:_spf.google.com include:sendgrid.net ip4:192.0.2.0/24 ip6:2001:db8::/32 mx ~all"}`,
+		},
+		{
+			"prefix and duplicates",
+			[]string{"v=spf1", "a", "a", " ip4:198.51.100.7 ", "-all", "exp=explain._spf.example.com"},
+			`{"chunks":["v=spf1 a ip4:198.51.100.7 -all exp=explain._spf.example.com"],"lookups":1,"record":"v=spf1 a ip4:198.51.100.7 -all exp=explain._spf.example.com"}`,
+		},
+		{
+			"a and mx forms",
+			[]string{"a/24", "a:mail.example.com/28//64", "mx//64", "mx:example.com", "?ptr", "exists:%{i}._spf.example.com", "redirect=_spf.example.com"},
+			`{"chunks":["v=spf1 a/24 a:mail.example.com/28//64 mx//64 mx:example.com ?ptr exists:%{i}._spf.example.com redirect=_spf.example.com"],"lookups":7,"record":"v=spf1 a/24 a:mail.example.com/28//64 mx//64 mx:example.com ?ptr exists:%{i}._spf.example.com redirect=_spf.example.com"}`,
+		},
+		{"empty", []string{}, `{"chunks":["v=spf1"],"lookups":0,"record":"v=spf1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewSPFRecordFunction(), stringList(tt.mechanisms...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s\ngot      %s", tt.expected, got)
+			}
+		})
+	}
+
+	// Long records are split into 255 character strings.
+	var ips []string
+	for i := 0; i < 20; i++ {
+		ips = append(ips, fmt.Sprintf("ip4:198.51.100.%d", i))
+	}
+	result, err := runFunction(t, NewSPFRecordFunction(), stringList(append(ips, "-all")...))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	record := result.(types.Object).Attributes()
+	chunks := record["chunks"].(types.List).Elements()
+	if len(chunks) != 2 || len(chunks[0].(types.String).ValueString()) != 255 {
+		t.Errorf("expected two chunks with the first 255 characters long, got %v", chunks)
+	}
+	if joined := chunks[0].(types.String).ValueString() + chunks[1].(types.String).ValueString(); joined != record["record"].(types.String).ValueString() {
+		t.Errorf("chunks do not add up to the record")
+	}
+
+	var includes []string
+	for i := 0; i < 11; i++ {
+		includes = append(includes, fmt.Sprintf("include:spf%d.example.com", i))
+	}
+	errorCases := map[string][]string{
+		"record needs 11 DNS lookups, the limit is 10": includes,
+		"comes after all":                               {"-all", "mx"},
+		"redirect is ignored":                           {"mx", "redirect=_spf.example.com", "~all"},
+		"unknown mechanism":                             {"ip:192.0.2.1"},
+		"unknown modifier":                              {"foo=bar.example.com"},
+		"is not an ip4 address":                         {"ip4:2001:db8::1"},
+		"is not an ip6 address":                         {"ip6:192.0.2.1"},
+		"invalid ip4 prefix length":                     {"a/33"},
+		"invalid ip6 prefix length":                     {"mx//129"},
+		"more than one qualifier":                       {"~-all"},
+		"missing domain":                                {"include:"},
+		"needs at least two labels":                     {"include:localhost"},
+		`label "bad domain" contains invalid character`: {"include:bad domain.com"},
+	}
+	for reason, mechanisms := range errorCases {
+		_, err := runFunction(t, NewSPFRecordFunction(), stringList(mechanisms...))
+		if err == nil {
+			t.Errorf("%v: expected error", mechanisms)
+		} else if !strings.Contains(err.Error(), reason) {
+			t.Errorf("%v: expected %q in error, got %s", mechanisms, reason, err)
+		}
+	}
+}
+
+func TestDMARCRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   map[string]any
+		expected string
+	}{
+		{
+			"minimal",
+			map[string]any{"p": "none"},
+			"v=DMARC1; p=none",
+		},
+		{
+			"full",
+			map[string]any{
+				"ri":    86400,
+				"rua":   []any{"dmarc@example.com", "mailto:reports@dmarc.example.net!10m"},
+				"p":     "reject",
+				"sp":    "quarantine",
+				"pct":   50,
+				"ruf":   []any{"Forensics <forensics@Example.com>"},
+				"adkim": "s",
+				"aspf":  "r",
+				"fo":    "1:d",
+				"rf":    "afrf",
+			},
+			"v=DMARC1; p=reject; sp=quarantine; pct=50; rua=mailto:dmarc@example.com,mailto:reports@dmarc.example.net!10m; ruf=mailto:forensics@example.com; adkim=s; aspf=r; fo=1:d; rf=afrf; ri=86400",
+		},
+		{
+			"zero percent",
+			map[string]any{"p": "quarantine", "pct": 0, "rua": nil},
+			"v=DMARC1; p=quarantine; pct=0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewDMARCRecordFunction(), dynamicOf(t, tt.policy))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			expected := fmt.Sprintf(`{"chunks":[%q],"record":%q}`, tt.expected, tt.expected)
+			if got := jsonOf(t, result); got != expected {
+				t.Errorf("expected %s\ngot      %s", expected, got)
+			}
+		})
+	}
+
+	errorCases := map[string]map[string]any{
+		"p must be one of":           {"sp": "reject"},
+		"sp must be one of":          {"p": "reject", "sp": "block"},
+		"pct must be a whole number": {"p": "reject", "pct": 101},
+		"adkim must be r or s":       {"p": "reject", "adkim": "strict"},
+		"fo must be":                 {"p": "reject", "fo": "1:x"},
+		"rf must be afrf":            {"p": "reject", "rf": "iodef"},
+		"ri must be a positive":      {"p": "reject", "ri": 0},
+		`rua address "not-an-email"`: {"p": "reject", "rua": []any{"not-an-email"}},
+		"unknown option":             {"p": "reject", "policy": "reject"},
+	}
+	for reason, policy := range errorCases {
+		_, err := runFunction(t, NewDMARCRecordFunction(), dynamicOf(t, policy))
+		if err == nil {
+			t.Errorf("%v: expected error", policy)
+		} else if !strings.Contains(err.Error(), reason) {
+			t.Errorf("%v: expected %q in error, got %s", policy, reason, err)
+		}
+	}
+}
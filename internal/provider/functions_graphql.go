@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// appSyncScalars and appSyncDirectives are provided implicitly by AWS
+// AppSync, so schemas written for it never declare them.
+var appSyncScalars = []string{
+	"AWSDate", "AWSTime", "AWSDateTime", "AWSTimestamp", "AWSEmail",
+	"AWSJSON", "AWSURL", "AWSPhone", "AWSIPAddress",
+}
+
+var appSyncDirectives = map[string]string{
+	"aws_subscribe":          "directive @aws_subscribe(mutations: [String]) on FIELD_DEFINITION",
+	"aws_auth":               "directive @aws_auth(cognito_groups: [String]) on FIELD_DEFINITION",
+	"aws_api_key":            "directive @aws_api_key on FIELD_DEFINITION | OBJECT",
+	"aws_iam":                "directive @aws_iam on FIELD_DEFINITION | OBJECT",
+	"aws_oidc":               "directive @aws_oidc on FIELD_DEFINITION | OBJECT",
+	"aws_lambda":             "directive @aws_lambda on FIELD_DEFINITION | OBJECT",
+	"aws_cognito_user_pools": "directive @aws_cognito_user_pools(cognito_groups: [String]) on FIELD_DEFINITION | OBJECT",
+}
+
+// appSyncPrelude returns declarations for the AppSync scalars and directives
+// that the SDL does not define itself.
+func appSyncPrelude(sdl string) string {
+	var prelude strings.Builder
+	for _, name := range appSyncScalars {
+		if !regexp.MustCompile(`\bscalar\s+` + name + `\b`).MatchString(sdl) {
+			prelude.WriteString("scalar " + name + "\n")
+		}
+	}
+	for _, name := range sortedKeys(appSyncDirectives) {
+		if !regexp.MustCompile(`\bdirective\s+@` + name + `\b`).MatchString(sdl) {
+			prelude.WriteString(appSyncDirectives[name] + "\n")
+		}
+	}
+	return prelude.String()
+}
+
+// loadGraphQLSchema parses and validates SDL, including the AppSync prelude.
+func loadGraphQLSchema(sdl string) (*ast.Schema, error) {
+	return gqlparser.LoadSchema(
+		&ast.Source{Name: "appsync.graphql", Input: appSyncPrelude(sdl), BuiltIn: true},
+		&ast.Source{Name: "schema.graphql", Input: sdl},
+	)
+}
+
+// formatGraphQLErrors renders parser errors one per line with positions.
+func formatGraphQLErrors(err error) string {
+	var list gqlerror.List
+	var single *gqlerror.Error
+	switch {
+	case errors.As(err, &list):
+	case errors.As(err, &single):
+		list = gqlerror.List{single}
+	default:
+		return err.Error()
+	}
+
+	messages := make([]string, len(list))
+	for i, e := range list {
+		if len(e.Locations) > 0 {
+			messages[i] = fmt.Sprintf("line %d, column %d: %s", e.Locations[0].Line, e.Locations[0].Column, e.Message)
+		} else {
+			messages[i] = e.Message
+		}
+	}
+	return strings.Join(messages, "\n  - ")
+}
+
+// GraphQL Validate Schema Function
+var _ function.Function = &GraphQLValidateSchemaFunction{}
+
+type GraphQLValidateSchemaFunction struct{}
+
+func NewGraphQLValidateSchemaFunction() function.Function {
+	return &GraphQLValidateSchemaFunction{}
+}
+
+func (f *GraphQLValidateSchemaFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "graphql_validate_schema"
+}
+
+func (f *GraphQLValidateSchemaFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates a GraphQL schema",
+		Description: "Takes a GraphQL schema in SDL form and returns it unchanged if it is valid, or an error describing each problem. " +
+			"The AWS AppSync scalars (AWSDateTime, AWSJSON, ...) and directives (@aws_iam, @aws_subscribe, ...) are predeclared.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "sdl",
+				Description: "The GraphQL schema definition",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *GraphQLValidateSchemaFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var sdl string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &sdl))
+	if resp.Error != nil {
+		return
+	}
+
+	if _, err := loadGraphQLSchema(sdl); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "Invalid GraphQL schema:\n  - "+formatGraphQLErrors(err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, sdl))
+}
+
+// GraphQL Validate Query Function
+var _ function.Function = &GraphQLValidateQueryFunction{}
+
+type GraphQLValidateQueryFunction struct{}
+
+func NewGraphQLValidateQueryFunction() function.Function {
+	return &GraphQLValidateQueryFunction{}
+}
+
+func (f *GraphQLValidateQueryFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "graphql_validate_query"
+}
+
+func (f *GraphQLValidateQueryFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates a GraphQL query against a schema",
+		Description: "Takes a GraphQL schema in SDL form and a query document, returning the query unchanged if it is valid " +
+			"against the schema, or an error describing each problem such as unknown fields or mistyped arguments.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "schema",
+				Description: "The GraphQL schema definition",
+			},
+			function.StringParameter{
+				Name:        "query",
+				Description: "The query, mutation or subscription document",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *GraphQLValidateQueryFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var sdl string
+	var query string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &sdl, &query))
+	if resp.Error != nil {
+		return
+	}
+
+	schema, err := loadGraphQLSchema(sdl)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "Invalid GraphQL schema:\n  - "+formatGraphQLErrors(err)))
+		return
+	}
+
+	if _, errs := gqlparser.LoadQuery(schema, query); len(errs) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "Invalid GraphQL query:\n  - "+formatGraphQLErrors(errs)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, query))
+}
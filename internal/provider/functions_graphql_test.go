@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testGraphQLSchema = `
+type User @aws_iam {
+	id: ID!
+	email: AWSEmail
+	createdAt: AWSDateTime!
+}
+
+type Query {
+	user(id: ID!): User
+	users(limit: Int): [User!]!
+}
+
+type Mutation {
+	createUser(email: String!): User
+}
+
+type Subscription {
+	onCreateUser: User @aws_subscribe(mutations: ["createUser"])
+}
+`
+
+func TestGraphQLValidateSchema(t *testing.T) {
+	result, err := runFunction(t, NewGraphQLValidateSchemaFunction(), types.StringValue(testGraphQLSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.(types.String).ValueString() != testGraphQLSchema {
+		t.Error("expected schema to be returned unchanged")
+	}
+
+	// A schema declaring its own AppSync scalar must not clash with the prelude.
+	if _, err := runFunction(t, NewGraphQLValidateSchemaFunction(), types.StringValue("scalar AWSJSON\ntype Query { a: AWSJSON }")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		sdl  string
+		want string
+	}{
+		{"syntax", "type Query {", "line 1"},
+		{"undefined type", "type Query { a: Missing }", "Missing"},
+		{"duplicate field", "type Query { a: Int a: Int }", "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runFunction(t, NewGraphQLValidateSchemaFunction(), types.StringValue(tt.sdl))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Text, tt.want) {
+				t.Errorf("expected error to mention %q, got %s", tt.want, err.Text)
+			}
+		})
+	}
+}
+
+func TestGraphQLValidateQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{"valid query", `query { user(id: "1") { id email } }`, false},
+		{"valid mutation", `mutation Create($e: String!) { createUser(email: $e) { id } }`, false},
+		{"unknown field", `query { user(id: "1") { name } }`, true},
+		{"missing argument", `query { user { id } }`, true},
+		{"wrong argument type", `query { users(limit: "ten") { id } }`, true},
+		{"syntax", `query { user(`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runFunction(t, NewGraphQLValidateQueryFunction(), types.StringValue(testGraphQLSchema), types.StringValue(tt.query))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := runFunction(t, NewGraphQLValidateQueryFunction(), types.StringValue("type {"), types.StringValue("{ a }")); err == nil {
+		t.Error("expected error for invalid schema")
+	}
+}
@@ -0,0 +1,2058 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// listArgument converts a dynamic argument to a list, treating null as an
+// empty list.
+func listArgument(ctx context.Context, value types.Dynamic) ([]any, error) {
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return []any{}, nil
+	}
+	list, ok := data.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a list, got %s", typeName(data))
+	}
+	return list, nil
+}
+
+// objectArgument converts a dynamic argument to an object, treating null as
+// an empty object.
+func objectArgument(ctx context.Context, value types.Dynamic) (map[string]any, error) {
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return map[string]any{}, nil
+	}
+	object, ok := data.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object or map, got %s", typeName(data))
+	}
+	return object, nil
+}
+
+// elementKey looks up path in a list element and renders it as a string
+// key. Missing, null and nested values are errors.
+func elementKey(element any, index int, path string) (string, error) {
+	value, ok := lookupPath(element, path)
+	if !ok || value == nil {
+		return "", fmt.Errorf("element %d has no value at %q", index, path)
+	}
+	key, err := csvField(value)
+	if err != nil {
+		return "", fmt.Errorf("element %d: %q: %w", index, path, err)
+	}
+	return key, nil
+}
+
+// Duplicate handling modes understood by index_by.
+const (
+	duplicatesError   = "error"
+	duplicatesFirst   = "first"
+	duplicatesLast    = "last"
+	duplicatesCollect = "collect"
+)
+
+// indexBy builds an object from list keyed by the value at path.
+func indexBy(list []any, path, duplicates string) (map[string]any, error) {
+	result := make(map[string]any, len(list))
+	for i, element := range list {
+		key, err := elementKey(element, i, path)
+		if err != nil {
+			return nil, err
+		}
+
+		existing, exists := result[key]
+		switch {
+		case duplicates == duplicatesCollect:
+			collected, _ := existing.([]any)
+			result[key] = append(collected, element)
+		case !exists, duplicates == duplicatesLast:
+			result[key] = element
+		case duplicates == duplicatesError:
+			return nil, fmt.Errorf("duplicate key %q at element %d", key, i)
+		}
+	}
+	return result, nil
+}
+
+// Index By Function
+var _ function.Function = &IndexByFunction{}
+
+type IndexByFunction struct{}
+
+func NewIndexByFunction() function.Function {
+	return &IndexByFunction{}
+}
+
+func (f *IndexByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "index_by"
+}
+
+func (f *IndexByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a list of objects into an object keyed by an attribute",
+		Description: "Keys each element of a list by the value at a dot-separated attribute path, ready for for_each. Duplicate " +
+			"keys are handled according to duplicates: 'error', 'first', 'last' or 'collect' to group elements into lists.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list of objects to index",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "key",
+				Description: "The attribute path to key by, such as \"name\" or \"metadata.id\"",
+			},
+			function.StringParameter{
+				Name:        "duplicates",
+				Description: "How to handle duplicate keys: 'error', 'first', 'last' or 'collect'",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *IndexByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var key, duplicates string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &key, &duplicates))
+	if resp.Error != nil {
+		return
+	}
+
+	switch duplicates {
+	case duplicatesError, duplicatesFirst, duplicatesLast, duplicatesCollect:
+	default:
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, fmt.Sprintf("duplicates must be one of 'error', 'first', 'last' or 'collect', got %q", duplicates)))
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	indexed, err := indexBy(list, key, duplicates)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(indexed)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Pluck Function
+var _ function.Function = &PluckFunction{}
+
+type PluckFunction struct{}
+
+func NewPluckFunction() function.Function {
+	return &PluckFunction{}
+}
+
+func (f *PluckFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "pluck"
+}
+
+func (f *PluckFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Extracts an attribute from every element of a list",
+		Description: "Returns the value at a dot-separated attribute path for each element of a list, in order. Elements " +
+			"where the path is missing or null produce the default value instead.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list of objects to extract from",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "attribute_path",
+				Description: "The attribute path to extract, such as \"name\" or \"network.subnets.0\"",
+			},
+			function.DynamicParameter{
+				Name:           "default",
+				Description:    "The value to use where the path is missing, or null",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *PluckFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var listValue, defaultValue types.Dynamic
+	var path string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &listValue, &path, &defaultValue))
+	if resp.Error != nil {
+		return
+	}
+
+	list, err := listArgument(ctx, listValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	fallback, err := fromValue(ctx, defaultValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+
+	plucked := make([]any, len(list))
+	for i, element := range list {
+		value, ok := lookupPath(element, path)
+		if !ok || value == nil {
+			value = fallback
+		}
+		plucked[i] = value
+	}
+
+	result, err := toDynamic(plucked)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// sortKey is one key of a sort_by specification.
+type sortKey struct {
+	path       string
+	descending bool
+	mode       string
+}
+
+// parseSortKeys pairs each key path with its direction, which is "asc" or
+// "desc" optionally followed by ":numeric" or ":lexical".
+func parseSortKeys(keys, directions []string) ([]sortKey, error) {
+	if len(directions) != 0 && len(directions) != len(keys) {
+		return nil, fmt.Errorf("directions must be empty or have one entry per key, got %d for %d keys", len(directions), len(keys))
+	}
+
+	specs := make([]sortKey, len(keys))
+	for i, key := range keys {
+		specs[i].path = key
+		if len(directions) == 0 {
+			continue
+		}
+		direction, mode, _ := strings.Cut(directions[i], ":")
+		switch direction {
+		case "asc":
+		case "desc":
+			specs[i].descending = true
+		default:
+			return nil, fmt.Errorf("direction %q must be \"asc\" or \"desc\", optionally followed by \":numeric\" or \":lexical\"", directions[i])
+		}
+		switch mode {
+		case "", "numeric", "lexical":
+			specs[i].mode = mode
+		default:
+			return nil, fmt.Errorf("direction %q has an unknown comparison %q, expected \"numeric\" or \"lexical\"", directions[i], mode)
+		}
+	}
+	return specs, nil
+}
+
+// sortValue converts a value for comparison under mode. Numbers compare
+// numerically, strings and lexical values as strings and bools false first.
+func sortValue(value any, mode string) (any, error) {
+	switch mode {
+	case "lexical":
+		return csvField(value)
+	case "numeric":
+		switch v := value.(type) {
+		case *big.Float:
+			return v, nil
+		case string:
+			if number, _, err := big.ParseFloat(strings.TrimSpace(v), 10, 512, big.ToNearestEven); err == nil {
+				return number, nil
+			}
+		}
+		return nil, fmt.Errorf("value %v is not a number", value)
+	}
+	switch value.(type) {
+	case string, bool, *big.Float:
+		return value, nil
+	}
+	return nil, fmt.Errorf("%s values cannot be sorted", typeName(value))
+}
+
+// compareSortValues compares two values produced by sortValue.
+func compareSortValues(a, b any) (int, error) {
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	case *big.Float:
+		if y, ok := b.(*big.Float); ok {
+			return x.Cmp(y), nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, nil
+			case y:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s, use \":lexical\" to compare as strings", typeName(a), typeName(b))
+}
+
+// sortBy stably sorts list by the given keys. Missing and null values sort
+// last regardless of direction.
+func sortBy(list []any, keys []sortKey) ([]any, error) {
+	values := make([][]any, len(list))
+	for i, element := range list {
+		values[i] = make([]any, len(keys))
+		for j, key := range keys {
+			value, ok := lookupPath(element, key.path)
+			if !ok || value == nil {
+				continue
+			}
+			converted, err := sortValue(value, key.mode)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %q: %w", i, key.path, err)
+			}
+			values[i][j] = converted
+		}
+	}
+
+	order := make([]int, len(list))
+	for i := range order {
+		order[i] = i
+	}
+	var sortErr error
+	sort.SliceStable(order, func(a, b int) bool {
+		for j, key := range keys {
+			x, y := values[order[a]][j], values[order[b]][j]
+			switch {
+			case x == nil && y == nil:
+				continue
+			case x == nil:
+				return false
+			case y == nil:
+				return true
+			}
+			cmp, err := compareSortValues(x, y)
+			if err != nil {
+				if sortErr == nil {
+					sortErr = fmt.Errorf("%q: %w", key.path, err)
+				}
+				return false
+			}
+			if cmp != 0 {
+				return (cmp < 0) != key.descending
+			}
+		}
+		return false
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	sorted := make([]any, len(list))
+	for i, index := range order {
+		sorted[i] = list[index]
+	}
+	return sorted, nil
+}
+
+// Sort By Function
+var _ function.Function = &SortByFunction{}
+
+type SortByFunction struct{}
+
+func NewSortByFunction() function.Function {
+	return &SortByFunction{}
+}
+
+func (f *SortByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sort_by"
+}
+
+func (f *SortByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Sorts a list of objects by one or more attributes",
+		Description: "Stably sorts a list of objects by attribute paths, comparing by the first key and breaking ties with " +
+			"the next. Each direction is \"asc\" or \"desc\", optionally followed by \":numeric\" to compare strings as " +
+			"numbers or \":lexical\" to compare values as strings. Missing and null values sort last.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list of objects to sort",
+				AllowNullValue: true,
+			},
+			function.ListParameter{
+				Name:        "keys",
+				Description: "The attribute paths to sort by, most significant first",
+				ElementType: types.StringType,
+			},
+			function.ListParameter{
+				Name:        "directions",
+				Description: "One direction per key, or an empty list to sort every key ascending",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *SortByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var keys, directions []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &keys, &directions))
+	if resp.Error != nil {
+		return
+	}
+
+	if len(keys) == 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "keys must not be empty"))
+		return
+	}
+	specs, err := parseSortKeys(keys, directions)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	sorted, err := sortBy(list, specs)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(sorted)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// dedupeBy removes elements whose key at path repeats, keeping the first or
+// last occurrence in its original position.
+func dedupeBy(list []any, path string, keepLast bool) ([]any, error) {
+	keys := make([]string, len(list))
+	kept := map[string]int{}
+	for i, element := range list {
+		key, err := elementKey(element, i, path)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+		if _, seen := kept[key]; !seen || keepLast {
+			kept[key] = i
+		}
+	}
+
+	result := make([]any, 0, len(kept))
+	for i, element := range list {
+		if kept[keys[i]] == i {
+			result = append(result, element)
+		}
+	}
+	return result, nil
+}
+
+// Dedupe By Function
+var _ function.Function = &DedupeByFunction{}
+
+type DedupeByFunction struct{}
+
+func NewDedupeByFunction() function.Function {
+	return &DedupeByFunction{}
+}
+
+func (f *DedupeByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dedupe_by"
+}
+
+func (f *DedupeByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Removes duplicate objects by an attribute",
+		Description: "Removes elements of a list whose value at a dot-separated attribute path has already been seen, keeping " +
+			"the 'first' or 'last' occurrence in its original position.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list of objects to deduplicate",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "key",
+				Description: "The attribute path that identifies duplicates",
+			},
+			function.StringParameter{
+				Name:        "keep",
+				Description: "Which occurrence to keep: 'first' or 'last'",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *DedupeByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var key, keep string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &key, &keep))
+	if resp.Error != nil {
+		return
+	}
+
+	if keep != duplicatesFirst && keep != duplicatesLast {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, fmt.Sprintf("keep must be 'first' or 'last', got %q", keep)))
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	deduped, err := dedupeBy(list, key, keep == duplicatesLast)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(deduped)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Zip Function
+var _ function.Function = &ZipFunction{}
+
+type ZipFunction struct{}
+
+func NewZipFunction() function.Function {
+	return &ZipFunction{}
+}
+
+func (f *ZipFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "zip"
+}
+
+func (f *ZipFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds an object from parallel lists of keys and values",
+		Description: "Pairs each key with the value at the same position. Unlike zipmap, lists of different lengths and " +
+			"duplicate keys are errors rather than being silently truncated or overwritten.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "keys",
+				Description: "The keys",
+				ElementType: types.StringType,
+			},
+			function.DynamicParameter{
+				Name:           "values",
+				Description:    "The values, one per key",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ZipFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var keys []string
+	var value types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &keys, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	values, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	if len(keys) != len(values) {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("keys has %d elements but values has %d", len(keys), len(values))))
+		return
+	}
+
+	zipped := make(map[string]any, len(keys))
+	for i, key := range keys {
+		if _, exists := zipped[key]; exists {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("duplicate key %q at element %d", key, i)))
+			return
+		}
+		zipped[key] = values[i]
+	}
+
+	result, err := toDynamic(zipped)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Unzip Function
+var _ function.Function = &UnzipFunction{}
+
+type UnzipFunction struct{}
+
+func NewUnzipFunction() function.Function {
+	return &UnzipFunction{}
+}
+
+func (f *UnzipFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "unzip"
+}
+
+func (f *UnzipFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Splits an object into parallel lists of keys and values",
+		Description: "Returns an object with keys and values lists in lexical key order, so values[i] belongs to keys[i].",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "map",
+				Description:    "The object or map to split",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *UnzipFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+	object, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("expected an object or map, got %s", typeName(data))))
+		return
+	}
+
+	keys := sortedKeys(object)
+	keyList, values := make([]any, len(keys)), make([]any, len(keys))
+	for i, key := range keys {
+		keyList[i], values[i] = key, object[key]
+	}
+
+	result, err := toDynamic(map[string]any{"keys": keyList, "values": values})
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Chunk Function
+var _ function.Function = &ChunkFunction{}
+
+type ChunkFunction struct{}
+
+func NewChunkFunction() function.Function {
+	return &ChunkFunction{}
+}
+
+func (f *ChunkFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "chunk"
+}
+
+func (f *ChunkFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Splits a list into sublists of a fixed size",
+		Description: "Returns consecutive sublists of at most size elements, in order. Only the last sublist may be shorter. " +
+			"Elements can be of any type, including objects.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list to split",
+				AllowNullValue: true,
+			},
+			function.Int64Parameter{
+				Name:        "size",
+				Description: "The maximum number of elements per sublist, at least 1",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ChunkFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var size int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &size))
+	if resp.Error != nil {
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if size < 1 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "size must be at least 1"))
+		return
+	}
+
+	chunks := []any{}
+	for start := 0; start < len(list); start += int(size) {
+		end := min(start+int(size), len(list))
+		chunks = append(chunks, list[start:end])
+	}
+
+	result, err := toDynamic(chunks)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+const (
+	setUnion      = "union"
+	setIntersect  = "intersect"
+	setDifference = "difference"
+)
+
+// keyedSetOperation combines two lists of objects using the value at path as
+// element identity. The first occurrence of each key is kept, with elements
+// of a before elements of b.
+func keyedSetOperation(a, b []any, path, operation string) ([]any, error) {
+	inB := map[string]bool{}
+	bKeys := make([]string, len(b))
+	for i, element := range b {
+		key, err := elementKey(element, i, path)
+		if err != nil {
+			return nil, fmt.Errorf("second list: %w", err)
+		}
+		inB[key], bKeys[i] = true, key
+	}
+
+	seen := map[string]bool{}
+	result := []any{}
+	for i, element := range a {
+		key, err := elementKey(element, i, path)
+		if err != nil {
+			return nil, fmt.Errorf("first list: %w", err)
+		}
+		if seen[key] || (operation == setIntersect && !inB[key]) || (operation == setDifference && inB[key]) {
+			continue
+		}
+		seen[key] = true
+		result = append(result, element)
+	}
+	if operation == setUnion {
+		for i, element := range b {
+			if !seen[bKeys[i]] {
+				seen[bKeys[i]] = true
+				result = append(result, element)
+			}
+		}
+	}
+	return result, nil
+}
+
+// keyedSetParameters are the parameters shared by union_by, intersect_by and
+// difference_by.
+var keyedSetParameters = []function.Parameter{
+	function.DynamicParameter{
+		Name:           "a",
+		Description:    "The first list of objects",
+		AllowNullValue: true,
+	},
+	function.DynamicParameter{
+		Name:           "b",
+		Description:    "The second list of objects",
+		AllowNullValue: true,
+	},
+	function.StringParameter{
+		Name:        "key",
+		Description: "The dot-separated attribute path that identifies an element, such as \"id\" or \"metadata.name\"",
+	},
+}
+
+// runKeyedSetOperation implements union_by, intersect_by and difference_by.
+func runKeyedSetOperation(ctx context.Context, req function.RunRequest, resp *function.RunResponse, operation string) {
+	var aValue, bValue types.Dynamic
+	var key string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &aValue, &bValue, &key))
+	if resp.Error != nil {
+		return
+	}
+
+	a, err := listArgument(ctx, aValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	b, err := listArgument(ctx, bValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	combined, err := keyedSetOperation(a, b, key, operation)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+
+	result, err := toDynamic(combined)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Union By Function
+var _ function.Function = &UnionByFunction{}
+
+type UnionByFunction struct{}
+
+func NewUnionByFunction() function.Function {
+	return &UnionByFunction{}
+}
+
+func (f *UnionByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "union_by"
+}
+
+func (f *UnionByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Combines two lists of objects, identified by an attribute",
+		Description: "Returns the elements of a followed by the elements of b whose key is not in a. Only the first " +
+			"element for each key is kept, so other attributes do not need to match.",
+		Parameters: keyedSetParameters,
+		Return:     function.DynamicReturn{},
+	}
+}
+
+func (f *UnionByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runKeyedSetOperation(ctx, req, resp, setUnion)
+}
+
+// Intersect By Function
+var _ function.Function = &IntersectByFunction{}
+
+type IntersectByFunction struct{}
+
+func NewIntersectByFunction() function.Function {
+	return &IntersectByFunction{}
+}
+
+func (f *IntersectByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "intersect_by"
+}
+
+func (f *IntersectByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Keeps the objects of a list whose attribute appears in another list",
+		Description: "Returns the elements of a whose key is also in b, in the order of a. Only the first element for each " +
+			"key is kept, and the elements always come from a.",
+		Parameters: keyedSetParameters,
+		Return:     function.DynamicReturn{},
+	}
+}
+
+func (f *IntersectByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runKeyedSetOperation(ctx, req, resp, setIntersect)
+}
+
+// Difference By Function
+var _ function.Function = &DifferenceByFunction{}
+
+type DifferenceByFunction struct{}
+
+func NewDifferenceByFunction() function.Function {
+	return &DifferenceByFunction{}
+}
+
+func (f *DifferenceByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "difference_by"
+}
+
+func (f *DifferenceByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Removes the objects of a list whose attribute appears in another list",
+		Description: "Returns the elements of a whose key is not in b, in the order of a. Only the first element for each key is kept.",
+		Parameters:  keyedSetParameters,
+		Return:      function.DynamicReturn{},
+	}
+}
+
+func (f *DifferenceByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runKeyedSetOperation(ctx, req, resp, setDifference)
+}
+
+const (
+	joinLeft  = "left"
+	joinInner = "inner"
+)
+
+// joinBy joins left and right on the value at path, merging the attributes of
+// the matching right element over each left element. Right keys must be
+// unique so every left element has at most one match.
+func joinBy(left, right []any, path, how string) ([]any, error) {
+	matches := map[string]map[string]any{}
+	for i, element := range right {
+		key, err := elementKey(element, i, path)
+		if err != nil {
+			return nil, fmt.Errorf("right: %w", err)
+		}
+		object, ok := element.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("right: element %d is %s, expected an object", i, typeName(element))
+		}
+		if _, exists := matches[key]; exists {
+			return nil, fmt.Errorf("right: duplicate key %q at element %d", key, i)
+		}
+		matches[key] = object
+	}
+
+	result := []any{}
+	for i, element := range left {
+		key, err := elementKey(element, i, path)
+		if err != nil {
+			return nil, fmt.Errorf("left: %w", err)
+		}
+		object, ok := element.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("left: element %d is %s, expected an object", i, typeName(element))
+		}
+		match, found := matches[key]
+		if !found {
+			if how == joinLeft {
+				result = append(result, object)
+			}
+			continue
+		}
+		joined := make(map[string]any, len(object)+len(match))
+		for k, v := range object {
+			joined[k] = v
+		}
+		for k, v := range match {
+			joined[k] = v
+		}
+		result = append(result, joined)
+	}
+	return result, nil
+}
+
+// Join By Function
+var _ function.Function = &JoinByFunction{}
+
+type JoinByFunction struct{}
+
+func NewJoinByFunction() function.Function {
+	return &JoinByFunction{}
+}
+
+func (f *JoinByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "join_by"
+}
+
+func (f *JoinByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Joins two lists of objects on a key attribute",
+		Description: "Merges the attributes of the right element with the same key into each left element, with right " +
+			"values winning. A 'left' join keeps unmatched left elements, an 'inner' join drops them.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "left",
+				Description:    "The list of objects to join onto, which determines the result order",
+				AllowNullValue: true,
+			},
+			function.DynamicParameter{
+				Name:           "right",
+				Description:    "The list of objects to join, with unique keys",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "key",
+				Description: "The dot-separated attribute path to join on, such as \"id\"",
+			},
+			function.StringParameter{
+				Name:        "how",
+				Description: "The join type: 'left' or 'inner'",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *JoinByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var leftValue, rightValue types.Dynamic
+	var key, how string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &leftValue, &rightValue, &key, &how))
+	if resp.Error != nil {
+		return
+	}
+
+	left, err := listArgument(ctx, leftValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	right, err := listArgument(ctx, rightValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	if how != joinLeft && how != joinInner {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(3, fmt.Sprintf("how must be 'left' or 'inner', got %q", how)))
+		return
+	}
+
+	joined, err := joinBy(left, right, key, how)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+
+	result, err := toDynamic(joined)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// topologicalSort orders the nodes of a dependency map so that every node
+// comes after its dependencies. Nodes that are only mentioned as
+// dependencies are included. Among nodes that are ready at the same time the
+// lexically smallest goes first, so the order is stable.
+func topologicalSort(dependencies map[string][]string) ([]string, error) {
+	pending := map[string]map[string]bool{}
+	dependents := map[string][]string{}
+	for node, deps := range dependencies {
+		if pending[node] == nil {
+			pending[node] = map[string]bool{}
+		}
+		for _, dep := range deps {
+			if pending[dep] == nil {
+				pending[dep] = map[string]bool{}
+			}
+			if !pending[node][dep] {
+				pending[node][dep] = true
+				dependents[dep] = append(dependents[dep], node)
+			}
+		}
+	}
+
+	var ready []string
+	for node, deps := range pending {
+		if len(deps) == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	order := make([]string, 0, len(pending))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		node := ready[0]
+		ready = ready[1:]
+		order = append(order, node)
+		for _, dependent := range dependents[node] {
+			delete(pending[dependent], node)
+			if len(pending[dependent]) == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) < len(pending) {
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(findCycle(pending), " -> "))
+	}
+	return order, nil
+}
+
+// findCycle returns one cycle among the nodes with unresolved dependencies,
+// starting and ending with the same node.
+func findCycle(pending map[string]map[string]bool) []string {
+	var remaining []string
+	for node, deps := range pending {
+		if len(deps) > 0 {
+			remaining = append(remaining, node)
+		}
+	}
+	sort.Strings(remaining)
+
+	// Every remaining node has at least one remaining dependency, so walking
+	// the smallest one from any start must eventually revisit a node.
+	position := map[string]int{}
+	var path []string
+	for node := remaining[0]; ; {
+		if start, seen := position[node]; seen {
+			return append(path[start:], node)
+		}
+		position[node] = len(path)
+		path = append(path, node)
+		deps := make([]string, 0, len(pending[node]))
+		for dep := range pending[node] {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		node = deps[0]
+	}
+}
+
+// Topological Sort Function
+var _ function.Function = &TopologicalSortFunction{}
+
+type TopologicalSortFunction struct{}
+
+func NewTopologicalSortFunction() function.Function {
+	return &TopologicalSortFunction{}
+}
+
+func (f *TopologicalSortFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "topological_sort"
+}
+
+func (f *TopologicalSortFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Orders the nodes of a dependency map",
+		Description: "Takes a map of node names to the names they depend on and returns every node after its dependencies. " +
+			"Ties are broken lexically so the order is stable, and cycles are errors.",
+		Parameters: []function.Parameter{
+			function.MapParameter{
+				Name:        "dependencies",
+				Description: "A map of each node to the list of nodes it depends on",
+				ElementType: types.ListType{ElemType: types.StringType},
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *TopologicalSortFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var dependencies map[string][]string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &dependencies))
+	if resp.Error != nil {
+		return
+	}
+
+	order, err := topologicalSort(dependencies)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, order))
+}
+
+// transposeMap turns a map of lists inside out: every element of every list
+// becomes a key whose value lists the original keys that contained it, in
+// lexical order and without repeats. Elements are keyed by the value at
+// path, or by themselves when path is empty.
+func transposeMap(object map[string]any, path string) (map[string]any, error) {
+	result := map[string]any{}
+	for _, key := range sortedKeys(object) {
+		if object[key] == nil {
+			continue
+		}
+		list, ok := object[key].([]any)
+		if !ok {
+			return nil, fmt.Errorf("%q: expected a list, got %s", key, typeName(object[key]))
+		}
+		for i, element := range list {
+			elementName, err := elementKey(element, i, path)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", key, err)
+			}
+			keys, _ := result[elementName].([]any)
+			if len(keys) == 0 || keys[len(keys)-1] != key {
+				result[elementName] = append(keys, key)
+			}
+		}
+	}
+	return result, nil
+}
+
+// invertMap groups the keys of a map of scalars by their value. Null values
+// are skipped.
+func invertMap(object map[string]any) (map[string]any, error) {
+	result := map[string]any{}
+	for _, key := range sortedKeys(object) {
+		if object[key] == nil {
+			continue
+		}
+		value, err := csvField(object[key])
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+		keys, _ := result[value].([]any)
+		result[value] = append(keys, key)
+	}
+	return result, nil
+}
+
+// Transpose Map Function
+var _ function.Function = &TransposeMapFunction{}
+
+type TransposeMapFunction struct{}
+
+func NewTransposeMapFunction() function.Function {
+	return &TransposeMapFunction{}
+}
+
+func (f *TransposeMapFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "transpose_map"
+}
+
+func (f *TransposeMapFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Swaps the keys and list elements of a map of lists",
+		Description: "Like the built-in transpose, converts {k = [v1, v2]} into {v1 = [k], v2 = [k]}, but list elements may " +
+			"be numbers or bools, or objects identified by the attribute at key. Keys in each result list are sorted and unique.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "map",
+				Description:    "The map of lists to transpose",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "key",
+				Description: "The dot-separated attribute path that names object elements, or \"\" for lists of scalars",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *TransposeMapFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var key string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &key))
+	if resp.Error != nil {
+		return
+	}
+
+	object, err := objectArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	transposed, err := transposeMap(object, key)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(transposed)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Invert Map Function
+var _ function.Function = &InvertMapFunction{}
+
+type InvertMapFunction struct{}
+
+func NewInvertMapFunction() function.Function {
+	return &InvertMapFunction{}
+}
+
+func (f *InvertMapFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "invert_map"
+}
+
+func (f *InvertMapFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Groups the keys of a map by their values",
+		Description: "Converts {k1 = v, k2 = v} into {v = [k1, k2]}, the reverse lookup of a many-to-one map. Values may be " +
+			"strings, numbers or bools, keys in each result list are sorted, and null values are skipped.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "map",
+				Description:    "The map of scalars to invert",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *InvertMapFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	object, err := objectArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	inverted, err := invertMap(object)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(inverted)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// extremeBy returns the element of list with the smallest value at path, or
+// the largest when largest is set. Elements with a missing or null value are
+// skipped and ties keep the first element. It returns nil when no element has
+// a value.
+func extremeBy(list []any, path string, largest bool) (any, error) {
+	var best, bestValue any
+	for i, element := range list {
+		value, ok := lookupPath(element, path)
+		if !ok || value == nil {
+			continue
+		}
+		converted, err := sortValue(value, "")
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %q: %w", i, path, err)
+		}
+		if bestValue != nil {
+			cmp, err := compareSortValues(converted, bestValue)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %q: %w", i, path, err)
+			}
+			if (largest && cmp <= 0) || (!largest && cmp >= 0) {
+				continue
+			}
+		}
+		best, bestValue = element, converted
+	}
+	return best, nil
+}
+
+// sumBy adds up the numeric values at path, skipping missing and null values.
+// Numeric strings are accepted. It returns the sum and the number of values.
+func sumBy(list []any, path string) (*big.Float, int, error) {
+	sum := new(big.Float).SetPrec(512)
+	count := 0
+	for i, element := range list {
+		value, ok := lookupPath(element, path)
+		if !ok || value == nil {
+			continue
+		}
+		number, err := sortValue(value, "numeric")
+		if err != nil {
+			return nil, 0, fmt.Errorf("element %d: %q: %w", i, path, err)
+		}
+		sum.Add(sum, number.(*big.Float))
+		count++
+	}
+	return sum, count, nil
+}
+
+// aggregateParameters are the parameters shared by min_by, max_by, sum_by
+// and avg_by.
+var aggregateParameters = []function.Parameter{
+	function.DynamicParameter{
+		Name:           "list",
+		Description:    "The list of objects to aggregate",
+		AllowNullValue: true,
+	},
+	function.StringParameter{
+		Name:        "key",
+		Description: "The dot-separated attribute path to aggregate, such as \"capacity\" or \"meta.created\"",
+	},
+}
+
+// runExtremeBy implements min_by and max_by.
+func runExtremeBy(ctx context.Context, req function.RunRequest, resp *function.RunResponse, largest bool) {
+	var value types.Dynamic
+	var key string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &key))
+	if resp.Error != nil {
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	element, err := extremeBy(list, key, largest)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(element)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// runSumBy implements sum_by and avg_by.
+func runSumBy(ctx context.Context, req function.RunRequest, resp *function.RunResponse, average bool) {
+	var value types.Dynamic
+	var key string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &key))
+	if resp.Error != nil {
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	sum, count, err := sumBy(list, key)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result := types.NumberValue(sum)
+	if average {
+		if count == 0 {
+			result = types.NumberNull()
+		} else {
+			result = types.NumberValue(sum.Quo(sum, new(big.Float).SetInt64(int64(count))))
+		}
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Min By Function
+var _ function.Function = &MinByFunction{}
+
+type MinByFunction struct{}
+
+func NewMinByFunction() function.Function {
+	return &MinByFunction{}
+}
+
+func (f *MinByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "min_by"
+}
+
+func (f *MinByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the object with the smallest value of an attribute",
+		Description: "Compares numbers numerically and strings lexically, skipping elements where the attribute is missing or " +
+			"null. Ties return the first element, and null is returned when no element has a value.",
+		Parameters: aggregateParameters,
+		Return:     function.DynamicReturn{},
+	}
+}
+
+func (f *MinByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runExtremeBy(ctx, req, resp, false)
+}
+
+// Max By Function
+var _ function.Function = &MaxByFunction{}
+
+type MaxByFunction struct{}
+
+func NewMaxByFunction() function.Function {
+	return &MaxByFunction{}
+}
+
+func (f *MaxByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "max_by"
+}
+
+func (f *MaxByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the object with the largest value of an attribute",
+		Description: "Compares numbers numerically and strings lexically, so RFC 3339 timestamps find the newest entry. Elements " +
+			"where the attribute is missing or null are skipped, ties return the first element, and null is returned when no element has a value.",
+		Parameters: aggregateParameters,
+		Return:     function.DynamicReturn{},
+	}
+}
+
+func (f *MaxByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runExtremeBy(ctx, req, resp, true)
+}
+
+// Sum By Function
+var _ function.Function = &SumByFunction{}
+
+type SumByFunction struct{}
+
+func NewSumByFunction() function.Function {
+	return &SumByFunction{}
+}
+
+func (f *SumByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sum_by"
+}
+
+func (f *SumByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Adds up an attribute across a list of objects",
+		Description: "Returns the sum of the numeric values of an attribute, skipping elements where it is missing or null. " +
+			"Numeric strings are accepted and an empty list sums to 0.",
+		Parameters: aggregateParameters,
+		Return:     function.NumberReturn{},
+	}
+}
+
+func (f *SumByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runSumBy(ctx, req, resp, false)
+}
+
+// Avg By Function
+var _ function.Function = &AvgByFunction{}
+
+type AvgByFunction struct{}
+
+func NewAvgByFunction() function.Function {
+	return &AvgByFunction{}
+}
+
+func (f *AvgByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "avg_by"
+}
+
+func (f *AvgByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Averages an attribute across a list of objects",
+		Description: "Returns the mean of the numeric values of an attribute, skipping elements where it is missing or null. " +
+			"Numeric strings are accepted and null is returned when no element has a value.",
+		Parameters: aggregateParameters,
+		Return:     function.NumberReturn{},
+	}
+}
+
+func (f *AvgByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runSumBy(ctx, req, resp, true)
+}
+
+// Count By Function
+var _ function.Function = &CountByFunction{}
+
+type CountByFunction struct{}
+
+func NewCountByFunction() function.Function {
+	return &CountByFunction{}
+}
+
+func (f *CountByFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "count_by"
+}
+
+func (f *CountByFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Counts the objects of a list by an attribute",
+		Description: "Returns a map of each distinct value of an attribute to the number of elements that have it.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list of objects to count",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "key",
+				Description: "The dot-separated attribute path to count by, such as \"availability_zone\"",
+			},
+		},
+		Return: function.MapReturn{
+			ElementType: types.NumberType,
+		},
+	}
+}
+
+func (f *CountByFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var key string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &key))
+	if resp.Error != nil {
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	counts := map[string]int64{}
+	for i, element := range list {
+		value, err := elementKey(element, i, key)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+			return
+		}
+		counts[value]++
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, counts))
+}
+
+// seededRand is a deterministic random number generator keyed by a seed
+// string. Each output is taken from SHA-256 of the seed and a counter, so the
+// sequence is the same on every platform and Go version.
+type seededRand struct {
+	seed    string
+	counter uint64
+}
+
+func newSeededRand(seed string) *seededRand {
+	return &seededRand{seed: seed}
+}
+
+// uint64 returns the next 64 bits of the sequence.
+func (r *seededRand) uint64() uint64 {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], r.counter)
+	r.counter++
+	sum := sha256.Sum256(append([]byte(r.seed+"\x00"), counter[:]...))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// intn returns a uniformly distributed integer in [0, n), rejecting values
+// that would bias the result. n must be positive.
+func (r *seededRand) intn(n uint64) uint64 {
+	limit := ^uint64(0) - ^uint64(0)%n
+	for {
+		if v := r.uint64(); v < limit {
+			return v % n
+		}
+	}
+}
+
+// shuffleSeeded returns a copy of list in a pseudo-random order determined
+// by seed, using a Fisher-Yates shuffle.
+func shuffleSeeded(list []any, seed string) []any {
+	shuffled := append([]any{}, list...)
+	random := newSeededRand(seed)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := random.intn(uint64(i + 1))
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled
+}
+
+// Shuffle Seeded Function
+var _ function.Function = &ShuffleSeededFunction{}
+
+type ShuffleSeededFunction struct{}
+
+func NewShuffleSeededFunction() function.Function {
+	return &ShuffleSeededFunction{}
+}
+
+func (f *ShuffleSeededFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "shuffle_seeded"
+}
+
+func (f *ShuffleSeededFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Shuffles a list in a stable order determined by a seed",
+		Description: "Returns the elements of a list in a pseudo-random order that only depends on the seed and the list, " +
+			"so the same inputs always give the same order without a stateful random resource. Not suitable for secrets.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list to shuffle",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "seed",
+				Description: "The seed, such as terraform.workspace",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ShuffleSeededFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var seed string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &seed))
+	if resp.Error != nil {
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(shuffleSeeded(list, seed))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Sample Seeded Function
+var _ function.Function = &SampleSeededFunction{}
+
+type SampleSeededFunction struct{}
+
+func NewSampleSeededFunction() function.Function {
+	return &SampleSeededFunction{}
+}
+
+func (f *SampleSeededFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sample_seeded"
+}
+
+func (f *SampleSeededFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Picks a stable subset of a list determined by a seed",
+		Description: "Returns n distinct elements of a list, chosen pseudo-randomly from the seed. The result is the first n " +
+			"elements of shuffle_seeded with the same seed, so growing n only adds elements. Not suitable for secrets.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list to sample from",
+				AllowNullValue: true,
+			},
+			function.Int64Parameter{
+				Name:        "n",
+				Description: "The number of elements to pick, between 0 and the length of the list",
+			},
+			function.StringParameter{
+				Name:        "seed",
+				Description: "The seed, such as terraform.workspace",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *SampleSeededFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var n int64
+	var seed string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &n, &seed))
+	if resp.Error != nil {
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if n < 0 || n > int64(len(list)) {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("n must be between 0 and the list length %d, got %d", len(list), n)))
+		return
+	}
+
+	result, err := toDynamic(shuffleSeeded(list, seed)[:n])
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// seededChoice picks one of the options with probability proportional to its
+// weight. Weights are scaled to whole numbers by their common denominator
+// and options are walked in lexical order, so the result does not depend on
+// map ordering.
+func seededChoice(options map[string]*big.Float, seed string) (string, error) {
+	weights := map[string]*big.Rat{}
+	denominator := big.NewInt(1)
+	for _, option := range sortedKeys(options) {
+		if options[option] == nil || options[option].Sign() < 0 {
+			return "", fmt.Errorf("weight of %q must be a non-negative number", option)
+		}
+		weight, _ := new(big.Rat).SetString(options[option].Text('f', -1))
+		weights[option] = weight
+		gcd := new(big.Int).GCD(nil, nil, denominator, weight.Denom())
+		denominator.Mul(denominator, new(big.Int).Quo(weight.Denom(), gcd))
+	}
+
+	scaled := map[string]*big.Int{}
+	total := new(big.Int)
+	for option, weight := range weights {
+		w := new(big.Int).Mul(weight.Num(), new(big.Int).Quo(denominator, weight.Denom()))
+		scaled[option] = w
+		total.Add(total, w)
+	}
+	if total.Sign() == 0 {
+		return "", fmt.Errorf("at least one weight must be positive")
+	}
+	if !total.IsUint64() {
+		return "", fmt.Errorf("weights are too large or have too many decimals")
+	}
+
+	var choice string
+	pick := new(big.Int).SetUint64(newSeededRand(seed).intn(total.Uint64()))
+	for _, option := range sortedKeys(scaled) {
+		choice = option
+		if pick.Cmp(scaled[option]) < 0 {
+			break
+		}
+		pick.Sub(pick, scaled[option])
+	}
+	return choice, nil
+}
+
+// Seeded Choice Function
+var _ function.Function = &SeededChoiceFunction{}
+
+type SeededChoiceFunction struct{}
+
+func NewSeededChoiceFunction() function.Function {
+	return &SeededChoiceFunction{}
+}
+
+func (f *SeededChoiceFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "seeded_choice"
+}
+
+func (f *SeededChoiceFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Picks a stable weighted option determined by a seed",
+		Description: "Returns one key of a map of options to weights, chosen pseudo-randomly from the seed with " +
+			"probability proportional to its weight. The same inputs always give the same option without a stateful " +
+			"random resource. Not suitable for secrets.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "seed",
+				Description: "The seed, such as the name of the resource being assigned",
+			},
+			function.MapParameter{
+				Name:        "options_with_weights",
+				Description: "A map of each option to its non-negative weight",
+				ElementType: types.NumberType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SeededChoiceFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var seed string
+	var options map[string]*big.Float
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &seed, &options))
+	if resp.Error != nil {
+		return
+	}
+
+	choice, err := seededChoice(options, seed)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, choice))
+}
+
+// Windows Function
+var _ function.Function = &WindowsFunction{}
+
+type WindowsFunction struct{}
+
+func NewWindowsFunction() function.Function {
+	return &WindowsFunction{}
+}
+
+func (f *WindowsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "windows"
+}
+
+func (f *WindowsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns sliding windows over a list",
+		Description: "Returns every sublist of size consecutive elements, starting at each multiple of step. Only full " +
+			"windows are returned, so a list shorter than size gives an empty list. Size 2 and step 1 gives adjacent pairs.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "list",
+				Description:    "The list to slide over",
+				AllowNullValue: true,
+			},
+			function.Int64Parameter{
+				Name:        "size",
+				Description: "The number of elements per window, at least 1",
+			},
+			function.Int64Parameter{
+				Name:        "step",
+				Description: "How many elements each window starts after the previous one, at least 1",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *WindowsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var size, step int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &size, &step))
+	if resp.Error != nil {
+		return
+	}
+
+	list, err := listArgument(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if size < 1 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "size must be at least 1"))
+		return
+	}
+	if step < 1 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, "step must be at least 1"))
+		return
+	}
+
+	// Clamp step so that advancing past the end cannot overflow.
+	step = min(step, int64(len(list))+1)
+	windows := []any{}
+	for start := int64(0); start <= int64(len(list))-size; start += step {
+		windows = append(windows, list[start:start+size])
+	}
+
+	result, err := toDynamic(windows)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Interleave Function
+var _ function.Function = &InterleaveFunction{}
+
+type InterleaveFunction struct{}
+
+func NewInterleaveFunction() function.Function {
+	return &InterleaveFunction{}
+}
+
+func (f *InterleaveFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "interleave"
+}
+
+func (f *InterleaveFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Merges lists by alternating their elements",
+		Description: "Takes the first element of each list in turn, then the second, and so on. Lists that run out are " +
+			"skipped, so the remaining elements of longer lists come last.",
+		VariadicParameter: function.DynamicParameter{
+			Name:           "lists",
+			Description:    "The lists to interleave",
+			AllowNullValue: true,
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *InterleaveFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var values []types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &values))
+	if resp.Error != nil {
+		return
+	}
+
+	lists := make([][]any, len(values))
+	longest := 0
+	for i, value := range values {
+		list, err := listArgument(ctx, value)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i), err.Error()))
+			return
+		}
+		lists[i] = list
+		longest = max(longest, len(list))
+	}
+
+	interleaved := []any{}
+	for index := 0; index < longest; index++ {
+		for _, list := range lists {
+			if index < len(list) {
+				interleaved = append(interleaved, list[index])
+			}
+		}
+	}
+
+	result, err := toDynamic(interleaved)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
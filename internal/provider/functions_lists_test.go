@@ -0,0 +1,757 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIndexBy(t *testing.T) {
+	users := `[{"name": "alice", "team": "ops", "meta": {"id": 1}}, {"name": "bob", "team": "dev", "meta": {"id": 2}}, {"name": "carol", "team": "ops", "meta": {"id": 3}}]`
+
+	tests := []struct {
+		key        string
+		duplicates string
+		expected   string
+	}{
+		{"name", "error", `{"alice":{"meta":{"id":1},"name":"alice","team":"ops"},"bob":{"meta":{"id":2},"name":"bob","team":"dev"},"carol":{"meta":{"id":3},"name":"carol","team":"ops"}}`},
+		{"meta.id", "error", `{"1":{"meta":{"id":1},"name":"alice","team":"ops"},"2":{"meta":{"id":2},"name":"bob","team":"dev"},"3":{"meta":{"id":3},"name":"carol","team":"ops"}}`},
+		{"team", "first", `{"dev":{"meta":{"id":2},"name":"bob","team":"dev"},"ops":{"meta":{"id":1},"name":"alice","team":"ops"}}`},
+		{"team", "last", `{"dev":{"meta":{"id":2},"name":"bob","team":"dev"},"ops":{"meta":{"id":3},"name":"carol","team":"ops"}}`},
+		{"team", "collect", `{"dev":[{"meta":{"id":2},"name":"bob","team":"dev"}],"ops":[{"meta":{"id":1},"name":"alice","team":"ops"},{"meta":{"id":3},"name":"carol","team":"ops"}]}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewIndexByFunction(), dynamicOf(t, mustJSON(t, users)), types.StringValue(tt.key), types.StringValue(tt.duplicates))
+		if err != nil {
+			t.Fatalf("unexpected error for %s/%s: %s", tt.key, tt.duplicates, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s/%s: expected %s, got %s", tt.key, tt.duplicates, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewIndexByFunction(), dynamicOf(t, nil), types.StringValue("name"), types.StringValue("error"))
+	if err != nil || jsonOf(t, result) != `{}` {
+		t.Errorf("expected empty object for null list, got %v %v", result, err)
+	}
+
+	errorCases := []struct {
+		list, key, duplicates string
+	}{
+		{users, "team", "error"},
+		{users, "missing", "error"},
+		{users, "meta", "error"},
+		{users, "name", "merge"},
+		{`{"a": 1}`, "name", "error"},
+		{`[{"name": null}]`, "name", "error"},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewIndexByFunction(), dynamicOf(t, mustJSON(t, tt.list)), types.StringValue(tt.key), types.StringValue(tt.duplicates)); err == nil {
+			t.Errorf("expected error for %s %s %s", tt.list, tt.key, tt.duplicates)
+		}
+	}
+}
+
+func TestPluck(t *testing.T) {
+	instances := `[{"name": "web", "network": {"ip": "10.0.0.1", "subnets": ["a", "b"]}}, {"name": "db", "network": {"ip": null}}, {"name": "cache"}]`
+
+	tests := []struct {
+		path     string
+		fallback any
+		expected string
+	}{
+		{"name", nil, `["web","db","cache"]`},
+		{"network.ip", "unassigned", `["10.0.0.1","unassigned","unassigned"]`},
+		{"network.subnets.1", nil, `["b",null,null]`},
+		{"network.subnets", []any{}, `[["a","b"],[],[]]`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewPluckFunction(), dynamicOf(t, mustJSON(t, instances)), types.StringValue(tt.path), dynamicOf(t, tt.fallback))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", tt.path, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%q: expected %s, got %s", tt.path, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewPluckFunction(), dynamicOf(t, mustJSON(t, `{"a": 1}`)), types.StringValue("a"), dynamicOf(t, nil)); err == nil {
+		t.Error("expected error for non-list input")
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	services := `[
+		{"name": "web", "tier": 2, "version": "10", "enabled": true},
+		{"name": "api", "tier": 1, "version": "9", "enabled": false},
+		{"name": "db", "tier": 2, "version": "2"},
+		{"name": "cache", "tier": null, "version": "1", "enabled": true},
+		{"name": "auth", "tier": 1, "version": "11", "enabled": true}
+	]`
+
+	tests := []struct {
+		name       string
+		keys       []string
+		directions []string
+		expected   []string
+	}{
+		{"single key ascending", []string{"name"}, []string{}, []string{"api", "auth", "cache", "db", "web"}},
+		{"multiple keys", []string{"tier", "name"}, []string{"desc", "asc"}, []string{"db", "web", "api", "auth", "cache"}},
+		{"lexical strings", []string{"version"}, []string{"asc"}, []string{"cache", "web", "auth", "db", "api"}},
+		{"numeric strings", []string{"version"}, []string{"desc:numeric"}, []string{"auth", "web", "api", "db", "cache"}},
+		{"lexical numbers", []string{"tier"}, []string{"asc:lexical"}, []string{"api", "auth", "web", "db", "cache"}},
+		{"bools with nulls last", []string{"enabled"}, []string{"desc"}, []string{"web", "cache", "auth", "api", "db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewSortByFunction(), dynamicOf(t, mustJSON(t, services)), stringList(tt.keys...), stringList(tt.directions...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			names, _ := runFunction(t, NewPluckFunction(), result.(types.Dynamic), types.StringValue("name"), dynamicOf(t, nil))
+			if got, want := jsonOf(t, names), jsonOf(t, dynamicOf(t, toAnyList(tt.expected))); got != want {
+				t.Errorf("expected %s, got %s", want, got)
+			}
+		})
+	}
+
+	errorCases := []struct {
+		list       string
+		keys       []string
+		directions []string
+	}{
+		{services, []string{}, []string{}},
+		{services, []string{"name", "tier"}, []string{"asc"}},
+		{services, []string{"name"}, []string{"up"}},
+		{services, []string{"name"}, []string{"asc:natural"}},
+		{services, []string{"name"}, []string{"asc:numeric"}},
+		{`[{"v": 1}, {"v": "a"}]`, []string{"v"}, []string{}},
+		{`[{"v": [1]}]`, []string{"v"}, []string{}},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewSortByFunction(), dynamicOf(t, mustJSON(t, tt.list)), stringList(tt.keys...), stringList(tt.directions...)); err == nil {
+			t.Errorf("expected error for keys %v directions %v", tt.keys, tt.directions)
+		}
+	}
+}
+
+// toAnyList converts strings to plain list data.
+func toAnyList(values []string) []any {
+	list := make([]any, len(values))
+	for i, value := range values {
+		list[i] = value
+	}
+	return list
+}
+
+func TestDedupeBy(t *testing.T) {
+	records := `[
+		{"id": "a", "seen": "2024-01-01"},
+		{"id": "b", "seen": "2024-01-02"},
+		{"id": "a", "seen": "2024-01-03"},
+		{"id": "c", "seen": "2024-01-04"},
+		{"id": "b", "seen": "2024-01-05"}
+	]`
+
+	tests := []struct {
+		keep     string
+		expected string
+	}{
+		{"first", `["2024-01-01","2024-01-02","2024-01-04"]`},
+		{"last", `["2024-01-03","2024-01-04","2024-01-05"]`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewDedupeByFunction(), dynamicOf(t, mustJSON(t, records)), types.StringValue("id"), types.StringValue(tt.keep))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.keep, err)
+		}
+		seen, _ := runFunction(t, NewPluckFunction(), result.(types.Dynamic), types.StringValue("seen"), dynamicOf(t, nil))
+		if got := jsonOf(t, seen); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.keep, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewDedupeByFunction(), dynamicOf(t, mustJSON(t, records)), types.StringValue("id"), types.StringValue("newest")); err == nil {
+		t.Error("expected error for invalid keep")
+	}
+	if _, err := runFunction(t, NewDedupeByFunction(), dynamicOf(t, mustJSON(t, `[{"id": "a"}, {}]`)), types.StringValue("id"), types.StringValue("first")); err == nil {
+		t.Error("expected error for missing key")
+	}
+}
+
+func TestZip(t *testing.T) {
+	result, err := runFunction(t, NewZipFunction(), stringList("web", "db"), dynamicOf(t, mustJSON(t, `[{"port": 443}, 5432]`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := jsonOf(t, result), `{"db":5432,"web":{"port":443}}`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	result, err = runFunction(t, NewZipFunction(), stringList(), dynamicOf(t, nil))
+	if err != nil || jsonOf(t, result) != `{}` {
+		t.Errorf("expected empty object, got %v %v", result, err)
+	}
+
+	errorCases := []struct {
+		keys   []string
+		values string
+	}{
+		{[]string{"a", "b"}, `[1]`},
+		{[]string{"a"}, `[1, 2]`},
+		{[]string{"a", "a"}, `[1, 2]`},
+		{[]string{"a"}, `{"a": 1}`},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewZipFunction(), stringList(tt.keys...), dynamicOf(t, mustJSON(t, tt.values))); err == nil {
+			t.Errorf("expected error for %v %s", tt.keys, tt.values)
+		}
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"web": 443, "db": 5432, "api": "8080"}`, `{"keys":["api","db","web"],"values":["8080",5432,443]}`},
+		{`null`, `{"keys":[],"values":[]}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewUnzipFunction(), dynamicOf(t, mustJSON(t, tt.input)))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewUnzipFunction(), dynamicOf(t, mustJSON(t, `[1]`))); err == nil {
+		t.Error("expected error for list input")
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		input    string
+		size     int64
+		expected string
+	}{
+		{`[1, 2, 3, 4, 5]`, 2, `[[1,2],[3,4],[5]]`},
+		{`[1, 2, 3, 4]`, 2, `[[1,2],[3,4]]`},
+		{`[1, 2]`, 50, `[[1,2]]`},
+		{`[{"port": 80}, {"port": 443}]`, 1, `[[{"port":80}],[{"port":443}]]`},
+		{`[]`, 3, `[]`},
+		{`null`, 3, `[]`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewChunkFunction(), dynamicOf(t, mustJSON(t, tt.input)), types.Int64Value(tt.size))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s/%d: expected %s, got %s", tt.input, tt.size, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewChunkFunction(), dynamicOf(t, mustJSON(t, `[1]`)), types.Int64Value(0)); err == nil {
+		t.Error("expected error for size 0")
+	}
+	if _, err := runFunction(t, NewChunkFunction(), dynamicOf(t, mustJSON(t, `"a"`)), types.Int64Value(1)); err == nil {
+		t.Error("expected error for non-list input")
+	}
+}
+
+func TestKeyedSetOperations(t *testing.T) {
+	current := `[{"id": "a", "rev": 1}, {"id": "b", "rev": 1}, {"id": "c", "rev": 1}, {"id": "a", "rev": 2}]`
+	desired := `[{"id": "b", "rev": 2}, {"id": "d", "rev": 2}]`
+
+	tests := []struct {
+		name     string
+		fn       func() function.Function
+		expected []string
+	}{
+		{"union_by", NewUnionByFunction, []string{"a/1", "b/1", "c/1", "d/2"}},
+		{"intersect_by", NewIntersectByFunction, []string{"b/1"}},
+		{"difference_by", NewDifferenceByFunction, []string{"a/1", "c/1"}},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, tt.fn(), dynamicOf(t, mustJSON(t, current)), dynamicOf(t, mustJSON(t, desired)), types.StringValue("id"))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+		var got []string
+		for _, element := range mustJSON(t, jsonOf(t, result)).([]any) {
+			object := element.(map[string]any)
+			got = append(got, fmt.Sprintf("%s/%v", object["id"], object["rev"]))
+		}
+		if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewUnionByFunction(), dynamicOf(t, nil), dynamicOf(t, nil), types.StringValue("id"))
+	if err != nil || jsonOf(t, result) != `[]` {
+		t.Errorf("expected empty list for null inputs, got %v %v", result, err)
+	}
+
+	errorCases := []struct {
+		a, b string
+	}{
+		{`[{"id": "a"}]`, `[{"name": "b"}]`},
+		{`[{"id": {"nested": true}}]`, `[]`},
+		{`{"id": "a"}`, `[]`},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewDifferenceByFunction(), dynamicOf(t, mustJSON(t, tt.a)), dynamicOf(t, mustJSON(t, tt.b)), types.StringValue("id")); err == nil {
+			t.Errorf("expected error for %s %s", tt.a, tt.b)
+		}
+	}
+}
+
+func TestJoinBy(t *testing.T) {
+	hosts := `[{"id": "web-1", "ip": "10.0.0.1"}, {"id": "web-2", "ip": "10.0.0.2"}, {"id": "db-1", "ip": "10.0.1.1"}]`
+	owners := `[{"id": "db-1", "owner": "data"}, {"id": "web-1", "owner": "platform", "ip": "10.9.9.9"}, {"id": "lb-1", "owner": "edge"}]`
+
+	tests := []struct {
+		how      string
+		expected string
+	}{
+		{"left", `[{"id":"web-1","ip":"10.9.9.9","owner":"platform"},{"id":"web-2","ip":"10.0.0.2"},{"id":"db-1","ip":"10.0.1.1","owner":"data"}]`},
+		{"inner", `[{"id":"web-1","ip":"10.9.9.9","owner":"platform"},{"id":"db-1","ip":"10.0.1.1","owner":"data"}]`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewJoinByFunction(), dynamicOf(t, mustJSON(t, hosts)), dynamicOf(t, mustJSON(t, owners)), types.StringValue("id"), types.StringValue(tt.how))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.how, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.how, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewJoinByFunction(), dynamicOf(t, mustJSON(t, hosts)), dynamicOf(t, nil), types.StringValue("id"), types.StringValue("inner"))
+	if err != nil || jsonOf(t, result) != `[]` {
+		t.Errorf("expected empty inner join with null right, got %v %v", result, err)
+	}
+
+	errorCases := []struct {
+		left, right, how string
+	}{
+		{`[{"id": 1}]`, `[{"id": 1}, {"id": "1"}]`, "left"},
+		{`[{"id": 1}]`, `[{"name": 1}]`, "left"},
+		{`[{"name": 1}]`, `[]`, "left"},
+		{`[{"id": 1}]`, `[]`, "outer"},
+		{`{"id": 1}`, `[]`, "left"},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewJoinByFunction(), dynamicOf(t, mustJSON(t, tt.left)), dynamicOf(t, mustJSON(t, tt.right)), types.StringValue("id"), types.StringValue(tt.how)); err == nil {
+			t.Errorf("expected error for %s %s %s", tt.left, tt.right, tt.how)
+		}
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	tests := []struct {
+		dependencies map[string][]string
+		expected     []string
+	}{
+		{
+			map[string][]string{"app": {"db", "cache"}, "db": {"network"}, "cache": {"network"}, "network": {}},
+			[]string{"network", "cache", "db", "app"},
+		},
+		{
+			map[string][]string{"c": {}, "b": {}, "a": {}},
+			[]string{"a", "b", "c"},
+		},
+		{
+			map[string][]string{"app": {"db", "db"}, "worker": {"queue"}},
+			[]string{"db", "app", "queue", "worker"},
+		},
+		{
+			map[string][]string{},
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		dependencies, diags := types.MapValueFrom(context.Background(), types.ListType{ElemType: types.StringType}, tt.dependencies)
+		if diags.HasError() {
+			t.Fatalf("failed to build map: %v", diags)
+		}
+		result, err := runFunction(t, NewTopologicalSortFunction(), dependencies)
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %s", tt.dependencies, err)
+		}
+		if !result.Equal(stringList(tt.expected...)) {
+			t.Errorf("%v: expected %v, got %s", tt.dependencies, tt.expected, result)
+		}
+	}
+
+	cycles := []struct {
+		dependencies map[string][]string
+		message      string
+	}{
+		{map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}, "d": {}}, "a -> b -> c -> a"},
+		{map[string][]string{"a": {"a"}}, "a -> a"},
+		{map[string][]string{"x": {"y"}, "y": {"z"}, "z": {"y"}}, "y -> z -> y"},
+	}
+	for _, tt := range cycles {
+		dependencies, _ := types.MapValueFrom(context.Background(), types.ListType{ElemType: types.StringType}, tt.dependencies)
+		_, err := runFunction(t, NewTopologicalSortFunction(), dependencies)
+		if err == nil {
+			t.Errorf("expected cycle error for %v", tt.dependencies)
+		} else if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("expected %q in error, got %s", tt.message, err)
+		}
+	}
+}
+
+func TestTransposeMap(t *testing.T) {
+	tests := []struct {
+		input    string
+		key      string
+		expected string
+	}{
+		{`{"admins": ["alice", "bob"], "devs": ["bob", "carol"]}`, "", `{"alice":["admins"],"bob":["admins","devs"],"carol":["devs"]}`},
+		{`{"web": [80, 443], "api": [443, 443]}`, "", `{"443":["api","web"],"80":["web"]}`},
+		{`{"admins": [{"name": "alice", "mfa": true}], "devs": [{"name": "alice", "mfa": false}], "empty": [], "none": null}`, "name", `{"alice":["admins","devs"]}`},
+		{`null`, "", `{}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewTransposeMapFunction(), dynamicOf(t, mustJSON(t, tt.input)), types.StringValue(tt.key))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	errorCases := []struct {
+		input string
+		key   string
+	}{
+		{`{"a": "b"}`, ""},
+		{`{"a": [{"name": "x"}]}`, ""},
+		{`{"a": [{"id": "x"}]}`, "name"},
+		{`{"a": [null]}`, ""},
+		{`["a"]`, ""},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewTransposeMapFunction(), dynamicOf(t, mustJSON(t, tt.input)), types.StringValue(tt.key)); err == nil {
+			t.Errorf("expected error for %s with key %q", tt.input, tt.key)
+		}
+	}
+}
+
+func TestInvertMap(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"bob": "admins", "alice": "admins", "carol": "devs"}`, `{"admins":["alice","bob"],"devs":["carol"]}`},
+		{`{"web": 443, "api": 443, "legacy": true, "unset": null}`, `{"443":["api","web"],"true":["legacy"]}`},
+		{`null`, `{}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewInvertMapFunction(), dynamicOf(t, mustJSON(t, tt.input)))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewInvertMapFunction(), dynamicOf(t, mustJSON(t, `{"a": ["b"]}`))); err == nil {
+		t.Error("expected error for list value")
+	}
+}
+
+func TestMinMaxBy(t *testing.T) {
+	releases := `[{"tag": "v2", "created": "2024-03-01T00:00:00Z", "size": 20}, {"tag": "v3", "created": "2024-05-01T00:00:00Z", "size": 5}, {"tag": "v1", "created": "2023-12-01T00:00:00Z", "size": 20}, {"tag": "draft", "created": null}]`
+
+	tests := []struct {
+		name     string
+		fn       func() function.Function
+		key      string
+		expected string
+	}{
+		{"newest", NewMaxByFunction, "created", "v3"},
+		{"oldest", NewMinByFunction, "created", "v1"},
+		{"largest keeps first tie", NewMaxByFunction, "size", "v2"},
+		{"smallest", NewMinByFunction, "size", "v3"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, tt.fn(), dynamicOf(t, mustJSON(t, releases)), types.StringValue(tt.key))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+		object, ok := mustJSON(t, jsonOf(t, result)).(map[string]any)
+		if !ok || object["tag"] != tt.expected {
+			t.Errorf("%s: expected tag %s, got %s", tt.name, tt.expected, jsonOf(t, result))
+		}
+	}
+
+	result, err := runFunction(t, NewMinByFunction(), dynamicOf(t, mustJSON(t, `[{"a": null}, {}]`)), types.StringValue("a"))
+	if err != nil || jsonOf(t, result) != `null` {
+		t.Errorf("expected null result when no element has a value, got %v %v", result, err)
+	}
+
+	if _, err := runFunction(t, NewMaxByFunction(), dynamicOf(t, mustJSON(t, `[{"a": 1}, {"a": "2"}]`)), types.StringValue("a")); err == nil {
+		t.Error("expected error for mixed types")
+	}
+	if _, err := runFunction(t, NewMaxByFunction(), dynamicOf(t, mustJSON(t, `[{"a": [1]}]`)), types.StringValue("a")); err == nil {
+		t.Error("expected error for list values")
+	}
+}
+
+func TestSumAvgBy(t *testing.T) {
+	pools := `[{"name": "a", "capacity": 10}, {"name": "b", "capacity": "2.5"}, {"name": "c"}, {"name": "d", "capacity": 0.5}]`
+
+	tests := []struct {
+		name     string
+		fn       func() function.Function
+		input    string
+		expected string
+	}{
+		{"sum", NewSumByFunction, pools, "13"},
+		{"avg", NewAvgByFunction, pools, "4.333333333333333"},
+		{"sum of empty list", NewSumByFunction, `[]`, "0"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, tt.fn(), dynamicOf(t, mustJSON(t, tt.input)), types.StringValue("capacity"))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+		number := result.(types.Number).ValueBigFloat()
+		if got := number.Text('g', 16); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewAvgByFunction(), dynamicOf(t, nil), types.StringValue("capacity"))
+	if err != nil || !result.IsNull() {
+		t.Errorf("expected null average of empty list, got %v %v", result, err)
+	}
+
+	if _, err := runFunction(t, NewSumByFunction(), dynamicOf(t, mustJSON(t, `[{"capacity": "large"}]`)), types.StringValue("capacity")); err == nil {
+		t.Error("expected error for non-numeric value")
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	subnets := `[{"id": "a", "az": "us-east-1a"}, {"id": "b", "az": "us-east-1b"}, {"id": "c", "az": "us-east-1a"}, {"id": "d", "az": "us-east-1a"}]`
+
+	result, err := runFunction(t, NewCountByFunction(), dynamicOf(t, mustJSON(t, subnets)), types.StringValue("az"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := jsonOf(t, result), `{"us-east-1a":3,"us-east-1b":1}`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	result, err = runFunction(t, NewCountByFunction(), dynamicOf(t, mustJSON(t, `[{"on": true}, {"on": "true"}, {"on": false}]`)), types.StringValue("on"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := jsonOf(t, result), `{"false":1,"true":2}`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	result, err = runFunction(t, NewCountByFunction(), dynamicOf(t, nil), types.StringValue("az"))
+	if err != nil || jsonOf(t, result) != `{}` {
+		t.Errorf("expected empty map for null list, got %v %v", result, err)
+	}
+
+	if _, err := runFunction(t, NewCountByFunction(), dynamicOf(t, mustJSON(t, `[{"id": "a"}]`)), types.StringValue("az")); err == nil {
+		t.Error("expected error for missing attribute")
+	}
+}
+
+func TestShuffleSeeded(t *testing.T) {
+	zones := dynamicOf(t, toAnyList([]string{"a", "b", "c", "d", "e", "f"}))
+
+	first, err := runFunction(t, NewShuffleSeededFunction(), zones, types.StringValue("prod"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	again, _ := runFunction(t, NewShuffleSeededFunction(), zones, types.StringValue("prod"))
+	if jsonOf(t, first) != jsonOf(t, again) {
+		t.Errorf("expected the same order for the same seed, got %s and %s", jsonOf(t, first), jsonOf(t, again))
+	}
+	// Pin the sequence so that a change to the generator is noticed.
+	if got, want := jsonOf(t, first), `["d","c","a","f","e","b"]`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	other, _ := runFunction(t, NewShuffleSeededFunction(), zones, types.StringValue("staging"))
+	if jsonOf(t, first) == jsonOf(t, other) {
+		t.Errorf("expected different orders for different seeds, both were %s", jsonOf(t, first))
+	}
+
+	counts := map[string]int{}
+	for _, element := range mustJSON(t, jsonOf(t, other)).([]any) {
+		counts[element.(string)]++
+	}
+	if len(counts) != 6 {
+		t.Errorf("expected a permutation, got %s", jsonOf(t, other))
+	}
+
+	result, err := runFunction(t, NewShuffleSeededFunction(), dynamicOf(t, nil), types.StringValue("prod"))
+	if err != nil || jsonOf(t, result) != `[]` {
+		t.Errorf("expected empty list, got %v %v", result, err)
+	}
+}
+
+func TestSampleSeeded(t *testing.T) {
+	zones := dynamicOf(t, toAnyList([]string{"a", "b", "c", "d", "e", "f"}))
+
+	shuffled, _ := runFunction(t, NewShuffleSeededFunction(), zones, types.StringValue("prod"))
+	for n := int64(0); n <= 6; n++ {
+		result, err := runFunction(t, NewSampleSeededFunction(), zones, types.Int64Value(n), types.StringValue("prod"))
+		if err != nil {
+			t.Fatalf("unexpected error for n=%d: %s", n, err)
+		}
+		want := jsonOf(t, dynamicOf(t, mustJSON(t, jsonOf(t, shuffled)).([]any)[:n]))
+		if got := jsonOf(t, result); got != want {
+			t.Errorf("n=%d: expected %s, got %s", n, want, got)
+		}
+	}
+
+	for _, n := range []int64{-1, 7} {
+		if _, err := runFunction(t, NewSampleSeededFunction(), zones, types.Int64Value(n), types.StringValue("prod")); err == nil {
+			t.Errorf("expected error for n=%d", n)
+		}
+	}
+}
+
+func TestSeededChoice(t *testing.T) {
+	weightsOf := func(weights map[string]float64) types.Map {
+		elements := map[string]attr.Value{}
+		for option, weight := range weights {
+			elements[option] = types.NumberValue(big.NewFloat(weight))
+		}
+		return types.MapValueMust(types.NumberType, elements)
+	}
+	choose := func(seed string, weights map[string]float64) string {
+		t.Helper()
+		result, err := runFunction(t, NewSeededChoiceFunction(), types.StringValue(seed), weightsOf(weights))
+		if err != nil {
+			t.Fatalf("%s %v: unexpected error: %s", seed, weights, err)
+		}
+		return result.(types.String).ValueString()
+	}
+
+	days := map[string]float64{"mon": 1, "tue": 1, "wed": 1}
+	if first, second := choose("db-1", days), choose("db-1", days); first != second {
+		t.Errorf("expected the same choice for the same seed, got %q and %q", first, second)
+	}
+	if got := choose("db-1", map[string]float64{"mon": 0, "tue": 2}); got != "tue" {
+		t.Errorf("expected the only weighted option, got %q", got)
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		counts[choose(fmt.Sprintf("cell-%d", i), map[string]float64{"canary": 0.1, "stable": 0.9})]++
+	}
+	if counts["canary"] < 140 || counts["canary"] > 260 {
+		t.Errorf("expected about 10%% canary choices, got %v", counts)
+	}
+
+	for _, weights := range []map[string]float64{
+		{},
+		{"a": 0, "b": 0},
+		{"a": -1, "b": 2},
+	} {
+		if _, err := runFunction(t, NewSeededChoiceFunction(), types.StringValue("seed"), weightsOf(weights)); err == nil {
+			t.Errorf("%v: expected error", weights)
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	tests := []struct {
+		input      string
+		size, step int64
+		expected   string
+	}{
+		{`[1, 2, 3, 4]`, 2, 1, `[[1,2],[2,3],[3,4]]`},
+		{`[1, 2, 3, 4, 5]`, 3, 2, `[[1,2,3],[3,4,5]]`},
+		{`[1, 2, 3, 4, 5]`, 2, 2, `[[1,2],[3,4]]`},
+		{`[1, 2, 3]`, 1, 5, `[[1]]`},
+		{`[1, 2, 3]`, 1, math.MaxInt64, `[[1]]`},
+		{`["a", "b", "c"]`, 3, 1, `[["a","b","c"]]`},
+		{`[1, 2]`, 3, 1, `[]`},
+		{`null`, 2, 1, `[]`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewWindowsFunction(), dynamicOf(t, mustJSON(t, tt.input)), types.Int64Value(tt.size), types.Int64Value(tt.step))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s/%d/%d: expected %s, got %s", tt.input, tt.size, tt.step, tt.expected, got)
+		}
+	}
+
+	for _, args := range [][2]int64{{0, 1}, {2, 0}} {
+		if _, err := runFunction(t, NewWindowsFunction(), dynamicOf(t, mustJSON(t, `[1, 2]`)), types.Int64Value(args[0]), types.Int64Value(args[1])); err == nil {
+			t.Errorf("expected error for size %d step %d", args[0], args[1])
+		}
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		lists    []string
+		expected string
+	}{
+		{[]string{`["a1", "a2", "a3"]`, `["b1", "b2", "b3"]`, `["c1", "c2", "c3"]`}, `["a1","b1","c1","a2","b2","c2","a3","b3","c3"]`},
+		{[]string{`[1, 2, 3, 4]`, `["x"]`, `[]`, `null`}, `[1,"x",2,3,4]`},
+		{[]string{`[{"id": 1}]`, `[{"id": 2}]`}, `[{"id":1},{"id":2}]`},
+		{[]string{`[1, 2]`}, `[1,2]`},
+		{nil, `[]`},
+	}
+
+	for _, tt := range tests {
+		args := make([]attr.Value, len(tt.lists))
+		for i, list := range tt.lists {
+			args[i] = dynamicOf(t, mustJSON(t, list))
+		}
+		result, err := runFunction(t, NewInterleaveFunction(), variadicOf(args...))
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %s", tt.lists, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%v: expected %s, got %s", tt.lists, tt.expected, got)
+		}
+	}
+
+	_, err := runFunction(t, NewInterleaveFunction(), variadicOf(dynamicOf(t, mustJSON(t, `[1]`)), dynamicOf(t, mustJSON(t, `{"a": 1}`))))
+	if err == nil || err.FunctionArgument == nil || *err.FunctionArgument != 1 {
+		t.Errorf("expected error for the second argument, got %v", err)
+	}
+}
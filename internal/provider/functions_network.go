@@ -0,0 +1,1981 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"net/netip"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// parseCIDR parses a CIDR block, converting IPv4-mapped IPv6 prefixes to
+// IPv4 and zeroing any host bits.
+func parseCIDR(text string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(text)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", text)
+	}
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), nil
+}
+
+// parseAddrOrCIDR parses a CIDR block like parseCIDR, or a single address
+// as a block of one address.
+func parseAddrOrCIDR(text string) (netip.Prefix, error) {
+	if strings.Contains(text, "/") {
+		return parseCIDR(text)
+	}
+	addr, err := netip.ParseAddr(text)
+	if err != nil || addr.Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address or CIDR %q", text)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// addrToInt returns the numeric value of an address.
+func addrToInt(addr netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(addr.AsSlice())
+}
+
+// intToAddr converts a numeric value back to an address of the given bit
+// length, 32 or 128. The value must fit.
+func intToAddr(n *big.Int, bitLen int) netip.Addr {
+	bytes := n.FillBytes(make([]byte, bitLen/8))
+	addr, _ := netip.AddrFromSlice(bytes)
+	return addr
+}
+
+// prefixSize returns the number of addresses in a prefix of the given length.
+func prefixSize(bits, bitLen int) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(bitLen-bits))
+}
+
+// prefixMask returns the netmask of a prefix length as an address, such as
+// 255.255.255.0 for /24.
+func prefixMask(bits, bitLen int) netip.Addr {
+	mask := new(big.Int).Sub(prefixSize(0, bitLen), prefixSize(bits, bitLen))
+	return intToAddr(mask, bitLen)
+}
+
+var cidrPlanHostDefaults = map[string]any{
+	"hosts":    new(big.Float),
+	"reserved": big.NewFloat(-1),
+}
+
+// subnetPrefixLength reads a cidr_plan request, which is either a prefix
+// length or an object with a host count. Host counts get the smallest prefix
+// with enough addresses after the reserved ones, which default to the
+// network and broadcast addresses for IPv4 and none for IPv6.
+func subnetPrefixLength(request any, bitLen int) (int, error) {
+	if bits, ok := toInt64(request); ok {
+		if bits < 0 || bits > int64(bitLen) {
+			return 0, fmt.Errorf("prefix length must be between 0 and %d, got %d", bitLen, bits)
+		}
+		return int(bits), nil
+	}
+	if _, ok := request.(map[string]any); !ok {
+		return 0, fmt.Errorf("expected a prefix length or an object with hosts, got %s", typeName(request))
+	}
+
+	options, err := parseOptions(request, cidrPlanHostDefaults)
+	if err != nil {
+		return 0, err
+	}
+	hosts, ok := toInt64(options["hosts"])
+	if !ok || hosts < 1 {
+		return 0, fmt.Errorf("hosts must be a whole number of at least 1")
+	}
+	reserved, ok := toInt64(options["reserved"])
+	if !ok || reserved < -1 {
+		return 0, fmt.Errorf("reserved must be a non-negative whole number")
+	}
+	if reserved == -1 {
+		reserved = 0
+		if bitLen == 32 {
+			reserved = 2
+		}
+	}
+
+	needed := new(big.Int).SetInt64(hosts + reserved)
+	for bits := bitLen; bits >= 0; bits-- {
+		if prefixSize(bits, bitLen).Cmp(needed) >= 0 {
+			return bits, nil
+		}
+	}
+	return 0, fmt.Errorf("%d hosts do not fit in any prefix", hosts)
+}
+
+// subnetRequest is a named block to allocate.
+type subnetRequest struct {
+	name string
+	bits int
+}
+
+// subnetRequests reads a map of subnet names to sizes in the format accepted
+// by subnetPrefixLength.
+func subnetRequests(ctx context.Context, value types.Dynamic, bitLen int) ([]subnetRequest, error) {
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	subnets, ok := data.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a map of subnets, got %s", typeName(data))
+	}
+
+	requests := make([]subnetRequest, 0, len(subnets))
+	for _, name := range sortedKeys(subnets) {
+		bits, err := subnetPrefixLength(subnets[name], bitLen)
+		if err != nil {
+			return nil, fmt.Errorf("subnet %q: %s", name, err)
+		}
+		requests = append(requests, subnetRequest{name: name, bits: bits})
+	}
+	return requests, nil
+}
+
+// planSubnets packs the requested blocks into base, largest first and by
+// name within the same size, each at the lowest free address. Allocating in
+// decreasing size keeps every block aligned without leaving gaps.
+func planSubnets(base netip.Prefix, requests []subnetRequest) (map[string]netip.Prefix, error) {
+	sorted := append([]subnetRequest(nil), requests...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].bits != sorted[j].bits {
+			return sorted[i].bits < sorted[j].bits
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	bitLen := base.Addr().BitLen()
+	next := addrToInt(base.Addr())
+	end := new(big.Int).Add(next, prefixSize(base.Bits(), bitLen))
+
+	plan := make(map[string]netip.Prefix, len(sorted))
+	for _, request := range sorted {
+		if request.bits < base.Bits() {
+			return nil, fmt.Errorf("subnet %q (/%d) is larger than %s", request.name, request.bits, base)
+		}
+		blockEnd := new(big.Int).Add(next, prefixSize(request.bits, bitLen))
+		if blockEnd.Cmp(end) > 0 {
+			return nil, fmt.Errorf("not enough space in %s for subnet %q (/%d)", base, request.name, request.bits)
+		}
+		plan[request.name] = netip.PrefixFrom(intToAddr(next, bitLen), request.bits)
+		next = blockEnd
+	}
+	return plan, nil
+}
+
+// CIDR Plan Function
+var _ function.Function = &CIDRPlanFunction{}
+
+type CIDRPlanFunction struct{}
+
+func NewCIDRPlanFunction() function.Function {
+	return &CIDRPlanFunction{}
+}
+
+func (f *CIDRPlanFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_plan"
+}
+
+func (f *CIDRPlanFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Allocates named subnets within a CIDR block",
+		Description: "Takes a map of subnet names to prefix lengths, or to objects with a hosts count, and packs them into " +
+			"the base block largest first, then by name. Returns a map of names to CIDR blocks, or an error when they do not fit.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "base_cidr",
+				Description: "The IPv4 or IPv6 block to allocate from",
+			},
+			function.DynamicParameter{
+				Name:        "subnets",
+				Description: "A map of subnet names to a prefix length such as 24, or an object such as { hosts = 100 }",
+			},
+		},
+		Return: function.MapReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *CIDRPlanFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var baseCIDR string
+	var subnetsValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &baseCIDR, &subnetsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	base, err := parseCIDR(baseCIDR)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	requests, err := subnetRequests(ctx, subnetsValue, base.Addr().BitLen())
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	plan, err := planSubnets(base, requests)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	result := make(map[string]string, len(plan))
+	for name, prefix := range plan {
+		result[name] = prefix.String()
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// CIDR Contains Function
+var _ function.Function = &CIDRContainsFunction{}
+
+type CIDRContainsFunction struct{}
+
+func NewCIDRContainsFunction() function.Function {
+	return &CIDRContainsFunction{}
+}
+
+func (f *CIDRContainsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_contains"
+}
+
+func (f *CIDRContainsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether a CIDR block contains an address or block",
+		Description: "Returns true when every address of the second argument, an IP address or CIDR block, is inside the " +
+			"first. Works for IPv4 and IPv6, and blocks of different address families never contain each other.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cidr",
+				Description: "The containing CIDR block",
+			},
+			function.StringParameter{
+				Name:        "address_or_cidr",
+				Description: "The IP address or CIDR block to look for",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *CIDRContainsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidr, target string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &cidr, &target))
+	if resp.Error != nil {
+		return
+	}
+
+	outer, err := parseCIDR(cidr)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	inner, err := parseAddrOrCIDR(target)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	contains := outer.Bits() <= inner.Bits() && outer.Contains(inner.Addr())
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, contains))
+}
+
+// CIDR Overlaps Function
+var _ function.Function = &CIDROverlapsFunction{}
+
+type CIDROverlapsFunction struct{}
+
+func NewCIDROverlapsFunction() function.Function {
+	return &CIDROverlapsFunction{}
+}
+
+func (f *CIDROverlapsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_overlaps"
+}
+
+func (f *CIDROverlapsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether two CIDR blocks share any address",
+		Description: "Returns true when the blocks or addresses have at least one address in common, which for CIDR blocks " +
+			"means one contains the other. Blocks of different address families never overlap.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "a",
+				Description: "The first IP address or CIDR block",
+			},
+			function.StringParameter{
+				Name:        "b",
+				Description: "The second IP address or CIDR block",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *CIDROverlapsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &a, &b))
+	if resp.Error != nil {
+		return
+	}
+
+	first, err := parseAddrOrCIDR(a)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	second, err := parseAddrOrCIDR(b)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, first.Overlaps(second)))
+}
+
+// nextAvailablePrefix returns the lowest block of the given length inside
+// pool that does not overlap any of the used blocks. Used blocks of the other
+// address family or outside the pool are ignored.
+func nextAvailablePrefix(pool netip.Prefix, bits int, used []netip.Prefix) (netip.Prefix, bool) {
+	bitLen := pool.Addr().BitLen()
+	size := prefixSize(bits, bitLen)
+	poolEnd := new(big.Int).Add(addrToInt(pool.Addr()), prefixSize(pool.Bits(), bitLen))
+
+	var blocks []netip.Prefix
+	for _, prefix := range used {
+		if prefix.Addr().BitLen() == bitLen && prefix.Overlaps(pool) {
+			blocks = append(blocks, prefix)
+		}
+	}
+
+	candidate := addrToInt(pool.Addr())
+	for _, block := range collapsePrefixes(blocks) {
+		blockStart := addrToInt(block.Addr())
+		if new(big.Int).Add(candidate, size).Cmp(blockStart) <= 0 {
+			break
+		}
+		// Move past the used block, rounding up to the next aligned
+		// candidate. Blocks are sorted and none contains another.
+		blockEnd := blockStart.Add(blockStart, prefixSize(block.Bits(), bitLen))
+		if blockEnd.Cmp(candidate) > 0 {
+			candidate = blockEnd.Add(blockEnd, new(big.Int).Sub(size, big.NewInt(1)))
+			candidate.Sub(candidate, new(big.Int).Mod(candidate, size))
+		}
+	}
+
+	if new(big.Int).Add(candidate, size).Cmp(poolEnd) > 0 {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(intToAddr(candidate, bitLen), bits), true
+}
+
+// Next Available CIDR Function
+var _ function.Function = &NextAvailableCIDRFunction{}
+
+type NextAvailableCIDRFunction struct{}
+
+func NewNextAvailableCIDRFunction() function.Function {
+	return &NextAvailableCIDRFunction{}
+}
+
+func (f *NextAvailableCIDRFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "next_available_cidr"
+}
+
+func (f *NextAvailableCIDRFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Finds the first free CIDR block of a given size in a pool",
+		Description: "Returns the lowest block with the requested prefix length inside the pool that does not overlap any of " +
+			"the used blocks. Used blocks outside the pool or of the other address family are ignored.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "pool_cidr",
+				Description: "The CIDR block to allocate from",
+			},
+			function.Int64Parameter{
+				Name:        "prefix_length",
+				Description: "The prefix length of the block to find",
+			},
+			function.ListParameter{
+				Name:        "used_cidrs",
+				Description: "CIDR blocks or addresses that are already allocated",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *NextAvailableCIDRFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var poolCIDR string
+	var bits int64
+	var usedCIDRs []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &poolCIDR, &bits, &usedCIDRs))
+	if resp.Error != nil {
+		return
+	}
+
+	pool, err := parseCIDR(poolCIDR)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if bits < int64(pool.Bits()) || bits > int64(pool.Addr().BitLen()) {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("prefix_length must be between %d and %d for %s, got %d", pool.Bits(), pool.Addr().BitLen(), pool, bits)))
+		return
+	}
+
+	used := make([]netip.Prefix, 0, len(usedCIDRs))
+	for _, text := range usedCIDRs {
+		prefix, err := parseAddrOrCIDR(text)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+			return
+		}
+		used = append(used, prefix)
+	}
+
+	prefix, ok := nextAvailablePrefix(pool, int(bits), used)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("no free /%d block left in %s", bits, pool)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, prefix.String()))
+}
+
+// maxCIDRSplitCount bounds the size of the list cidr_split returns.
+const maxCIDRSplitCount = 65536
+
+// CIDR Split Function
+var _ function.Function = &CIDRSplitFunction{}
+
+type CIDRSplitFunction struct{}
+
+func NewCIDRSplitFunction() function.Function {
+	return &CIDRSplitFunction{}
+}
+
+func (f *CIDRSplitFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_split"
+}
+
+func (f *CIDRSplitFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Divides a CIDR block into equal subnets",
+		Description: "Returns count equal subnets covering the block in address order. The count must be a power of two " +
+			"unless round_up is set, in which case the first count subnets of the next power of two are returned.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cidr",
+				Description: "The CIDR block to split",
+			},
+			function.Int64Parameter{
+				Name:        "count",
+				Description: "The number of subnets, at least 1",
+			},
+			function.BoolParameter{
+				Name:        "round_up",
+				Description: "Whether a count that is not a power of two uses the next power of two, leaving the remainder unallocated",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *CIDRSplitFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidr string
+	var count int64
+	var roundUp bool
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &cidr, &count, &roundUp))
+	if resp.Error != nil {
+		return
+	}
+
+	prefix, err := parseCIDR(cidr)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if count < 1 || count > maxCIDRSplitCount {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("count must be between 1 and %d", maxCIDRSplitCount)))
+		return
+	}
+
+	newBits := bits.Len64(uint64(count - 1))
+	if !roundUp && count != 1<<newBits {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("count %d is not a power of two, set round_up to use %d subnets of the next size", count, int64(1)<<newBits)))
+		return
+	}
+	bitLen := prefix.Addr().BitLen()
+	if prefix.Bits()+newBits > bitLen {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("%s is too small to split into %d subnets", prefix, count)))
+		return
+	}
+
+	subnetBits := prefix.Bits() + newBits
+	size := prefixSize(subnetBits, bitLen)
+	next := addrToInt(prefix.Addr())
+	subnets := make([]string, count)
+	for i := range subnets {
+		subnets[i] = netip.PrefixFrom(intToAddr(next, bitLen), subnetBits).String()
+		next.Add(next, size)
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, subnets))
+}
+
+// rangeToPrefixes returns the fewest CIDR blocks that exactly cover the
+// addresses from first to last inclusive. Both must be of the same family
+// with first not after last.
+func rangeToPrefixes(first, last netip.Addr) []netip.Prefix {
+	bitLen := first.BitLen()
+	start, end := addrToInt(first), addrToInt(last)
+	one := big.NewInt(1)
+
+	var prefixes []netip.Prefix
+	for start.Cmp(end) <= 0 {
+		// Take the largest block that is aligned at start and does not
+		// extend past end.
+		hostBits := bitLen
+		if start.Sign() != 0 {
+			hostBits = int(start.TrailingZeroBits())
+		}
+		remaining := new(big.Int).Sub(end, start)
+		remaining.Add(remaining, one)
+		hostBits = min(hostBits, remaining.BitLen()-1)
+
+		prefixes = append(prefixes, netip.PrefixFrom(intToAddr(start, bitLen), bitLen-hostBits))
+		start = new(big.Int).Add(start, prefixSize(bitLen-hostBits, bitLen))
+	}
+	return prefixes
+}
+
+// Range To CIDRs Function
+var _ function.Function = &RangeToCIDRsFunction{}
+
+type RangeToCIDRsFunction struct{}
+
+func NewRangeToCIDRsFunction() function.Function {
+	return &RangeToCIDRsFunction{}
+}
+
+func (f *RangeToCIDRsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "range_to_cidrs"
+}
+
+func (f *RangeToCIDRsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts an IP address range to CIDR blocks",
+		Description: "Returns the shortest list of CIDR blocks that together cover exactly the addresses from first_ip to last_ip inclusive.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "first_ip",
+				Description: "The first address of the range",
+			},
+			function.StringParameter{
+				Name:        "last_ip",
+				Description: "The last address of the range, of the same family",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *RangeToCIDRsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var firstIP, lastIP string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &firstIP, &lastIP))
+	if resp.Error != nil {
+		return
+	}
+
+	first, err := netip.ParseAddr(firstIP)
+	if err != nil || first.Zone() != "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("invalid IP address %q", firstIP)))
+		return
+	}
+	last, err := netip.ParseAddr(lastIP)
+	if err != nil || last.Zone() != "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("invalid IP address %q", lastIP)))
+		return
+	}
+	first, last = first.Unmap(), last.Unmap()
+	if first.BitLen() != last.BitLen() {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("%s and %s are of different address families", first, last)))
+		return
+	}
+	if first.Compare(last) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("first_ip %s is after last_ip %s", first, last)))
+		return
+	}
+
+	prefixes := rangeToPrefixes(first, last)
+	cidrs := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		cidrs[i] = prefix.String()
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, cidrs))
+}
+
+// cidrInfo describes a CIDR block.
+type cidrInfo struct {
+	Network      string       `tfsdk:"network"`
+	FirstHost    string       `tfsdk:"first_host"`
+	LastHost     string       `tfsdk:"last_host"`
+	Broadcast    types.String `tfsdk:"broadcast"`
+	Netmask      string       `tfsdk:"netmask"`
+	PrefixLength int64        `tfsdk:"prefix_length"`
+	AddressCount *big.Float   `tfsdk:"address_count"`
+	UsableHosts  *big.Float   `tfsdk:"usable_hosts"`
+}
+
+var cidrInfoType = map[string]attr.Type{
+	"network":       types.StringType,
+	"first_host":    types.StringType,
+	"last_host":     types.StringType,
+	"broadcast":     types.StringType,
+	"netmask":       types.StringType,
+	"prefix_length": types.Int64Type,
+	"address_count": types.NumberType,
+	"usable_hosts":  types.NumberType,
+}
+
+// describeCIDR computes the addresses of a masked prefix. IPv4 blocks of /30
+// and larger reserve the network and broadcast addresses, /31 blocks have two
+// usable addresses as point-to-point links, and IPv6 has no broadcast.
+func describeCIDR(prefix netip.Prefix) cidrInfo {
+	bitLen := prefix.Addr().BitLen()
+	size := prefixSize(prefix.Bits(), bitLen)
+	network := addrToInt(prefix.Addr())
+	last := new(big.Int).Add(network, size)
+	last.Sub(last, big.NewInt(1))
+
+	info := cidrInfo{
+		Network:      prefix.Addr().String(),
+		FirstHost:    prefix.Addr().String(),
+		LastHost:     intToAddr(last, bitLen).String(),
+		Broadcast:    types.StringNull(),
+		Netmask:      prefixMask(prefix.Bits(), bitLen).String(),
+		PrefixLength: int64(prefix.Bits()),
+		AddressCount: new(big.Float).SetInt(size),
+		UsableHosts:  new(big.Float).SetInt(size),
+	}
+	if bitLen == 32 && prefix.Bits() <= 30 {
+		info.Broadcast = types.StringValue(info.LastHost)
+		info.FirstHost = intToAddr(new(big.Int).Add(network, big.NewInt(1)), bitLen).String()
+		info.LastHost = intToAddr(new(big.Int).Sub(last, big.NewInt(1)), bitLen).String()
+		info.UsableHosts = new(big.Float).SetInt(new(big.Int).Sub(size, big.NewInt(2)))
+	}
+	return info
+}
+
+// CIDR Info Function
+var _ function.Function = &CIDRInfoFunction{}
+
+type CIDRInfoFunction struct{}
+
+func NewCIDRInfoFunction() function.Function {
+	return &CIDRInfoFunction{}
+}
+
+func (f *CIDRInfoFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_info"
+}
+
+func (f *CIDRInfoFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Describes the addresses of a CIDR block",
+		Description: "Returns the network address, first and last usable host, broadcast address, netmask, prefix length, " +
+			"address count and usable host count of an IPv4 or IPv6 block. The broadcast address is null for IPv6, /31 and /32.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cidr",
+				Description: "The CIDR block to describe",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: cidrInfoType,
+		},
+	}
+}
+
+func (f *CIDRInfoFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidr string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &cidr))
+	if resp.Error != nil {
+		return
+	}
+
+	prefix, err := parseCIDR(cidr)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, describeCIDR(prefix)))
+}
+
+// wildcardMask returns the inverse of a netmask, as used by router ACLs.
+func wildcardMask(bits, bitLen int) netip.Addr {
+	mask := prefixSize(bits, bitLen)
+	return intToAddr(mask.Sub(mask, big.NewInt(1)), bitLen)
+}
+
+// netmaskPrefixLength returns the prefix length of a contiguous netmask.
+func netmaskPrefixLength(mask netip.Addr) (int, bool) {
+	for bits := 0; bits <= mask.BitLen(); bits++ {
+		if prefixMask(bits, mask.BitLen()) == mask {
+			return bits, true
+		}
+	}
+	return 0, false
+}
+
+// Prefix To Netmask Function
+var _ function.Function = &PrefixToNetmaskFunction{}
+
+type PrefixToNetmaskFunction struct{}
+
+func NewPrefixToNetmaskFunction() function.Function {
+	return &PrefixToNetmaskFunction{}
+}
+
+func (f *PrefixToNetmaskFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "prefix_to_netmask"
+}
+
+func (f *PrefixToNetmaskFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts an IPv4 prefix length to a dotted netmask",
+		Description: "Returns the dotted-decimal netmask for a prefix length between 0 and 32, such as 255.255.255.0 for 24.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "length",
+				Description: "The prefix length",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PrefixToNetmaskFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var length int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &length))
+	if resp.Error != nil {
+		return
+	}
+
+	if length < 0 || length > 32 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("length must be between 0 and 32, got %d", length)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, prefixMask(int(length), 32).String()))
+}
+
+// Prefix To Wildcard Function
+var _ function.Function = &PrefixToWildcardFunction{}
+
+type PrefixToWildcardFunction struct{}
+
+func NewPrefixToWildcardFunction() function.Function {
+	return &PrefixToWildcardFunction{}
+}
+
+func (f *PrefixToWildcardFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "prefix_to_wildcard"
+}
+
+func (f *PrefixToWildcardFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts an IPv4 prefix length to a Cisco wildcard mask",
+		Description: "Returns the inverted netmask used by router ACLs for a prefix length between 0 and 32, such as 0.0.0.255 for 24.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "length",
+				Description: "The prefix length",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PrefixToWildcardFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var length int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &length))
+	if resp.Error != nil {
+		return
+	}
+
+	if length < 0 || length > 32 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("length must be between 0 and 32, got %d", length)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, wildcardMask(int(length), 32).String()))
+}
+
+// CIDR To Wildcard Function
+var _ function.Function = &CIDRToWildcardFunction{}
+
+type CIDRToWildcardFunction struct{}
+
+func NewCIDRToWildcardFunction() function.Function {
+	return &CIDRToWildcardFunction{}
+}
+
+func (f *CIDRToWildcardFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_to_wildcard"
+}
+
+func (f *CIDRToWildcardFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts an IPv4 CIDR block to a Cisco wildcard mask",
+		Description: "Returns the inverted netmask used by router ACLs for an IPv4 CIDR block, such as 0.0.0.255 for 10.1.2.0/24.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cidr",
+				Description: "The IPv4 CIDR block",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *CIDRToWildcardFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidr string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &cidr))
+	if resp.Error != nil {
+		return
+	}
+
+	prefix, err := parseCIDR(cidr)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if !prefix.Addr().Is4() {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("wildcard masks are only defined for IPv4, got %s", cidr)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, wildcardMask(prefix.Bits(), 32).String()))
+}
+
+// Netmask To Prefix Function
+var _ function.Function = &NetmaskToPrefixFunction{}
+
+type NetmaskToPrefixFunction struct{}
+
+func NewNetmaskToPrefixFunction() function.Function {
+	return &NetmaskToPrefixFunction{}
+}
+
+func (f *NetmaskToPrefixFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "netmask_to_prefix"
+}
+
+func (f *NetmaskToPrefixFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a dotted IPv4 netmask to a prefix length",
+		Description: "Returns the prefix length of a netmask such as 255.255.255.0. Masks whose one bits are not contiguous are errors.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "mask",
+				Description: "The dotted-decimal netmask",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *NetmaskToPrefixFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	mask, err := netip.ParseAddr(text)
+	if err != nil || !mask.Is4() {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("invalid IPv4 netmask %q", text)))
+		return
+	}
+	bits, ok := netmaskPrefixLength(mask)
+	if !ok {
+		message := fmt.Sprintf("%s is not a valid netmask, its one bits must be contiguous", mask)
+		inverted := intToAddr(new(big.Int).Xor(addrToInt(mask), addrToInt(wildcardMask(0, 32))), 32)
+		if length, ok := netmaskPrefixLength(inverted); ok {
+			message = fmt.Sprintf("%s looks like a wildcard mask, the netmask for /%d is %s", mask, length, inverted)
+		}
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, message))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, int64(bits)))
+}
+
+// parseIPv6 parses an IPv6 address, which may have a zone. IPv4 addresses
+// are errors.
+func parseIPv6(text string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(text)
+	if err != nil || !addr.Is6() {
+		return netip.Addr{}, fmt.Errorf("invalid IPv6 address %q", text)
+	}
+	return addr, nil
+}
+
+// expandIPv6 writes an IPv6 address as eight groups of four lowercase hex
+// digits.
+func expandIPv6(addr netip.Addr) string {
+	raw := addr.As16()
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("%02x%02x", raw[2*i], raw[2*i+1])
+	}
+	expanded := strings.Join(groups, ":")
+	if zone := addr.Zone(); zone != "" {
+		expanded += "%" + zone
+	}
+	return expanded
+}
+
+// IPv6 Expand Function
+var _ function.Function = &IPv6ExpandFunction{}
+
+type IPv6ExpandFunction struct{}
+
+func NewIPv6ExpandFunction() function.Function {
+	return &IPv6ExpandFunction{}
+}
+
+func (f *IPv6ExpandFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ipv6_expand"
+}
+
+func (f *IPv6ExpandFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Writes an IPv6 address in full",
+		Description: "Returns the address as eight groups of four lowercase hex digits, such as 2001:0db8:0000:0000:0000:0000:0000:0001.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "address",
+				Description: "The IPv6 address",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *IPv6ExpandFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	addr, err := parseIPv6(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, expandIPv6(addr)))
+}
+
+// IPv6 Compress Function
+var _ function.Function = &IPv6CompressFunction{}
+
+type IPv6CompressFunction struct{}
+
+func NewIPv6CompressFunction() function.Function {
+	return &IPv6CompressFunction{}
+}
+
+func (f *IPv6CompressFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ipv6_compress"
+}
+
+func (f *IPv6CompressFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Writes an IPv6 address in canonical compressed form",
+		Description: "Returns the RFC 5952 form of the address: lowercase, without leading zeros and with the longest run " +
+			"of zero groups replaced by \"::\", so that the same address from different sources compares equal.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "address",
+				Description: "The IPv6 address",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *IPv6CompressFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	addr, err := parseIPv6(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, addr.String()))
+}
+
+// IPv6 Valid Function
+var _ function.Function = &IPv6ValidFunction{}
+
+type IPv6ValidFunction struct{}
+
+func NewIPv6ValidFunction() function.Function {
+	return &IPv6ValidFunction{}
+}
+
+func (f *IPv6ValidFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ipv6_valid"
+}
+
+func (f *IPv6ValidFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Checks whether a string is an IPv6 address",
+		Description: "Returns true for any valid IPv6 address in compressed or expanded form, including zoned addresses, and false otherwise.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "address",
+				Description: "The string to check",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *IPv6ValidFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	_, err := parseIPv6(text)
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, err == nil))
+}
+
+// ptrRecord is the reverse DNS name of an address split at a zone cut.
+type ptrRecord struct {
+	FQDN string `tfsdk:"fqdn"`
+	Zone string `tfsdk:"zone"`
+	Name string `tfsdk:"name"`
+}
+
+var ptrRecordType = map[string]attr.Type{
+	"fqdn": types.StringType,
+	"zone": types.StringType,
+	"name": types.StringType,
+}
+
+// reversePTR builds the in-addr.arpa or ip6.arpa name of addr, with the zone
+// made of the first zoneBits bits. The cut must fall on a label boundary:
+// octets for IPv4 and nibbles for IPv6.
+func reversePTR(addr netip.Addr, zoneBits int) (ptrRecord, error) {
+	var labels []string
+	suffix, labelBits := "in-addr.arpa", 8
+	if addr.Is4() {
+		for _, b := range addr.As4() {
+			labels = append(labels, fmt.Sprint(b))
+		}
+	} else {
+		suffix, labelBits = "ip6.arpa", 4
+		for _, b := range addr.As16() {
+			labels = append(labels, fmt.Sprintf("%x", b>>4), fmt.Sprintf("%x", b&0xf))
+		}
+	}
+	if zoneBits < 0 || zoneBits > addr.BitLen() || zoneBits%labelBits != 0 {
+		return ptrRecord{}, fmt.Errorf("zone prefix length must be a multiple of %d between 0 and %d for %s, got %d", labelBits, addr.BitLen(), addr, zoneBits)
+	}
+	slices.Reverse(labels)
+
+	cut := len(labels) - zoneBits/labelBits
+	record := ptrRecord{
+		FQDN: strings.Join(append(slices.Clone(labels), suffix), "."),
+		Zone: strings.Join(append(slices.Clone(labels[cut:]), suffix), "."),
+		Name: strings.Join(labels[:cut], "."),
+	}
+	return record, nil
+}
+
+// PTR Name Function
+var _ function.Function = &PTRNameFunction{}
+
+type PTRNameFunction struct{}
+
+func NewPTRNameFunction() function.Function {
+	return &PTRNameFunction{}
+}
+
+func (f *PTRNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ptr_name"
+}
+
+func (f *PTRNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds the reverse DNS name of an IP address",
+		Description: "Returns the in-addr.arpa or ip6.arpa name of an address as fqdn, split at the zone cut given by " +
+			"zone_prefix_length into the reverse zone and the record name within it. Names have no trailing dot.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "ip",
+				Description: "The IPv4 or IPv6 address",
+			},
+			function.Int64Parameter{
+				Name:        "zone_prefix_length",
+				Description: "The prefix length of the reverse zone, a multiple of 8 for IPv4 or 4 for IPv6, such as 24 or 48",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: ptrRecordType,
+		},
+	}
+}
+
+func (f *PTRNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var ip string
+	var zoneBits int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &ip, &zoneBits))
+	if resp.Error != nil {
+		return
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || addr.Zone() != "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("invalid IP address %q", ip)))
+		return
+	}
+
+	record, err := reversePTR(addr.Unmap(), int(zoneBits))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, record))
+}
+
+// macFormats maps the output formats of mac_normalize to their group size
+// in hex digits and separator.
+var macFormats = map[string]struct {
+	group     int
+	separator string
+}{
+	"colon": {2, ":"},
+	"dash":  {2, "-"},
+	"dot":   {4, "."},
+	"bare":  {12, ""},
+}
+
+// parseMAC parses a 48-bit MAC address written with colons, dashes, Cisco
+// style dots or no separators at all, in either case.
+func parseMAC(text string) ([6]byte, error) {
+	var mac [6]byte
+	digits := text
+	switch {
+	case len(text) == 17 && (strings.Count(text, ":") == 5 || strings.Count(text, "-") == 5):
+		sep := text[2:3]
+		for i := 2; i < len(text); i += 3 {
+			if text[i:i+1] != sep {
+				return mac, fmt.Errorf("invalid MAC address %q", text)
+			}
+		}
+		digits = strings.ReplaceAll(text, sep, "")
+	case len(text) == 14 && strings.Count(text, ".") == 2:
+		if text[4] != '.' || text[9] != '.' {
+			return mac, fmt.Errorf("invalid MAC address %q", text)
+		}
+		digits = strings.ReplaceAll(text, ".", "")
+	}
+	if len(digits) != 12 {
+		return mac, fmt.Errorf("invalid MAC address %q", text)
+	}
+	for i := range mac {
+		var b byte
+		for _, c := range []byte(digits[2*i : 2*i+2]) {
+			switch {
+			case c >= '0' && c <= '9':
+				c -= '0'
+			case c >= 'a' && c <= 'f':
+				c -= 'a' - 10
+			case c >= 'A' && c <= 'F':
+				c -= 'A' - 10
+			default:
+				return mac, fmt.Errorf("invalid MAC address %q", text)
+			}
+			b = b<<4 | c
+		}
+		mac[i] = b
+	}
+	return mac, nil
+}
+
+// formatMAC renders mac in lowercase using one of macFormats.
+func formatMAC(mac [6]byte, format string) string {
+	spec := macFormats[format]
+	digits := fmt.Sprintf("%x", mac[:])
+	groups := make([]string, 0, 6)
+	for i := 0; i < len(digits); i += spec.group {
+		groups = append(groups, digits[i:i+spec.group])
+	}
+	return strings.Join(groups, spec.separator)
+}
+
+// MAC Normalize Function
+var _ function.Function = &MACNormalizeFunction{}
+
+type MACNormalizeFunction struct{}
+
+func NewMACNormalizeFunction() function.Function {
+	return &MACNormalizeFunction{}
+}
+
+func (f *MACNormalizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "mac_normalize"
+}
+
+func (f *MACNormalizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Normalizes a MAC address",
+		Description: "Parses a 48-bit MAC address written with colons, dashes, Cisco style dots or no separators, in " +
+			"either case, and renders it in lowercase in the given format: colon, dash, dot or bare.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "mac",
+				Description: "The MAC address, such as 00-1A-2B-3C-4D-5E or 001a.2b3c.4d5e",
+			},
+			function.StringParameter{
+				Name:        "format",
+				Description: "The output format: colon (00:1a:2b:3c:4d:5e), dash (00-1a-2b-3c-4d-5e), dot (001a.2b3c.4d5e) or bare (001a2b3c4d5e)",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *MACNormalizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text, format string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text, &format))
+	if resp.Error != nil {
+		return
+	}
+
+	if _, ok := macFormats[format]; !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("unsupported format %q, expected one of: bare, colon, dash, dot", format)))
+		return
+	}
+	mac, err := parseMAC(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatMAC(mac, format)))
+}
+
+// MAC Valid Function
+var _ function.Function = &MACValidFunction{}
+
+type MACValidFunction struct{}
+
+func NewMACValidFunction() function.Function {
+	return &MACValidFunction{}
+}
+
+func (f *MACValidFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "mac_valid"
+}
+
+func (f *MACValidFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Checks whether a string is a valid MAC address",
+		Description: "Returns true if the string is a 48-bit MAC address in any of the formats accepted by mac_normalize.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "mac",
+				Description: "The string to check",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *MACValidFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	_, err := parseMAC(text)
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, err == nil))
+}
+
+// MAC To EUI-64 Function
+var _ function.Function = &MACToEUI64Function{}
+
+type MACToEUI64Function struct{}
+
+func NewMACToEUI64Function() function.Function {
+	return &MACToEUI64Function{}
+}
+
+func (f *MACToEUI64Function) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "mac_to_eui64"
+}
+
+func (f *MACToEUI64Function) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Derives an EUI-64 IPv6 address from a MAC address",
+		Description: "Builds the modified EUI-64 interface identifier of a MAC address (ff:fe inserted in the middle and the " +
+			"universal/local bit flipped) and combines it with an IPv6 prefix of length 64 or shorter, as used by SLAAC.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "mac",
+				Description: "The MAC address in any format accepted by mac_normalize",
+			},
+			function.StringParameter{
+				Name:        "prefix",
+				Description: "The IPv6 prefix, such as 2001:db8:1:2::/64",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *MACToEUI64Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text, cidr string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text, &cidr))
+	if resp.Error != nil {
+		return
+	}
+
+	mac, err := parseMAC(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	prefix, err := parseCIDR(cidr)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	if !prefix.Addr().Is6() || prefix.Bits() > 64 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("prefix must be an IPv6 prefix of length 64 or shorter, got %s", cidr)))
+		return
+	}
+
+	addr := prefix.Addr().As16()
+	copy(addr[8:], []byte{mac[0] ^ 0x02, mac[1], mac[2], 0xff, 0xfe, mac[3], mac[4], mac[5]})
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, netip.AddrFrom16(addr).String()))
+}
+
+// parseMACPrefix parses the leading bytes of a MAC address, such as an OUI,
+// written with colons, dashes or no separators. At least one byte must be
+// left for generated values.
+func parseMACPrefix(text string) ([]byte, error) {
+	digits := strings.NewReplacer(":", "", "-", "").Replace(text)
+	prefix, err := hex.DecodeString(digits)
+	if err != nil || len(prefix) > 5 {
+		return nil, fmt.Errorf("invalid MAC prefix %q, expected up to 5 hex bytes such as 52:54:00", text)
+	}
+	if len(prefix) > 0 && prefix[0]&0x01 != 0 {
+		return nil, fmt.Errorf("MAC prefix %q is a multicast prefix", text)
+	}
+	return prefix, nil
+}
+
+// MAC Generate Function
+var _ function.Function = &MACGenerateFunction{}
+
+type MACGenerateFunction struct{}
+
+func NewMACGenerateFunction() function.Function {
+	return &MACGenerateFunction{}
+}
+
+func (f *MACGenerateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "mac_generate"
+}
+
+func (f *MACGenerateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Generates a stable MAC address from a seed",
+		Description: "Derives a unicast MAC address deterministically from a seed. With an empty oui_prefix the address " +
+			"is locally administered; otherwise it starts with the given prefix and the remaining bytes are generated.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "seed",
+				Description: "The seed, such as the VM name; the same seed always gives the same address",
+			},
+			function.StringParameter{
+				Name:        "oui_prefix",
+				Description: "Up to 5 leading bytes, such as 52:54:00, or an empty string for a locally administered address",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *MACGenerateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var seed, ouiPrefix string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &seed, &ouiPrefix))
+	if resp.Error != nil {
+		return
+	}
+
+	prefix, err := parseMACPrefix(ouiPrefix)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	var mac [6]byte
+	v := newSeededRand(seed).uint64()
+	for i := range mac {
+		mac[i] = byte(v >> (8 * i))
+	}
+	if len(prefix) == 0 {
+		// Set the locally administered bit and clear the multicast bit.
+		mac[0] = mac[0]&^0x01 | 0x02
+	}
+	copy(mac[:], prefix)
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatMAC(mac, "colon")))
+}
+
+// mustPrefixes parses a fixed list of CIDR blocks.
+func mustPrefixes(cidrs ...string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, len(cidrs))
+	for i, cidr := range cidrs {
+		prefixes[i] = netip.MustParsePrefix(cidr)
+	}
+	return prefixes
+}
+
+var (
+	// privateRanges are the RFC 1918 blocks and IPv6 unique local addresses.
+	privateRanges = mustPrefixes("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7")
+
+	loopbackRanges = mustPrefixes("127.0.0.0/8", "::1/128")
+
+	// nonPublicRanges are the special-purpose blocks from the IANA registries
+	// that are not globally reachable.
+	nonPublicRanges = mustPrefixes(
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+		"192.0.0.0/24", "192.0.2.0/24", "192.168.0.0/16", "198.18.0.0/15", "198.51.100.0/24",
+		"203.0.113.0/24", "224.0.0.0/4", "240.0.0.0/4",
+		"::/128", "::1/128", "64:ff9b:1::/48", "100::/64", "2001:db8::/32", "3fff::/20", "fc00::/7",
+		"fe80::/10", "ff00::/8",
+	)
+)
+
+// withinRanges reports whether prefix lies entirely inside one of ranges.
+func withinRanges(prefix netip.Prefix, ranges []netip.Prefix) bool {
+	for _, r := range ranges {
+		if r.Bits() <= prefix.Bits() && r.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapsRanges reports whether any address of prefix is in one of ranges.
+func overlapsRanges(prefix netip.Prefix, ranges []netip.Prefix) bool {
+	for _, r := range ranges {
+		if r.Overlaps(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var ipClassParameters = []function.Parameter{
+	function.StringParameter{
+		Name:        "address",
+		Description: "The IP address or CIDR block to classify",
+	},
+}
+
+// runIPClass parses the address argument and sets the result of classify.
+func runIPClass(ctx context.Context, req function.RunRequest, resp *function.RunResponse, classify func(netip.Prefix) bool) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	prefix, err := parseAddrOrCIDR(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, classify(prefix)))
+}
+
+// IP Is Private Function
+var _ function.Function = &IPIsPrivateFunction{}
+
+type IPIsPrivateFunction struct{}
+
+func NewIPIsPrivateFunction() function.Function {
+	return &IPIsPrivateFunction{}
+}
+
+func (f *IPIsPrivateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ip_is_private"
+}
+
+func (f *IPIsPrivateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether an address is private",
+		Description: "Returns true if the address, or every address of the CIDR block, is in an RFC 1918 range " +
+			"(10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16) or the IPv6 unique local range fc00::/7.",
+		Parameters: ipClassParameters,
+		Return:     function.BoolReturn{},
+	}
+}
+
+func (f *IPIsPrivateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runIPClass(ctx, req, resp, func(prefix netip.Prefix) bool {
+		return withinRanges(prefix, privateRanges)
+	})
+}
+
+// IP Is Public Function
+var _ function.Function = &IPIsPublicFunction{}
+
+type IPIsPublicFunction struct{}
+
+func NewIPIsPublicFunction() function.Function {
+	return &IPIsPublicFunction{}
+}
+
+func (f *IPIsPublicFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ip_is_public"
+}
+
+func (f *IPIsPublicFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether an address is publicly routable",
+		Description: "Returns true if the address, or every address of the CIDR block, is globally reachable: not private, " +
+			"loopback, link-local, shared (CGNAT), documentation, benchmarking, multicast or reserved.",
+		Parameters: ipClassParameters,
+		Return:     function.BoolReturn{},
+	}
+}
+
+func (f *IPIsPublicFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runIPClass(ctx, req, resp, func(prefix netip.Prefix) bool {
+		return !overlapsRanges(prefix, nonPublicRanges)
+	})
+}
+
+// IP Is Loopback Function
+var _ function.Function = &IPIsLoopbackFunction{}
+
+type IPIsLoopbackFunction struct{}
+
+func NewIPIsLoopbackFunction() function.Function {
+	return &IPIsLoopbackFunction{}
+}
+
+func (f *IPIsLoopbackFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ip_is_loopback"
+}
+
+func (f *IPIsLoopbackFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Checks whether an address is a loopback address",
+		Description: "Returns true if the address, or every address of the CIDR block, is in 127.0.0.0/8 or is ::1.",
+		Parameters:  ipClassParameters,
+		Return:      function.BoolReturn{},
+	}
+}
+
+func (f *IPIsLoopbackFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runIPClass(ctx, req, resp, func(prefix netip.Prefix) bool {
+		return withinRanges(prefix, loopbackRanges)
+	})
+}
+
+// IP Version Function
+var _ function.Function = &IPVersionFunction{}
+
+type IPVersionFunction struct{}
+
+func NewIPVersionFunction() function.Function {
+	return &IPVersionFunction{}
+}
+
+func (f *IPVersionFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ip_version"
+}
+
+func (f *IPVersionFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns the IP version of an address",
+		Description: "Returns 4 or 6 for an IP address or CIDR block. IPv4-mapped IPv6 addresses are reported as 4.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "address",
+				Description: "The IP address or CIDR block",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *IPVersionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	prefix, err := parseAddrOrCIDR(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	version := int64(6)
+	if prefix.Addr().Is4() {
+		version = 4
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, version))
+}
+
+// CIDR Sort Function
+var _ function.Function = &CIDRSortFunction{}
+
+type CIDRSortFunction struct{}
+
+func NewCIDRSortFunction() function.Function {
+	return &CIDRSortFunction{}
+}
+
+func (f *CIDRSortFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_sort"
+}
+
+func (f *CIDRSortFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Canonicalizes, dedupes and sorts CIDR blocks",
+		Description: "Validates a list of CIDR blocks or addresses, zeroes host bits, removes duplicates and sorts the " +
+			"result numerically, IPv4 before IPv6 and shorter prefixes first for the same address. Addresses become /32 or /128 blocks.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "cidrs",
+				Description: "The CIDR blocks or addresses to sort",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *CIDRSortFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var texts []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &texts))
+	if resp.Error != nil {
+		return
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(texts))
+	for _, text := range texts {
+		prefix, err := parseAddrOrCIDR(text)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+			return
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return comparePrefixes(prefixes[i], prefixes[j]) < 0
+	})
+
+	cidrs := make([]string, 0, len(prefixes))
+	for i, prefix := range prefixes {
+		if i == 0 || prefix != prefixes[i-1] {
+			cidrs = append(cidrs, prefix.String())
+		}
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, cidrs))
+}
+
+// vlsmReport is the result of vlsm_allocate.
+type vlsmReport struct {
+	Allocations map[string]string `tfsdk:"allocations"`
+	FreeCIDRs   []string          `tfsdk:"free_cidrs"`
+	Utilization *big.Float        `tfsdk:"utilization_percent"`
+}
+
+var vlsmReportType = map[string]attr.Type{
+	"allocations":         types.MapType{ElemType: types.StringType},
+	"free_cidrs":          types.ListType{ElemType: types.StringType},
+	"utilization_percent": types.NumberType,
+}
+
+// VLSM Allocate Function
+var _ function.Function = &VLSMAllocateFunction{}
+
+type VLSMAllocateFunction struct{}
+
+func NewVLSMAllocateFunction() function.Function {
+	return &VLSMAllocateFunction{}
+}
+
+func (f *VLSMAllocateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "vlsm_allocate"
+}
+
+func (f *VLSMAllocateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Allocates named subnets and reports the remaining space",
+		Description: "Packs sized subnet requests into the base block like cidr_plan and returns an object with the " +
+			"allocations, the remaining free space as a minimal list of CIDR blocks, and the percentage of the block allocated.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "base_cidr",
+				Description: "The IPv4 or IPv6 block to allocate from",
+			},
+			function.DynamicParameter{
+				Name:        "requests",
+				Description: "A map of subnet names to a prefix length such as 24, or an object such as { hosts = 100 }",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: vlsmReportType,
+		},
+	}
+}
+
+func (f *VLSMAllocateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var baseCIDR string
+	var requestsValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &baseCIDR, &requestsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	base, err := parseCIDR(baseCIDR)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	bitLen := base.Addr().BitLen()
+
+	requests, err := subnetRequests(ctx, requestsValue, bitLen)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	plan, err := planSubnets(base, requests)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	report := vlsmReport{
+		Allocations: make(map[string]string, len(plan)),
+		FreeCIDRs:   []string{},
+	}
+	allocated := new(big.Int)
+	for name, prefix := range plan {
+		report.Allocations[name] = prefix.String()
+		allocated.Add(allocated, prefixSize(prefix.Bits(), bitLen))
+	}
+
+	// planSubnets packs from the start of the block, so the free space is
+	// everything after the allocated addresses.
+	total := prefixSize(base.Bits(), bitLen)
+	if allocated.Cmp(total) < 0 {
+		first := new(big.Int).Add(addrToInt(base.Addr()), allocated)
+		last := new(big.Int).Add(addrToInt(base.Addr()), total)
+		last.Sub(last, big.NewInt(1))
+		for _, prefix := range rangeToPrefixes(intToAddr(first, bitLen), intToAddr(last, bitLen)) {
+			report.FreeCIDRs = append(report.FreeCIDRs, prefix.String())
+		}
+	}
+
+	// Round the utilization to two decimal places.
+	percent := new(big.Rat).SetFrac(new(big.Int).Mul(allocated, big.NewInt(100)), total)
+	report.Utilization, _ = new(big.Float).SetString(percent.FloatString(2))
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, report))
+}
+
+// noProxyEntry parses one NO_PROXY entry into either an address block or a
+// domain. URLs are reduced to their host, and ports and leading "*." or "."
+// wildcards are dropped. Names ending in a numeric label are rejected as
+// malformed addresses.
+func noProxyEntry(entry string) (netip.Prefix, string, error) {
+	if prefix, err := parseAddrOrCIDR(entry); err == nil {
+		return prefix, "", nil
+	}
+
+	host := entry
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host, _, _ = strings.Cut(rest, "/")
+	}
+	if inner, ok := strings.CutPrefix(host, "["); ok {
+		host, _, _ = strings.Cut(inner, "]")
+	} else if strings.Count(host, ":") == 1 {
+		host, _, _ = strings.Cut(host, ":")
+	}
+	if prefix, err := parseAddrOrCIDR(host); err == nil {
+		return prefix, "", nil
+	}
+
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "*"), ".")
+	ascii, err := convertIDN(host, true)
+	if err == nil {
+		host, err = validateHostname(ascii, "hostname")
+	}
+	if err != nil || strings.Trim(host[strings.LastIndex(host, ".")+1:], "0123456789") == "" {
+		return netip.Prefix{}, "", fmt.Errorf("invalid NO_PROXY entry %q", entry)
+	}
+	return netip.Prefix{}, host, nil
+}
+
+// normalizeNoProxy builds a NO_PROXY value from entries that may themselves
+// be comma or space separated lists. Domains are written without a leading
+// dot, which Go, Python and curl all treat as matching the domain and every
+// subdomain, and domains covered by a parent domain are dropped. Address
+// blocks are collapsed and single addresses are written without a prefix
+// length. A "*" entry disables the proxy for every host.
+func normalizeNoProxy(entries []string) (string, error) {
+	var prefixes []netip.Prefix
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		for _, field := range strings.FieldsFunc(strings.ToLower(entry), func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n'
+		}) {
+			if field == "*" {
+				return "*", nil
+			}
+			prefix, domain, err := noProxyEntry(field)
+			if err != nil {
+				return "", err
+			}
+			if domain != "" {
+				seen[domain] = true
+			} else {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+
+	var values []string
+	for _, domain := range sortedKeys(seen) {
+		covered := false
+		for parent := domain; !covered; {
+			_, rest, ok := strings.Cut(parent, ".")
+			if !ok {
+				break
+			}
+			parent, covered = rest, seen[rest]
+		}
+		if !covered {
+			values = append(values, domain)
+		}
+	}
+	for _, prefix := range collapsePrefixes(prefixes) {
+		if prefix.IsSingleIP() {
+			values = append(values, prefix.Addr().String())
+		} else {
+			values = append(values, prefix.String())
+		}
+	}
+	return strings.Join(values, ","), nil
+}
+
+// No Proxy Normalize Function
+var _ function.Function = &NoProxyNormalizeFunction{}
+
+type NoProxyNormalizeFunction struct{}
+
+func NewNoProxyNormalizeFunction() function.Function {
+	return &NoProxyNormalizeFunction{}
+}
+
+func (f *NoProxyNormalizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "no_proxy_normalize"
+}
+
+func (f *NoProxyNormalizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds a normalized NO_PROXY value",
+		Description: "Merges hosts, domains, URLs, addresses and CIDR blocks into a deduplicated, comma separated NO_PROXY " +
+			"value: lowercase domains without leading dots or wildcards, ports removed, address blocks collapsed. Domains " +
+			"come first in alphabetical order, then addresses. Go and curl match the CIDR blocks; Python's urllib only " +
+			"matches hosts and exact addresses, and JVM clients read http.nonProxyHosts rather than NO_PROXY.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "entries",
+				Description: "The entries, each of which may itself be a comma separated list",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *NoProxyNormalizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var entries []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &entries))
+	if resp.Error != nil {
+		return
+	}
+
+	noProxy, err := normalizeNoProxy(entries)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, noProxy))
+}
@@ -0,0 +1,817 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCIDRPlan(t *testing.T) {
+	tests := []struct {
+		base     string
+		subnets  string
+		expected string
+	}{
+		{
+			"10.0.0.0/16",
+			`{"public-a": 24, "public-b": 24, "private-a": 20, "private-b": 20, "db": {"hosts": 20}}`,
+			`{"db":"10.0.34.0/27","private-a":"10.0.0.0/20","private-b":"10.0.16.0/20","public-a":"10.0.32.0/24","public-b":"10.0.33.0/24"}`,
+		},
+		{
+			"10.1.2.3/24",
+			`{"a": {"hosts": 62}, "b": {"hosts": 63}, "c": {"hosts": 10, "reserved": 5}}`,
+			`{"a":"10.1.2.128/26","b":"10.1.2.0/25","c":"10.1.2.192/28"}`,
+		},
+		{
+			"2001:db8::/56",
+			`{"app": 64, "db": {"hosts": 65536}}`,
+			`{"app":"2001:db8::/64","db":"2001:db8:0:1::/112"}`,
+		},
+		{"192.168.0.0/24", `{"all": 24}`, `{"all":"192.168.0.0/24"}`},
+		{"192.168.0.0/24", `{}`, `{}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewCIDRPlanFunction(), types.StringValue(tt.base), dynamicOf(t, mustJSON(t, tt.subnets)))
+		if err != nil {
+			t.Fatalf("unexpected error for %s %s: %s", tt.base, tt.subnets, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s %s:\nexpected %s\ngot      %s", tt.base, tt.subnets, tt.expected, got)
+		}
+	}
+
+	errorCases := []struct {
+		base    string
+		subnets string
+	}{
+		{"10.0.0.0/24", `{"a": 25, "b": 25, "c": 26}`},
+		{"10.0.0.0/24", `{"a": 23}`},
+		{"10.0.0.0/24", `{"a": 33}`},
+		{"10.0.0.0/24", `{"a": {"hosts": 0}}`},
+		{"10.0.0.0/24", `{"a": {"host": 10}}`},
+		{"10.0.0.0/24", `{"a": "24"}`},
+		{"10.0.0.0/24", `[24]`},
+		{"10.0.0.0", `{"a": 24}`},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewCIDRPlanFunction(), types.StringValue(tt.base), dynamicOf(t, mustJSON(t, tt.subnets))); err == nil {
+			t.Errorf("expected error for %s %s", tt.base, tt.subnets)
+		}
+	}
+}
+
+func TestCIDRContains(t *testing.T) {
+	tests := []struct {
+		cidr     string
+		target   string
+		expected bool
+	}{
+		{"10.0.0.0/16", "10.0.12.7", true},
+		{"10.0.0.0/16", "10.1.0.1", false},
+		{"10.0.0.0/16", "10.0.128.0/17", true},
+		{"10.0.0.0/16", "10.0.0.0/16", true},
+		{"10.0.0.0/16", "10.0.0.0/15", false},
+		{"10.0.0.5/16", "10.0.255.255", true},
+		{"0.0.0.0/0", "203.0.113.9", true},
+		{"2001:db8::/32", "2001:db8:1::1", true},
+		{"2001:db8::/32", "2001:db9::/48", false},
+		{"10.0.0.0/8", "::ffff:10.1.2.3", true},
+		{"::/0", "10.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewCIDRContainsFunction(), types.StringValue(tt.cidr), types.StringValue(tt.target))
+		if err != nil {
+			t.Fatalf("unexpected error for %s %s: %s", tt.cidr, tt.target, err)
+		}
+		if !result.Equal(types.BoolValue(tt.expected)) {
+			t.Errorf("cidr_contains(%s, %s): expected %t, got %s", tt.cidr, tt.target, tt.expected, result)
+		}
+	}
+
+	for _, args := range [][2]string{{"10.0.0.1", "10.0.0.1"}, {"10.0.0.0/8", "10.0.0"}, {"10.0.0.0/8", "fe80::1%eth0"}} {
+		if _, err := runFunction(t, NewCIDRContainsFunction(), types.StringValue(args[0]), types.StringValue(args[1])); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestCIDROverlaps(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"10.0.0.0/16", "10.0.128.0/20", true},
+		{"10.0.128.0/20", "10.0.0.0/16", true},
+		{"10.0.0.0/17", "10.0.128.0/17", false},
+		{"10.0.0.0/24", "10.0.0.255", true},
+		{"192.168.1.1", "192.168.1.1", true},
+		{"2001:db8::/48", "2001:db8:0:ff::/64", true},
+		{"2001:db8::/48", "2001:db8:1::/48", false},
+		{"0.0.0.0/0", "::/0", false},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewCIDROverlapsFunction(), types.StringValue(tt.a), types.StringValue(tt.b))
+		if err != nil {
+			t.Fatalf("unexpected error for %s %s: %s", tt.a, tt.b, err)
+		}
+		if !result.Equal(types.BoolValue(tt.expected)) {
+			t.Errorf("cidr_overlaps(%s, %s): expected %t, got %s", tt.a, tt.b, tt.expected, result)
+		}
+	}
+
+	if _, err := runFunction(t, NewCIDROverlapsFunction(), types.StringValue("10.0.0.0/33"), types.StringValue("10.0.0.0/8")); err == nil {
+		t.Error("expected error for invalid CIDR")
+	}
+}
+
+func TestNextAvailableCIDR(t *testing.T) {
+	tests := []struct {
+		pool     string
+		bits     int64
+		used     []string
+		expected string
+	}{
+		{"10.0.0.0/16", 24, nil, "10.0.0.0/24"},
+		{"10.0.0.0/16", 24, []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.3.0/24"}, "10.0.2.0/24"},
+		{"10.0.0.0/16", 23, []string{"10.0.0.0/24", "10.0.3.0/24"}, "10.0.4.0/23"},
+		{"10.0.0.0/16", 24, []string{"10.0.0.0/26", "10.0.0.64/26"}, "10.0.1.0/24"},
+		{"10.0.0.0/16", 26, []string{"10.0.0.0/26", "10.0.0.128/25"}, "10.0.0.64/26"},
+		{"10.0.0.0/16", 24, []string{"172.16.0.0/12", "2001:db8::/32", "10.0.0.7"}, "10.0.1.0/24"},
+		{"10.0.0.0/16", 24, []string{"10.0.1.0/24", "10.0.1.0/25", "10.0.0.5"}, "10.0.2.0/24"},
+		{"2001:db8::/56", 64, []string{"2001:db8::/64", "2001:db8:0:1::/64"}, "2001:db8:0:2::/64"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewNextAvailableCIDRFunction(), types.StringValue(tt.pool), types.Int64Value(tt.bits), stringList(tt.used...))
+		if err != nil {
+			t.Fatalf("unexpected error for %s /%d %v: %s", tt.pool, tt.bits, tt.used, err)
+		}
+		if !result.Equal(types.StringValue(tt.expected)) {
+			t.Errorf("%s /%d %v: expected %s, got %s", tt.pool, tt.bits, tt.used, tt.expected, result)
+		}
+	}
+
+	errorCases := []struct {
+		pool string
+		bits int64
+		used []string
+	}{
+		{"10.0.0.0/24", 25, []string{"10.0.0.0/25", "10.0.0.200"}},
+		{"10.0.0.0/24", 28, []string{"10.0.0.0/8"}},
+		{"10.0.0.0/24", 23, nil},
+		{"10.0.0.0/24", 33, nil},
+		{"10.0.0.0/24", 26, []string{"bogus"}},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewNextAvailableCIDRFunction(), types.StringValue(tt.pool), types.Int64Value(tt.bits), stringList(tt.used...)); err == nil {
+			t.Errorf("expected error for %s /%d %v", tt.pool, tt.bits, tt.used)
+		}
+	}
+}
+
+func TestCIDRSplit(t *testing.T) {
+	tests := []struct {
+		cidr     string
+		count    int64
+		roundUp  bool
+		expected []string
+	}{
+		{"10.0.0.0/16", 4, false, []string{"10.0.0.0/18", "10.0.64.0/18", "10.0.128.0/18", "10.0.192.0/18"}},
+		{"10.0.0.0/16", 1, false, []string{"10.0.0.0/16"}},
+		{"10.0.0.0/16", 3, true, []string{"10.0.0.0/18", "10.0.64.0/18", "10.0.128.0/18"}},
+		{"10.0.0.0/16", 5, true, []string{"10.0.0.0/19", "10.0.32.0/19", "10.0.64.0/19", "10.0.96.0/19", "10.0.128.0/19"}},
+		{"192.168.1.7/30", 4, false, []string{"192.168.1.4/32", "192.168.1.5/32", "192.168.1.6/32", "192.168.1.7/32"}},
+		{"2001:db8::/48", 2, false, []string{"2001:db8::/49", "2001:db8:0:8000::/49"}},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewCIDRSplitFunction(), types.StringValue(tt.cidr), types.Int64Value(tt.count), types.BoolValue(tt.roundUp))
+		if err != nil {
+			t.Fatalf("unexpected error for %s %d: %s", tt.cidr, tt.count, err)
+		}
+		if !result.Equal(stringList(tt.expected...)) {
+			t.Errorf("%s %d: expected %v, got %s", tt.cidr, tt.count, tt.expected, result)
+		}
+	}
+
+	errorCases := []struct {
+		cidr    string
+		count   int64
+		roundUp bool
+	}{
+		{"10.0.0.0/16", 3, false},
+		{"10.0.0.0/16", 0, true},
+		{"10.0.0.0/31", 3, true},
+		{"10.0.0.0/8", 1 << 20, false},
+		{"10.0.0.0", 2, false},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewCIDRSplitFunction(), types.StringValue(tt.cidr), types.Int64Value(tt.count), types.BoolValue(tt.roundUp)); err == nil {
+			t.Errorf("expected error for %s %d %t", tt.cidr, tt.count, tt.roundUp)
+		}
+	}
+}
+
+func TestRangeToCIDRs(t *testing.T) {
+	tests := []struct {
+		first, last string
+		expected    []string
+	}{
+		{"10.0.0.0", "10.0.0.255", []string{"10.0.0.0/24"}},
+		{"10.0.0.5", "10.0.0.5", []string{"10.0.0.5/32"}},
+		{"192.168.1.10", "192.168.1.20", []string{"192.168.1.10/31", "192.168.1.12/30", "192.168.1.16/30", "192.168.1.20/32"}},
+		{"10.0.0.0", "10.0.2.255", []string{"10.0.0.0/23", "10.0.2.0/24"}},
+		{"0.0.0.0", "255.255.255.255", []string{"0.0.0.0/0"}},
+		{"255.255.255.254", "255.255.255.255", []string{"255.255.255.254/31"}},
+		{"::", "::3", []string{"::/126"}},
+		{"2001:db8::1", "2001:db8::4", []string{"2001:db8::1/128", "2001:db8::2/127", "2001:db8::4/128"}},
+		{"::ffff:10.0.0.0", "10.0.0.1", []string{"10.0.0.0/31"}},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewRangeToCIDRsFunction(), types.StringValue(tt.first), types.StringValue(tt.last))
+		if err != nil {
+			t.Fatalf("unexpected error for %s-%s: %s", tt.first, tt.last, err)
+		}
+		if !result.Equal(stringList(tt.expected...)) {
+			t.Errorf("%s-%s: expected %v, got %s", tt.first, tt.last, tt.expected, result)
+		}
+	}
+
+	for _, args := range [][2]string{{"10.0.0.2", "10.0.0.1"}, {"10.0.0.1", "::1"}, {"10.0.0.1", "10.0.0"}, {"10.0.0.0/24", "10.0.0.255"}} {
+		if _, err := runFunction(t, NewRangeToCIDRsFunction(), types.StringValue(args[0]), types.StringValue(args[1])); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestCIDRInfo(t *testing.T) {
+	tests := []struct {
+		cidr     string
+		expected string
+	}{
+		{"10.0.1.0/24", `{"address_count":256,"broadcast":"10.0.1.255","first_host":"10.0.1.1","last_host":"10.0.1.254","netmask":"255.255.255.0","network":"10.0.1.0","prefix_length":24,"usable_hosts":254}`},
+		{"172.16.5.9/20", `{"address_count":4096,"broadcast":"172.16.15.255","first_host":"172.16.0.1","last_host":"172.16.15.254","netmask":"255.255.240.0","network":"172.16.0.0","prefix_length":20,"usable_hosts":4094}`},
+		{"192.168.0.0/31", `{"address_count":2,"broadcast":null,"first_host":"192.168.0.0","last_host":"192.168.0.1","netmask":"255.255.255.254","network":"192.168.0.0","prefix_length":31,"usable_hosts":2}`},
+		{"192.168.0.7/32", `{"address_count":1,"broadcast":null,"first_host":"192.168.0.7","last_host":"192.168.0.7","netmask":"255.255.255.255","network":"192.168.0.7","prefix_length":32,"usable_hosts":1}`},
+		{"0.0.0.0/0", `{"address_count":4294967296,"broadcast":"255.255.255.255","first_host":"0.0.0.1","last_host":"255.255.255.254","netmask":"0.0.0.0","network":"0.0.0.0","prefix_length":0,"usable_hosts":4294967294}`},
+		{"2001:db8::/64", `{"address_count":18446744073709551616,"broadcast":null,"first_host":"2001:db8::","last_host":"2001:db8::ffff:ffff:ffff:ffff","netmask":"ffff:ffff:ffff:ffff::","network":"2001:db8::","prefix_length":64,"usable_hosts":18446744073709551616}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewCIDRInfoFunction(), types.StringValue(tt.cidr))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.cidr, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s:\nexpected %s\ngot      %s", tt.cidr, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewCIDRInfoFunction(), types.StringValue("10.0.0.1")); err == nil {
+		t.Error("expected error for an address without a prefix length")
+	}
+}
+
+func TestNetmaskConversions(t *testing.T) {
+	tests := []struct {
+		length   int64
+		netmask  string
+		wildcard string
+	}{
+		{0, "0.0.0.0", "255.255.255.255"},
+		{8, "255.0.0.0", "0.255.255.255"},
+		{20, "255.255.240.0", "0.0.15.255"},
+		{24, "255.255.255.0", "0.0.0.255"},
+		{31, "255.255.255.254", "0.0.0.1"},
+		{32, "255.255.255.255", "0.0.0.0"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewPrefixToNetmaskFunction(), types.Int64Value(tt.length))
+		if err != nil || !result.Equal(types.StringValue(tt.netmask)) {
+			t.Errorf("prefix_to_netmask(%d): expected %s, got %v %v", tt.length, tt.netmask, result, err)
+		}
+		result, err = runFunction(t, NewPrefixToWildcardFunction(), types.Int64Value(tt.length))
+		if err != nil || !result.Equal(types.StringValue(tt.wildcard)) {
+			t.Errorf("prefix_to_wildcard(%d): expected %s, got %v %v", tt.length, tt.wildcard, result, err)
+		}
+		result, err = runFunction(t, NewNetmaskToPrefixFunction(), types.StringValue(tt.netmask))
+		if err != nil || !result.Equal(types.Int64Value(tt.length)) {
+			t.Errorf("netmask_to_prefix(%s): expected %d, got %v %v", tt.netmask, tt.length, result, err)
+		}
+	}
+
+	for _, length := range []int64{-1, 33} {
+		if _, err := runFunction(t, NewPrefixToNetmaskFunction(), types.Int64Value(length)); err == nil {
+			t.Errorf("expected prefix_to_netmask error for %d", length)
+		}
+		if _, err := runFunction(t, NewPrefixToWildcardFunction(), types.Int64Value(length)); err == nil {
+			t.Errorf("expected prefix_to_wildcard error for %d", length)
+		}
+	}
+
+	errorCases := []struct {
+		mask    string
+		message string
+	}{
+		{"255.255.0.255", "contiguous"},
+		{"0.0.0.255", "wildcard mask, the netmask for /24 is 255.255.255.0"},
+		{"ffff::", "invalid IPv4 netmask"},
+		{"255.255.255", "invalid IPv4 netmask"},
+	}
+	for _, tt := range errorCases {
+		_, err := runFunction(t, NewNetmaskToPrefixFunction(), types.StringValue(tt.mask))
+		if err == nil {
+			t.Errorf("expected netmask_to_prefix error for %s", tt.mask)
+		} else if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: expected %q in error, got %s", tt.mask, tt.message, err)
+		}
+	}
+}
+
+func TestCIDRToWildcard(t *testing.T) {
+	tests := map[string]string{
+		"10.1.2.0/24":         "0.0.0.255",
+		"10.1.0.0/16":         "0.0.255.255",
+		"172.16.5.4/20":       "0.0.15.255",
+		"192.0.2.1/32":        "0.0.0.0",
+		"0.0.0.0/0":           "255.255.255.255",
+		"::ffff:10.0.0.0/104": "0.255.255.255",
+	}
+
+	for cidr, want := range tests {
+		result, err := runFunction(t, NewCIDRToWildcardFunction(), types.StringValue(cidr))
+		if err != nil || !result.Equal(types.StringValue(want)) {
+			t.Errorf("cidr_to_wildcard(%s): expected %s, got %v %v", cidr, want, result, err)
+		}
+	}
+
+	for _, cidr := range []string{"2001:db8::/32", "10.0.0.1", "10.0.0.0/33"} {
+		if _, err := runFunction(t, NewCIDRToWildcardFunction(), types.StringValue(cidr)); err == nil {
+			t.Errorf("expected error for %s", cidr)
+		}
+	}
+}
+
+func TestIPv6Canonicalization(t *testing.T) {
+	tests := []struct {
+		input      string
+		expanded   string
+		compressed string
+	}{
+		{"2001:db8::1", "2001:0db8:0000:0000:0000:0000:0000:0001", "2001:db8::1"},
+		{"2001:0DB8:0000:0000:0000:0000:0000:0001", "2001:0db8:0000:0000:0000:0000:0000:0001", "2001:db8::1"},
+		{"2001:db8:0:0:1:0:0:1", "2001:0db8:0000:0000:0001:0000:0000:0001", "2001:db8::1:0:0:1"},
+		{"2001:db8:0:1:1:1:1:1", "2001:0db8:0000:0001:0001:0001:0001:0001", "2001:db8:0:1:1:1:1:1"},
+		{"::", "0000:0000:0000:0000:0000:0000:0000:0000", "::"},
+		{"::ffff:192.0.2.1", "0000:0000:0000:0000:0000:ffff:c000:0201", "::ffff:192.0.2.1"},
+		{"fe80::0001%eth0", "fe80:0000:0000:0000:0000:0000:0000:0001%eth0", "fe80::1%eth0"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewIPv6ExpandFunction(), types.StringValue(tt.input))
+		if err != nil || !result.Equal(types.StringValue(tt.expanded)) {
+			t.Errorf("ipv6_expand(%s): expected %s, got %v %v", tt.input, tt.expanded, result, err)
+		}
+		result, err = runFunction(t, NewIPv6CompressFunction(), types.StringValue(tt.input))
+		if err != nil || !result.Equal(types.StringValue(tt.compressed)) {
+			t.Errorf("ipv6_compress(%s): expected %s, got %v %v", tt.input, tt.compressed, result, err)
+		}
+		result, err = runFunction(t, NewIPv6ValidFunction(), types.StringValue(tt.input))
+		if err != nil || !result.Equal(types.BoolValue(true)) {
+			t.Errorf("ipv6_valid(%s): expected true, got %v %v", tt.input, result, err)
+		}
+	}
+
+	for _, input := range []string{"192.0.2.1", "2001:db8::1::2", "2001:db8::g", "2001:db8::/32", "", "[::1]"} {
+		if _, err := runFunction(t, NewIPv6ExpandFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("expected ipv6_expand error for %q", input)
+		}
+		if _, err := runFunction(t, NewIPv6CompressFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("expected ipv6_compress error for %q", input)
+		}
+		result, err := runFunction(t, NewIPv6ValidFunction(), types.StringValue(input))
+		if err != nil || !result.Equal(types.BoolValue(false)) {
+			t.Errorf("ipv6_valid(%q): expected false, got %v %v", input, result, err)
+		}
+	}
+}
+
+func TestPTRName(t *testing.T) {
+	tests := []struct {
+		ip       string
+		zoneBits int64
+		expected string
+	}{
+		{"192.0.2.10", 24, `{"fqdn":"10.2.0.192.in-addr.arpa","name":"10","zone":"2.0.192.in-addr.arpa"}`},
+		{"10.1.2.3", 16, `{"fqdn":"3.2.1.10.in-addr.arpa","name":"3.2","zone":"1.10.in-addr.arpa"}`},
+		{"10.1.2.3", 0, `{"fqdn":"3.2.1.10.in-addr.arpa","name":"3.2.1.10","zone":"in-addr.arpa"}`},
+		{"10.1.2.3", 32, `{"fqdn":"3.2.1.10.in-addr.arpa","name":"","zone":"3.2.1.10.in-addr.arpa"}`},
+		{"::ffff:10.1.2.3", 8, `{"fqdn":"3.2.1.10.in-addr.arpa","name":"3.2.1","zone":"10.in-addr.arpa"}`},
+		{
+			"2001:db8::567:89ab", 48,
+			`{"fqdn":"b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa","name":"b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0","zone":"0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"}`,
+		},
+		{
+			"2001:db8::1", 36,
+			`{"fqdn":"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa","name":"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0","zone":"0.8.b.d.0.1.0.0.2.ip6.arpa"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewPTRNameFunction(), types.StringValue(tt.ip), types.Int64Value(tt.zoneBits))
+		if err != nil {
+			t.Fatalf("unexpected error for %s/%d: %s", tt.ip, tt.zoneBits, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s/%d:\nexpected %s\ngot      %s", tt.ip, tt.zoneBits, tt.expected, got)
+		}
+	}
+
+	errorCases := []struct {
+		ip       string
+		zoneBits int64
+	}{
+		{"192.0.2.10", 26},
+		{"192.0.2.10", 40},
+		{"2001:db8::1", 50},
+		{"2001:db8::1", -4},
+		{"192.0.2.0/24", 24},
+		{"fe80::1%eth0", 64},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewPTRNameFunction(), types.StringValue(tt.ip), types.Int64Value(tt.zoneBits)); err == nil {
+			t.Errorf("expected error for %s/%d", tt.ip, tt.zoneBits)
+		}
+	}
+}
+
+func TestMACNormalize(t *testing.T) {
+	inputs := []string{
+		"00:1A:2B:3C:4D:5E",
+		"00-1a-2b-3c-4d-5e",
+		"001a.2b3c.4D5E",
+		"001A2B3C4D5E",
+	}
+	expected := map[string]string{
+		"colon": "00:1a:2b:3c:4d:5e",
+		"dash":  "00-1a-2b-3c-4d-5e",
+		"dot":   "001a.2b3c.4d5e",
+		"bare":  "001a2b3c4d5e",
+	}
+
+	for _, input := range inputs {
+		for format, want := range expected {
+			result, err := runFunction(t, NewMACNormalizeFunction(), types.StringValue(input), types.StringValue(format))
+			if err != nil {
+				t.Fatalf("unexpected error for %s/%s: %s", input, format, err)
+			}
+			if got := result.(types.String).ValueString(); got != want {
+				t.Errorf("%s/%s: expected %s, got %s", input, format, want, got)
+			}
+		}
+	}
+
+	if _, err := runFunction(t, NewMACNormalizeFunction(), types.StringValue(inputs[0]), types.StringValue("upper")); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if _, err := runFunction(t, NewMACNormalizeFunction(), types.StringValue("00:1a:2b"), types.StringValue("colon")); err == nil {
+		t.Error("expected error for invalid MAC")
+	}
+}
+
+func TestMACValid(t *testing.T) {
+	tests := map[string]bool{
+		"00:1a:2b:3c:4d:5e":       true,
+		"00-1A-2B-3C-4D-5E":       true,
+		"001a.2b3c.4d5e":          true,
+		"001a2b3c4d5e":            true,
+		"00:1a-2b:3c:4d:5e":       false,
+		"00:1a:2b:3c:4d:5g":       false,
+		"001a2b3c4d5":             false,
+		"00:1a:2b:3c:4d:5e:6f:70": false,
+		"0:1a:2b:3c:4d:5e":        false,
+		"001.a2b3c.4d5e":          false,
+		"":                        false,
+	}
+
+	for input, want := range tests {
+		result, err := runFunction(t, NewMACValidFunction(), types.StringValue(input))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", input, err)
+		}
+		if got := result.(types.Bool).ValueBool(); got != want {
+			t.Errorf("%q: expected %v, got %v", input, want, got)
+		}
+	}
+}
+
+func TestMACToEUI64(t *testing.T) {
+	tests := []struct {
+		mac, prefix, expected string
+	}{
+		{"00:1a:2b:3c:4d:5e", "2001:db8:1:2::/64", "2001:db8:1:2:21a:2bff:fe3c:4d5e"},
+		{"02-00-00-00-00-01", "fd00::/48", "fd00::ff:fe00:1"},
+		{"001a.2b3c.4d5e", "2001:db8:1:2::99/64", "2001:db8:1:2:21a:2bff:fe3c:4d5e"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewMACToEUI64Function(), types.StringValue(tt.mac), types.StringValue(tt.prefix))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.mac, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%s in %s: expected %s, got %s", tt.mac, tt.prefix, tt.expected, got)
+		}
+	}
+
+	for _, prefix := range []string{"2001:db8::/80", "10.0.0.0/8", "bogus"} {
+		if _, err := runFunction(t, NewMACToEUI64Function(), types.StringValue("00:1a:2b:3c:4d:5e"), types.StringValue(prefix)); err == nil {
+			t.Errorf("expected error for prefix %s", prefix)
+		}
+	}
+}
+
+func TestMACGenerate(t *testing.T) {
+	generate := func(seed, prefix string) string {
+		t.Helper()
+		result, err := runFunction(t, NewMACGenerateFunction(), types.StringValue(seed), types.StringValue(prefix))
+		if err != nil {
+			t.Fatalf("unexpected error for %s/%s: %s", seed, prefix, err)
+		}
+		return result.(types.String).ValueString()
+	}
+
+	mac := generate("web-01", "")
+	if mac != generate("web-01", "") {
+		t.Error("expected the same MAC for the same seed")
+	}
+	if mac == generate("web-02", "") {
+		t.Error("expected different MACs for different seeds")
+	}
+	parsed, err := parseMAC(mac)
+	if err != nil {
+		t.Fatalf("generated invalid MAC %s: %s", mac, err)
+	}
+	if parsed[0]&0x03 != 0x02 {
+		t.Errorf("expected a locally administered unicast MAC, got %s", mac)
+	}
+
+	for _, prefix := range []string{"52:54:00", "00-50-56", "005056", "02:00:00:00:aa"} {
+		want := strings.ToLower(strings.ReplaceAll(prefix, "-", ":"))
+		if !strings.Contains(want, ":") {
+			want = want[0:2] + ":" + want[2:4] + ":" + want[4:6]
+		}
+		if got := generate("web-01", prefix); !strings.HasPrefix(got, want+":") {
+			t.Errorf("expected %s to start with %s", got, want)
+		}
+	}
+
+	for _, prefix := range []string{"01:00:5e", "52:54:0", "00:11:22:33:44:55", "zz"} {
+		if _, err := runFunction(t, NewMACGenerateFunction(), types.StringValue("web-01"), types.StringValue(prefix)); err == nil {
+			t.Errorf("expected error for prefix %s", prefix)
+		}
+	}
+}
+
+func TestIPClassification(t *testing.T) {
+	tests := []struct {
+		address                   string
+		private, public, loopback bool
+	}{
+		{"10.1.2.3", true, false, false},
+		{"172.31.255.255", true, false, false},
+		{"172.32.0.1", false, true, false},
+		{"192.168.0.0/16", true, false, false},
+		{"192.168.0.0/15", false, false, false},
+		{"8.8.8.8", false, true, false},
+		{"1.0.0.0/8", false, true, false},
+		{"0.0.0.0/0", false, false, false},
+		{"127.0.0.1", false, false, true},
+		{"127.0.0.0/8", false, false, true},
+		{"100.64.0.1", false, false, false},
+		{"169.254.169.254", false, false, false},
+		{"192.0.2.10", false, false, false},
+		{"224.0.0.251", false, false, false},
+		{"255.255.255.255", false, false, false},
+		{"::ffff:10.0.0.1", true, false, false},
+		{"fd12:3456::1", true, false, false},
+		{"2606:4700::1111", false, true, false},
+		{"2001:db8::1", false, false, false},
+		{"fe80::1", false, false, false},
+		{"::1", false, false, true},
+		{"::", false, false, false},
+	}
+
+	for _, tt := range tests {
+		for name, check := range map[string]struct {
+			fn   function.Function
+			want bool
+		}{
+			"ip_is_private":  {NewIPIsPrivateFunction(), tt.private},
+			"ip_is_public":   {NewIPIsPublicFunction(), tt.public},
+			"ip_is_loopback": {NewIPIsLoopbackFunction(), tt.loopback},
+		} {
+			result, err := runFunction(t, check.fn, types.StringValue(tt.address))
+			if err != nil {
+				t.Fatalf("%s(%s): unexpected error: %s", name, tt.address, err)
+			}
+			if got := result.(types.Bool).ValueBool(); got != check.want {
+				t.Errorf("%s(%s): expected %v, got %v", name, tt.address, check.want, got)
+			}
+		}
+	}
+
+	if _, err := runFunction(t, NewIPIsPrivateFunction(), types.StringValue("10.0.0.256")); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
+
+func TestIPVersion(t *testing.T) {
+	tests := map[string]int64{
+		"10.0.0.1":        4,
+		"10.0.0.0/8":      4,
+		"::ffff:10.0.0.1": 4,
+		"2001:db8::1":     6,
+		"2001:db8::/32":   6,
+	}
+
+	for address, want := range tests {
+		result, err := runFunction(t, NewIPVersionFunction(), types.StringValue(address))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", address, err)
+		}
+		if got := result.(types.Int64).ValueInt64(); got != want {
+			t.Errorf("%s: expected %d, got %d", address, want, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewIPVersionFunction(), types.StringValue("not-an-ip")); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
+
+func TestCIDRSort(t *testing.T) {
+	input := []string{
+		"10.0.10.0/24",
+		"2001:db8::/32",
+		"10.0.2.5/24",
+		"192.168.1.1",
+		"10.0.2.0/24",
+		"10.0.0.0/8",
+		"::ffff:10.0.0.0/104",
+		"9.255.255.255/32",
+		"2001:db8:0:1::1/64",
+	}
+	expected := `["9.255.255.255/32","10.0.0.0/8","10.0.2.0/24","10.0.10.0/24","192.168.1.1/32","2001:db8::/32","2001:db8:0:1::/64"]`
+
+	result, err := runFunction(t, NewCIDRSortFunction(), stringList(input...))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	result, err = runFunction(t, NewCIDRSortFunction(), stringList())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := jsonOf(t, result); got != "[]" {
+		t.Errorf("expected [], got %s", got)
+	}
+
+	if _, err := runFunction(t, NewCIDRSortFunction(), stringList("10.0.0.0/8", "10.0.0.0/33")); err == nil {
+		t.Error("expected error for invalid CIDR")
+	}
+}
+
+func TestVLSMAllocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		requests map[string]any
+		expected string
+	}{
+		{
+			"mixed sizes",
+			"10.0.0.0/24",
+			map[string]any{"app": map[string]any{"hosts": 100}, "db": 26, "mgmt": map[string]any{"hosts": 10}},
+			`{"allocations":{"app":"10.0.0.0/25","db":"10.0.0.128/26","mgmt":"10.0.0.192/28"},"free_cidrs":["10.0.0.208/28","10.0.0.224/27"],"utilization_percent":81.25}`,
+		},
+		{
+			"full",
+			"10.0.0.0/24",
+			map[string]any{"a": 25, "b": 25},
+			`{"allocations":{"a":"10.0.0.0/25","b":"10.0.0.128/25"},"free_cidrs":[],"utilization_percent":100}`,
+		},
+		{
+			"empty",
+			"10.0.0.0/16",
+			map[string]any{},
+			`{"allocations":{},"free_cidrs":["10.0.0.0/16"],"utilization_percent":0}`,
+		},
+		{
+			"rounded",
+			"10.0.0.0/22",
+			map[string]any{"a": 26, "b": 26, "c": 26},
+			`{"allocations":{"a":"10.0.0.0/26","b":"10.0.0.64/26","c":"10.0.0.128/26"},"free_cidrs":["10.0.0.192/26","10.0.1.0/24","10.0.2.0/23"],"utilization_percent":18.75}`,
+		},
+		{
+			"ipv6",
+			"2001:db8::/48",
+			map[string]any{"a": 64, "b": 50},
+			`{"allocations":{"a":"2001:db8:0:4000::/64","b":"2001:db8::/50"},"free_cidrs":["2001:db8:0:4001::/64","2001:db8:0:4002::/63","2001:db8:0:4004::/62","2001:db8:0:4008::/61","2001:db8:0:4010::/60","2001:db8:0:4020::/59","2001:db8:0:4040::/58","2001:db8:0:4080::/57","2001:db8:0:4100::/56","2001:db8:0:4200::/55","2001:db8:0:4400::/54","2001:db8:0:4800::/53","2001:db8:0:5000::/52","2001:db8:0:6000::/51","2001:db8:0:8000::/49"],"utilization_percent":25}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewVLSMAllocateFunction(), types.StringValue(tt.base), dynamicOf(t, tt.requests))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s\ngot      %s", tt.expected, got)
+			}
+		})
+	}
+
+	// 4 of 256 addresses is 1.5625%, reported as 1.56.
+	result, err := runFunction(t, NewVLSMAllocateFunction(), types.StringValue("10.0.0.0/24"), dynamicOf(t, map[string]any{"link": 30}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := jsonOf(t, result); !strings.Contains(got, `"utilization_percent":1.56}`) {
+		t.Errorf("expected utilization rounded to 1.56, got %s", got)
+	}
+
+	if _, err := runFunction(t, NewVLSMAllocateFunction(), types.StringValue("10.0.0.0/24"), dynamicOf(t, map[string]any{"a": 24, "b": 30})); err == nil {
+		t.Error("expected error when requests do not fit")
+	}
+}
+
+func TestNoProxyNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []string
+		expected string
+	}{
+		{
+			"mixed",
+			[]string{
+				"localhost,127.0.0.1",
+				".internal.example.com",
+				"*.svc.cluster.local",
+				"api.internal.example.com",
+				"10.0.0.0/8",
+				"10.1.2.3",
+				"169.254.169.254",
+				"https://Registry.Example.com:5000/v2/",
+				"localhost",
+			},
+			"internal.example.com,localhost,registry.example.com,svc.cluster.local,10.0.0.0/8,127.0.0.1,169.254.169.254",
+		},
+		{
+			"subdomains collapse",
+			[]string{"a.b.example.com", "b.example.com example.com", "example.org"},
+			"example.com,example.org",
+		},
+		{
+			"ipv6",
+			[]string{"[::1]:8080", "fd00::/8", "fd00:1::5", "::ffff:192.168.0.1"},
+			"192.168.0.1,::1,fd00::/8",
+		},
+		{
+			"host bits and ports",
+			[]string{"192.168.1.10/24", "192.168.1.1:3128", "proxy.corp:8080"},
+			"proxy.corp,192.168.1.0/24",
+		},
+		{"wildcard", []string{"example.com", "*"}, "*"},
+		{"empty", []string{"", " , "}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewNoProxyNormalizeFunction(), stringList(tt.entries...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.(types.String).ValueString(); got != tt.expected {
+				t.Errorf("expected %s\ngot      %s", tt.expected, got)
+			}
+		})
+	}
+
+	for _, entry := range []string{"bad_host.example.com", "-example.com", "300.1.1.1/8", "10.0.0.300", "example.com/path"} {
+		if _, err := runFunction(t, NewNoProxyNormalizeFunction(), stringList(entry)); err == nil {
+			t.Errorf("expected error for %q", entry)
+		}
+	}
+}
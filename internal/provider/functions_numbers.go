@@ -0,0 +1,1277 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// byteUnitSystems lists the units of each unit system accepted by
+// format_bytes, from bytes upwards, with the factor between them.
+var byteUnitSystems = map[string]struct {
+	base  int64
+	units []string
+}{
+	"si":  {1000, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}},
+	"iec": {1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"}},
+}
+
+// byteSizePattern matches a byte size such as 1.5GiB, 512 MB or 10Gi.
+var byteSizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?|\.\d+)\s*([A-Za-z]*)$`)
+
+// byteUnitFactor returns the number of bytes in a unit. Units are
+// case-insensitive; SI units may omit the B (K, M, G) and IEC units may omit
+// the B as Kubernetes quantities do (Ki, Mi, Gi).
+func byteUnitFactor(unit string) (*big.Int, bool) {
+	unit = strings.ToLower(unit)
+	if unit == "" || unit == "b" {
+		return big.NewInt(1), true
+	}
+	for _, system := range byteUnitSystems {
+		for power, name := range system.units[1:] {
+			name = strings.ToLower(name)
+			short := strings.TrimSuffix(name, "b")
+			if unit == name || unit == short {
+				return new(big.Int).Exp(big.NewInt(system.base), big.NewInt(int64(power+1)), nil), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// formatBytes renders a number of bytes in the largest unit of the system
+// that keeps the value at least 1, with up to two decimals.
+func formatBytes(n *big.Int, system string) string {
+	base := new(big.Rat).SetInt64(byteUnitSystems[system].base)
+	units := byteUnitSystems[system].units
+	value := new(big.Rat).SetInt(n)
+	i := 0
+	for ; i < len(units)-1 && value.Cmp(base) >= 0; i++ {
+		value.Quo(value, base)
+	}
+	rounded, _ := new(big.Rat).SetString(value.FloatString(2))
+	if rounded.Cmp(base) >= 0 && i < len(units)-1 {
+		rounded.Quo(rounded, base)
+		i++
+	}
+	text := rounded.FloatString(2)
+	text = strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+	return text + units[i]
+}
+
+// Format Bytes Function
+var _ function.Function = &FormatBytesFunction{}
+
+type FormatBytesFunction struct{}
+
+func NewFormatBytesFunction() function.Function {
+	return &FormatBytesFunction{}
+}
+
+func (f *FormatBytesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_bytes"
+}
+
+func (f *FormatBytesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Formats a number of bytes as a human-readable size",
+		Description: "Formats a number of bytes in the largest SI (kB, MB, GB) or IEC (KiB, MiB, GiB) unit that keeps the " +
+			"value at least 1, with up to two decimals, such as 1.5GiB.",
+		Parameters: []function.Parameter{
+			function.NumberParameter{
+				Name:        "number",
+				Description: "The number of bytes, a whole number of at least 0",
+			},
+			function.StringParameter{
+				Name:        "unit_system",
+				Description: "si for powers of 1000 or iec for powers of 1024",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FormatBytesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var number *big.Float
+	var system string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &number, &system))
+	if resp.Error != nil {
+		return
+	}
+
+	if !number.IsInt() || number.Sign() < 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("number must be a whole number of at least 0, got %s", number.Text('f', -1))))
+		return
+	}
+	if _, ok := byteUnitSystems[system]; !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("unit_system must be \"si\" or \"iec\", got %q", system)))
+		return
+	}
+	n, _ := number.Int(nil)
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatBytes(n, system)))
+}
+
+// Parse Bytes Function
+var _ function.Function = &ParseBytesFunction{}
+
+type ParseBytesFunction struct{}
+
+func NewParseBytesFunction() function.Function {
+	return &ParseBytesFunction{}
+}
+
+func (f *ParseBytesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_bytes"
+}
+
+func (f *ParseBytesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a human-readable size into a number of bytes",
+		Description: "Parses a size such as 1.5GiB, 512 MB or 10Gi into a number of bytes. SI units (kB, MB, GB) are " +
+			"powers of 1000 and IEC units (KiB, MiB, GiB) powers of 1024; units are case-insensitive.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The size, such as \"1.5GiB\"",
+			},
+		},
+		Return: function.NumberReturn{},
+	}
+}
+
+func (f *ParseBytesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	m := byteSizePattern.FindStringSubmatch(strings.TrimSpace(input))
+	if m == nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("invalid size %q, expected a number and unit such as 1.5GiB or 512MB", input)))
+		return
+	}
+	factor, ok := byteUnitFactor(m[2])
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("unknown unit %q in %q, expected B, an SI unit such as kB or GB, or an IEC unit such as KiB or GiB", m[2], input)))
+		return
+	}
+	size, _ := new(big.Rat).SetString(m[1])
+	size.Mul(size, new(big.Rat).SetInt(factor))
+	if !size.IsInt() {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("%q is not a whole number of bytes", input)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, new(big.Float).SetInt(size.Num())))
+}
+
+// numberFormatDefaults are the options accepted by format_number. A precision
+// of -1 shows as many decimals as the value has.
+var numberFormatDefaults = map[string]any{
+	"locale":    "en",
+	"precision": big.NewFloat(-1),
+	"percent":   false,
+	"grouping":  true,
+}
+
+// roundingModes turn a scaled value into a whole number for round_to and
+// format_number.
+var roundingModes = map[string]func(x *big.Rat) *big.Int{
+	"floor": func(x *big.Rat) *big.Int {
+		return new(big.Int).Div(x.Num(), x.Denom())
+	},
+	"ceil": func(x *big.Rat) *big.Int {
+		whole := new(big.Int).Div(new(big.Int).Neg(x.Num()), x.Denom())
+		return whole.Neg(whole)
+	},
+	"truncate": func(x *big.Rat) *big.Int {
+		return new(big.Int).Quo(x.Num(), x.Denom())
+	},
+	"half_up": func(x *big.Rat) *big.Int {
+		half := big.NewRat(1, 2)
+		if x.Sign() < 0 {
+			half.Neg(half)
+		}
+		shifted := new(big.Rat).Add(x, half)
+		return new(big.Int).Quo(shifted.Num(), shifted.Denom())
+	},
+	"half_even": func(x *big.Rat) *big.Int {
+		whole := new(big.Int).Div(x.Num(), x.Denom())
+		fraction := new(big.Rat).Sub(x, new(big.Rat).SetInt(whole))
+		switch fraction.Cmp(big.NewRat(1, 2)) {
+		case 1:
+			whole.Add(whole, big.NewInt(1))
+		case 0:
+			if whole.Bit(0) == 1 {
+				whole.Add(whole, big.NewInt(1))
+			}
+		}
+		return whole
+	},
+}
+
+// roundRat rounds r to the given number of decimals using one of
+// roundingModes. Negative decimals round to tens, hundreds and so on.
+func roundRat(r *big.Rat, decimals int, mode string) *big.Rat {
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(decimals, -decimals))), nil))
+	if decimals < 0 {
+		scale.Inv(scale)
+	}
+	whole := roundingModes[mode](new(big.Rat).Mul(r, scale))
+	return new(big.Rat).Quo(new(big.Rat).SetInt(whole), scale)
+}
+
+// formatNumber formats value in the conventions of a locale. The value is
+// rounded exactly first and then written with the locale's separators and
+// digits, so it keeps about 15 significant digits.
+func formatNumber(value *big.Float, options map[string]any) (string, error) {
+	tag, err := language.Parse(options["locale"].(string))
+	if err != nil {
+		return "", fmt.Errorf("invalid locale %q, expected a BCP 47 tag such as en, de-CH or en-IN", options["locale"])
+	}
+	precision, accuracy := options["precision"].(*big.Float).Int64()
+	if accuracy != big.Exact || precision < -1 || precision > 20 {
+		return "", fmt.Errorf("precision must be a whole number between 0 and 20, or -1 for every decimal")
+	}
+
+	r, _ := new(big.Rat).SetString(value.Text('f', -1))
+	if options["percent"].(bool) {
+		r.Mul(r, big.NewRat(100, 1))
+	}
+	if precision == -1 {
+		_, fraction, _ := strings.Cut(r.FloatString(20), ".")
+		precision = int64(len(strings.TrimRight(fraction, "0")))
+	}
+	digits := roundRat(r, int(precision), "half_up").FloatString(int(precision))
+
+	symbols := readNumberSymbols(message.NewPrinter(tag), options["percent"].(bool))
+	return symbols.format(digits, options["grouping"].(bool)), nil
+}
+
+// numberSymbols are the digits, separators, group sizes and affixes a locale
+// uses to write a number.
+type numberSymbols struct {
+	digits                       [10]string
+	decimal, group               string
+	primaryGroup, secondaryGroup int
+	positive, negative           [2]string
+}
+
+// readNumberSymbols reads the symbols of a locale back from how printer
+// formats sample numbers. format_number writes the exact decimal digits
+// itself, because x/text only formats float64 values and would invent
+// digits beyond their precision.
+func readNumberSymbols(printer *message.Printer, percent bool) numberSymbols {
+	var symbols numberSymbols
+
+	// The sample has every digit, several groups and one decimal.
+	var runs, separators []string
+	for _, c := range printer.Sprint(number.Decimal(1234567890.5, number.Scale(1))) {
+		switch {
+		case unicode.IsDigit(c):
+			if len(runs) == len(separators) {
+				runs = append(runs, "")
+			}
+			runs[len(runs)-1] += string(c)
+		case len(runs) > len(separators):
+			separators = append(separators, string(c))
+		case len(separators) > 0:
+			separators[len(separators)-1] += string(c)
+		}
+	}
+	for i, c := range []rune(strings.Join(runs, ""))[:10] {
+		symbols.digits[(i+1)%10] = string(c)
+	}
+	if len(separators) > 0 {
+		symbols.decimal = separators[len(separators)-1]
+	}
+	if groups := runs[:len(runs)-1]; len(groups) > 1 {
+		symbols.group = separators[0]
+		symbols.primaryGroup = len([]rune(groups[len(groups)-1]))
+		symbols.secondaryGroup = symbols.primaryGroup
+		if len(groups) > 2 {
+			symbols.secondaryGroup = len([]rune(groups[len(groups)-2]))
+		}
+	}
+
+	// The text around a single digit is the sign and percent sign.
+	for _, sign := range []float64{1, -1} {
+		sample := printer.Sprint(number.Decimal(sign, number.Scale(0)))
+		if percent {
+			sample = printer.Sprint(number.Percent(sign/100, number.Scale(0)))
+		}
+		affixes := &symbols.positive
+		if sign < 0 {
+			affixes = &symbols.negative
+		}
+		prefix, suffix, _ := strings.Cut(sample, symbols.digits[1])
+		*affixes = [2]string{prefix, suffix}
+	}
+	return symbols
+}
+
+// format writes an ASCII decimal such as -1234.50 with the symbols.
+func (s numberSymbols) format(decimal string, grouping bool) string {
+	unsigned, negative := strings.CutPrefix(decimal, "-")
+	integer, fraction, _ := strings.Cut(unsigned, ".")
+
+	localize := func(digits string) string {
+		var b strings.Builder
+		for _, c := range digits {
+			b.WriteString(s.digits[c-'0'])
+		}
+		return b.String()
+	}
+
+	var groups []string
+	for size := s.primaryGroup; grouping && size > 0 && len(integer) > size; size = s.secondaryGroup {
+		groups = append([]string{localize(integer[len(integer)-size:])}, groups...)
+		integer = integer[:len(integer)-size]
+	}
+	groups = append([]string{localize(integer)}, groups...)
+
+	result := strings.Join(groups, s.group)
+	if fraction != "" {
+		result += s.decimal + localize(fraction)
+	}
+	affixes := s.positive
+	if negative {
+		affixes = s.negative
+	}
+	return affixes[0] + result + affixes[1]
+}
+
+// Format Number Function
+var _ function.Function = &FormatNumberFunction{}
+
+type FormatNumberFunction struct{}
+
+func NewFormatNumberFunction() function.Function {
+	return &FormatNumberFunction{}
+}
+
+func (f *FormatNumberFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_number"
+}
+
+func (f *FormatNumberFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Formats a number for display",
+		Description: "Formats a number with the thousands and decimal separators of a locale, an optional fixed number " +
+			"of decimals, and optionally as a percentage of 1.",
+		Parameters: []function.Parameter{
+			function.NumberParameter{
+				Name:        "value",
+				Description: "The number to format",
+			},
+			function.DynamicParameter{
+				Name:           "options",
+				Description:    "An object with optional locale, precision, percent and grouping attributes, or null for defaults",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FormatNumberFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value *big.Float
+	var optionsValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &optionsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, optionsValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	options, err := parseOptions(data, numberFormatDefaults)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	formatted, err := formatNumber(value, options)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatted))
+}
+
+// baseIntegerPrefixes are the prefixes parse_int_base accepts for the bases
+// that have one.
+var baseIntegerPrefixes = map[int64]string{2: "0b", 8: "0o", 16: "0x"}
+
+// parseIntBase parses an integer in base 2 to 36, with an optional sign and,
+// for bases 2, 8 and 16, an optional 0b, 0o or 0x prefix. Digits above 9 are
+// letters in either case.
+func parseIntBase(input string, base int64) (*big.Int, error) {
+	text, negative := strings.CutPrefix(input, "-")
+	if prefix, ok := baseIntegerPrefixes[base]; ok && len(text) > 2 && strings.EqualFold(text[:2], prefix) {
+		text = text[2:]
+	}
+	n, ok := new(big.Int).SetString(text, int(base))
+	if !ok || strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-") {
+		return nil, fmt.Errorf("invalid base %d integer %q", base, input)
+	}
+	if negative {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+// Parse Int Base Function
+var _ function.Function = &ParseIntBaseFunction{}
+
+type ParseIntBaseFunction struct{}
+
+func NewParseIntBaseFunction() function.Function {
+	return &ParseIntBaseFunction{}
+}
+
+func (f *ParseIntBaseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_int_base"
+}
+
+func (f *ParseIntBaseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses an integer in any base from 2 to 36",
+		Description: "Parses an integer written in a base from 2 to 36, with an optional minus sign and, for bases 2, 8 " +
+			"and 16, an optional 0b, 0o or 0x prefix. Integers of any size are supported.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The integer, such as \"ff\", \"0x1F\" or \"-1010\"",
+			},
+			function.Int64Parameter{
+				Name:        "base",
+				Description: "The base, between 2 and 36",
+			},
+		},
+		Return: function.NumberReturn{},
+	}
+}
+
+func (f *ParseIntBaseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	var base int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &base))
+	if resp.Error != nil {
+		return
+	}
+
+	if base < 2 || base > 36 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("base must be between 2 and 36, got %d", base)))
+		return
+	}
+	n, err := parseIntBase(input, base)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, new(big.Float).SetInt(n)))
+}
+
+// wholeNumber converts a number argument to an integer, failing for
+// fractions.
+func wholeNumber(number *big.Float) (*big.Int, error) {
+	if !number.IsInt() {
+		return nil, fmt.Errorf("number must be a whole number, got %s", number.Text('f', -1))
+	}
+	n, _ := number.Int(nil)
+	return n, nil
+}
+
+var toBaseParameters = []function.Parameter{
+	function.NumberParameter{
+		Name:        "number",
+		Description: "The whole number to convert",
+	},
+}
+
+// runToBase implements to_hex, to_octal and to_binary.
+func runToBase(ctx context.Context, req function.RunRequest, resp *function.RunResponse, base int) {
+	var number *big.Float
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &number))
+	if resp.Error != nil {
+		return
+	}
+
+	n, err := wholeNumber(number)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, n.Text(base)))
+}
+
+// To Hex Function
+var _ function.Function = &ToHexFunction{}
+
+type ToHexFunction struct{}
+
+func NewToHexFunction() function.Function {
+	return &ToHexFunction{}
+}
+
+func (f *ToHexFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_hex"
+}
+
+func (f *ToHexFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a number to hexadecimal",
+		Description: "Returns a whole number in base 16 with lowercase digits and no prefix, such as ff for 255.",
+		Parameters:  toBaseParameters,
+		Return:      function.StringReturn{},
+	}
+}
+
+func (f *ToHexFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runToBase(ctx, req, resp, 16)
+}
+
+// To Octal Function
+var _ function.Function = &ToOctalFunction{}
+
+type ToOctalFunction struct{}
+
+func NewToOctalFunction() function.Function {
+	return &ToOctalFunction{}
+}
+
+func (f *ToOctalFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_octal"
+}
+
+func (f *ToOctalFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a number to octal",
+		Description: "Returns a whole number in base 8 with no prefix, such as 755 for 493.",
+		Parameters:  toBaseParameters,
+		Return:      function.StringReturn{},
+	}
+}
+
+func (f *ToOctalFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runToBase(ctx, req, resp, 8)
+}
+
+// To Binary Function
+var _ function.Function = &ToBinaryFunction{}
+
+type ToBinaryFunction struct{}
+
+func NewToBinaryFunction() function.Function {
+	return &ToBinaryFunction{}
+}
+
+func (f *ToBinaryFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_binary"
+}
+
+func (f *ToBinaryFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a number to binary",
+		Description: "Returns a whole number in base 2 with no prefix, such as 1010 for 10.",
+		Parameters:  toBaseParameters,
+		Return:      function.StringReturn{},
+	}
+}
+
+func (f *ToBinaryFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runToBase(ctx, req, resp, 2)
+}
+
+// maxBitShift limits shift counts so results stay within the precision of a
+// Terraform number.
+const maxBitShift = 512
+
+var bitOperandParameters = []function.Parameter{
+	function.NumberParameter{
+		Name:        "a",
+		Description: "The first whole number",
+	},
+	function.NumberParameter{
+		Name:        "b",
+		Description: "The second whole number",
+	},
+}
+
+// runBitOperation implements bit_and, bit_or and bit_xor. Negative operands
+// behave as infinitely sign-extended two's complement.
+func runBitOperation(ctx context.Context, req function.RunRequest, resp *function.RunResponse, op func(z, x, y *big.Int) *big.Int) {
+	var a, b *big.Float
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &a, &b))
+	if resp.Error != nil {
+		return
+	}
+
+	x, err := wholeNumber(a)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	y, err := wholeNumber(b)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, new(big.Float).SetInt(op(new(big.Int), x, y))))
+}
+
+// Bit And Function
+var _ function.Function = &BitAndFunction{}
+
+type BitAndFunction struct{}
+
+func NewBitAndFunction() function.Function {
+	return &BitAndFunction{}
+}
+
+func (f *BitAndFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "bit_and"
+}
+
+func (f *BitAndFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Bitwise AND of two integers",
+		Description: "Returns the bitwise AND of two whole numbers. Negative numbers are treated as two's complement.",
+		Parameters:  bitOperandParameters,
+		Return:      function.NumberReturn{},
+	}
+}
+
+func (f *BitAndFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runBitOperation(ctx, req, resp, (*big.Int).And)
+}
+
+// Bit Or Function
+var _ function.Function = &BitOrFunction{}
+
+type BitOrFunction struct{}
+
+func NewBitOrFunction() function.Function {
+	return &BitOrFunction{}
+}
+
+func (f *BitOrFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "bit_or"
+}
+
+func (f *BitOrFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Bitwise OR of two integers",
+		Description: "Returns the bitwise OR of two whole numbers. Negative numbers are treated as two's complement.",
+		Parameters:  bitOperandParameters,
+		Return:      function.NumberReturn{},
+	}
+}
+
+func (f *BitOrFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runBitOperation(ctx, req, resp, (*big.Int).Or)
+}
+
+// Bit Xor Function
+var _ function.Function = &BitXorFunction{}
+
+type BitXorFunction struct{}
+
+func NewBitXorFunction() function.Function {
+	return &BitXorFunction{}
+}
+
+func (f *BitXorFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "bit_xor"
+}
+
+func (f *BitXorFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Bitwise XOR of two integers",
+		Description: "Returns the bitwise exclusive OR of two whole numbers. Negative numbers are treated as two's complement.",
+		Parameters:  bitOperandParameters,
+		Return:      function.NumberReturn{},
+	}
+}
+
+func (f *BitXorFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runBitOperation(ctx, req, resp, (*big.Int).Xor)
+}
+
+// Bit Not Function
+var _ function.Function = &BitNotFunction{}
+
+type BitNotFunction struct{}
+
+func NewBitNotFunction() function.Function {
+	return &BitNotFunction{}
+}
+
+func (f *BitNotFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "bit_not"
+}
+
+func (f *BitNotFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Bitwise NOT of an integer within a bit width",
+		Description: "Inverts the lowest width bits of a non-negative whole number, so that bit_not(4294967040, 32) " +
+			"turns a netmask into its host mask. The number must fit in width bits.",
+		Parameters: []function.Parameter{
+			function.NumberParameter{
+				Name:        "number",
+				Description: "The non-negative whole number to invert",
+			},
+			function.Int64Parameter{
+				Name:        "width",
+				Description: fmt.Sprintf("The number of bits to invert, between 1 and %d", maxBitShift),
+			},
+		},
+		Return: function.NumberReturn{},
+	}
+}
+
+func (f *BitNotFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var number *big.Float
+	var width int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &number, &width))
+	if resp.Error != nil {
+		return
+	}
+
+	if width < 1 || width > maxBitShift {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("width must be between 1 and %d, got %d", maxBitShift, width)))
+		return
+	}
+	n, err := wholeNumber(number)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if n.Sign() < 0 || n.BitLen() > int(width) {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("number %s does not fit in %d bits", n, width)))
+		return
+	}
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(width)), big.NewInt(1))
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, new(big.Float).SetInt(mask.Xor(mask, n))))
+}
+
+var bitShiftParameters = []function.Parameter{
+	function.NumberParameter{
+		Name:        "number",
+		Description: "The whole number to shift",
+	},
+	function.Int64Parameter{
+		Name:        "count",
+		Description: fmt.Sprintf("The number of bits to shift by, between 0 and %d", maxBitShift),
+	},
+}
+
+// runBitShift implements bit_shift_left and bit_shift_right.
+func runBitShift(ctx context.Context, req function.RunRequest, resp *function.RunResponse, left bool) {
+	var number *big.Float
+	var count int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &number, &count))
+	if resp.Error != nil {
+		return
+	}
+
+	n, err := wholeNumber(number)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if count < 0 || count > maxBitShift {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("count must be between 0 and %d, got %d", maxBitShift, count)))
+		return
+	}
+	if left {
+		n.Lsh(n, uint(count))
+	} else {
+		n.Rsh(n, uint(count))
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, new(big.Float).SetInt(n)))
+}
+
+// Bit Shift Left Function
+var _ function.Function = &BitShiftLeftFunction{}
+
+type BitShiftLeftFunction struct{}
+
+func NewBitShiftLeftFunction() function.Function {
+	return &BitShiftLeftFunction{}
+}
+
+func (f *BitShiftLeftFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "bit_shift_left"
+}
+
+func (f *BitShiftLeftFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Shifts an integer's bits left",
+		Description: "Shifts a whole number left by count bits, multiplying it by 2 to the power of count.",
+		Parameters:  bitShiftParameters,
+		Return:      function.NumberReturn{},
+	}
+}
+
+func (f *BitShiftLeftFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runBitShift(ctx, req, resp, true)
+}
+
+// Bit Shift Right Function
+var _ function.Function = &BitShiftRightFunction{}
+
+type BitShiftRightFunction struct{}
+
+func NewBitShiftRightFunction() function.Function {
+	return &BitShiftRightFunction{}
+}
+
+func (f *BitShiftRightFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "bit_shift_right"
+}
+
+func (f *BitShiftRightFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Shifts an integer's bits right",
+		Description: "Shifts a whole number right by count bits, discarding the bits shifted out. Negative numbers " +
+			"are shifted arithmetically and round towards negative infinity.",
+		Parameters: bitShiftParameters,
+		Return:     function.NumberReturn{},
+	}
+}
+
+func (f *BitShiftRightFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runBitShift(ctx, req, resp, false)
+}
+
+// ratNumber converts an exact result to a Terraform number.
+func ratNumber(r *big.Rat) *big.Float {
+	return new(big.Float).SetPrec(512).SetRat(r)
+}
+
+// Clamp Function
+var _ function.Function = &ClampFunction{}
+
+type ClampFunction struct{}
+
+func NewClampFunction() function.Function {
+	return &ClampFunction{}
+}
+
+func (f *ClampFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "clamp"
+}
+
+func (f *ClampFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Limits a number to a range",
+		Description: "Returns value if it is between min and max inclusive, otherwise the nearer of the two bounds.",
+		Parameters: []function.Parameter{
+			function.NumberParameter{
+				Name:        "value",
+				Description: "The number to limit",
+			},
+			function.NumberParameter{
+				Name:        "min",
+				Description: "The lowest allowed value",
+			},
+			function.NumberParameter{
+				Name:        "max",
+				Description: "The highest allowed value",
+			},
+		},
+		Return: function.NumberReturn{},
+	}
+}
+
+func (f *ClampFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value, lower, upper *big.Float
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &lower, &upper))
+	if resp.Error != nil {
+		return
+	}
+
+	if lower.Cmp(upper) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, fmt.Sprintf("max %s is less than min %s", upper.Text('f', -1), lower.Text('f', -1))))
+		return
+	}
+	result := value
+	if value.Cmp(lower) < 0 {
+		result = lower
+	} else if value.Cmp(upper) > 0 {
+		result = upper
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Round To Function
+var _ function.Function = &RoundToFunction{}
+
+type RoundToFunction struct{}
+
+func NewRoundToFunction() function.Function {
+	return &RoundToFunction{}
+}
+
+func (f *RoundToFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "round_to"
+}
+
+func (f *RoundToFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Rounds a number to a number of decimals",
+		Description: "Rounds a number to precision decimal places using exact decimal arithmetic. Negative precision " +
+			"rounds to tens, hundreds and so on. The mode is floor, ceil, truncate, half_up (halves away from zero) " +
+			"or half_even (halves to the nearest even digit).",
+		Parameters: []function.Parameter{
+			function.NumberParameter{
+				Name:        "value",
+				Description: "The number to round",
+			},
+			function.Int64Parameter{
+				Name:        "precision",
+				Description: "The number of decimal places, between -20 and 20",
+			},
+			function.StringParameter{
+				Name:        "mode",
+				Description: "The rounding mode: floor, ceil, truncate, half_up or half_even",
+			},
+		},
+		Return: function.NumberReturn{},
+	}
+}
+
+func (f *RoundToFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value *big.Float
+	var precision int64
+	var mode string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &precision, &mode))
+	if resp.Error != nil {
+		return
+	}
+
+	if precision < -20 || precision > 20 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("precision must be between -20 and 20, got %d", precision)))
+		return
+	}
+	if _, ok := roundingModes[mode]; !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, fmt.Sprintf("unknown rounding mode %q, expected one of %s", mode, strings.Join(sortedKeys(roundingModes), ", "))))
+		return
+	}
+	r, _ := new(big.Rat).SetString(value.Text('f', -1))
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, ratNumber(roundRat(r, int(precision), mode))))
+}
+
+// Percent Of Function
+var _ function.Function = &PercentOfFunction{}
+
+type PercentOfFunction struct{}
+
+func NewPercentOfFunction() function.Function {
+	return &PercentOfFunction{}
+}
+
+func (f *PercentOfFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "percent_of"
+}
+
+func (f *PercentOfFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Calculates what percentage one number is of another",
+		Description: "Returns part divided by whole, multiplied by 100, using exact decimal arithmetic so that " +
+			"percent_of(7, 100) is exactly 7. Combine with round_to to limit the decimals.",
+		Parameters: []function.Parameter{
+			function.NumberParameter{
+				Name:        "part",
+				Description: "The part",
+			},
+			function.NumberParameter{
+				Name:        "whole",
+				Description: "The whole, which must not be zero",
+			},
+		},
+		Return: function.NumberReturn{},
+	}
+}
+
+func (f *PercentOfFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var part, whole *big.Float
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &part, &whole))
+	if resp.Error != nil {
+		return
+	}
+
+	if whole.Sign() == 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "whole must not be zero"))
+		return
+	}
+	p, _ := new(big.Rat).SetString(part.Text('f', -1))
+	w, _ := new(big.Rat).SetString(whole.Text('f', -1))
+	percent := new(big.Rat).Quo(p, w)
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, ratNumber(percent.Mul(percent, big.NewRat(100, 1)))))
+}
+
+// romanNumerals lists numeral values in descending order, including the
+// subtractive pairs.
+var romanNumerals = []struct {
+	value   int64
+	numeral string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+func toRoman(n int64) string {
+	var b strings.Builder
+	for _, r := range romanNumerals {
+		for ; n >= r.value; n -= r.value {
+			b.WriteString(r.numeral)
+		}
+	}
+	return b.String()
+}
+
+// fromRoman parses a numeral in standard form. Non-standard spellings such as
+// IIII or IC are rejected by converting the result back and comparing.
+func fromRoman(input string) (int64, error) {
+	numeral := strings.ToUpper(input)
+	var n int64
+	rest := numeral
+	for _, r := range romanNumerals {
+		for strings.HasPrefix(rest, r.numeral) {
+			n += r.value
+			rest = rest[len(r.numeral):]
+		}
+	}
+	if n == 0 || rest != "" || n > 3999 || toRoman(n) != numeral {
+		return 0, fmt.Errorf("invalid Roman numeral %q", input)
+	}
+	return n, nil
+}
+
+// To Roman Function
+var _ function.Function = &ToRomanFunction{}
+
+type ToRomanFunction struct{}
+
+func NewToRomanFunction() function.Function {
+	return &ToRomanFunction{}
+}
+
+func (f *ToRomanFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_roman"
+}
+
+func (f *ToRomanFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a number to a Roman numeral",
+		Description: "Returns an integer from 1 to 3999 as an uppercase Roman numeral in standard subtractive form, such as XIV for 14.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "number",
+				Description: "The integer to convert, between 1 and 3999",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ToRomanFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var number int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &number))
+	if resp.Error != nil {
+		return
+	}
+
+	if number < 1 || number > 3999 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("number must be between 1 and 3999, got %d", number)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, toRoman(number)))
+}
+
+// From Roman Function
+var _ function.Function = &FromRomanFunction{}
+
+type FromRomanFunction struct{}
+
+func NewFromRomanFunction() function.Function {
+	return &FromRomanFunction{}
+}
+
+func (f *FromRomanFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "from_roman"
+}
+
+func (f *FromRomanFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a Roman numeral",
+		Description: "Returns the value of a Roman numeral from I to MMMCMXCIX in either case. Only the standard " +
+			"subtractive form is accepted, so IIII and IC are errors.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The Roman numeral to parse",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *FromRomanFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	n, err := fromRoman(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, n))
+}
+
+// ordinalSuffix returns the English ordinal suffix for n. Numbers ending in
+// 11, 12 and 13 always take "th".
+func ordinalSuffix(n int64) string {
+	n = max(n, -n)
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+// Ordinal Function
+var _ function.Function = &OrdinalFunction{}
+
+type OrdinalFunction struct{}
+
+func NewOrdinalFunction() function.Function {
+	return &OrdinalFunction{}
+}
+
+func (f *OrdinalFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ordinal"
+}
+
+func (f *OrdinalFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Formats an integer as an English ordinal",
+		Description: "Returns an integer followed by its English ordinal suffix, such as 1st, 2nd, 3rd, 11th or 22nd.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "number",
+				Description: "The integer to format",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *OrdinalFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var number int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &number))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, fmt.Sprintf("%d%s", number, ordinalSuffix(number))))
+}
+
+// Seeded Int Function
+var _ function.Function = &SeededIntFunction{}
+
+type SeededIntFunction struct{}
+
+func NewSeededIntFunction() function.Function {
+	return &SeededIntFunction{}
+}
+
+func (f *SeededIntFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "seeded_int"
+}
+
+func (f *SeededIntFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Generates a stable integer in a range from a seed",
+		Description: "Returns a pseudo-random integer between min and max inclusive that only depends on the seed, so " +
+			"the same inputs always give the same number without a stateful random resource. Not suitable for secrets.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "seed",
+				Description: "The seed, such as terraform.workspace",
+			},
+			function.Int64Parameter{
+				Name:        "min",
+				Description: "The lowest possible result",
+			},
+			function.Int64Parameter{
+				Name:        "max",
+				Description: "The highest possible result",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *SeededIntFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var seed string
+	var lower, upper int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &seed, &lower, &upper))
+	if resp.Error != nil {
+		return
+	}
+
+	if lower > upper {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, fmt.Sprintf("max %d is less than min %d", upper, lower)))
+		return
+	}
+	random := newSeededRand(seed)
+	// The span wraps to zero when the range covers every int64.
+	span := uint64(upper-lower) + 1
+	var offset uint64
+	if span == 0 {
+		offset = random.uint64()
+	} else {
+		offset = random.intn(span)
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, lower+int64(offset)))
+}
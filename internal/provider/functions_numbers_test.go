@@ -0,0 +1,529 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		number   string
+		system   string
+		expected string
+	}{
+		{"1610612736", "iec", "1.5GiB"},
+		{"1610612736", "si", "1.61GB"},
+		{"0", "si", "0B"},
+		{"999", "si", "999B"},
+		{"1000", "si", "1kB"},
+		{"1023", "iec", "1023B"},
+		{"1048575", "iec", "1MiB"},
+		{"1536000", "si", "1.54MB"},
+		{"1000000000000000000000000000", "si", "1000YB"},
+	}
+
+	for _, tt := range tests {
+		number, _, _ := big.ParseFloat(tt.number, 10, 512, big.ToNearestEven)
+		result, err := runFunction(t, NewFormatBytesFunction(), types.NumberValue(number), types.StringValue(tt.system))
+		if err != nil {
+			t.Fatalf("%s (%s): unexpected error: %s", tt.number, tt.system, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%s (%s): expected %s, got %s", tt.number, tt.system, tt.expected, got)
+		}
+	}
+
+	for _, tt := range []struct{ number, system string }{{"-1", "si"}, {"1.5", "si"}, {"1024", "binary"}} {
+		number, _, _ := big.ParseFloat(tt.number, 10, 512, big.ToNearestEven)
+		if _, err := runFunction(t, NewFormatBytesFunction(), types.NumberValue(number), types.StringValue(tt.system)); err == nil {
+			t.Errorf("%s (%s): expected error", tt.number, tt.system)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.5GiB", "1610612736"},
+		{"1.5 GB", "1500000000"},
+		{"512MB", "512000000"},
+		{"10Gi", "10737418240"},
+		{"2k", "2000"},
+		{"4kib", "4096"},
+		{"1024", "1024"},
+		{"100B", "100"},
+		{" .5KiB ", "512"},
+		{"8EiB", "9223372036854775808"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewParseBytesFunction(), types.StringValue(tt.input))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", tt.input, err)
+		}
+		if got := result.(types.Number).ValueBigFloat().Text('f', -1); got != tt.expected {
+			t.Errorf("%q: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	for _, input := range []string{"", "GiB", "1.5.2GB", "-1GB", "1.5XB", "1.0001kB", "0.5B"} {
+		if _, err := runFunction(t, NewParseBytesFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("%q: expected error", input)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		value    string
+		options  map[string]any
+		expected string
+	}{
+		{"1234567.891", nil, "1,234,567.891"},
+		{"1234567.891", map[string]any{"precision": 2}, "1,234,567.89"},
+		{"2.5", map[string]any{"precision": 0}, "3"},
+		{"-2.5", map[string]any{"precision": 0}, "-3"},
+		{"1234.5", map[string]any{"precision": 2, "grouping": false}, "1234.50"},
+		{"1234567.891", map[string]any{"locale": "de", "precision": 2}, "1.234.567,89"},
+		{"1234567.891", map[string]any{"locale": "de-CH", "precision": 1}, "1’234’567.9"},
+		{"1234567", map[string]any{"locale": "en-IN"}, "12,34,567"},
+		{"1234567.5", map[string]any{"locale": "fr", "precision": 0}, "1\u00a0234\u00a0568"},
+		{"0.25675", map[string]any{"percent": true, "precision": 2}, "25.68%"},
+		{"0.256", map[string]any{"percent": true}, "25.6%"},
+		{"0.5", map[string]any{"percent": true, "locale": "de"}, "50\u00a0%"},
+		{"0.000012345", nil, "0.000012345"},
+		{"12345678901234567890", nil, "12,345,678,901,234,567,890"},
+		{"123456789012.345678901", map[string]any{"locale": "en-IN"}, "1,23,45,67,89,012.345678901"},
+		{"-0.001", map[string]any{"precision": 2}, "0.00"},
+		{"-1234.5", map[string]any{"locale": "ar", "precision": 2}, "\u061c-\u0661\u066c\u0662\u0663\u0664\u066b\u0665\u0660"},
+		{"-0.5", map[string]any{"percent": true, "locale": "tr"}, "-%50"},
+	}
+
+	for _, tt := range tests {
+		value, _, _ := big.ParseFloat(tt.value, 10, 512, big.ToNearestEven)
+		options := types.DynamicNull()
+		if tt.options != nil {
+			options = dynamicOf(t, tt.options)
+		}
+		result, err := runFunction(t, NewFormatNumberFunction(), types.NumberValue(value), options)
+		if err != nil {
+			t.Fatalf("%s %v: unexpected error: %s", tt.value, tt.options, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%s %v: expected %q, got %q", tt.value, tt.options, tt.expected, got)
+		}
+	}
+
+	for _, options := range []map[string]any{
+		{"locale": "not a locale!"},
+		{"precision": 1.5},
+		{"precision": 21},
+		{"precision": "2"},
+		{"separator": " "},
+	} {
+		if _, err := runFunction(t, NewFormatNumberFunction(), types.NumberValue(big.NewFloat(1)), dynamicOf(t, options)); err == nil {
+			t.Errorf("%v: expected error", options)
+		}
+	}
+}
+
+func TestParseIntBase(t *testing.T) {
+	tests := []struct {
+		input    string
+		base     int64
+		expected string
+	}{
+		{"ff", 16, "255"},
+		{"0xFF", 16, "255"},
+		{"-0x1f", 16, "-31"},
+		{"0o755", 8, "493"},
+		{"0b1010", 2, "10"},
+		{"zz", 36, "1295"},
+		{"ffffffffffffffffffff", 16, "1208925819614629174706175"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewParseIntBaseFunction(), types.StringValue(tt.input), types.Int64Value(tt.base))
+		if err != nil {
+			t.Fatalf("%s base %d: unexpected error: %s", tt.input, tt.base, err)
+		}
+		if got := result.(types.Number).ValueBigFloat().Text('f', -1); got != tt.expected {
+			t.Errorf("%s base %d: expected %s, got %s", tt.input, tt.base, tt.expected, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		input string
+		base  int64
+	}{
+		{"", 16},
+		{"0x", 16},
+		{"12", 2},
+		{"+1", 10},
+		{"--1", 10},
+		{"0xff", 10},
+		{"1_000", 10},
+		{"10", 1},
+		{"10", 37},
+	} {
+		if _, err := runFunction(t, NewParseIntBaseFunction(), types.StringValue(tt.input), types.Int64Value(tt.base)); err == nil {
+			t.Errorf("%q base %d: expected error", tt.input, tt.base)
+		}
+	}
+}
+
+func TestToBase(t *testing.T) {
+	tests := []struct {
+		number string
+		hex    string
+		octal  string
+		binary string
+	}{
+		{"0", "0", "0", "0"},
+		{"10", "a", "12", "1010"},
+		{"255", "ff", "377", "11111111"},
+		{"-493", "-1ed", "-755", "-111101101"},
+		{"1208925819614629174706175", "ffffffffffffffffffff", "377777777777777777777777777", strings.Repeat("1", 80)},
+	}
+
+	for _, tt := range tests {
+		number, _, _ := big.ParseFloat(tt.number, 10, 512, big.ToNearestEven)
+		for fn, expected := range map[function.Function]string{
+			NewToHexFunction():    tt.hex,
+			NewToOctalFunction():  tt.octal,
+			NewToBinaryFunction(): tt.binary,
+		} {
+			result, err := runFunction(t, fn, types.NumberValue(number))
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", tt.number, err)
+			}
+			if got := result.(types.String).ValueString(); got != expected {
+				t.Errorf("%s: expected %q, got %q", tt.number, expected, got)
+			}
+		}
+	}
+
+	if _, err := runFunction(t, NewToHexFunction(), types.NumberValue(big.NewFloat(1.5))); err == nil {
+		t.Error("1.5: expected error")
+	}
+}
+
+func TestBitOperations(t *testing.T) {
+	tests := []struct {
+		fn       function.Function
+		a, b     int64
+		expected int64
+	}{
+		{NewBitAndFunction(), 0b1100, 0b1010, 0b1000},
+		{NewBitOrFunction(), 0b1100, 0b1010, 0b1110},
+		{NewBitXorFunction(), 0b1100, 0b1010, 0b0110},
+		{NewBitAndFunction(), -1, 0xff, 0xff},
+		{NewBitOrFunction(), -16, 3, -13},
+		{NewBitAndFunction(), 0xc0a80a05, 0xffffff00, 0xc0a80a00},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, tt.fn, types.NumberValue(big.NewFloat(float64(tt.a))), types.NumberValue(big.NewFloat(float64(tt.b))))
+		if err != nil {
+			t.Fatalf("%T(%d, %d): unexpected error: %s", tt.fn, tt.a, tt.b, err)
+		}
+		if got, _ := result.(types.Number).ValueBigFloat().Int64(); got != tt.expected {
+			t.Errorf("%T(%d, %d): expected %d, got %d", tt.fn, tt.a, tt.b, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewBitXorFunction(), types.NumberValue(big.NewFloat(1)), types.NumberValue(big.NewFloat(0.5))); err == nil {
+		t.Error("fractional operand: expected error")
+	}
+}
+
+func TestBitNot(t *testing.T) {
+	tests := []struct {
+		number   int64
+		width    int64
+		expected int64
+	}{
+		{0xffffff00, 32, 0xff},
+		{0, 8, 0xff},
+		{0b1010, 4, 0b0101},
+		{1, 1, 0},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewBitNotFunction(), types.NumberValue(big.NewFloat(float64(tt.number))), types.Int64Value(tt.width))
+		if err != nil {
+			t.Fatalf("%d/%d: unexpected error: %s", tt.number, tt.width, err)
+		}
+		if got, _ := result.(types.Number).ValueBigFloat().Int64(); got != tt.expected {
+			t.Errorf("%d/%d: expected %d, got %d", tt.number, tt.width, tt.expected, got)
+		}
+	}
+
+	for _, tt := range []struct{ number, width int64 }{{256, 8}, {-1, 8}, {1, 0}, {1, 513}} {
+		if _, err := runFunction(t, NewBitNotFunction(), types.NumberValue(big.NewFloat(float64(tt.number))), types.Int64Value(tt.width)); err == nil {
+			t.Errorf("%d/%d: expected error", tt.number, tt.width)
+		}
+	}
+}
+
+func TestBitShift(t *testing.T) {
+	tests := []struct {
+		fn       function.Function
+		number   int64
+		count    int64
+		expected string
+	}{
+		{NewBitShiftLeftFunction(), 1, 4, "16"},
+		{NewBitShiftLeftFunction(), 3, 0, "3"},
+		{NewBitShiftLeftFunction(), 1, 100, "1267650600228229401496703205376"},
+		{NewBitShiftRightFunction(), 0xc0a80a05, 24, "192"},
+		{NewBitShiftRightFunction(), 1, 1, "0"},
+		{NewBitShiftRightFunction(), -5, 1, "-3"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, tt.fn, types.NumberValue(big.NewFloat(float64(tt.number))), types.Int64Value(tt.count))
+		if err != nil {
+			t.Fatalf("%T(%d, %d): unexpected error: %s", tt.fn, tt.number, tt.count, err)
+		}
+		if got := result.(types.Number).ValueBigFloat().Text('f', -1); got != tt.expected {
+			t.Errorf("%T(%d, %d): expected %s, got %s", tt.fn, tt.number, tt.count, tt.expected, got)
+		}
+	}
+
+	for _, count := range []int64{-1, 513} {
+		if _, err := runFunction(t, NewBitShiftLeftFunction(), types.NumberValue(big.NewFloat(1)), types.Int64Value(count)); err == nil {
+			t.Errorf("count %d: expected error", count)
+		}
+	}
+}
+
+func numberOf(t *testing.T, s string) types.Number {
+	t.Helper()
+	value, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+	if err != nil {
+		t.Fatalf("invalid number %q: %s", s, err)
+	}
+	return types.NumberValue(value)
+}
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		value, min, max string
+		expected        string
+	}{
+		{"5", "1", "10", "5"},
+		{"-3", "1", "10", "1"},
+		{"12.5", "1", "10", "10"},
+		{"0.7", "0.2", "0.8", "0.7"},
+		{"3", "3", "3", "3"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewClampFunction(), numberOf(t, tt.value), numberOf(t, tt.min), numberOf(t, tt.max))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.value, err)
+		}
+		if got := result.(types.Number).ValueBigFloat().Text('f', -1); got != tt.expected {
+			t.Errorf("clamp(%s, %s, %s): expected %s, got %s", tt.value, tt.min, tt.max, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewClampFunction(), numberOf(t, "5"), numberOf(t, "10"), numberOf(t, "1")); err == nil {
+		t.Error("min above max: expected error")
+	}
+}
+
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision int64
+		mode      string
+		expected  string
+	}{
+		{"2.675", 2, "half_up", "2.68"},
+		{"2.675", 2, "half_even", "2.68"},
+		{"2.665", 2, "half_even", "2.66"},
+		{"-2.5", 0, "half_up", "-3"},
+		{"-2.5", 0, "half_even", "-2"},
+		{"-3.5", 0, "half_even", "-4"},
+		{"1.21", 1, "ceil", "1.3"},
+		{"-1.29", 1, "ceil", "-1.2"},
+		{"1.29", 1, "floor", "1.2"},
+		{"-1.21", 1, "floor", "-1.3"},
+		{"-1.29", 1, "truncate", "-1.2"},
+		{"1234", -2, "half_up", "1200"},
+		{"1250", -2, "half_even", "1200"},
+		{"1201", -2, "ceil", "1300"},
+		{"0.1", 0, "ceil", "1"},
+		{"7", 3, "floor", "7"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewRoundToFunction(), numberOf(t, tt.value), types.Int64Value(tt.precision), types.StringValue(tt.mode))
+		if err != nil {
+			t.Fatalf("%s %d %s: unexpected error: %s", tt.value, tt.precision, tt.mode, err)
+		}
+		if got := result.(types.Number).ValueBigFloat().Text('f', -1); got != tt.expected {
+			t.Errorf("%s %d %s: expected %s, got %s", tt.value, tt.precision, tt.mode, tt.expected, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		precision int64
+		mode      string
+	}{{21, "floor"}, {-21, "floor"}, {2, "round"}, {2, "HALF_UP"}} {
+		if _, err := runFunction(t, NewRoundToFunction(), numberOf(t, "1.5"), types.Int64Value(tt.precision), types.StringValue(tt.mode)); err == nil {
+			t.Errorf("%d %s: expected error", tt.precision, tt.mode)
+		}
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		part, whole string
+		expected    string
+	}{
+		{"7", "100", "7"},
+		{"0.1", "0.3", "33.33333333"},
+		{"3", "4", "75"},
+		{"150", "100", "150"},
+		{"-1", "8", "-12.5"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewPercentOfFunction(), numberOf(t, tt.part), numberOf(t, tt.whole))
+		if err != nil {
+			t.Fatalf("%s/%s: unexpected error: %s", tt.part, tt.whole, err)
+		}
+		if got := result.(types.Number).ValueBigFloat().Text('f', 8); strings.TrimRight(strings.TrimRight(got, "0"), ".") != tt.expected {
+			t.Errorf("%s/%s: expected %s, got %s", tt.part, tt.whole, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewPercentOfFunction(), numberOf(t, "1"), numberOf(t, "0")); err == nil {
+		t.Error("zero whole: expected error")
+	}
+}
+
+func TestRomanNumerals(t *testing.T) {
+	tests := []struct {
+		number  int64
+		numeral string
+	}{
+		{1, "I"},
+		{4, "IV"},
+		{9, "IX"},
+		{14, "XIV"},
+		{40, "XL"},
+		{90, "XC"},
+		{400, "CD"},
+		{1994, "MCMXCIV"},
+		{2024, "MMXXIV"},
+		{3999, "MMMCMXCIX"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewToRomanFunction(), types.Int64Value(tt.number))
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", tt.number, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.numeral {
+			t.Errorf("to_roman(%d): expected %q, got %q", tt.number, tt.numeral, got)
+		}
+
+		result, err = runFunction(t, NewFromRomanFunction(), types.StringValue(strings.ToLower(tt.numeral)))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.numeral, err)
+		}
+		if got := result.(types.Int64).ValueInt64(); got != tt.number {
+			t.Errorf("from_roman(%q): expected %d, got %d", tt.numeral, tt.number, got)
+		}
+	}
+
+	for _, number := range []int64{0, -1, 4000} {
+		if _, err := runFunction(t, NewToRomanFunction(), types.Int64Value(number)); err == nil {
+			t.Errorf("to_roman(%d): expected error", number)
+		}
+	}
+
+	for _, input := range []string{"", "IIII", "IC", "VX", "MMMM", "XIVX", "ABC", "X I"} {
+		if _, err := runFunction(t, NewFromRomanFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("from_roman(%q): expected error", input)
+		}
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	tests := map[int64]string{
+		0:    "0th",
+		1:    "1st",
+		2:    "2nd",
+		3:    "3rd",
+		4:    "4th",
+		11:   "11th",
+		12:   "12th",
+		13:   "13th",
+		21:   "21st",
+		22:   "22nd",
+		101:  "101st",
+		111:  "111th",
+		1013: "1013th",
+		-1:   "-1st",
+		-12:  "-12th",
+	}
+
+	for number, expected := range tests {
+		result, err := runFunction(t, NewOrdinalFunction(), types.Int64Value(number))
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", number, err)
+		}
+		if got := result.(types.String).ValueString(); got != expected {
+			t.Errorf("ordinal(%d): expected %q, got %q", number, expected, got)
+		}
+	}
+}
+
+func TestSeededInt(t *testing.T) {
+	seeded := func(seed string, lower, upper int64) int64 {
+		t.Helper()
+		result, err := runFunction(t, NewSeededIntFunction(), types.StringValue(seed), types.Int64Value(lower), types.Int64Value(upper))
+		if err != nil {
+			t.Fatalf("%s [%d, %d]: unexpected error: %s", seed, lower, upper, err)
+		}
+		return result.(types.Int64).ValueInt64()
+	}
+
+	if first, second := seeded("prod", 100, 200), seeded("prod", 100, 200); first != second {
+		t.Errorf("expected the same result for the same seed, got %d and %d", first, second)
+	}
+	if got := seeded("prod", 7, 7); got != 7 {
+		t.Errorf("single value range: expected 7, got %d", got)
+	}
+
+	seen := map[int64]bool{}
+	for i := 0; i < 200; i++ {
+		got := seeded(fmt.Sprintf("workspace-%d", i), -2, 2)
+		if got < -2 || got > 2 {
+			t.Fatalf("workspace-%d: %d is outside [-2, 2]", i, got)
+		}
+		seen[got] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected every value in [-2, 2], got %v", seen)
+	}
+
+	seeded("full", math.MinInt64, math.MaxInt64)
+
+	if _, err := runFunction(t, NewSeededIntFunction(), types.StringValue("prod"), types.Int64Value(2), types.Int64Value(1)); err == nil {
+		t.Error("min above max: expected error")
+	}
+}
@@ -0,0 +1,1204 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Array strategies understood by deep_merge.
+const (
+	arrayStrategyReplace      = "replace"
+	arrayStrategyAppend       = "append"
+	arrayStrategyMergeByIndex = "merge_by_index"
+)
+
+// deepMerge recursively merges override into base with the merge_deep
+// defaults: nested objects are merged key by key, lists are combined
+// according to strategy and any other value in override, null included,
+// replaces the one in base.
+func deepMerge(base, override any, strategy string) any {
+	return mergeDeep(base, override, mergeDeepOptions{nulls: "override", lists: strategy}, 0)
+}
+
+// Deep Merge Function
+var _ function.Function = &DeepMergeFunction{}
+
+type DeepMergeFunction struct{}
+
+func NewDeepMergeFunction() function.Function {
+	return &DeepMergeFunction{}
+}
+
+func (f *DeepMergeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "deep_merge"
+}
+
+func (f *DeepMergeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Recursively merges objects",
+		Description: "Takes an array strategy and any number of objects, merging them recursively from left to right. " +
+			"Nested objects are merged key by key while lists are combined according to the strategy: " +
+			"'replace' uses the later list, 'append' concatenates the lists and 'merge_by_index' merges elements at the same position.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "strategy",
+				Description: "How to combine lists: 'replace', 'append' or 'merge_by_index'",
+			},
+		},
+		VariadicParameter: function.DynamicParameter{
+			Name:           "objects",
+			Description:    "The objects to merge, later objects taking precedence",
+			AllowNullValue: true,
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *DeepMergeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var strategy string
+	var objects []types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &strategy, &objects))
+	if resp.Error != nil {
+		return
+	}
+
+	switch strategy {
+	case arrayStrategyReplace, arrayStrategyAppend, arrayStrategyMergeByIndex:
+	default:
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("strategy must be one of 'replace', 'append' or 'merge_by_index', got %q", strategy)))
+		return
+	}
+
+	merged := map[string]any{}
+	for i, object := range objects {
+		data, err := fromValue(ctx, object)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i+1), err.Error()))
+			return
+		}
+		if data == nil {
+			continue
+		}
+		if _, ok := data.(map[string]any); !ok {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i+1), fmt.Sprintf("expected an object, got %s", typeName(data))))
+			return
+		}
+		merged = deepMerge(merged, data, strategy).(map[string]any)
+	}
+
+	result, err := toDynamic(merged)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// mergeDeepDefaults lists the options accepted by merge_deep.
+var mergeDeepDefaults = map[string]any{
+	"nulls":               "override",
+	"empty_maps_override": false,
+	"max_depth":           new(big.Float),
+	"lists":               arrayStrategyReplace,
+}
+
+// mergeDeepOptions are the validated merge_deep options.
+type mergeDeepOptions struct {
+	nulls             string
+	emptyMapsOverride bool
+	maxDepth          int64
+	lists             string
+}
+
+func parseMergeDeepOptions(data any) (mergeDeepOptions, error) {
+	options, err := parseOptions(data, mergeDeepDefaults)
+	if err != nil {
+		return mergeDeepOptions{}, err
+	}
+
+	result := mergeDeepOptions{
+		nulls:             options["nulls"].(string),
+		emptyMapsOverride: options["empty_maps_override"].(bool),
+		lists:             options["lists"].(string),
+	}
+	switch result.nulls {
+	case "override", "ignore", "delete":
+	default:
+		return mergeDeepOptions{}, fmt.Errorf("option \"nulls\" must be one of 'override', 'ignore' or 'delete', got %q", result.nulls)
+	}
+	switch result.lists {
+	case arrayStrategyReplace, arrayStrategyAppend, arrayStrategyMergeByIndex:
+	default:
+		return mergeDeepOptions{}, fmt.Errorf("option \"lists\" must be one of 'replace', 'append' or 'merge_by_index', got %q", result.lists)
+	}
+	var ok bool
+	if result.maxDepth, ok = toInt64(options["max_depth"]); !ok || result.maxDepth < 0 {
+		return mergeDeepOptions{}, fmt.Errorf("option \"max_depth\" must be a non-negative integer")
+	}
+	return result, nil
+}
+
+// mergeDeep recursively merges override into base, applying the null, empty
+// map, depth and list rules of merge_deep. Objects at depth maxDepth are
+// replaced rather than merged.
+func mergeDeep(base, override any, options mergeDeepOptions, depth int64) any {
+	if options.maxDepth > 0 && depth >= options.maxDepth {
+		return override
+	}
+
+	switch overrideValue := override.(type) {
+	case map[string]any:
+		baseMap, ok := base.(map[string]any)
+		if !ok || (len(overrideValue) == 0 && options.emptyMapsOverride) {
+			return overrideValue
+		}
+		result := make(map[string]any, len(baseMap)+len(overrideValue))
+		for key, value := range baseMap {
+			result[key] = value
+		}
+		for key, value := range overrideValue {
+			existing, exists := result[key]
+			switch {
+			case value == nil && options.nulls == "delete":
+				delete(result, key)
+			case value == nil && options.nulls == "ignore":
+				if !exists {
+					result[key] = nil
+				}
+			case exists:
+				result[key] = mergeDeep(existing, value, options, depth+1)
+			default:
+				result[key] = value
+			}
+		}
+		return result
+	case []any:
+		baseList, ok := base.([]any)
+		if !ok {
+			return overrideValue
+		}
+		switch options.lists {
+		case arrayStrategyAppend:
+			result := make([]any, 0, len(baseList)+len(overrideValue))
+			result = append(result, baseList...)
+			return append(result, overrideValue...)
+		case arrayStrategyMergeByIndex:
+			result := make([]any, max(len(baseList), len(overrideValue)))
+			for i := range result {
+				switch {
+				case i >= len(overrideValue):
+					result[i] = baseList[i]
+				case i >= len(baseList):
+					result[i] = overrideValue[i]
+				default:
+					result[i] = mergeDeep(baseList[i], overrideValue[i], options, depth+1)
+				}
+			}
+			return result
+		}
+	}
+	return override
+}
+
+// Merge Deep Function
+var _ function.Function = &MergeDeepFunction{}
+
+type MergeDeepFunction struct{}
+
+func NewMergeDeepFunction() function.Function {
+	return &MergeDeepFunction{}
+}
+
+func (f *MergeDeepFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "merge_deep"
+}
+
+func (f *MergeDeepFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Recursively merges objects with configurable null handling",
+		Description: "Merges overrides into base recursively. Options control how null values are treated ('override', " +
+			"'ignore' or 'delete'), whether an empty object replaces the base value, the maximum depth to merge to and how " +
+			"lists are combined.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "base",
+				Description: "The base object",
+			},
+			function.DynamicParameter{
+				Name:           "overrides",
+				Description:    "An object, or a list of objects applied in order, taking precedence over base",
+				AllowNullValue: true,
+			},
+			function.DynamicParameter{
+				Name:           "options",
+				Description:    "An object of options, or null for the defaults",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *MergeDeepFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var baseValue, overridesValue, optionsValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &baseValue, &overridesValue, &optionsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	base, err := fromValue(ctx, baseValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if _, ok := base.(map[string]any); !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("expected an object, got %s", typeName(base))))
+		return
+	}
+
+	overridesData, err := fromValue(ctx, overridesValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	overrides, ok := overridesData.([]any)
+	if !ok {
+		overrides = []any{overridesData}
+	}
+
+	optionsData, err := fromValue(ctx, optionsValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+	options, err := parseMergeDeepOptions(optionsData)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+
+	merged := base
+	for i, override := range overrides {
+		if override == nil {
+			continue
+		}
+		if _, ok := override.(map[string]any); !ok {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("override %d: expected an object, got %s", i, typeName(override))))
+			return
+		}
+		merged = mergeDeep(merged, override, options, 0)
+	}
+
+	result, err := toDynamic(merged)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// flattenMap writes the leaves of data into result under keys joined with
+// separator. List elements are keyed by index, and empty objects and lists
+// are kept as leaf values.
+func flattenMap(data any, prefix, separator string, result map[string]any) error {
+	var children map[string]any
+	switch v := data.(type) {
+	case map[string]any:
+		children = v
+	case []any:
+		children = make(map[string]any, len(v))
+		for i, element := range v {
+			children[strconv.Itoa(i)] = element
+		}
+	}
+
+	if len(children) == 0 {
+		if _, exists := result[prefix]; exists {
+			return fmt.Errorf("key %q is produced more than once", prefix)
+		}
+		result[prefix] = data
+		return nil
+	}
+
+	for _, key := range sortedKeys(children) {
+		path := key
+		if prefix != "" {
+			path = prefix + separator + key
+		}
+		if err := flattenMap(children[key], path, separator, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unflattenMap nests the entries of a flat map by splitting keys on
+// separator. Objects whose keys are exactly 0..n-1 become lists.
+func unflattenMap(flat map[string]any, separator string) (map[string]any, error) {
+	root := map[string]any{}
+	leaves := map[string]bool{}
+
+	for _, key := range sortedKeys(flat) {
+		segments := strings.Split(strings.TrimPrefix(key, separator), separator)
+		if slices.Contains(segments, "") {
+			return nil, fmt.Errorf("key %q has an empty segment", key)
+		}
+
+		node := root
+		for i, segment := range segments[:len(segments)-1] {
+			prefix := strings.Join(segments[:i+1], separator)
+			if leaves[prefix] {
+				return nil, fmt.Errorf("key %q conflicts with %q", key, prefix)
+			}
+			child, ok := node[segment].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				node[segment] = child
+			}
+			node = child
+		}
+
+		last := segments[len(segments)-1]
+		path := strings.Join(segments, separator)
+		if _, exists := node[last]; exists {
+			return nil, fmt.Errorf("key %q conflicts with another key", key)
+		}
+		node[last], leaves[path] = flat[key], true
+	}
+
+	for key, value := range root {
+		root[key] = listifyIndexes(value)
+	}
+	return root, nil
+}
+
+// listifyIndexes converts objects keyed by consecutive indexes from 0
+// into lists, recursively.
+func listifyIndexes(data any) any {
+	object, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+	for key, value := range object {
+		object[key] = listifyIndexes(value)
+	}
+
+	if len(object) == 0 {
+		return object
+	}
+	list := make([]any, len(object))
+	for key, value := range object {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(list) || strconv.Itoa(index) != key {
+			return object
+		}
+		list[index] = value
+	}
+	return list
+}
+
+// Flatten Map Function
+var _ function.Function = &FlattenMapFunction{}
+
+type FlattenMapFunction struct{}
+
+func NewFlattenMapFunction() function.Function {
+	return &FlattenMapFunction{}
+}
+
+func (f *FlattenMapFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "flatten_map"
+}
+
+func (f *FlattenMapFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Flattens a nested object into path keys",
+		Description: "Flattens a nested object into a single-level object whose keys are the paths to each leaf value joined " +
+			"with separator, such as {\"a.b.c\" = 1}. List elements are keyed by index.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "object",
+				Description: "The object to flatten",
+			},
+			function.StringParameter{
+				Name:        "separator",
+				Description: "The string to join path segments with, such as \".\" or \"/\"",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *FlattenMapFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var separator string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &separator))
+	if resp.Error != nil {
+		return
+	}
+
+	if separator == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "separator must not be empty"))
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	object, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("expected an object, got %s", typeName(data))))
+		return
+	}
+
+	flat := map[string]any{}
+	for _, key := range sortedKeys(object) {
+		if err := flattenMap(object[key], key, separator, flat); err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+			return
+		}
+	}
+
+	result, err := toDynamic(flat)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Unflatten Map Function
+var _ function.Function = &UnflattenMapFunction{}
+
+type UnflattenMapFunction struct{}
+
+func NewUnflattenMapFunction() function.Function {
+	return &UnflattenMapFunction{}
+}
+
+func (f *UnflattenMapFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "unflatten_map"
+}
+
+func (f *UnflattenMapFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Nests a flat object by splitting its keys",
+		Description: "Reverses flatten_map, splitting each key on separator to build nested objects. Objects whose keys are " +
+			"exactly 0 to n-1 become lists, and a single leading separator is ignored.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "map",
+				Description: "The flat object or map to nest",
+			},
+			function.StringParameter{
+				Name:        "separator",
+				Description: "The string that separates path segments, such as \".\" or \"/\"",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *UnflattenMapFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var separator string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &separator))
+	if resp.Error != nil {
+		return
+	}
+
+	if separator == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "separator must not be empty"))
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	flat, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("expected an object or map, got %s", typeName(data))))
+		return
+	}
+
+	nested, err := unflattenMap(flat, separator)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(nested)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// objectDiff records the differences between two objects by dot-separated
+// path.
+type objectDiff struct {
+	added   map[string]any
+	removed map[string]any
+	changed map[string]any
+}
+
+// diffObjects compares a and b recursively. Objects present on both sides
+// are compared key by key; any other differing value is a change.
+func (d *objectDiff) diffObjects(a, b map[string]any, prefix string) {
+	for _, key := range sortedKeys(a) {
+		path := prefix + key
+		other, exists := b[key]
+		if !exists {
+			d.removed[path] = a[key]
+			continue
+		}
+		nestedA, okA := a[key].(map[string]any)
+		nestedB, okB := other.(map[string]any)
+		switch {
+		case okA && okB:
+			d.diffObjects(nestedA, nestedB, path+".")
+		case !valuesEqual(a[key], other):
+			d.changed[path] = map[string]any{"old": a[key], "new": other}
+		}
+	}
+	for _, key := range sortedKeys(b) {
+		if _, exists := a[key]; !exists {
+			d.added[prefix+key] = b[key]
+		}
+	}
+}
+
+// Object Diff Function
+var _ function.Function = &ObjectDiffFunction{}
+
+type ObjectDiffFunction struct{}
+
+func NewObjectDiffFunction() function.Function {
+	return &ObjectDiffFunction{}
+}
+
+func (f *ObjectDiffFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "object_diff"
+}
+
+func (f *ObjectDiffFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compares two objects recursively",
+		Description: "Compares two objects and returns the keys added in b, removed from a and changed between them, keyed " +
+			"by dot-separated path. Nested objects are compared key by key, and changes hold the old and new values. " +
+			"equal is true when there are no differences.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "a",
+				Description:    "The original object",
+				AllowNullValue: true,
+			},
+			function.DynamicParameter{
+				Name:           "b",
+				Description:    "The object to compare against",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ObjectDiffFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var aValue, bValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &aValue, &bValue))
+	if resp.Error != nil {
+		return
+	}
+
+	objects := make([]map[string]any, 2)
+	for i, value := range []types.Dynamic{aValue, bValue} {
+		data, err := fromValue(ctx, value)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i), err.Error()))
+			return
+		}
+		if data == nil {
+			data = map[string]any{}
+		}
+		object, ok := data.(map[string]any)
+		if !ok {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i), fmt.Sprintf("expected an object, got %s", typeName(data))))
+			return
+		}
+		objects[i] = object
+	}
+
+	diff := &objectDiff{added: map[string]any{}, removed: map[string]any{}, changed: map[string]any{}}
+	diff.diffObjects(objects[0], objects[1], "")
+
+	result, err := toDynamic(map[string]any{
+		"added":   diff.added,
+		"removed": diff.removed,
+		"changed": diff.changed,
+		"equal":   len(diff.added)+len(diff.removed)+len(diff.changed) == 0,
+	})
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// compileKeyPatterns builds one matcher for a list of exact keys and glob
+// patterns, where "*" matches any run of characters, including "/", and "?"
+// matches exactly one.
+func compileKeyPatterns(patterns []string) *regexp.Regexp {
+	alternatives := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		var b strings.Builder
+		for _, r := range pattern {
+			switch r {
+			case '*':
+				b.WriteString(".*")
+			case '?':
+				b.WriteString(".")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		alternatives[i] = b.String()
+	}
+	return regexp.MustCompile(`^(?s:` + strings.Join(alternatives, "|") + `)$`)
+}
+
+// filterKeys returns the attributes of object whose keys match patterns, or
+// those that don't when exclude is set.
+func filterKeys(object map[string]any, patterns []string, exclude bool) map[string]any {
+	matcher := compileKeyPatterns(patterns)
+	result := make(map[string]any, len(object))
+	for key, value := range object {
+		matched := len(patterns) > 0 && matcher.MatchString(key)
+		if matched != exclude {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// runFilterKeys implements pick and omit.
+func runFilterKeys(ctx context.Context, req function.RunRequest, resp *function.RunResponse, exclude bool) {
+	var value types.Dynamic
+	var keys []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &keys))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+	object, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("expected an object or map, got %s", typeName(data))))
+		return
+	}
+
+	result, err := toDynamic(filterKeys(object, keys, exclude))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Pick Function
+var _ function.Function = &PickFunction{}
+
+type PickFunction struct{}
+
+func NewPickFunction() function.Function {
+	return &PickFunction{}
+}
+
+func (f *PickFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "pick"
+}
+
+func (f *PickFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Keeps the keys of an object that match a list",
+		Description: "Returns only the attributes of an object or map whose keys match one of the given exact keys or glob " +
+			"patterns, where \"*\" matches any characters including \"/\" and \"?\" matches one character.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "map",
+				Description:    "The object or map to filter",
+				AllowNullValue: true,
+			},
+			function.ListParameter{
+				Name:        "keys",
+				Description: "Keys or glob patterns to keep, such as \"Name\" or \"kubernetes.io/*\"",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *PickFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runFilterKeys(ctx, req, resp, false)
+}
+
+// Omit Function
+var _ function.Function = &OmitFunction{}
+
+type OmitFunction struct{}
+
+func NewOmitFunction() function.Function {
+	return &OmitFunction{}
+}
+
+func (f *OmitFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "omit"
+}
+
+func (f *OmitFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Removes the keys of an object that match a list",
+		Description: "Returns the attributes of an object or map except those whose keys match one of the given exact keys or " +
+			"glob patterns, where \"*\" matches any characters including \"/\" and \"?\" matches one character.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "map",
+				Description:    "The object or map to filter",
+				AllowNullValue: true,
+			},
+			function.ListParameter{
+				Name:        "keys",
+				Description: "Keys or glob patterns to remove, such as \"Name\" or \"aws:*\"",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *OmitFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runFilterKeys(ctx, req, resp, true)
+}
+
+// remapKeys renames the attributes of object according to mapping. Unmapped
+// keys are kept, or are errors in strict mode, and renames that collide are
+// errors.
+func remapKeys(object map[string]any, mapping map[string]string, strict bool) (map[string]any, error) {
+	result := make(map[string]any, len(object))
+	sources := make(map[string]string, len(object))
+	var unmapped []string
+
+	for _, key := range sortedKeys(object) {
+		target, mapped := mapping[key]
+		if !mapped {
+			if strict {
+				unmapped = append(unmapped, key)
+				continue
+			}
+			target = key
+		}
+		if source, exists := sources[target]; exists {
+			return nil, fmt.Errorf("keys %q and %q both map to %q", source, key, target)
+		}
+		result[target], sources[target] = object[key], key
+	}
+
+	if len(unmapped) > 0 {
+		return nil, fmt.Errorf("no mapping for keys: %s", strings.Join(unmapped, ", "))
+	}
+	return result, nil
+}
+
+// Remap Keys Function
+var _ function.Function = &RemapKeysFunction{}
+
+type RemapKeysFunction struct{}
+
+func NewRemapKeysFunction() function.Function {
+	return &RemapKeysFunction{}
+}
+
+func (f *RemapKeysFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "remap_keys"
+}
+
+func (f *RemapKeysFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Renames the keys of an object",
+		Description: "Renames the top-level keys of an object or map according to a mapping of old to new names. Unmapped " +
+			"keys are kept unchanged, or are an error when strict is true. Two keys renamed to the same name are an error.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "map",
+				Description:    "The object or map whose keys to rename",
+				AllowNullValue: true,
+			},
+			function.MapParameter{
+				Name:        "mapping",
+				Description: "A map of existing key names to new key names",
+				ElementType: types.StringType,
+			},
+			function.BoolParameter{
+				Name:        "strict",
+				Description: "Whether keys missing from mapping are an error",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *RemapKeysFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+	var mapping map[string]string
+	var strict bool
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &mapping, &strict))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+	object, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("expected an object or map, got %s", typeName(data))))
+		return
+	}
+
+	remapped, err := remapKeys(object, mapping, strict)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(remapped)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// defaultsWildcard is the defaults key that applies to every key of a map
+// that has no default of its own.
+const defaultsWildcard = "*"
+
+// applyDefaults fills missing and null attributes of value from defaults,
+// recursing into objects. An object default for a list is applied to each
+// object element, and defaults under "*" apply to every other key of a map.
+// Values that are set always win over defaults.
+func applyDefaults(value, defaults any) any {
+	defaultsObject, ok := defaults.(map[string]any)
+	if !ok {
+		if value == nil {
+			return defaults
+		}
+		return value
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return applyDefaults(map[string]any{}, defaultsObject)
+	case []any:
+		result := make([]any, len(v))
+		for i, element := range v {
+			if _, isObject := element.(map[string]any); isObject {
+				element = applyDefaults(element, defaultsObject)
+			}
+			result[i] = element
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v)+len(defaultsObject))
+		wildcard, hasWildcard := defaultsObject[defaultsWildcard]
+		for key, element := range v {
+			if _, hasDefault := defaultsObject[key]; !hasDefault && hasWildcard {
+				element = applyDefaults(element, wildcard)
+			}
+			result[key] = element
+		}
+		for key, def := range defaultsObject {
+			if key != defaultsWildcard {
+				result[key] = applyDefaults(v[key], def)
+			}
+		}
+		return result
+	}
+	return value
+}
+
+// Defaults Deep Function
+var _ function.Function = &DefaultsDeepFunction{}
+
+type DefaultsDeepFunction struct{}
+
+func NewDefaultsDeepFunction() function.Function {
+	return &DefaultsDeepFunction{}
+}
+
+func (f *DefaultsDeepFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "defaults_deep"
+}
+
+func (f *DefaultsDeepFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Recursively fills missing or null attributes from a defaults object",
+		Description: "Walks value and defaults together, taking the default wherever value is missing or null. An object " +
+			"default for a list applies to each object element, and a \"*\" key applies to every map key without its own default.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "value",
+				Description:    "The value to fill in, typically a module variable",
+				AllowNullValue: true,
+			},
+			function.DynamicParameter{
+				Name:        "defaults",
+				Description: "The defaults, shaped like value",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *DefaultsDeepFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value, defaultsValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &defaultsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	defaults, err := fromValue(ctx, defaultsValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	if _, ok := defaults.(map[string]any); !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("expected an object, got %s", typeName(defaults))))
+		return
+	}
+
+	result, err := toDynamic(applyDefaults(data, defaults))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+var compactDeepDefaults = map[string]any{
+	"nulls":         true,
+	"empty_strings": true,
+	"empty_lists":   true,
+	"empty_maps":    true,
+}
+
+// compactDeep removes the empty values selected by options from objects and
+// lists, recursively. Children are compacted first, so a collection that
+// only held empty values is itself removed. The top-level value is never
+// removed.
+func compactDeep(data any, options map[string]any) any {
+	removable := func(value any) bool {
+		switch v := value.(type) {
+		case nil:
+			return options["nulls"].(bool)
+		case string:
+			return v == "" && options["empty_strings"].(bool)
+		case []any:
+			return len(v) == 0 && options["empty_lists"].(bool)
+		case map[string]any:
+			return len(v) == 0 && options["empty_maps"].(bool)
+		}
+		return false
+	}
+
+	switch v := data.(type) {
+	case []any:
+		result := []any{}
+		for _, element := range v {
+			if element = compactDeep(element, options); !removable(element) {
+				result = append(result, element)
+			}
+		}
+		return result
+	case map[string]any:
+		result := map[string]any{}
+		for key, element := range v {
+			if element = compactDeep(element, options); !removable(element) {
+				result[key] = element
+			}
+		}
+		return result
+	}
+	return data
+}
+
+// Compact Deep Function
+var _ function.Function = &CompactDeepFunction{}
+
+type CompactDeepFunction struct{}
+
+func NewCompactDeepFunction() function.Function {
+	return &CompactDeepFunction{}
+}
+
+func (f *CompactDeepFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "compact_deep"
+}
+
+func (f *CompactDeepFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Recursively removes empty values from a structure",
+		Description: "Removes nulls, empty strings, empty lists and empty objects from objects and lists at any depth. " +
+			"Collections that become empty are removed too. Options 'nulls', 'empty_strings', 'empty_lists' and " +
+			"'empty_maps' turn each category off.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:           "value",
+				Description:    "The structure to compact",
+				AllowNullValue: true,
+			},
+			function.DynamicParameter{
+				Name:           "options",
+				Description:    "An object of options, or null for the defaults",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *CompactDeepFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value, optionsValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &optionsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	optionsData, err := fromValue(ctx, optionsValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	options, err := parseOptions(optionsData, compactDeepDefaults)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	result, err := toDynamic(compactDeep(data, options))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Coalesce Objects Function
+var _ function.Function = &CoalesceObjectsFunction{}
+
+type CoalesceObjectsFunction struct{}
+
+func NewCoalesceObjectsFunction() function.Function {
+	return &CoalesceObjectsFunction{}
+}
+
+func (f *CoalesceObjectsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "coalesce_objects"
+}
+
+func (f *CoalesceObjectsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the first non-null value of every key across objects",
+		Description: "Resolves layered configuration: for each key that appears in any object, takes the value from the " +
+			"first object where it is not null. Keys that are null everywhere are null in the result.",
+		VariadicParameter: function.DynamicParameter{
+			Name:           "objects",
+			Description:    "The objects in order of precedence, the highest first",
+			AllowNullValue: true,
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *CoalesceObjectsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var objects []types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &objects))
+	if resp.Error != nil {
+		return
+	}
+
+	coalesced := map[string]any{}
+	for i, object := range objects {
+		data, err := fromValue(ctx, object)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i), err.Error()))
+			return
+		}
+		if data == nil {
+			continue
+		}
+		layer, ok := data.(map[string]any)
+		if !ok {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i), fmt.Sprintf("expected an object, got %s", typeName(data))))
+			return
+		}
+		for key, value := range layer {
+			if coalesced[key] == nil {
+				coalesced[key] = value
+			}
+		}
+	}
+
+	result, err := toDynamic(coalesced)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
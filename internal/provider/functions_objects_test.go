@@ -0,0 +1,508 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDeepMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		objects  []string
+		expected string
+	}{
+		{
+			name:     "nested objects",
+			strategy: "replace",
+			objects:  []string{`{"a":{"x":1,"y":2},"b":1}`, `{"a":{"y":3,"z":4}}`},
+			expected: `{"a":{"x":1,"y":3,"z":4},"b":1}`,
+		},
+		{
+			name:     "replace lists",
+			strategy: "replace",
+			objects:  []string{`{"l":[1,2,3]}`, `{"l":[4]}`},
+			expected: `{"l":[4]}`,
+		},
+		{
+			name:     "append lists",
+			strategy: "append",
+			objects:  []string{`{"l":[1,2]}`, `{"l":[3]}`, `{"l":[4]}`},
+			expected: `{"l":[1,2,3,4]}`,
+		},
+		{
+			name:     "merge lists by index",
+			strategy: "merge_by_index",
+			objects:  []string{`{"l":[{"a":1},{"b":2},3]}`, `{"l":[{"c":1},{"b":5}]}`},
+			expected: `{"l":[{"a":1,"c":1},{"b":5},3]}`,
+		},
+		{
+			name:     "null object skipped",
+			strategy: "replace",
+			objects:  []string{`{"a":1}`, `null`},
+			expected: `{"a":1}`,
+		},
+		{
+			name:     "no objects",
+			strategy: "append",
+			objects:  nil,
+			expected: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := make([]attr.Value, len(tt.objects))
+			for i, object := range tt.objects {
+				objects[i] = dynamicOf(t, mustJSON(t, object))
+			}
+
+			result, err := runFunction(t, NewDeepMergeFunction(), types.StringValue(tt.strategy), variadicOf(objects...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDeepMergeErrors(t *testing.T) {
+	if _, err := runFunction(t, NewDeepMergeFunction(), types.StringValue("zip"), variadicOf()); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+
+	if _, err := runFunction(t, NewDeepMergeFunction(), types.StringValue("replace"), variadicOf(dynamicOf(t, "nope"))); err == nil {
+		t.Error("expected error for non-object argument")
+	}
+}
+
+func TestMergeDeep(t *testing.T) {
+	base := `{"name": "app", "tags": {"team": "platform", "cost": "1"}, "settings": {"db": {"size": 10, "replicas": 2}}, "zones": ["a", "b"]}`
+
+	tests := []struct {
+		name      string
+		overrides string
+		options   string
+		expected  string
+	}{
+		{
+			name:      "defaults",
+			overrides: `{"tags": {"env": "prod", "cost": null}, "settings": {"db": {"size": 20}}, "zones": ["c"]}`,
+			options:   `null`,
+			expected:  `{"name":"app","settings":{"db":{"replicas":2,"size":20}},"tags":{"cost":null,"env":"prod","team":"platform"},"zones":["c"]}`,
+		},
+		{
+			name:      "nulls delete",
+			overrides: `{"tags": {"cost": null}, "name": null, "extra": null}`,
+			options:   `{"nulls": "delete"}`,
+			expected:  `{"settings":{"db":{"replicas":2,"size":10}},"tags":{"team":"platform"},"zones":["a","b"]}`,
+		},
+		{
+			name:      "nulls ignored",
+			overrides: `{"name": null, "tags": {"cost": null, "env": "dev"}, "extra": null}`,
+			options:   `{"nulls": "ignore"}`,
+			expected:  `{"extra":null,"name":"app","settings":{"db":{"replicas":2,"size":10}},"tags":{"cost":"1","env":"dev","team":"platform"},"zones":["a","b"]}`,
+		},
+		{
+			name:      "empty maps",
+			overrides: `{"tags": {}, "settings": {"db": {}}}`,
+			options:   `{"empty_maps_override": true}`,
+			expected:  `{"name":"app","settings":{"db":{}},"tags":{},"zones":["a","b"]}`,
+		},
+		{
+			name:      "max depth",
+			overrides: `{"settings": {"db": {"size": 20}}}`,
+			options:   `{"max_depth": 2}`,
+			expected:  `{"name":"app","settings":{"db":{"size":20}},"tags":{"cost":"1","team":"platform"},"zones":["a","b"]}`,
+		},
+		{
+			name:      "layers and lists",
+			overrides: `[{"zones": ["c"]}, null, {"zones": ["d"], "name": "web"}]`,
+			options:   `{"lists": "append"}`,
+			expected:  `{"name":"web","settings":{"db":{"replicas":2,"size":10}},"tags":{"cost":"1","team":"platform"},"zones":["a","b","c","d"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewMergeDeepFunction(), dynamicOf(t, mustJSON(t, base)), dynamicOf(t, mustJSON(t, tt.overrides)), dynamicOf(t, mustJSON(t, tt.options)))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	errorCases := []struct {
+		base, overrides, options string
+	}{
+		{`[1]`, `{}`, `null`},
+		{`{}`, `[1]`, `null`},
+		{`{}`, `{}`, `{"nulls": "drop"}`},
+		{`{}`, `{}`, `{"lists": "zip"}`},
+		{`{}`, `{}`, `{"max_depth": -1}`},
+		{`{}`, `{}`, `{"depth": 1}`},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewMergeDeepFunction(), dynamicOf(t, mustJSON(t, tt.base)), dynamicOf(t, mustJSON(t, tt.overrides)), dynamicOf(t, mustJSON(t, tt.options))); err == nil {
+			t.Errorf("expected error for %s %s %s", tt.base, tt.overrides, tt.options)
+		}
+	}
+}
+
+func TestFlattenMap(t *testing.T) {
+	tests := []struct {
+		input     string
+		separator string
+		expected  string
+	}{
+		{`{"app": {"db": {"host": "db.internal", "port": 5432}, "debug": false}, "name": "web"}`, ".", `{"app.db.host":"db.internal","app.db.port":5432,"app.debug":false,"name":"web"}`},
+		{`{"app": {"hosts": ["a", "b"], "empty": {}, "none": []}}`, "/", `{"app/empty":{},"app/hosts/0":"a","app/hosts/1":"b","app/none":[]}`},
+		{`{}`, ".", `{}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewFlattenMapFunction(), dynamicOf(t, mustJSON(t, tt.input)), types.StringValue(tt.separator))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.expected, got)
+		}
+
+		round, err := runFunction(t, NewUnflattenMapFunction(), dynamicOf(t, mustJSON(t, tt.expected)), types.StringValue(tt.separator))
+		if err != nil {
+			t.Fatalf("unexpected error unflattening %s: %s", tt.expected, err)
+		}
+		if got, want := jsonOf(t, round), jsonOf(t, dynamicOf(t, mustJSON(t, tt.input))); got != want {
+			t.Errorf("round trip: expected %s, got %s", want, got)
+		}
+	}
+
+	errorCases := []struct {
+		input     string
+		separator string
+	}{
+		{`{"a.b": 1, "a": {"b": 2}}`, "."},
+		{`{"a": 1}`, ""},
+		{`[1]`, "."},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewFlattenMapFunction(), dynamicOf(t, mustJSON(t, tt.input)), types.StringValue(tt.separator)); err == nil {
+			t.Errorf("expected error for %s with separator %q", tt.input, tt.separator)
+		}
+	}
+}
+
+func TestUnflattenMap(t *testing.T) {
+	tests := []struct {
+		input     string
+		separator string
+		expected  string
+	}{
+		{`{"/app/db/host": "db", "/app/db/port": "5432"}`, "/", `{"app":{"db":{"host":"db","port":"5432"}}}`},
+		{`{"0": "root", "x.1": "b", "x.0": "a", "y.1": "b", "y.2": "c", "z.01": "d"}`, ".", `{"0":"root","x":["a","b"],"y":{"1":"b","2":"c"},"z":{"01":"d"}}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewUnflattenMapFunction(), dynamicOf(t, mustJSON(t, tt.input)), types.StringValue(tt.separator))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	for _, input := range []string{`{"a": 1, "a.b": 2}`, `{"a..b": 1}`, `{"a.": 1}`, `{".a.b": 1, "a": 2}`, `"flat"`} {
+		if _, err := runFunction(t, NewUnflattenMapFunction(), dynamicOf(t, mustJSON(t, input)), types.StringValue(".")); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestObjectDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{
+			name:     "tags drift",
+			a:        `{"team": "ops", "env": "prod", "owner": "alice"}`,
+			b:        `{"team": "ops", "env": "staging", "cost_center": "42"}`,
+			expected: `{"added":{"cost_center":"42"},"changed":{"env":{"new":"staging","old":"prod"}},"equal":false,"removed":{"owner":"alice"}}`,
+		},
+		{
+			name:     "nested",
+			a:        `{"db": {"size": 10, "flags": ["a"], "tls": {"enabled": true}}, "name": "x"}`,
+			b:        `{"db": {"size": 10.0, "flags": ["a", "b"], "tls": "off"}, "name": "x"}`,
+			expected: `{"added":{},"changed":{"db.flags":{"new":["a","b"],"old":["a"]},"db.tls":{"new":"off","old":{"enabled":true}}},"equal":false,"removed":{}}`,
+		},
+		{
+			name:     "equal",
+			a:        `{"a": {"b": [1, {"c": null}]}}`,
+			b:        `{"a": {"b": [1, {"c": null}]}}`,
+			expected: `{"added":{},"changed":{},"equal":true,"removed":{}}`,
+		},
+		{
+			name:     "null side",
+			a:        `null`,
+			b:        `{"a": 1}`,
+			expected: `{"added":{"a":1},"changed":{},"equal":false,"removed":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewObjectDiffFunction(), dynamicOf(t, mustJSON(t, tt.a)), dynamicOf(t, mustJSON(t, tt.b)))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := runFunction(t, NewObjectDiffFunction(), dynamicOf(t, mustJSON(t, `[1]`)), dynamicOf(t, mustJSON(t, `{}`))); err == nil {
+		t.Error("expected error for non-object argument")
+	}
+}
+
+func TestPickOmit(t *testing.T) {
+	tags := `{"Name": "web", "kubernetes.io/cluster/prod": "owned", "kubernetes.io/role/elb": "1", "aws:cloudformation:stack": "x", "env": "prod", "env2": "x"}`
+
+	tests := []struct {
+		keys   []string
+		picked string
+		omits  string
+	}{
+		{[]string{"Name", "kubernetes.io/*"}, `{"Name":"web","kubernetes.io/cluster/prod":"owned","kubernetes.io/role/elb":"1"}`, `{"aws:cloudformation:stack":"x","env":"prod","env2":"x"}`},
+		{[]string{"env?", "aws:*", "missing"}, `{"aws:cloudformation:stack":"x","env2":"x"}`, `{"Name":"web","env":"prod","kubernetes.io/cluster/prod":"owned","kubernetes.io/role/elb":"1"}`},
+		{[]string{"kubernetes.io"}, `{}`, tags},
+		{[]string{}, `{}`, tags},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewPickFunction(), dynamicOf(t, mustJSON(t, tags)), stringList(tt.keys...))
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %s", tt.keys, err)
+		}
+		if got := jsonOf(t, result); got != tt.picked {
+			t.Errorf("pick %v: expected %s, got %s", tt.keys, tt.picked, got)
+		}
+
+		result, err = runFunction(t, NewOmitFunction(), dynamicOf(t, mustJSON(t, tags)), stringList(tt.keys...))
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %s", tt.keys, err)
+		}
+		if got, want := jsonOf(t, result), jsonOf(t, dynamicOf(t, mustJSON(t, tt.omits))); got != want {
+			t.Errorf("omit %v: expected %s, got %s", tt.keys, want, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewPickFunction(), dynamicOf(t, mustJSON(t, `["a"]`)), stringList("a")); err == nil {
+		t.Error("expected error for non-object argument")
+	}
+}
+
+func TestRemapKeys(t *testing.T) {
+	mapping := map[string]string{"instanceType": "instance_type", "subnetId": "subnet_id", "unused": "x"}
+	mappingValue, diags := types.MapValueFrom(context.Background(), types.StringType, mapping)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	tests := []struct {
+		input    string
+		strict   bool
+		expected string
+	}{
+		{`{"instanceType": "t3.small", "subnetId": "subnet-1", "tags": {"a": "b"}}`, false, `{"instance_type":"t3.small","subnet_id":"subnet-1","tags":{"a":"b"}}`},
+		{`{"instanceType": "t3.small", "subnetId": "subnet-1"}`, true, `{"instance_type":"t3.small","subnet_id":"subnet-1"}`},
+		{`null`, true, `{}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewRemapKeysFunction(), dynamicOf(t, mustJSON(t, tt.input)), mappingValue, types.BoolValue(tt.strict))
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		input  string
+		strict bool
+	}{
+		{`{"instanceType": "t3", "tags": {}, "vpc": "v"}`, true},
+		{`{"instanceType": "t3", "instance_type": "t2"}`, false},
+		{`["instanceType"]`, false},
+	} {
+		if _, err := runFunction(t, NewRemapKeysFunction(), dynamicOf(t, mustJSON(t, tt.input)), mappingValue, types.BoolValue(tt.strict)); err == nil {
+			t.Errorf("expected error for %s", tt.input)
+		}
+	}
+}
+
+func TestDefaultsDeep(t *testing.T) {
+	defaults := `{
+		"enabled": true,
+		"tags": {},
+		"logging": {"level": "info", "retention": 30},
+		"rules": {"protocol": "tcp", "cidrs": ["10.0.0.0/8"]},
+		"subnets": {"*": {"public": false, "size": 24}}
+	}`
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			"fills missing and null",
+			`{"enabled": null, "logging": {"level": "debug"}}`,
+			`{"enabled":true,"logging":{"level":"debug","retention":30},"rules":{"cidrs":["10.0.0.0/8"],"protocol":"tcp"},"subnets":{},"tags":{}}`,
+		},
+		{
+			"set values win",
+			`{"enabled": false, "tags": {"team": "ops"}, "logging": "off", "rules": [], "subnets": {}}`,
+			`{"enabled":false,"logging":"off","rules":[],"subnets":{},"tags":{"team":"ops"}}`,
+		},
+		{
+			"lists of objects",
+			`{"rules": [{"port": 443}, {"port": 53, "protocol": "udp"}, null]}`,
+			`{"enabled":true,"logging":{"level":"info","retention":30},"rules":[{"cidrs":["10.0.0.0/8"],"port":443,"protocol":"tcp"},{"cidrs":["10.0.0.0/8"],"port":53,"protocol":"udp"},null],"subnets":{},"tags":{}}`,
+		},
+		{
+			"wildcard map entries",
+			`{"subnets": {"a": {"public": true}, "b": {}}, "extra": 1}`,
+			`{"enabled":true,"extra":1,"logging":{"level":"info","retention":30},"rules":{"cidrs":["10.0.0.0/8"],"protocol":"tcp"},"subnets":{"a":{"public":true,"size":24},"b":{"public":false,"size":24}},"tags":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewDefaultsDeepFunction(), dynamicOf(t, mustJSON(t, tt.value)), dynamicOf(t, mustJSON(t, defaults)))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s:\nexpected %s\ngot      %s", tt.name, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewDefaultsDeepFunction(), dynamicOf(t, nil), dynamicOf(t, mustJSON(t, `{"a": {"b": 1}, "list": {"*": 1}}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := jsonOf(t, result), `{"a":{"b":1},"list":{}}`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	result, err = runFunction(t, NewDefaultsDeepFunction(), dynamicOf(t, mustJSON(t, `[{"a": 1}, {}]`)), dynamicOf(t, mustJSON(t, `{"a": 0, "b": 2}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := jsonOf(t, result), `[{"a":1,"b":2},{"a":0,"b":2}]`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if _, err := runFunction(t, NewDefaultsDeepFunction(), dynamicOf(t, mustJSON(t, `{}`)), dynamicOf(t, mustJSON(t, `[1]`))); err == nil {
+		t.Error("expected error for non-object defaults")
+	}
+}
+
+func TestCompactDeep(t *testing.T) {
+	input := `{
+		"name": "web",
+		"description": "",
+		"tags": {},
+		"ingress": [{"port": 443, "cidrs": []}, {"cidrs": [], "note": null}, null, ""],
+		"logging": {"bucket": null, "prefix": ""},
+		"enabled": false,
+		"count": 0
+	}`
+
+	tests := []struct {
+		options  string
+		expected string
+	}{
+		{`null`, `{"count":0,"enabled":false,"ingress":[{"port":443}],"name":"web"}`},
+		{`{"nulls": false}`, `{"count":0,"enabled":false,"ingress":[{"port":443},{"note":null},null],"logging":{"bucket":null},"name":"web"}`},
+		{`{"empty_strings": false}`, `{"count":0,"description":"","enabled":false,"ingress":[{"port":443},""],"logging":{"prefix":""},"name":"web"}`},
+		{`{"empty_lists": false}`, `{"count":0,"enabled":false,"ingress":[{"cidrs":[],"port":443},{"cidrs":[]}],"name":"web"}`},
+		{`{"empty_maps": false}`, `{"count":0,"enabled":false,"ingress":[{"port":443},{}],"logging":{},"name":"web","tags":{}}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewCompactDeepFunction(), dynamicOf(t, mustJSON(t, input)), dynamicOf(t, mustJSON(t, tt.options)))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.options, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s:\nexpected %s\ngot      %s", tt.options, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewCompactDeepFunction(), dynamicOf(t, mustJSON(t, `{"a": {"b": null}}`)), dynamicOf(t, nil))
+	if err != nil || jsonOf(t, result) != `{}` {
+		t.Errorf("expected the top-level object to be kept, got %v %v", result, err)
+	}
+
+	if _, err := runFunction(t, NewCompactDeepFunction(), dynamicOf(t, mustJSON(t, `{}`)), dynamicOf(t, mustJSON(t, `{"zeros": true}`))); err == nil {
+		t.Error("expected error for unknown option")
+	}
+}
+
+func TestCoalesceObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		objects  []string
+		expected string
+	}{
+		{
+			"layers",
+			[]string{`{"size": null, "region": "eu-west-1", "tags": null}`, `{"size": "large", "tags": {"env": "prod"}}`, `{"size": "small", "region": "us-east-1", "zone": "a"}`},
+			`{"region":"eu-west-1","size":"large","tags":{"env":"prod"},"zone":"a"}`,
+		},
+		{
+			"empty strings and false are values",
+			[]string{`{"name": "", "enabled": false}`, `{"name": "web", "enabled": true}`},
+			`{"enabled":false,"name":""}`,
+		},
+		{
+			"null everywhere",
+			[]string{`{"a": null}`, `null`, `{"a": null}`},
+			`{"a":null}`,
+		},
+		{"no objects", nil, `{}`},
+	}
+
+	for _, tt := range tests {
+		args := make([]attr.Value, len(tt.objects))
+		for i, object := range tt.objects {
+			args[i] = dynamicOf(t, mustJSON(t, object))
+		}
+		result, err := runFunction(t, NewCoalesceObjectsFunction(), variadicOf(args...))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewCoalesceObjectsFunction(), variadicOf(dynamicOf(t, mustJSON(t, `{}`)), dynamicOf(t, mustJSON(t, `[1]`)))); err == nil {
+		t.Error("expected error for non-object argument")
+	}
+}
@@ -0,0 +1,1336 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// condToken is a lexical token of a condition expression.
+type condToken struct {
+	kind  string // "ident", "string", "number", "op", or the punctuation itself
+	text  string
+	value any
+	pos   int
+}
+
+// condKeywords are identifiers with special meaning in conditions.
+var condKeywords = map[string]bool{"and": true, "or": true, "not": true, "in": true, "like": true, "true": true, "false": true}
+
+// tokenizeCondition splits a condition expression into tokens.
+func tokenizeCondition(input string) ([]condToken, error) {
+	var tokens []condToken
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.ContainsRune("()[],", rune(c)):
+			tokens = append(tokens, condToken{kind: string(c), text: string(c), pos: i})
+			i++
+		case strings.HasPrefix(input[i:], "==") || strings.HasPrefix(input[i:], "!=") ||
+			strings.HasPrefix(input[i:], "<=") || strings.HasPrefix(input[i:], ">="):
+			tokens = append(tokens, condToken{kind: "op", text: input[i : i+2], pos: i})
+			i += 2
+		case c == '<' || c == '>':
+			tokens = append(tokens, condToken{kind: "op", text: string(c), pos: i})
+			i++
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(input) && input[j] != c; j++ {
+				if input[j] == '\\' && j+1 < len(input) {
+					j++
+				}
+				b.WriteByte(input[j])
+			}
+			if j >= len(input) {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			tokens = append(tokens, condToken{kind: "string", text: input[i : j+1], value: b.String(), pos: i})
+			i = j + 1
+		case c == '-' || c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(input) && (input[j] >= '0' && input[j] <= '9' || input[j] == '.') {
+				j++
+			}
+			number, _, err := big.ParseFloat(input[i:j], 10, 512, big.ToNearestEven)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", input[i:j], i+1)
+			}
+			tokens = append(tokens, condToken{kind: "number", text: input[i:j], value: number, pos: i})
+			i = j
+		case c == '$' || c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(input) && (strings.ContainsRune("_.:/-$", rune(input[j])) || unicode.IsLetter(rune(input[j])) || unicode.IsDigit(rune(input[j]))) {
+				j++
+			}
+			tokens = append(tokens, condToken{kind: "ident", text: input[i:j], pos: i})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i+1)
+		}
+	}
+	return tokens, nil
+}
+
+// condNode is a parsed condition: a boolean combination of comparisons.
+type condNode struct {
+	op       string // "and", "or", "not" or "cmp"
+	children []*condNode
+	key      string
+	cmp      string // "==", "!=", "<", "<=", ">", ">=", "in", "not in", "like", "not like"
+	values   []any
+}
+
+type condParser struct {
+	tokens []condToken
+	pos    int
+}
+
+func (p *condParser) peek() (condToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return condToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *condParser) keyword(word string) bool {
+	token, ok := p.peek()
+	if ok && token.kind == "ident" && token.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *condParser) errorf(format string, args ...any) error {
+	if token, ok := p.peek(); ok {
+		return fmt.Errorf("%s at position %d (%q)", fmt.Sprintf(format, args...), token.pos+1, token.text)
+	}
+	return fmt.Errorf("%s at end of expression", fmt.Sprintf(format, args...))
+}
+
+// parseCondition parses a complete condition expression.
+func parseCondition(input string) (*condNode, error) {
+	tokens, err := tokenizeCondition(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &condParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.peek(); ok {
+		return nil, p.errorf("unexpected token")
+	}
+	return node, nil
+}
+
+func (p *condParser) parseOr() (*condNode, error) {
+	return p.parseBinary("or", p.parseAnd)
+}
+
+func (p *condParser) parseAnd() (*condNode, error) {
+	return p.parseBinary("and", p.parseNot)
+}
+
+func (p *condParser) parseBinary(op string, next func() (*condNode, error)) (*condNode, error) {
+	node, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword(op) {
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if node.op == op {
+			node.children = append(node.children, right)
+		} else {
+			node = &condNode{op: op, children: []*condNode{node, right}}
+		}
+	}
+	return node, nil
+}
+
+func (p *condParser) parseNot() (*condNode, error) {
+	if p.keyword("not") {
+		child, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &condNode{op: "not", children: []*condNode{child}}, nil
+	}
+	if token, ok := p.peek(); ok && token.kind == "(" {
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if token, ok := p.peek(); !ok || token.kind != ")" {
+			return nil, p.errorf("expected )")
+		}
+		p.pos++
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *condParser) parseComparison() (*condNode, error) {
+	token, ok := p.peek()
+	if !ok || token.kind != "ident" || condKeywords[token.text] {
+		return nil, p.errorf("expected a key")
+	}
+	p.pos++
+	node := &condNode{op: "cmp", key: token.text}
+
+	negated := p.keyword("not")
+	switch {
+	case p.keyword("in"):
+		node.cmp = "in"
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		node.values = values
+	case p.keyword("like"):
+		node.cmp = "like"
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("like requires a string pattern for %s", node.key)
+		}
+		node.values = []any{value}
+	default:
+		op, ok := p.peek()
+		if negated || !ok || op.kind != "op" {
+			return nil, p.errorf("expected a comparison operator")
+		}
+		p.pos++
+		node.cmp = op.text
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		node.values = []any{value}
+	}
+	if negated {
+		node.cmp = "not " + node.cmp
+	}
+	return node, nil
+}
+
+func (p *condParser) parseValue() (any, error) {
+	token, ok := p.peek()
+	if !ok {
+		return nil, p.errorf("expected a value")
+	}
+	switch {
+	case token.kind == "string" || token.kind == "number":
+		p.pos++
+		return token.value, nil
+	case token.kind == "ident" && (token.text == "true" || token.text == "false"):
+		p.pos++
+		return token.text == "true", nil
+	}
+	return nil, p.errorf("expected a string, number or bool")
+}
+
+func (p *condParser) parseList() ([]any, error) {
+	if token, ok := p.peek(); !ok || token.kind != "[" {
+		return nil, p.errorf("expected [")
+	}
+	p.pos++
+	var values []any
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		token, ok := p.peek()
+		if ok && token.kind == "," {
+			p.pos++
+			continue
+		}
+		if ok && token.kind == "]" {
+			p.pos++
+			return values, nil
+		}
+		return nil, p.errorf("expected , or ]")
+	}
+}
+
+// choiceOperators maps comparison operators to Step Functions choice rule
+// operator suffixes.
+var choiceOperators = map[string]string{
+	"==": "Equals", "<": "LessThan", "<=": "LessThanEquals", ">": "GreaterThan", ">=": "GreaterThanEquals",
+}
+
+// compileChoiceRule renders a condition as a Step Functions choice rule.
+func compileChoiceRule(node *condNode) (any, error) {
+	switch node.op {
+	case "and", "or":
+		rules := make([]any, len(node.children))
+		for i, child := range node.children {
+			rule, err := compileChoiceRule(child)
+			if err != nil {
+				return nil, err
+			}
+			rules[i] = rule
+		}
+		return map[string]any{strings.ToUpper(node.op[:1]) + node.op[1:]: rules}, nil
+	case "not":
+		rule, err := compileChoiceRule(node.children[0])
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"Not": rule}, nil
+	}
+
+	variable := node.key
+	if !strings.HasPrefix(variable, "$") {
+		variable = "$." + variable
+	}
+	cmp, negate := strings.CutPrefix(node.cmp, "not ")
+	if cmp == "!=" {
+		cmp, negate = "==", !negate
+	}
+
+	var rule any
+	switch cmp {
+	case "like":
+		rule = map[string]any{"Variable": variable, "StringMatches": node.values[0]}
+	case "in":
+		rules := make([]any, len(node.values))
+		for i, value := range node.values {
+			r, err := choiceComparison(variable, "==", value)
+			if err != nil {
+				return nil, err
+			}
+			rules[i] = r
+		}
+		rule = rules[0]
+		if len(rules) > 1 {
+			rule = map[string]any{"Or": rules}
+		}
+	default:
+		r, err := choiceComparison(variable, cmp, node.values[0])
+		if err != nil {
+			return nil, err
+		}
+		rule = r
+	}
+	if negate {
+		rule = map[string]any{"Not": rule}
+	}
+	return rule, nil
+}
+
+func choiceComparison(variable, cmp string, value any) (any, error) {
+	var prefix string
+	switch value.(type) {
+	case string:
+		prefix = "String"
+	case *big.Float:
+		prefix = "Numeric"
+	case bool:
+		if cmp != "==" {
+			return nil, fmt.Errorf("operator %s cannot compare bools", cmp)
+		}
+		prefix = "Boolean"
+	}
+	return map[string]any{"Variable": variable, prefix + choiceOperators[cmp]: value}, nil
+}
+
+// iamNegations maps IAM condition operators to their negated forms.
+var iamNegations = map[string]string{
+	"StringEquals": "StringNotEquals", "StringNotEquals": "StringEquals",
+	"StringLike": "StringNotLike", "StringNotLike": "StringLike",
+	"NumericEquals": "NumericNotEquals", "NumericNotEquals": "NumericEquals",
+	"NumericLessThan": "NumericGreaterThanEquals", "NumericGreaterThanEquals": "NumericLessThan",
+	"NumericGreaterThan": "NumericLessThanEquals", "NumericLessThanEquals": "NumericGreaterThan",
+}
+
+// iamOperator picks the IAM condition operator for a comparison.
+func iamOperator(node *condNode) (string, error) {
+	cmp, negate := strings.CutPrefix(node.cmp, "not ")
+	if cmp == "!=" {
+		cmp, negate = "==", !negate
+	}
+
+	var operator string
+	switch node.values[0].(type) {
+	case string:
+		switch cmp {
+		case "==", "in":
+			operator = "StringEquals"
+		case "like":
+			operator = "StringLike"
+		default:
+			return "", fmt.Errorf("IAM conditions cannot compare strings with %s", cmp)
+		}
+	case *big.Float:
+		operator = "Numeric" + choiceOperators[cmp]
+		if cmp == "in" {
+			operator = "NumericEquals"
+		}
+	case bool:
+		if cmp != "==" || negate {
+			return "", fmt.Errorf("IAM conditions can only compare bools with ==")
+		}
+		return "Bool", nil
+	}
+	if negate {
+		operator = iamNegations[operator]
+	}
+	return operator, nil
+}
+
+// compileIAMCondition renders a condition as an IAM policy Condition block.
+// IAM ANDs every operator and key, so only conjunctions of comparisons,
+// optionally negated, can be expressed.
+func compileIAMCondition(node *condNode, negate bool, block map[string]any) error {
+	switch node.op {
+	case "and":
+		if negate {
+			return fmt.Errorf("IAM conditions cannot negate a group of conditions")
+		}
+		for _, child := range node.children {
+			if err := compileIAMCondition(child, false, block); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "or":
+		return fmt.Errorf("IAM conditions cannot express 'or'; use 'in' to match any of several values for one key")
+	case "not":
+		return compileIAMCondition(node.children[0], !negate, block)
+	}
+
+	if negate {
+		if strings.HasPrefix(node.cmp, "not ") {
+			node = &condNode{op: node.op, key: node.key, cmp: strings.TrimPrefix(node.cmp, "not "), values: node.values}
+		} else {
+			node = &condNode{op: node.op, key: node.key, cmp: "not " + node.cmp, values: node.values}
+		}
+	}
+	if b, ok := node.values[0].(bool); ok && len(node.values) == 1 && (node.cmp == "!=" || node.cmp == "not ==") {
+		node = &condNode{op: node.op, key: node.key, cmp: "==", values: []any{!b}}
+	}
+	operator, err := iamOperator(node)
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, len(node.values))
+	for i, value := range node.values {
+		if typeName(value) != typeName(node.values[0]) {
+			return fmt.Errorf("values for %s must all have the same type", node.key)
+		}
+		values[i], _ = csvField(value)
+	}
+
+	keys, _ := block[operator].(map[string]any)
+	if keys == nil {
+		keys = map[string]any{}
+		block[operator] = keys
+	}
+	if _, exists := keys[node.key]; exists {
+		return fmt.Errorf("%s is compared with %s more than once; IAM would treat the values as alternatives", node.key, operator)
+	}
+	if len(values) == 1 {
+		keys[node.key] = values[0]
+	} else {
+		keys[node.key] = values
+	}
+	return nil
+}
+
+// Condition Compile Function
+var _ function.Function = &ConditionCompileFunction{}
+
+type ConditionCompileFunction struct{}
+
+func NewConditionCompileFunction() function.Function {
+	return &ConditionCompileFunction{}
+}
+
+func (f *ConditionCompileFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "condition_compile"
+}
+
+func (f *ConditionCompileFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compiles a boolean expression into a policy condition",
+		Description: "Parses an expression such as \"env == 'prod' and region in ['us-east-1']\" and returns the equivalent " +
+			"Step Functions choice rule (target \"step_functions\") or IAM Condition block (target \"iam\") as an object. " +
+			"Syntax errors and constructs the target cannot express are reported at plan time.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "expression",
+				Description: "The condition expression",
+			},
+			function.StringParameter{
+				Name:        "target",
+				Description: "The output format: \"step_functions\" or \"iam\"",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ConditionCompileFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var expression, target string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &expression, &target))
+	if resp.Error != nil {
+		return
+	}
+
+	node, err := parseCondition(expression)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid condition: %s", err)))
+		return
+	}
+
+	var compiled any
+	switch target {
+	case "step_functions":
+		compiled, err = compileChoiceRule(node)
+	case "iam":
+		block := map[string]any{}
+		err = compileIAMCondition(node, false, block)
+		compiled = block
+	default:
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("target must be \"step_functions\" or \"iam\", got %q", target)))
+		return
+	}
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid condition: %s", err)))
+		return
+	}
+
+	result, err := toDynamic(compiled)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// iamVersions are the IAM policy language versions in ascending order.
+var iamVersions = []string{"2008-10-17", "2012-10-17"}
+
+// iamStatementKeys are the fields a policy statement may have.
+var iamStatementKeys = map[string]bool{
+	"Sid": true, "Effect": true, "Principal": true, "NotPrincipal": true, "Action": true, "NotAction": true,
+	"Resource": true, "NotResource": true, "Condition": true,
+}
+
+// canonicalKey serialises policy data so it can be compared. Documents are
+// checked with writeCanonicalJSON when they are parsed, so it cannot fail.
+func canonicalKey(data any) string {
+	var b strings.Builder
+	_ = writeCanonicalJSON(&b, data)
+	return b.String()
+}
+
+// normalizeIAMStrings turns a string or list of strings into a sorted list
+// without repeats. Actions are compared case-insensitively, as IAM does.
+func normalizeIAMStrings(value any, foldCase bool) ([]any, error) {
+	list, ok := value.([]any)
+	if !ok {
+		list = []any{value}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("must not be empty")
+	}
+
+	seen := map[string]bool{}
+	var result []string
+	for _, element := range list {
+		s, ok := element.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected strings, got %s", typeName(element))
+		}
+		key := s
+		if foldCase {
+			key = strings.ToLower(s)
+		}
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if a, b := strings.ToLower(result[i]), strings.ToLower(result[j]); a != b {
+			return a < b
+		}
+		return result[i] < result[j]
+	})
+
+	normalized := make([]any, len(result))
+	for i, s := range result {
+		normalized[i] = s
+	}
+	return normalized, nil
+}
+
+// normalizeIAMPrincipal normalizes a Principal or NotPrincipal, which is
+// either "*" or a map of principal types to one or more identifiers.
+func normalizeIAMPrincipal(value any) (any, error) {
+	if value == "*" {
+		return value, nil
+	}
+	principals, ok := value.(map[string]any)
+	if !ok || len(principals) == 0 {
+		return nil, fmt.Errorf("expected \"*\" or an object of principal types, got %s", typeName(value))
+	}
+	normalized := map[string]any{}
+	for _, principalType := range sortedKeys(principals) {
+		list, err := normalizeIAMStrings(principals[principalType], false)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", principalType, err)
+		}
+		normalized[principalType] = list
+	}
+	return normalized, nil
+}
+
+// normalizeIAMCondition normalizes a Condition block so that the values of
+// every key form a sorted list without repeats.
+func normalizeIAMCondition(value any) (any, error) {
+	operators, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object of condition operators, got %s", typeName(value))
+	}
+	normalized := map[string]any{}
+	for _, operator := range sortedKeys(operators) {
+		block := operators[operator]
+		keys, ok := block.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an object of condition keys, got %s", operator, typeName(block))
+		}
+		normalizedKeys := map[string]any{}
+		for _, key := range sortedKeys(keys) {
+			list, ok := keys[key].([]any)
+			if !ok {
+				list = []any{keys[key]}
+			}
+			unique := map[string]any{}
+			for _, v := range list {
+				switch v.(type) {
+				case string, bool, *big.Float:
+					unique[canonicalKey(v)] = v
+				default:
+					return nil, fmt.Errorf("%s.%s: expected strings, numbers or bools, got %s", operator, key, typeName(v))
+				}
+			}
+			sorted := []any{}
+			for _, k := range sortedKeys(unique) {
+				sorted = append(sorted, unique[k])
+			}
+			normalizedKeys[key] = sorted
+		}
+		normalized[operator] = normalizedKeys
+	}
+	return normalized, nil
+}
+
+// normalizeIAMStatement checks the structure of a statement and returns it
+// with every list sorted and without repeats.
+func normalizeIAMStatement(value any) (map[string]any, error) {
+	statement, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %s", typeName(value))
+	}
+	for _, key := range sortedKeys(statement) {
+		if !iamStatementKeys[key] {
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	if effect := statement["Effect"]; effect != "Allow" && effect != "Deny" {
+		return nil, fmt.Errorf("Effect must be \"Allow\" or \"Deny\"")
+	}
+	if sid, ok := statement["Sid"]; ok {
+		if _, ok := sid.(string); !ok {
+			return nil, fmt.Errorf("Sid must be a string")
+		}
+	}
+	for _, pair := range [][2]string{{"Action", "NotAction"}, {"Resource", "NotResource"}, {"Principal", "NotPrincipal"}} {
+		_, positive := statement[pair[0]]
+		_, negative := statement[pair[1]]
+		if positive && negative {
+			return nil, fmt.Errorf("%s and %s cannot be used together", pair[0], pair[1])
+		}
+	}
+	_, hasAction := statement["Action"]
+	_, hasNotAction := statement["NotAction"]
+	if !hasAction && !hasNotAction {
+		return nil, fmt.Errorf("Action or NotAction is required")
+	}
+
+	normalized := map[string]any{}
+	for _, key := range sortedKeys(statement) {
+		v := statement[key]
+		var err error
+		switch key {
+		case "Sid":
+			if v != "" {
+				normalized[key] = v
+			}
+		case "Effect":
+			normalized[key] = v
+		case "Action", "NotAction":
+			normalized[key], err = normalizeIAMStrings(v, true)
+		case "Resource", "NotResource":
+			normalized[key], err = normalizeIAMStrings(v, false)
+		case "Principal", "NotPrincipal":
+			normalized[key], err = normalizeIAMPrincipal(v)
+		case "Condition":
+			normalized[key], err = normalizeIAMCondition(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err)
+		}
+	}
+	return normalized, nil
+}
+
+// iamPolicy is a parsed policy document.
+type iamPolicy struct {
+	version    string
+	id         any
+	statements []map[string]any
+}
+
+// parseIAMPolicy decodes a policy document and normalizes its statements.
+func parseIAMPolicy(input string) (*iamPolicy, error) {
+	data, err := decodeJSON(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %s", err)
+	}
+	document, ok := data.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %s", typeName(data))
+	}
+	if err := writeCanonicalJSON(&strings.Builder{}, document); err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(document) {
+		if key != "Version" && key != "Id" && key != "Statement" {
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+	}
+
+	policy := &iamPolicy{id: document["Id"]}
+	if _, ok := policy.id.(string); !ok && policy.id != nil {
+		return nil, fmt.Errorf("Id must be a string")
+	}
+	if version, ok := document["Version"]; ok {
+		if s, _ := version.(string); !slices.Contains(iamVersions, s) {
+			return nil, fmt.Errorf("Version must be one of %s", strings.Join(iamVersions, ", "))
+		}
+		policy.version = version.(string)
+	}
+
+	statements, ok := document["Statement"].([]any)
+	if !ok && document["Statement"] != nil {
+		statements = []any{document["Statement"]}
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("at least one Statement is required")
+	}
+	for i, s := range statements {
+		statement, err := normalizeIAMStatement(s)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %s", i+1, err)
+		}
+		policy.statements = append(policy.statements, statement)
+	}
+	return policy, nil
+}
+
+// mergeIAMStatementField combines statements without a Sid that are equal
+// apart from field, taking the union of field. It reports whether anything
+// was merged.
+func mergeIAMStatementField(statements []map[string]any, field string) ([]map[string]any, bool) {
+	groups := map[string]map[string]any{}
+	var merged []map[string]any
+	changed := false
+	for _, statement := range statements {
+		_, hasSid := statement["Sid"]
+		if _, ok := statement[field]; !ok || hasSid {
+			merged = append(merged, statement)
+			continue
+		}
+		rest := map[string]any{}
+		for key, v := range statement {
+			if key != field {
+				rest[key] = v
+			}
+		}
+		key := canonicalKey(rest)
+		if first, ok := groups[key]; ok {
+			first[field], _ = normalizeIAMStrings(append(append([]any{}, first[field].([]any)...), statement[field].([]any)...), field == "Action")
+			changed = true
+			continue
+		}
+		copied := map[string]any{}
+		for k, v := range statement {
+			copied[k] = v
+		}
+		groups[key] = copied
+		merged = append(merged, copied)
+	}
+	return merged, changed
+}
+
+// mergeIAMPolicies combines policy documents. Identical statements are kept
+// once, statements without a Sid that only differ in Action or Resource are
+// combined, and a Sid used for two different statements is an error.
+func mergeIAMPolicies(policies []*iamPolicy) (map[string]any, error) {
+	document := map[string]any{}
+	versionIndex := -1
+	seen := map[string]bool{}
+	sids := map[string]string{}
+	var statements []map[string]any
+	for _, policy := range policies {
+		if i := slices.Index(iamVersions, policy.version); i > versionIndex {
+			versionIndex = i
+		}
+		if _, ok := document["Id"]; !ok && policy.id != nil {
+			document["Id"] = policy.id
+		}
+		for _, statement := range policy.statements {
+			key := canonicalKey(statement)
+			if sid, ok := statement["Sid"].(string); ok {
+				if existing, ok := sids[sid]; ok && existing != key {
+					return nil, fmt.Errorf("Sid %q is used by two different statements", sid)
+				}
+				sids[sid] = key
+			}
+			if !seen[key] {
+				seen[key] = true
+				statements = append(statements, statement)
+			}
+		}
+	}
+	if versionIndex >= 0 {
+		document["Version"] = iamVersions[versionIndex]
+	}
+
+	for changed := true; changed; {
+		var byAction, byResource bool
+		statements, byAction = mergeIAMStatementField(statements, "Action")
+		statements, byResource = mergeIAMStatementField(statements, "Resource")
+		changed = byAction || byResource
+	}
+
+	rendered := make([]any, len(statements))
+	for i, statement := range statements {
+		rendered[i] = collapseIAMLists(statement)
+	}
+	document["Statement"] = rendered
+	return document, nil
+}
+
+// collapseIAMLists replaces single-element lists with their element, the
+// usual way policies are written.
+func collapseIAMLists(data any) any {
+	switch v := data.(type) {
+	case []any:
+		if len(v) == 1 {
+			return v[0]
+		}
+		return v
+	case map[string]any:
+		collapsed := map[string]any{}
+		for key, value := range v {
+			collapsed[key] = collapseIAMLists(value)
+		}
+		return collapsed
+	}
+	return data
+}
+
+// IAM Policy Merge Function
+var _ function.Function = &IAMPolicyMergeFunction{}
+
+type IAMPolicyMergeFunction struct{}
+
+func NewIAMPolicyMergeFunction() function.Function {
+	return &IAMPolicyMergeFunction{}
+}
+
+func (f *IAMPolicyMergeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "iam_policy_merge"
+}
+
+func (f *IAMPolicyMergeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Merges and normalizes IAM policy documents",
+		Description: "Combines the statements of several IAM policy JSON documents into one canonical document. " +
+			"Identical statements are kept once, statements without a Sid that differ only in Action or only in " +
+			"Resource are combined, and a Sid used for two different statements is an error.",
+		VariadicParameter: function.StringParameter{
+			Name:        "documents",
+			Description: "The policy documents as JSON strings",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *IAMPolicyMergeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var documents []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &documents))
+	if resp.Error != nil {
+		return
+	}
+
+	if len(documents) == 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError("At least one policy document is required"))
+		return
+	}
+	policies := make([]*iamPolicy, len(documents))
+	for i, document := range documents {
+		policy, err := parseIAMPolicy(document)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i), fmt.Sprintf("Invalid policy document: %s", err)))
+			return
+		}
+		policies[i] = policy
+	}
+
+	merged, err := mergeIAMPolicies(policies)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("Unable to merge policy documents: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, canonicalKey(merged)))
+}
+
+// iamPolicyLimits are the maximum sizes of each policy type in characters,
+// not counting whitespace outside strings.
+var iamPolicyLimits = map[string]int64{
+	"managed":      6144,
+	"inline_user":  2048,
+	"inline_group": 5120,
+	"inline_role":  10240,
+	"trust":        2048,
+	"scp":          5120,
+}
+
+// iamServicePrefixes are common AWS service prefixes, used to spot typos in
+// actions. Prefixes that are not listed are only reported when they are a
+// close misspelling of one that is.
+var iamServicePrefixes = []string{
+	"access-analyzer", "acm", "acm-pca", "airflow", "amplify", "apigateway", "application-autoscaling", "appconfig",
+	"appflow", "appmesh", "apprunner", "appstream", "appsync", "athena", "autoscaling", "aws-marketplace", "backup",
+	"batch", "bedrock", "budgets", "ce", "chatbot", "cloud9", "cloudformation", "cloudfront", "cloudhsm", "cloudshell",
+	"cloudtrail", "cloudwatch", "codeartifact", "codebuild", "codecommit", "codedeploy", "codeguru", "codepipeline",
+	"codestar-connections", "cognito-identity", "cognito-idp", "cognito-sync", "comprehend", "config", "connect",
+	"databrew", "datasync", "dax", "directconnect", "dms", "ds", "dynamodb", "ebs", "ec2", "ec2messages", "ecr",
+	"ecr-public", "ecs", "eks", "elasticache", "elasticbeanstalk", "elasticfilesystem", "elasticloadbalancing",
+	"elasticmapreduce", "emr-containers", "emr-serverless", "es", "events", "execute-api", "firehose", "fms", "fsx",
+	"glacier", "globalaccelerator", "glue", "grafana", "guardduty", "health", "iam", "identitystore", "imagebuilder",
+	"inspector", "inspector2", "iot", "kafka", "kendra", "kinesis", "kinesisanalytics", "kinesisvideo", "kms",
+	"lakeformation", "lambda", "lightsail", "logs", "macie2", "mediaconvert", "memorydb", "mq", "network-firewall",
+	"networkmanager", "opensearch", "organizations", "outposts", "personalize", "pi", "pipes", "polly", "pricing",
+	"quicksight", "ram", "rds", "rds-data", "rds-db", "redshift", "redshift-data", "redshift-serverless", "rekognition",
+	"resource-groups", "route53", "route53domains", "route53resolver", "s3", "s3-object-lambda", "s3express",
+	"sagemaker", "scheduler", "schemas", "secretsmanager", "securityhub", "servicecatalog", "servicediscovery",
+	"servicequotas", "ses", "shield", "signer", "sns", "sqs", "ssm", "ssmmessages", "sso", "sso-directory", "states",
+	"storagegateway", "sts", "support", "swf", "synthetics", "tag", "textract", "timestream", "transcribe", "transfer",
+	"translate", "trustedadvisor", "waf", "waf-regional", "wafv2", "workspaces", "xray",
+}
+
+// iamConditionOperators are the IAM condition operators, without the
+// IfExists suffix or set prefixes.
+var iamConditionOperators = []string{
+	"StringEquals", "StringNotEquals", "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase", "StringLike", "StringNotLike",
+	"NumericEquals", "NumericNotEquals", "NumericLessThan", "NumericLessThanEquals", "NumericGreaterThan", "NumericGreaterThanEquals",
+	"DateEquals", "DateNotEquals", "DateLessThan", "DateLessThanEquals", "DateGreaterThan", "DateGreaterThanEquals",
+	"Bool", "BinaryEquals", "IpAddress", "NotIpAddress", "ArnEquals", "ArnNotEquals", "ArnLike", "ArnNotLike", "Null",
+}
+
+var (
+	iamSidPattern     = regexp.MustCompile(`^[A-Za-z0-9]*$`)
+	iamServicePattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+	iamActionPattern  = regexp.MustCompile(`^[A-Za-z0-9*?]+$`)
+)
+
+type iamFinding struct {
+	Severity string `tfsdk:"severity"`
+	Path     string `tfsdk:"path"`
+	Message  string `tfsdk:"message"`
+}
+
+type iamValidation struct {
+	Valid    bool         `tfsdk:"valid"`
+	Size     int64        `tfsdk:"size"`
+	Limit    int64        `tfsdk:"limit"`
+	Findings []iamFinding `tfsdk:"findings"`
+}
+
+var iamValidationType = map[string]attr.Type{
+	"valid": types.BoolType,
+	"size":  types.Int64Type,
+	"limit": types.Int64Type,
+	"findings": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+		"severity": types.StringType,
+		"path":     types.StringType,
+		"message":  types.StringType,
+	}}},
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// iamValidator collects the findings for one policy document.
+type iamValidator struct {
+	policyType string
+	findings   []iamFinding
+}
+
+func (v *iamValidator) errorf(path, format string, args ...any) {
+	v.findings = append(v.findings, iamFinding{Severity: "error", Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *iamValidator) warnf(path, format string, args ...any) {
+	v.findings = append(v.findings, iamFinding{Severity: "warning", Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// stringList returns the strings of a field that may be a string or a list,
+// reporting anything else.
+func (v *iamValidator) stringList(path string, value any) []string {
+	list, ok := value.([]any)
+	if !ok {
+		list = []any{value}
+	}
+	if len(list) == 0 {
+		v.errorf(path, "must not be empty")
+	}
+	var result []string
+	for i, element := range list {
+		s, ok := element.(string)
+		if !ok {
+			v.errorf(fmt.Sprintf("%s[%d]", path, i), "expected a string, got %s", typeName(element))
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func (v *iamValidator) checkActions(path string, value any) {
+	for i, action := range v.stringList(path, value) {
+		elementPath := path
+		if _, ok := value.([]any); ok {
+			elementPath = fmt.Sprintf("%s[%d]", path, i)
+		}
+		if action == "*" {
+			continue
+		}
+		service, name, ok := strings.Cut(action, ":")
+		service = strings.ToLower(service)
+		switch {
+		case !ok:
+			v.errorf(elementPath, "action %q must be \"*\" or have the form service:action", action)
+		case !iamServicePattern.MatchString(service):
+			v.errorf(elementPath, "action %q has an invalid service prefix", action)
+		case !iamActionPattern.MatchString(name):
+			v.errorf(elementPath, "action %q has an invalid action name", action)
+		case !slices.Contains(iamServicePrefixes, service):
+			suggestion, best := "", 3
+			for _, known := range iamServicePrefixes {
+				if d := editDistance(service, known); d < best && d <= max(1, len(known)/4) {
+					suggestion, best = known, d
+				}
+			}
+			if suggestion != "" {
+				v.warnf(elementPath, "action %q has an unknown service prefix; did you mean %q?", action, suggestion+":"+name)
+			}
+		}
+	}
+}
+
+func (v *iamValidator) checkResources(path string, value any) {
+	for i, resource := range v.stringList(path, value) {
+		elementPath := path
+		if _, ok := value.([]any); ok {
+			elementPath = fmt.Sprintf("%s[%d]", path, i)
+		}
+		if resource != "*" && (!strings.HasPrefix(resource, "arn:") || strings.Count(resource, ":") < 5) {
+			v.errorf(elementPath, "resource %q must be \"*\" or an ARN of the form arn:partition:service:region:account:resource", resource)
+		}
+	}
+}
+
+func (v *iamValidator) checkPrincipal(path string, value any) {
+	if value == "*" {
+		v.warnf(path, "principal \"*\" applies to everyone")
+		return
+	}
+	principals, ok := value.(map[string]any)
+	if !ok || len(principals) == 0 {
+		v.errorf(path, "expected \"*\" or an object of principal types such as AWS or Service")
+		return
+	}
+	for _, principalType := range sortedKeys(principals) {
+		v.stringList(path+"."+principalType, principals[principalType])
+	}
+}
+
+func (v *iamValidator) checkCondition(path string, value any) {
+	operators, ok := value.(map[string]any)
+	if !ok {
+		v.errorf(path, "expected an object of condition operators, got %s", typeName(value))
+		return
+	}
+	for _, operator := range sortedKeys(operators) {
+		base := operator
+		for _, prefix := range []string{"ForAllValues:", "ForAnyValue:"} {
+			if len(base) > len(prefix) && strings.EqualFold(base[:len(prefix)], prefix) {
+				base = base[len(prefix):]
+			}
+		}
+		if trimmed := strings.TrimSuffix(base, "IfExists"); trimmed != "Null" {
+			base = trimmed
+		}
+		if !slices.ContainsFunc(iamConditionOperators, func(known string) bool { return strings.EqualFold(known, base) }) {
+			v.errorf(path+"."+operator, "unknown condition operator %q", operator)
+			continue
+		}
+		keys, ok := operators[operator].(map[string]any)
+		if !ok || len(keys) == 0 {
+			v.errorf(path+"."+operator, "expected an object of condition keys to values")
+		}
+	}
+}
+
+func (v *iamValidator) checkStatement(path string, value any, sids map[string]bool) {
+	statement, ok := value.(map[string]any)
+	if !ok {
+		v.errorf(path, "statement must be an object, got %s", typeName(value))
+		return
+	}
+	for _, key := range sortedKeys(statement) {
+		if !iamStatementKeys[key] {
+			v.errorf(path+"."+key, "unknown statement field %q", key)
+		}
+	}
+
+	if sid, ok := statement["Sid"]; ok {
+		s, isString := sid.(string)
+		switch {
+		case !isString:
+			v.errorf(path+".Sid", "Sid must be a string")
+		case !iamSidPattern.MatchString(s):
+			v.errorf(path+".Sid", "Sid %q may only contain letters and digits", s)
+		case s != "" && sids[s]:
+			v.errorf(path+".Sid", "Sid %q is used by more than one statement", s)
+		default:
+			sids[s] = true
+		}
+	}
+
+	switch effect := statement["Effect"]; {
+	case effect == nil:
+		v.errorf(path, "Effect is required")
+	case effect != "Allow" && effect != "Deny":
+		v.errorf(path+".Effect", "Effect must be \"Allow\" or \"Deny\", got %s", canonicalKey(effect))
+	}
+
+	for _, pair := range [][2]string{{"Action", "NotAction"}, {"Resource", "NotResource"}, {"Principal", "NotPrincipal"}} {
+		_, positive := statement[pair[0]]
+		_, negative := statement[pair[1]]
+		if positive && negative {
+			v.errorf(path, "%s and %s cannot be used together", pair[0], pair[1])
+		}
+	}
+	_, hasAction := statement["Action"]
+	_, hasNotAction := statement["NotAction"]
+	_, hasResource := statement["Resource"]
+	_, hasNotResource := statement["NotResource"]
+	_, hasPrincipal := statement["Principal"]
+	_, hasNotPrincipal := statement["NotPrincipal"]
+	if !hasAction && !hasNotAction {
+		v.errorf(path, "Action or NotAction is required")
+	}
+	switch v.policyType {
+	case "trust":
+		if !hasPrincipal && !hasNotPrincipal {
+			v.errorf(path, "Principal is required in a trust policy")
+		}
+		if hasResource || hasNotResource {
+			v.errorf(path, "Resource is not allowed in a trust policy")
+		}
+	case "scp":
+		if hasPrincipal || hasNotPrincipal {
+			v.errorf(path, "Principal is not allowed in a service control policy")
+		}
+	default:
+		if hasPrincipal || hasNotPrincipal {
+			v.errorf(path, "Principal is not allowed in an identity-based policy")
+		}
+		if !hasResource && !hasNotResource {
+			v.errorf(path, "Resource or NotResource is required")
+		}
+	}
+
+	for _, key := range sortedKeys(statement) {
+		switch key {
+		case "Action", "NotAction":
+			v.checkActions(path+"."+key, statement[key])
+		case "Resource", "NotResource":
+			v.checkResources(path+"."+key, statement[key])
+		case "Principal", "NotPrincipal":
+			v.checkPrincipal(path+"."+key, statement[key])
+		case "Condition":
+			v.checkCondition(path+"."+key, statement[key])
+		}
+	}
+}
+
+// validateIAMPolicy checks a policy document's size and structure.
+func validateIAMPolicy(input, policyType string) iamValidation {
+	v := &iamValidator{policyType: policyType}
+	result := iamValidation{Size: int64(len(input)), Limit: iamPolicyLimits[policyType]}
+
+	var compact bytes.Buffer
+	data, err := decodeJSON(input)
+	if err == nil {
+		err = json.Compact(&compact, []byte(input))
+	}
+	if err != nil {
+		v.errorf("", "invalid JSON: %s", err)
+	} else {
+		result.Size = int64(compact.Len())
+	}
+	if result.Size > result.Limit {
+		v.errorf("", "policy is %d characters, over the %d character limit for %s policies", result.Size, result.Limit, policyType)
+	}
+
+	if document, ok := data.(map[string]any); ok {
+		for _, key := range sortedKeys(document) {
+			if key != "Version" && key != "Id" && key != "Statement" {
+				v.errorf(key, "unknown policy field %q", key)
+			}
+		}
+		version, ok := document["Version"]
+		switch s, _ := version.(string); {
+		case !ok:
+			v.warnf("Version", "Version is missing, so AWS uses 2008-10-17, which does not support policy variables")
+		case !slices.Contains(iamVersions, s):
+			v.errorf("Version", "Version must be one of %s", strings.Join(iamVersions, ", "))
+		}
+
+		sids := map[string]bool{}
+		switch statements := document["Statement"].(type) {
+		case nil:
+			v.errorf("Statement", "Statement is required")
+		case []any:
+			if len(statements) == 0 {
+				v.errorf("Statement", "at least one statement is required")
+			}
+			for i, statement := range statements {
+				v.checkStatement(fmt.Sprintf("Statement[%d]", i), statement, sids)
+			}
+		default:
+			v.checkStatement("Statement", statements, sids)
+		}
+	} else if err == nil {
+		v.errorf("", "policy must be a JSON object, got %s", typeName(data))
+	}
+
+	result.Findings = append([]iamFinding{}, v.findings...)
+	result.Valid = !slices.ContainsFunc(result.Findings, func(f iamFinding) bool { return f.Severity == "error" })
+	return result
+}
+
+// IAM Policy Validate Function
+var _ function.Function = &IAMPolicyValidateFunction{}
+
+type IAMPolicyValidateFunction struct{}
+
+func NewIAMPolicyValidateFunction() function.Function {
+	return &IAMPolicyValidateFunction{}
+}
+
+func (f *IAMPolicyValidateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "iam_policy_validate"
+}
+
+func (f *IAMPolicyValidateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks an IAM policy document's size and syntax",
+		Description: "Checks an IAM policy JSON document against the size limit of its policy type and the structure IAM " +
+			"requires, and looks for malformed or misspelled actions. Returns an object with a valid flag, the size and " +
+			"limit in characters, and a list of findings with a severity, path and message, for use in preconditions.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "document",
+				Description: "The policy document as a JSON string",
+			},
+			function.StringParameter{
+				Name:        "policy_type",
+				Description: "One of managed, inline_user, inline_group, inline_role, trust or scp",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: iamValidationType},
+	}
+}
+
+func (f *IAMPolicyValidateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var document, policyType string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &document, &policyType))
+	if resp.Error != nil {
+		return
+	}
+
+	if _, ok := iamPolicyLimits[policyType]; !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("policy_type must be one of %s, got %q", strings.Join(sortedKeys(iamPolicyLimits), ", "), policyType)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, validateIAMPolicy(document, policyType)))
+}
@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestConditionCompile(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		target     string
+		expected   string
+	}{
+		{
+			name:       "choice and in",
+			expression: "env == 'prod' and region in ['us-east-1', \"eu-west-1\"]",
+			target:     "step_functions",
+			expected:   `{"And":[{"StringEquals":"prod","Variable":"$.env"},{"Or":[{"StringEquals":"us-east-1","Variable":"$.region"},{"StringEquals":"eu-west-1","Variable":"$.region"}]}]}`,
+		},
+		{
+			name:       "choice precedence and negation",
+			expression: "count >= 3 or not (enabled == true and name like 'web-*') or tier != 'free'",
+			target:     "step_functions",
+			expected: `{"Or":[{"NumericGreaterThanEquals":3,"Variable":"$.count"},{"Not":{"And":[{"BooleanEquals":true,"Variable":"$.enabled"},` +
+				`{"StringMatches":"web-*","Variable":"$.name"}]}},{"Not":{"StringEquals":"free","Variable":"$.tier"}}]}`,
+		},
+		{
+			name:       "choice explicit path",
+			expression: "$.detail.size < 1.5",
+			target:     "step_functions",
+			expected:   `{"NumericLessThan":1.5,"Variable":"$.detail.size"}`,
+		},
+		{
+			name:       "iam",
+			expression: "aws:RequestTag/env == 'prod' and aws:RequestedRegion in ['us-east-1', 'eu-west-1'] and s3:prefix not like 'tmp/*'",
+			target:     "iam",
+			expected:   `{"StringEquals":{"aws:RequestTag/env":"prod","aws:RequestedRegion":["us-east-1","eu-west-1"]},"StringNotLike":{"s3:prefix":"tmp/*"}}`,
+		},
+		{
+			name:       "double negation",
+			expression: "not (env != 'prod')",
+			target:     "iam",
+			expected:   `{"StringEquals":{"env":"prod"}}`,
+		},
+		{
+			name:       "iam numbers bools and negation",
+			expression: "aws:MultiFactorAuthAge < 3600 and aws:SecureTransport != false and not (aws:PrincipalTag/team == 'ops')",
+			target:     "iam",
+			expected:   `{"Bool":{"aws:SecureTransport":"true"},"NumericLessThan":{"aws:MultiFactorAuthAge":"3600"},"StringNotEquals":{"aws:PrincipalTag/team":"ops"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewConditionCompileFunction(), types.StringValue(tt.expression), types.StringValue(tt.target))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConditionCompileErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		target     string
+	}{
+		{"unterminated string", "env == 'prod", "iam"},
+		{"missing value", "env ==", "iam"},
+		{"missing operator", "env 'prod'", "iam"},
+		{"unbalanced", "(env == 'a'", "iam"},
+		{"trailing", "env == 'a' env", "iam"},
+		{"bad character", "env = 'a'", "iam"},
+		{"keyword as key", "and == 'a'", "iam"},
+		{"bool ordering", "flag > true", "step_functions"},
+		{"iam or", "env == 'a' or env == 'b'", "iam"},
+		{"iam negated group", "not (a == 'x' and b == 'y')", "iam"},
+		{"iam string ordering", "env < 'b'", "iam"},
+		{"iam repeated key", "env == 'a' and env == 'b'", "iam"},
+		{"iam string ordering negated", "not (env >= 'b')", "iam"},
+		{"unknown target", "env == 'a'", "opa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFunction(t, NewConditionCompileFunction(), types.StringValue(tt.expression), types.StringValue(tt.target)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestIAMPolicyMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents []string
+		expected  string
+	}{
+		{
+			name: "normalizes a single document",
+			documents: []string{
+				`{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": ["s3:PutObject", "s3:GetObject", "S3:GETOBJECT"], "Resource": "arn:aws:s3:::b/*"}}`,
+			},
+			expected: `{"Statement":[{"Action":["s3:GetObject","s3:PutObject"],"Effect":"Allow","Resource":"arn:aws:s3:::b/*"}],"Version":"2012-10-17"}`,
+		},
+		{
+			name: "merges actions for the same resources",
+			documents: []string{
+				`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"}]}`,
+				`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:PutObject", "Resource": ["arn:aws:s3:::b/*"]}]}`,
+			},
+			expected: `{"Statement":[{"Action":["s3:GetObject","s3:PutObject"],"Effect":"Allow","Resource":"arn:aws:s3:::b/*"}],"Version":"2012-10-17"}`,
+		},
+		{
+			name: "merges resources for the same actions",
+			documents: []string{
+				`{"Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::a/*"}]}`,
+				`{"Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"}]}`,
+			},
+			expected: `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Resource":["arn:aws:s3:::a/*","arn:aws:s3:::b/*"]}]}`,
+		},
+		{
+			name: "keeps statements that differ in actions and resources",
+			documents: []string{
+				`{"Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::a/*"}, {"Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::b/*"}]}`,
+			},
+			expected: `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Resource":"arn:aws:s3:::a/*"},{"Action":"s3:PutObject","Effect":"Allow","Resource":"arn:aws:s3:::b/*"}]}`,
+		},
+		{
+			name: "keeps different conditions and effects apart",
+			documents: []string{
+				`{"Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*", "Condition": {"Bool": {"aws:SecureTransport": "true"}}}]}`,
+				`{"Statement": [{"Effect": "Allow", "Action": "s3:PutObject", "Resource": "*"}, {"Effect": "Deny", "Action": "s3:DeleteObject", "Resource": "*"}]}`,
+				`{"Statement": [{"Effect": "Allow", "Action": "s3:ListBucket", "Resource": "*", "Condition": {"Bool": {"aws:SecureTransport": ["true"]}}}]}`,
+			},
+			expected: `{"Statement":[{"Action":["s3:GetObject","s3:ListBucket"],"Condition":{"Bool":{"aws:SecureTransport":"true"}},"Effect":"Allow","Resource":"*"},` +
+				`{"Action":"s3:PutObject","Effect":"Allow","Resource":"*"},{"Action":"s3:DeleteObject","Effect":"Deny","Resource":"*"}]}`,
+		},
+		{
+			name: "dedupes statements with the same Sid and keeps them separate",
+			documents: []string{
+				`{"Version": "2008-10-17", "Id": "shared", "Statement": [{"Sid": "Read", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`,
+				`{"Version": "2012-10-17", "Id": "other", "Statement": [{"Sid": "Read", "Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["*"]}, {"Effect": "Allow", "Action": "s3:PutObject", "Resource": "*"}]}`,
+			},
+			expected: `{"Id":"shared","Statement":[{"Action":"s3:GetObject","Effect":"Allow","Resource":"*","Sid":"Read"},{"Action":"s3:PutObject","Effect":"Allow","Resource":"*"}],"Version":"2012-10-17"}`,
+		},
+		{
+			name: "trust policies without resources",
+			documents: []string{
+				`{"Statement": [{"Sid": "", "Effect": "Allow", "Principal": {"Service": ["lambda.amazonaws.com", "ec2.amazonaws.com"]}, "Action": "sts:AssumeRole"}]}`,
+				`{"Statement": [{"Effect": "Allow", "Principal": {"Service": ["ec2.amazonaws.com", "lambda.amazonaws.com"]}, "Action": "sts:TagSession"}]}`,
+			},
+			expected: `{"Statement":[{"Action":["sts:AssumeRole","sts:TagSession"],"Effect":"Allow","Principal":{"Service":["ec2.amazonaws.com","lambda.amazonaws.com"]}}]}`,
+		},
+		{
+			name: "does not merge NotAction",
+			documents: []string{
+				`{"Statement": [{"Effect": "Deny", "NotAction": "iam:*", "Resource": "*"}, {"Effect": "Deny", "NotAction": "sts:*", "Resource": "*"}]}`,
+			},
+			expected: `{"Statement":[{"Effect":"Deny","NotAction":"iam:*","Resource":"*"},{"Effect":"Deny","NotAction":"sts:*","Resource":"*"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]attr.Value, len(tt.documents))
+			for i, document := range tt.documents {
+				args[i] = types.StringValue(document)
+			}
+			result, err := runFunction(t, NewIAMPolicyMergeFunction(), variadicOf(args...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.(types.String).ValueString(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIAMPolicyMergeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents []string
+	}{
+		{"no documents", nil},
+		{"invalid JSON", []string{`{"Statement": [`}},
+		{"not an object", []string{`[]`}},
+		{"unknown field", []string{`{"Statement": [{"Effect": "Allow", "Action": "*"}], "Extra": 1}`}},
+		{"no statements", []string{`{"Version": "2012-10-17", "Statement": []}`}},
+		{"bad version", []string{`{"Version": "2024-01-01", "Statement": [{"Effect": "Allow", "Action": "*"}]}`}},
+		{"bad effect", []string{`{"Statement": [{"Effect": "allow", "Action": "*"}]}`}},
+		{"missing action", []string{`{"Statement": [{"Effect": "Allow", "Resource": "*"}]}`}},
+		{"action and not action", []string{`{"Statement": [{"Effect": "Allow", "Action": "*", "NotAction": "iam:*"}]}`}},
+		{"empty resource", []string{`{"Statement": [{"Effect": "Allow", "Action": "*", "Resource": []}]}`}},
+		{"bad principal", []string{`{"Statement": [{"Effect": "Allow", "Action": "*", "Principal": "arn:aws:iam::123456789012:root"}]}`}},
+		{"bad condition", []string{`{"Statement": [{"Effect": "Allow", "Action": "*", "Condition": {"Bool": "true"}}]}`}},
+		{"sid collision", []string{
+			`{"Statement": [{"Sid": "Read", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`,
+			`{"Statement": [{"Sid": "Read", "Effect": "Allow", "Action": "s3:ListBucket", "Resource": "*"}]}`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]attr.Value, len(tt.documents))
+			for i, document := range tt.documents {
+				args[i] = types.StringValue(document)
+			}
+			if _, err := runFunction(t, NewIAMPolicyMergeFunction(), variadicOf(args...)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestIAMPolicyValidate(t *testing.T) {
+	readOnly := `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "Read",
+      "Effect": "Allow",
+      "Action": ["s3:GetObject", "s3:ListBucket"],
+      "Resource": ["arn:aws:s3:::assets", "arn:aws:s3:::assets/*"],
+      "Condition": {"ForAnyValue:StringLike": {"s3:prefix": ["public/*"]}, "BoolIfExists": {"aws:SecureTransport": "true"}}
+    }
+  ]
+}`
+	result, err := runFunction(t, NewIAMPolicyValidateFunction(), types.StringValue(readOnly), types.StringValue("managed"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	compact := `{"Version":"2012-10-17","Statement":[{"Sid":"Read","Effect":"Allow","Action":["s3:GetObject","s3:ListBucket"],` +
+		`"Resource":["arn:aws:s3:::assets","arn:aws:s3:::assets/*"],"Condition":{"ForAnyValue:StringLike":{"s3:prefix":["public/*"]},` +
+		`"BoolIfExists":{"aws:SecureTransport":"true"}}}]}`
+	expected := fmt.Sprintf(`{"findings":[],"limit":6144,"size":%d,"valid":true}`, len(compact))
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	tests := []struct {
+		name       string
+		document   string
+		policyType string
+		valid      bool
+		path       string
+		message    string
+	}{
+		{"invalid JSON", `{"Statement": [`, "managed", false, "", "invalid JSON"},
+		{"not an object", `"policy"`, "managed", false, "", "must be a JSON object"},
+		{"too large", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::` + strings.Repeat("a", 2048) + `"}]}`, "inline_user", false, "", "over the 2048 character limit"},
+		{"missing version", `{"Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`, "managed", true, "Version", "2008-10-17"},
+		{"bad version", `{"Version": "2012-10-18", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`, "managed", false, "Version", "Version must be"},
+		{"unknown top-level field", `{"Version": "2012-10-17", "Statements": []}`, "managed", false, "Statements", "unknown policy field"},
+		{"missing statement", `{"Version": "2012-10-17"}`, "managed", false, "Statement", "Statement is required"},
+		{"lowercase effect", `{"Version": "2012-10-17", "Statement": {"Effect": "allow", "Action": "s3:GetObject", "Resource": "*"}}`, "managed", false, "Statement.Effect", "Effect must be"},
+		{"missing action", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Resource": "*"}]}`, "managed", false, "Statement[0]", "Action or NotAction is required"},
+		{"missing resource", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject"}]}`, "inline_role", false, "Statement[0]", "Resource or NotResource is required"},
+		{"unknown statement field", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Actions": "s3:GetObject", "Action": "s3:GetObject", "Resource": "*"}]}`, "managed", false, "Statement[0].Actions", "unknown statement field"},
+		{"action without service", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "GetObject"], "Resource": "*"}]}`, "managed", false, "Statement[0].Action[1]", "service:action"},
+		{"action with double colon", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3::GetObject", "Resource": "*"}]}`, "managed", false, "Statement[0].Action", "invalid action name"},
+		{"action with space", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "ec2:Describe Instances", "Resource": "*"}]}`, "managed", false, "Statement[0].Action", "invalid action name"},
+		{"misspelled service", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "dynamdb:GetItem", "Resource": "*"}]}`, "managed", true, "Statement[0].Action", `did you mean "dynamodb:GetItem"`},
+		{"bad resource", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "my-bucket/*"}]}`, "managed", false, "Statement[0].Resource", "must be \"*\" or an ARN"},
+		{"duplicate sid", `{"Version": "2012-10-17", "Statement": [{"Sid": "A", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}, {"Sid": "A", "Effect": "Deny", "Action": "s3:PutObject", "Resource": "*"}]}`, "managed", false, "Statement[1].Sid", "more than one statement"},
+		{"invalid sid", `{"Version": "2012-10-17", "Statement": [{"Sid": "read-only", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`, "managed", false, "Statement[0].Sid", "letters and digits"},
+		{"unknown condition operator", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*", "Condition": {"StringEqual": {"aws:PrincipalTag/team": "ops"}}}]}`, "managed", false, "Statement[0].Condition.StringEqual", "unknown condition operator"},
+		{"null if exists", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*", "Condition": {"NullIfExists": {"aws:TokenIssueTime": "true"}}}]}`, "managed", false, "Statement[0].Condition.NullIfExists", "unknown condition operator"},
+		{"principal in identity policy", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "s3:GetObject", "Resource": "*"}]}`, "managed", false, "Statement[0]", "Principal is not allowed"},
+		{"trust without principal", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "sts:AssumeRole"}]}`, "trust", false, "Statement[0]", "Principal is required"},
+		{"trust with resource", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole", "Resource": "*"}]}`, "trust", false, "Statement[0]", "Resource is not allowed"},
+		{"trust with wildcard principal", `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "sts:AssumeRole"}]}`, "trust", true, "Statement[0].Principal", "applies to everyone"},
+		{"scp without resource", `{"Version": "2012-10-17", "Statement": [{"Effect": "Deny", "NotAction": ["iam:*", "sts:*"]}]}`, "scp", true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewIAMPolicyValidateFunction(), types.StringValue(tt.document), types.StringValue(tt.policyType))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			object := result.(types.Object).Attributes()
+			if valid := object["valid"].(types.Bool).ValueBool(); valid != tt.valid {
+				t.Errorf("expected valid %t, got %t: %s", tt.valid, valid, jsonOf(t, result))
+			}
+			if tt.message == "" {
+				return
+			}
+			for _, finding := range object["findings"].(types.List).Elements() {
+				attributes := finding.(types.Object).Attributes()
+				if attributes["path"].(types.String).ValueString() == tt.path && strings.Contains(attributes["message"].(types.String).ValueString(), tt.message) {
+					return
+				}
+			}
+			t.Errorf("expected a finding at %q containing %q, got %s", tt.path, tt.message, jsonOf(t, result))
+		})
+	}
+
+	if _, err := runFunction(t, NewIAMPolicyValidateFunction(), types.StringValue(readOnly), types.StringValue("bucket")); err == nil {
+		t.Error("unknown policy type: expected error")
+	}
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// protoMethodInfo, protoServiceInfo and protoDescriptorInfo mirror the
+// object returned by proto_descriptor_info.
+type protoMethodInfo struct {
+	Name            string `tfsdk:"name"`
+	InputType       string `tfsdk:"input_type"`
+	OutputType      string `tfsdk:"output_type"`
+	ClientStreaming bool   `tfsdk:"client_streaming"`
+	ServerStreaming bool   `tfsdk:"server_streaming"`
+}
+
+type protoServiceInfo struct {
+	Name     string            `tfsdk:"name"`
+	FullName string            `tfsdk:"full_name"`
+	Methods  []protoMethodInfo `tfsdk:"methods"`
+}
+
+type protoDescriptorInfo struct {
+	Files    []string           `tfsdk:"files"`
+	Packages []string           `tfsdk:"packages"`
+	Services []protoServiceInfo `tfsdk:"services"`
+	Messages []string           `tfsdk:"messages"`
+	Enums    []string           `tfsdk:"enums"`
+}
+
+var protoMethodType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"name":             types.StringType,
+	"input_type":       types.StringType,
+	"output_type":      types.StringType,
+	"client_streaming": types.BoolType,
+	"server_streaming": types.BoolType,
+}}
+
+var protoServiceType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"name":      types.StringType,
+	"full_name": types.StringType,
+	"methods":   types.ListType{ElemType: protoMethodType},
+}}
+
+func protoFullName(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// collectProtoMessages appends the full names of messages and enums,
+// including nested declarations.
+func collectProtoMessages(prefix string, messages []*descriptorpb.DescriptorProto, info *protoDescriptorInfo) {
+	for _, message := range messages {
+		name := protoFullName(prefix, message.GetName())
+		info.Messages = append(info.Messages, name)
+		for _, enum := range message.GetEnumType() {
+			info.Enums = append(info.Enums, protoFullName(name, enum.GetName()))
+		}
+		collectProtoMessages(name, message.GetNestedType(), info)
+	}
+}
+
+// describeProtoDescriptorSet summarises a serialized FileDescriptorSet.
+func describeProtoDescriptorSet(data []byte) (protoDescriptorInfo, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return protoDescriptorInfo{}, err
+	}
+	if len(set.GetFile()) == 0 {
+		return protoDescriptorInfo{}, fmt.Errorf("descriptor set contains no files")
+	}
+
+	info := protoDescriptorInfo{
+		Files:    []string{},
+		Packages: []string{},
+		Services: []protoServiceInfo{},
+		Messages: []string{},
+		Enums:    []string{},
+	}
+	packages := map[string]bool{}
+
+	for _, file := range set.GetFile() {
+		pkg := file.GetPackage()
+		info.Files = append(info.Files, file.GetName())
+		if pkg != "" && !packages[pkg] {
+			packages[pkg] = true
+			info.Packages = append(info.Packages, pkg)
+		}
+
+		for _, service := range file.GetService() {
+			serviceInfo := protoServiceInfo{
+				Name:     service.GetName(),
+				FullName: protoFullName(pkg, service.GetName()),
+				Methods:  []protoMethodInfo{},
+			}
+			for _, method := range service.GetMethod() {
+				serviceInfo.Methods = append(serviceInfo.Methods, protoMethodInfo{
+					Name:            method.GetName(),
+					InputType:       strings.TrimPrefix(method.GetInputType(), "."),
+					OutputType:      strings.TrimPrefix(method.GetOutputType(), "."),
+					ClientStreaming: method.GetClientStreaming(),
+					ServerStreaming: method.GetServerStreaming(),
+				})
+			}
+			info.Services = append(info.Services, serviceInfo)
+		}
+
+		for _, enum := range file.GetEnumType() {
+			info.Enums = append(info.Enums, protoFullName(pkg, enum.GetName()))
+		}
+		collectProtoMessages(pkg, file.GetMessageType(), &info)
+	}
+
+	sort.Strings(info.Packages)
+	sort.Strings(info.Messages)
+	sort.Strings(info.Enums)
+	sort.Slice(info.Services, func(i, j int) bool {
+		return info.Services[i].FullName < info.Services[j].FullName
+	})
+	return info, nil
+}
+
+// Proto Descriptor Info Function
+var _ function.Function = &ProtoDescriptorInfoFunction{}
+
+type ProtoDescriptorInfoFunction struct{}
+
+func NewProtoDescriptorInfoFunction() function.Function {
+	return &ProtoDescriptorInfoFunction{}
+}
+
+func (f *ProtoDescriptorInfoFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "proto_descriptor_info"
+}
+
+func (f *ProtoDescriptorInfoFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Describes a compiled protobuf descriptor set",
+		Description: "Takes a base64-encoded FileDescriptorSet (as produced by protoc --descriptor_set_out or buf build) and returns " +
+			"its files, packages, services with their methods, and the fully-qualified names of all messages and enums.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "descriptor_set_b64",
+				Description: "The base64-encoded FileDescriptorSet, e.g. from filebase64()",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"files":    types.ListType{ElemType: types.StringType},
+				"packages": types.ListType{ElemType: types.StringType},
+				"services": types.ListType{ElemType: protoServiceType},
+				"messages": types.ListType{ElemType: types.StringType},
+				"enums":    types.ListType{ElemType: types.StringType},
+			},
+		},
+	}
+}
+
+func (f *ProtoDescriptorInfoFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(input))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid base64 string: %s", err)))
+		return
+	}
+
+	info, err := describeProtoDescriptorSet(data)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid FileDescriptorSet: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, info))
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestProtoDescriptorInfo(t *testing.T) {
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("acme/users/v1/users.proto"),
+			Package: proto.String("acme.users.v1"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name:       proto.String("User"),
+					NestedType: []*descriptorpb.DescriptorProto{{Name: proto.String("Address")}},
+					EnumType:   []*descriptorpb.EnumDescriptorProto{{Name: proto.String("Status")}},
+				},
+				{Name: proto.String("GetUserRequest")},
+			},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("Users"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("GetUser"), InputType: proto.String(".acme.users.v1.GetUserRequest"), OutputType: proto.String(".acme.users.v1.User")},
+					{Name: proto.String("Watch"), InputType: proto.String(".acme.users.v1.GetUserRequest"), OutputType: proto.String(".acme.users.v1.User"), ServerStreaming: proto.Bool(true)},
+				},
+			}},
+		}},
+	}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, funcErr := runFunction(t, NewProtoDescriptorInfoFunction(), types.StringValue(base64.StdEncoding.EncodeToString(data)))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+
+	expected := `{"enums":["acme.users.v1.User.Status"],"files":["acme/users/v1/users.proto"],` +
+		`"messages":["acme.users.v1.GetUserRequest","acme.users.v1.User","acme.users.v1.User.Address"],"packages":["acme.users.v1"],` +
+		`"services":[{"full_name":"acme.users.v1.Users","methods":[` +
+		`{"client_streaming":false,"input_type":"acme.users.v1.GetUserRequest","name":"GetUser","output_type":"acme.users.v1.User","server_streaming":false},` +
+		`{"client_streaming":false,"input_type":"acme.users.v1.GetUserRequest","name":"Watch","output_type":"acme.users.v1.User","server_streaming":true}],"name":"Users"}]}`
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestProtoDescriptorInfoErrors(t *testing.T) {
+	for _, input := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte{0xff, 0xff}), ""} {
+		if _, err := runFunction(t, NewProtoDescriptorInfoFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,772 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Compatibility modes understood by schema_compatible, following the
+// schema registry terminology.
+const (
+	compatibilityBackward = "backward"
+	compatibilityForward  = "forward"
+	compatibilityFull     = "full"
+	compatibilityNone     = "none"
+)
+
+// schemaCompatibility is the result object of schema_compatible.
+type schemaCompatibility struct {
+	Compatible bool     `tfsdk:"compatible"`
+	Format     string   `tfsdk:"format"`
+	Issues     []string `tfsdk:"issues"`
+}
+
+// looksLikeAvro reports whether a decoded schema uses Avro constructs.
+func looksLikeAvro(data any) bool {
+	switch v := data.(type) {
+	case string:
+		return true
+	case []any:
+		return true
+	case map[string]any:
+		if _, ok := v["$schema"]; ok {
+			return false
+		}
+		switch v["type"] {
+		case "record", "enum", "fixed", "map", "error":
+			return true
+		}
+		if _, ok := v["fields"]; ok {
+			return true
+		}
+		if items, ok := v["items"]; ok {
+			return looksLikeAvro(items)
+		}
+	}
+	return false
+}
+
+// avroNames indexes the named types (records, enums, fixed) of an Avro
+// schema by full name and by short name.
+type avroNames map[string]map[string]any
+
+func (n avroNames) collect(data any, namespace string) {
+	switch v := data.(type) {
+	case []any:
+		for _, branch := range v {
+			n.collect(branch, namespace)
+		}
+	case map[string]any:
+		switch v["type"] {
+		case "record", "error", "enum", "fixed":
+			name, _ := v["name"].(string)
+			if ns, ok := v["namespace"].(string); ok {
+				namespace = ns
+			}
+			fullName := name
+			if !strings.Contains(name, ".") && namespace != "" {
+				fullName = namespace + "." + name
+			} else if i := strings.LastIndex(name, "."); i >= 0 {
+				namespace = name[:i]
+			}
+			n[fullName] = v
+			n[avroShortName(fullName)] = v
+			if fields, ok := v["fields"].([]any); ok {
+				for _, field := range fields {
+					if f, ok := field.(map[string]any); ok {
+						n.collect(f["type"], namespace)
+					}
+				}
+			}
+		case "array":
+			n.collect(v["items"], namespace)
+		case "map":
+			n.collect(v["values"], namespace)
+		default:
+			n.collect(v["type"], namespace)
+		}
+	}
+}
+
+func avroShortName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// avroChecker resolves writer data against a reader schema following the
+// Avro schema resolution rules.
+type avroChecker struct {
+	reader, writer avroNames
+	issues         []string
+	seen           map[string]bool
+}
+
+// resolveAvro turns a schema reference into its definition. Primitive names are
+// returned as strings, named type references as their definition.
+func resolveAvro(names avroNames, schema any) any {
+	switch v := schema.(type) {
+	case string:
+		if named, ok := names[v]; ok {
+			return named
+		}
+		if named, ok := names[avroShortName(v)]; ok {
+			return named
+		}
+		return v
+	case map[string]any:
+		if t, ok := v["type"].(string); ok {
+			switch t {
+			case "record", "error", "enum", "fixed", "array", "map":
+				return v
+			}
+			return resolveAvro(names, t)
+		}
+		if t, ok := v["type"].([]any); ok {
+			return t
+		}
+	}
+	return schema
+}
+
+func avroTypeName(schema any) string {
+	switch v := schema.(type) {
+	case string:
+		return v
+	case []any:
+		return "union"
+	case map[string]any:
+		if t, ok := v["type"].(string); ok {
+			if t == "error" {
+				return "record"
+			}
+			return t
+		}
+	}
+	return "unknown"
+}
+
+// avroPromotions lists which writer primitives each reader primitive accepts.
+var avroPromotions = map[string][]string{
+	"long":   {"int"},
+	"float":  {"int", "long"},
+	"double": {"int", "long", "float"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+func (c *avroChecker) canRead(path string, readerSchema, writerSchema any) bool {
+	reader := resolveAvro(c.reader, readerSchema)
+	writer := resolveAvro(c.writer, writerSchema)
+
+	if writerUnion, ok := writer.([]any); ok {
+		compatible := true
+		for _, branch := range writerUnion {
+			if !c.canRead(path, reader, branch) {
+				compatible = false
+			}
+		}
+		return compatible
+	}
+
+	if readerUnion, ok := reader.([]any); ok {
+		for _, branch := range readerUnion {
+			seen := make(map[string]bool, len(c.seen))
+			for key, value := range c.seen {
+				seen[key] = value
+			}
+			probe := &avroChecker{reader: c.reader, writer: c.writer, seen: seen}
+			if probe.canRead(path, branch, writer) {
+				return true
+			}
+		}
+		c.addf(path, "reader union has no branch that can read writer type %s", avroTypeName(writer))
+		return false
+	}
+
+	readerType, writerType := avroTypeName(reader), avroTypeName(writer)
+	if readerType != writerType {
+		for _, promoted := range avroPromotions[readerType] {
+			if promoted == writerType {
+				return true
+			}
+		}
+		c.addf(path, "type changed from %s to %s", writerType, readerType)
+		return false
+	}
+
+	readerMap, _ := reader.(map[string]any)
+	writerMap, _ := writer.(map[string]any)
+
+	switch readerType {
+	case "record":
+		return c.canReadRecord(path, readerMap, writerMap)
+	case "enum":
+		return c.canReadEnum(path, readerMap, writerMap)
+	case "fixed":
+		if !c.sameName(readerMap, writerMap) {
+			c.addf(path, "fixed name changed from %v to %v", writerMap["name"], readerMap["name"])
+			return false
+		}
+		if fmt.Sprint(readerMap["size"]) != fmt.Sprint(writerMap["size"]) {
+			c.addf(path, "fixed size changed from %v to %v", writerMap["size"], readerMap["size"])
+			return false
+		}
+	case "array":
+		return c.canRead(path+"[]", readerMap["items"], writerMap["items"])
+	case "map":
+		return c.canRead(path+"{}", readerMap["values"], writerMap["values"])
+	}
+	return true
+}
+
+func (c *avroChecker) sameName(reader, writer map[string]any) bool {
+	writerName, _ := writer["name"].(string)
+	readerName, _ := reader["name"].(string)
+	if avroShortName(readerName) == avroShortName(writerName) {
+		return true
+	}
+	aliases, _ := reader["aliases"].([]any)
+	for _, alias := range aliases {
+		if a, ok := alias.(string); ok && avroShortName(a) == avroShortName(writerName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *avroChecker) canReadRecord(path string, reader, writer map[string]any) bool {
+	if !c.sameName(reader, writer) {
+		c.addf(path, "record name changed from %v to %v", writer["name"], reader["name"])
+		return false
+	}
+
+	key := fmt.Sprintf("%v<-%v", reader["name"], writer["name"])
+	if c.seen[key] {
+		return true
+	}
+	c.seen[key] = true
+
+	writerFields := map[string]map[string]any{}
+	if fields, ok := writer["fields"].([]any); ok {
+		for _, field := range fields {
+			if f, ok := field.(map[string]any); ok {
+				if name, ok := f["name"].(string); ok {
+					writerFields[name] = f
+				}
+			}
+		}
+	}
+
+	compatible := true
+	readerFields, _ := reader["fields"].([]any)
+	for _, field := range readerFields {
+		f, ok := field.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := f["name"].(string)
+		fieldPath := strings.TrimPrefix(path+"."+name, ".")
+
+		writerField, found := writerFields[name]
+		if !found {
+			aliases, _ := f["aliases"].([]any)
+			for _, alias := range aliases {
+				if a, ok := alias.(string); ok {
+					if writerField, found = writerFields[a]; found {
+						break
+					}
+				}
+			}
+		}
+
+		if !found {
+			if _, hasDefault := f["default"]; !hasDefault {
+				c.addf(fieldPath, "field is missing from the writer schema and has no default")
+				compatible = false
+			}
+			continue
+		}
+		if !c.canRead(fieldPath, f["type"], writerField["type"]) {
+			compatible = false
+		}
+	}
+	return compatible
+}
+
+func (c *avroChecker) canReadEnum(path string, reader, writer map[string]any) bool {
+	if !c.sameName(reader, writer) {
+		c.addf(path, "enum name changed from %v to %v", writer["name"], reader["name"])
+		return false
+	}
+	if _, hasDefault := reader["default"]; hasDefault {
+		return true
+	}
+
+	readerSymbols := map[string]bool{}
+	symbols, _ := reader["symbols"].([]any)
+	for _, symbol := range symbols {
+		if s, ok := symbol.(string); ok {
+			readerSymbols[s] = true
+		}
+	}
+
+	compatible := true
+	symbols, _ = writer["symbols"].([]any)
+	for _, symbol := range symbols {
+		if s, ok := symbol.(string); ok && !readerSymbols[s] {
+			c.addf(path, "enum symbol %q is not known to the reader", s)
+			compatible = false
+		}
+	}
+	return compatible
+}
+
+func (c *avroChecker) addf(path, format string, args ...any) {
+	if path == "" {
+		path = "<root>"
+	}
+	c.issues = append(c.issues, path+": "+fmt.Sprintf(format, args...))
+}
+
+// avroCanRead returns the issues preventing reader from reading data
+// written with writer.
+func avroCanRead(reader, writer any) []string {
+	readerNames, writerNames := avroNames{}, avroNames{}
+	readerNames.collect(reader, "")
+	writerNames.collect(writer, "")
+
+	checker := &avroChecker{reader: readerNames, writer: writerNames, seen: map[string]bool{}}
+	checker.canRead("", reader, writer)
+	return checker.issues
+}
+
+// jsonSchemaChecker checks that every document valid under a writer JSON
+// Schema is also valid under a reader schema.
+type jsonSchemaChecker struct {
+	readerRoot, writerRoot any
+	issues                 []string
+	depth                  int
+}
+
+func (c *jsonSchemaChecker) addf(path, format string, args ...any) {
+	if path == "" {
+		path = "<root>"
+	}
+	c.issues = append(c.issues, path+": "+fmt.Sprintf(format, args...))
+}
+
+// resolveJSONSchemaRef follows local $ref pointers.
+func resolveJSONSchemaRef(root, schema any) any {
+	for i := 0; i < 32; i++ {
+		m, ok := schema.(map[string]any)
+		if !ok {
+			return schema
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		resolved, ok := resolveJSONPointer(root, ref)
+		if !ok {
+			return schema
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+func jsonSchemaTypes(schema map[string]any) map[string]bool {
+	result := map[string]bool{}
+	switch t := schema["type"].(type) {
+	case string:
+		result[t] = true
+	case []any:
+		for _, entry := range t {
+			if s, ok := entry.(string); ok {
+				result[s] = true
+			}
+		}
+	}
+	return result
+}
+
+func jsonSchemaNumber(schema map[string]any, key string) *big.Float {
+	n, _ := schema[key].(*big.Float)
+	return n
+}
+
+// jsonSchemaWithout returns a copy of schema without key.
+func jsonSchemaWithout(schema map[string]any, key string) map[string]any {
+	result := make(map[string]any, len(schema))
+	for k, v := range schema {
+		if k != key {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// jsonSchemaMerge adds the keywords of branch to base, branch winning where
+// both set one. Every document valid under both is valid under the result,
+// so checking the result never hides an incompatibility. It returns false
+// when branch is the false schema and nothing is valid.
+func jsonSchemaMerge(base map[string]any, branch any) (map[string]any, bool) {
+	switch branch := branch.(type) {
+	case bool:
+		return base, branch
+	case map[string]any:
+		result := make(map[string]any, len(base)+len(branch))
+		for k, v := range base {
+			result[k] = v
+		}
+		for k, v := range branch {
+			result[k] = v
+		}
+		return result, true
+	}
+	return base, true
+}
+
+// jsonSchemaRational returns a number keyword as an exact decimal.
+func jsonSchemaRational(schema map[string]any, key string) *big.Rat {
+	n := jsonSchemaNumber(schema, key)
+	if n == nil {
+		return nil
+	}
+	r, _ := new(big.Rat).SetString(n.Text('g', -1))
+	return r
+}
+
+// acceptsCombinators checks the anyOf, oneOf and allOf keywords, calling
+// accepts again with them expanded. It returns false when it has done so,
+// and otherwise the reader with its remaining combinators removed.
+func (c *jsonSchemaChecker) acceptsCombinators(path string, reader, writer map[string]any) (map[string]any, bool) {
+	if readerOneOf, ok := reader["oneOf"]; ok {
+		readerEncoded, _ := encodeJSON(readerOneOf)
+		writerEncoded, _ := encodeJSON(writer["oneOf"])
+		if _, ok := writer["oneOf"]; ok && readerEncoded == writerEncoded {
+			c.accepts(path, jsonSchemaWithout(reader, "oneOf"), jsonSchemaWithout(writer, "oneOf"))
+			return nil, false
+		}
+	}
+
+	// A document valid under the writer matches all of its allOf branches
+	// and at least one of its anyOf or oneOf branches.
+	if branches, ok := writer["allOf"].([]any); ok {
+		merged := jsonSchemaWithout(writer, "allOf")
+		for _, branch := range branches {
+			if merged, ok = jsonSchemaMerge(merged, resolveJSONSchemaRef(c.writerRoot, branch)); !ok {
+				return nil, false
+			}
+		}
+		c.accepts(path, reader, merged)
+		return nil, false
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		branches, ok := writer[key].([]any)
+		if !ok {
+			continue
+		}
+		for i, branch := range branches {
+			if merged, ok := jsonSchemaMerge(jsonSchemaWithout(writer, key), resolveJSONSchemaRef(c.writerRoot, branch)); ok {
+				c.accepts(strings.TrimPrefix(fmt.Sprintf("%s.%s[%d]", path, key, i), "."), reader, merged)
+			}
+		}
+		return nil, false
+	}
+
+	if branches, ok := reader["allOf"].([]any); ok {
+		for _, branch := range branches {
+			c.accepts(path, branch, writer)
+		}
+		reader = jsonSchemaWithout(reader, "allOf")
+	}
+	if branches, ok := reader["anyOf"].([]any); ok {
+		accepted := false
+		for _, branch := range branches {
+			trial := &jsonSchemaChecker{readerRoot: c.readerRoot, writerRoot: c.writerRoot, depth: c.depth}
+			if trial.accepts(path, branch, writer); len(trial.issues) == 0 {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			c.addf(path, "no anyOf branch accepts every previously valid value")
+		}
+		reader = jsonSchemaWithout(reader, "anyOf")
+	}
+	return reader, true
+}
+
+func (c *jsonSchemaChecker) accepts(path string, readerSchema, writerSchema any) {
+	c.depth++
+	defer func() { c.depth-- }()
+	if c.depth > 64 {
+		return
+	}
+
+	readerSchema = resolveJSONSchemaRef(c.readerRoot, readerSchema)
+	writerSchema = resolveJSONSchemaRef(c.writerRoot, writerSchema)
+
+	if b, ok := readerSchema.(bool); ok {
+		if !b {
+			if wb, ok := writerSchema.(bool); !ok || wb {
+				c.addf(path, "schema now rejects every value")
+			}
+		}
+		return
+	}
+	reader, ok := readerSchema.(map[string]any)
+	if !ok {
+		return
+	}
+	writer, ok := writerSchema.(map[string]any)
+	if !ok {
+		if len(reader) > 0 {
+			c.addf(path, "constraints added to a previously unconstrained value")
+		}
+		return
+	}
+
+	if reader, ok = c.acceptsCombinators(path, reader, writer); !ok {
+		return
+	}
+
+	// Documents valid under these keywords cannot be compared, so any
+	// change to them is reported rather than assumed compatible.
+	for _, key := range []string{"oneOf", "not", "if", "then", "else"} {
+		if readerValue, ok := reader[key]; ok {
+			readerEncoded, _ := encodeJSON(readerValue)
+			writerEncoded, _ := encodeJSON(writer[key])
+			if _, ok := writer[key]; !ok || readerEncoded != writerEncoded {
+				c.addf(path, "unsupported keyword %q added or changed", key)
+			}
+		}
+	}
+
+	readerTypes, writerTypes := jsonSchemaTypes(reader), jsonSchemaTypes(writer)
+	if len(readerTypes) > 0 {
+		if len(writerTypes) == 0 {
+			c.addf(path, "type constraint added")
+		}
+		for _, t := range sortedKeys(writerTypes) {
+			if !readerTypes[t] && !(t == "integer" && readerTypes["number"]) {
+				c.addf(path, "type %q is no longer accepted", t)
+			}
+		}
+	}
+
+	if readerEnum, ok := reader["enum"].([]any); ok {
+		writerEnum, ok := writer["enum"].([]any)
+		if !ok {
+			c.addf(path, "enum constraint added")
+		}
+		accepted := map[string]bool{}
+		for _, value := range readerEnum {
+			encoded, _ := encodeJSON(value)
+			accepted[encoded] = true
+		}
+		for _, value := range writerEnum {
+			if encoded, _ := encodeJSON(value); !accepted[encoded] {
+				c.addf(path, "enum value %s is no longer accepted", encoded)
+			}
+		}
+	}
+
+	if readerConst, ok := reader["const"]; ok {
+		readerEncoded, _ := encodeJSON(readerConst)
+		writerEncoded, _ := encodeJSON(writer["const"])
+		if _, ok := writer["const"]; !ok || readerEncoded != writerEncoded {
+			c.addf(path, "const constraint added or changed")
+		}
+	}
+
+	if pattern, ok := reader["pattern"].(string); ok && pattern != writer["pattern"] {
+		c.addf(path, "pattern constraint added or changed")
+	}
+	if format, ok := reader["format"].(string); ok && format != writer["format"] {
+		c.addf(path, "format constraint added or changed")
+	}
+	if r := jsonSchemaRational(reader, "multipleOf"); r != nil && r.Sign() > 0 {
+		if w := jsonSchemaRational(writer, "multipleOf"); w == nil || !new(big.Rat).Quo(w, r).IsInt() {
+			c.addf(path, "multipleOf narrowed")
+		}
+	}
+
+	// Upper bounds may only grow and lower bounds may only shrink.
+	for _, key := range []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"} {
+		if r := jsonSchemaNumber(reader, key); r != nil {
+			if w := jsonSchemaNumber(writer, key); w == nil || r.Cmp(w) < 0 {
+				c.addf(path, "%s narrowed", key)
+			}
+		}
+	}
+	for _, key := range []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"} {
+		if r := jsonSchemaNumber(reader, key); r != nil {
+			if w := jsonSchemaNumber(writer, key); w == nil || r.Cmp(w) > 0 {
+				c.addf(path, "%s narrowed", key)
+			}
+		}
+	}
+
+	if readerItems, ok := reader["items"]; ok {
+		c.accepts(path+"[]", readerItems, writer["items"])
+	}
+
+	c.acceptsObject(path, reader, writer)
+}
+
+func (c *jsonSchemaChecker) acceptsObject(path string, reader, writer map[string]any) {
+	readerProperties, _ := reader["properties"].(map[string]any)
+	writerProperties, _ := writer["properties"].(map[string]any)
+
+	writerRequired := map[string]bool{}
+	required, _ := writer["required"].([]any)
+	for _, name := range required {
+		if s, ok := name.(string); ok {
+			writerRequired[s] = true
+		}
+	}
+	required, _ = reader["required"].([]any)
+	for _, name := range required {
+		if s, ok := name.(string); ok && !writerRequired[s] {
+			c.addf(strings.TrimPrefix(path+"."+s, "."), "property is now required")
+		}
+	}
+
+	readerClosed := reader["additionalProperties"] == false
+	writerClosed := writer["additionalProperties"] == false
+	if readerClosed && !writerClosed {
+		c.addf(path, "additional properties are no longer allowed")
+	}
+
+	for _, name := range sortedKeys(writerProperties) {
+		propertyPath := strings.TrimPrefix(path+"."+name, ".")
+		if readerProperty, ok := readerProperties[name]; ok {
+			c.accepts(propertyPath, readerProperty, writerProperties[name])
+		} else if readerClosed {
+			c.addf(propertyPath, "property was removed from a closed object")
+		} else if additional, ok := reader["additionalProperties"].(map[string]any); ok {
+			c.accepts(propertyPath, additional, writerProperties[name])
+		}
+	}
+
+	for _, name := range sortedKeys(readerProperties) {
+		if _, ok := writerProperties[name]; ok || writerClosed {
+			continue
+		}
+		if constrained, ok := readerProperties[name].(map[string]any); (ok && len(constrained) > 0) || readerProperties[name] == false {
+			c.addf(strings.TrimPrefix(path+"."+name, "."), "property was added to an open object and constrains previously allowed values")
+		}
+	}
+}
+
+// jsonSchemaAccepts returns the issues preventing reader from accepting
+// every document valid under writer.
+func jsonSchemaAccepts(reader, writer any) []string {
+	checker := &jsonSchemaChecker{readerRoot: reader, writerRoot: writer}
+	checker.accepts("", reader, writer)
+	return checker.issues
+}
+
+// Schema Compatible Function
+var _ function.Function = &SchemaCompatibleFunction{}
+
+type SchemaCompatibleFunction struct{}
+
+func NewSchemaCompatibleFunction() function.Function {
+	return &SchemaCompatibleFunction{}
+}
+
+func (f *SchemaCompatibleFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "schema_compatible"
+}
+
+func (f *SchemaCompatibleFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks compatibility between two schema versions",
+		Description: "Takes the old and new versions of an Avro or JSON Schema (as JSON) and a compatibility mode, returning an object " +
+			"with a compatible flag, the detected format and a list of issues. 'backward' checks that the new schema can read data " +
+			"written with the old one, 'forward' the reverse, 'full' both and 'none' always succeeds.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "old",
+				Description: "The currently registered schema",
+			},
+			function.StringParameter{
+				Name:        "new",
+				Description: "The proposed schema",
+			},
+			function.StringParameter{
+				Name:        "mode",
+				Description: "One of 'backward', 'forward', 'full' or 'none'",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"compatible": types.BoolType,
+				"format":     types.StringType,
+				"issues":     types.ListType{ElemType: types.StringType},
+			},
+		},
+	}
+}
+
+func (f *SchemaCompatibleFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var oldInput string
+	var newInput string
+	var mode string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &oldInput, &newInput, &mode))
+	if resp.Error != nil {
+		return
+	}
+
+	oldSchema, err := decodeJSON(oldInput)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid schema JSON: %s", err)))
+		return
+	}
+	newSchema, err := decodeJSON(newInput)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("Invalid schema JSON: %s", err)))
+		return
+	}
+
+	avro := looksLikeAvro(oldSchema) || looksLikeAvro(newSchema)
+	result := schemaCompatibility{Format: "json_schema", Issues: []string{}}
+	check := jsonSchemaAccepts
+	if avro {
+		result.Format = "avro"
+		check = avroCanRead
+	}
+
+	switch strings.ToLower(mode) {
+	case compatibilityBackward:
+		result.Issues = append(result.Issues, check(newSchema, oldSchema)...)
+	case compatibilityForward:
+		result.Issues = append(result.Issues, check(oldSchema, newSchema)...)
+	case compatibilityFull:
+		for _, issue := range check(newSchema, oldSchema) {
+			result.Issues = append(result.Issues, "backward: "+issue)
+		}
+		for _, issue := range check(oldSchema, newSchema) {
+			result.Issues = append(result.Issues, "forward: "+issue)
+		}
+	case compatibilityNone:
+	default:
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, fmt.Sprintf("mode must be one of 'backward', 'forward', 'full' or 'none', got %q", mode)))
+		return
+	}
+
+	result.Compatible = len(result.Issues) == 0
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
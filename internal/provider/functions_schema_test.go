@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func runSchemaCompatible(t *testing.T, oldSchema, newSchema, mode string) (bool, string, []string) {
+	t.Helper()
+	result, err := runFunction(t, NewSchemaCompatibleFunction(), types.StringValue(oldSchema), types.StringValue(newSchema), types.StringValue(mode))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	attributes := result.(types.Object).Attributes()
+	var issues []string
+	for _, issue := range attributes["issues"].(types.List).Elements() {
+		issues = append(issues, issue.(types.String).ValueString())
+	}
+	return attributes["compatible"].(types.Bool).ValueBool(), attributes["format"].(types.String).ValueString(), issues
+}
+
+func TestSchemaCompatibleAvro(t *testing.T) {
+	v1 := `{"type":"record","name":"User","namespace":"acme","fields":[
+		{"name":"id","type":"int"},
+		{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","DISABLED"]}}
+	]}`
+
+	tests := []struct {
+		name       string
+		newSchema  string
+		mode       string
+		compatible bool
+	}{
+		{"identical", v1, "full", true},
+		{"add field with default", `{"type":"record","name":"User","namespace":"acme","fields":[
+			{"name":"id","type":"int"},
+			{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","DISABLED"]}},
+			{"name":"email","type":["null","string"],"default":null}]}`, "full", true},
+		{"add field without default", `{"type":"record","name":"User","namespace":"acme","fields":[
+			{"name":"id","type":"int"},
+			{"name":"status","type":"acme.Status"},
+			{"name":"email","type":"string"}]}`, "backward", false},
+		{"promote int to long", `{"type":"record","name":"User","fields":[
+			{"name":"id","type":"long"},
+			{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","DISABLED"]}}]}`, "backward", true},
+		{"promotion is not forward compatible", `{"type":"record","name":"User","fields":[
+			{"name":"id","type":"long"},
+			{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","DISABLED"]}}]}`, "forward", false},
+		{"remove enum symbol", `{"type":"record","name":"User","fields":[
+			{"name":"id","type":"int"},
+			{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE"]}}]}`, "backward", false},
+		{"field renamed with alias", `{"type":"record","name":"User","fields":[
+			{"name":"user_id","aliases":["id"],"type":"int"},
+			{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","DISABLED"]}}]}`, "backward", true},
+		{"record renamed", `{"type":"record","name":"Account","fields":[]}`, "backward", false},
+		{"none mode", `"string"`, "none", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compatible, format, issues := runSchemaCompatible(t, v1, tt.newSchema, tt.mode)
+			if format != "avro" {
+				t.Errorf("expected avro format, got %s", format)
+			}
+			if compatible != tt.compatible {
+				t.Errorf("expected compatible=%v, got %v (issues: %v)", tt.compatible, compatible, issues)
+			}
+		})
+	}
+}
+
+func TestSchemaCompatibleAvroUnions(t *testing.T) {
+	if ok, _, issues := runSchemaCompatible(t, `"string"`, `["null","string"]`, "backward"); !ok {
+		t.Errorf("expected widening to a union to be backward compatible: %v", issues)
+	}
+	if ok, _, _ := runSchemaCompatible(t, `["null","string"]`, `"string"`, "backward"); ok {
+		t.Error("expected narrowing a union to be backward incompatible")
+	}
+}
+
+func TestSchemaCompatibleJSONSchema(t *testing.T) {
+	v1 := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object",
+		"properties":{"id":{"type":"integer"},"name":{"type":"string","maxLength":50}},
+		"required":["id"],"additionalProperties":false}`
+
+	tests := []struct {
+		name       string
+		newSchema  string
+		mode       string
+		compatible bool
+		issue      string
+	}{
+		{"identical", v1, "full", true, ""},
+		{"widen type", `{"type":"object","properties":{"id":{"type":"number"},"name":{"type":"string","maxLength":50}},"required":["id"],"additionalProperties":false}`, "backward", true, ""},
+		{"add optional property to closed object", `{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string","maxLength":50},"email":{"type":"string"}},"required":["id"],"additionalProperties":false}`, "backward", true, ""},
+		{"add required property", `{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"}},"required":["id","name"],"additionalProperties":false}`, "backward", false, "name: property is now required"},
+		{"narrow max length", `{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string","maxLength":10}},"required":["id"],"additionalProperties":false}`, "backward", false, "maxLength narrowed"},
+		{"remove property from closed object", `{"type":"object","properties":{"id":{"type":"integer"}},"required":["id"],"additionalProperties":false}`, "backward", false, "property was removed"},
+		{"open the object", `{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string","maxLength":50}},"required":["id"]}`, "backward", true, ""},
+		{"opening is not forward compatible", `{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string","maxLength":50}},"required":["id"]}`, "forward", false, "additional properties"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compatible, format, issues := runSchemaCompatible(t, v1, tt.newSchema, tt.mode)
+			if format != "json_schema" {
+				t.Errorf("expected json_schema format, got %s", format)
+			}
+			if compatible != tt.compatible {
+				t.Errorf("expected compatible=%v, got %v (issues: %v)", tt.compatible, compatible, issues)
+			}
+			if tt.issue != "" && !strings.Contains(strings.Join(issues, "\n"), tt.issue) {
+				t.Errorf("expected an issue containing %q, got %v", tt.issue, issues)
+			}
+		})
+	}
+}
+
+func TestSchemaCompatibleJSONSchemaCombinators(t *testing.T) {
+	tests := []struct {
+		name, oldSchema, newSchema string
+		compatible                 bool
+		issue                      string
+	}{
+		{"anyOf without the old type", `{"type":"string"}`, `{"anyOf":[{"type":"integer"}]}`, false, "no anyOf branch accepts"},
+		{"anyOf with the old type", `{"type":"string"}`, `{"anyOf":[{"type":"integer"},{"type":"string"}]}`, true, ""},
+		{"narrowed writer branch", `{"anyOf":[{"type":"integer"},{"type":"string"}]}`, `{"anyOf":[{"type":"integer"}]}`, false, "anyOf[1]"},
+		{"widened writer branches", `{"oneOf":[{"type":"integer"},{"type":"string"}]}`, `{"anyOf":[{"type":"number"},{"type":"string"}]}`, true, ""},
+		{"allOf branch narrows", `{"type":"string"}`, `{"allOf":[{"type":"string"},{"maxLength":5}]}`, false, "maxLength narrowed"},
+		{"writer allOf merged", `{"allOf":[{"type":"string"},{"maxLength":5}]}`, `{"type":"string","maxLength":10}`, true, ""},
+		{"unchanged oneOf", `{"oneOf":[{"type":"integer"},{"type":"string"}]}`, `{"oneOf":[{"type":"integer"},{"type":"string"}]}`, true, ""},
+		{"oneOf added", `{"type":"string"}`, `{"oneOf":[{"type":"string"},{"maxLength":3}]}`, false, `unsupported keyword "oneOf"`},
+		{"not added", `{"type":"string"}`, `{"type":"string","not":{"const":""}}`, false, `unsupported keyword "not"`},
+		{"if added", `{"type":"object"}`, `{"type":"object","if":{"required":["a"]},"then":{"required":["b"]}}`, false, `unsupported keyword "if"`},
+		{"format added", `{"type":"string"}`, `{"type":"string","format":"email"}`, false, "format constraint"},
+		{"multipleOf relaxed", `{"type":"number","multipleOf":0.3}`, `{"type":"number","multipleOf":0.1}`, true, ""},
+		{"multipleOf narrowed", `{"type":"number","multipleOf":0.1}`, `{"type":"number","multipleOf":0.3}`, false, "multipleOf narrowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compatible, _, issues := runSchemaCompatible(t, tt.oldSchema, tt.newSchema, "backward")
+			if compatible != tt.compatible {
+				t.Errorf("expected compatible=%v, got %v (issues: %v)", tt.compatible, compatible, issues)
+			}
+			if tt.issue != "" && !strings.Contains(strings.Join(issues, "\n"), tt.issue) {
+				t.Errorf("expected an issue containing %q, got %v", tt.issue, issues)
+			}
+		})
+	}
+}
+
+func TestSchemaCompatibleErrors(t *testing.T) {
+	args := [][]attr.Value{
+		{types.StringValue(`{`), types.StringValue(`{}`), types.StringValue("backward")},
+		{types.StringValue(`{}`), types.StringValue(`{}`), types.StringValue("sideways")},
+	}
+	for _, a := range args {
+		if _, err := runFunction(t, NewSchemaCompatibleFunction(), a...); err == nil {
+			t.Error("expected error")
+		}
+	}
+}
@@ -0,0 +1,837 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// hostnameLabel matches a single RFC 1123 hostname label.
+var hostnameLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// normalizeHostname lowercases a hostname and strips a trailing dot. A
+// leading "*." wildcard label is allowed when allowWildcard is set.
+func normalizeHostname(name string, allowWildcard bool) (string, error) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	if name == "" || len(name) > 253 {
+		return "", fmt.Errorf("hostname must be between 1 and 253 characters")
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if i == 0 && label == "*" && allowWildcard && len(labels) > 1 {
+			continue
+		}
+		if !hostnameLabel.MatchString(label) {
+			return "", fmt.Errorf("invalid hostname label %q", label)
+		}
+	}
+	return name, nil
+}
+
+// allowlist holds compiled allowlist entries by type.
+type allowlist struct {
+	IPv4      []string `tfsdk:"ipv4"`
+	IPv6      []string `tfsdk:"ipv6"`
+	Hostnames []string `tfsdk:"hostnames"`
+}
+
+// compileAllowlist classifies and normalizes entries. Addresses become
+// single-host prefixes, prefixes covered by another entry are dropped, and
+// hostnames covered by a wildcard entry are dropped.
+func compileAllowlist(entries []string) (allowlist, []string) {
+	var prefixes []netip.Prefix
+	hostnames := map[string]bool{}
+	var problems []string
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			prefix, err := parseCIDR(entry)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%q: invalid CIDR", entry))
+				continue
+			}
+			prefixes = append(prefixes, prefix)
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			if addr.Zone() != "" {
+				problems = append(problems, fmt.Sprintf("%q: zoned addresses are not supported", entry))
+				continue
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		hostname, err := normalizeHostname(entry, true)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%q: not an IP address, CIDR or hostname (%s)", entry, err))
+			continue
+		}
+		hostnames[hostname] = true
+	}
+
+	result := allowlist{IPv4: []string{}, IPv6: []string{}, Hostnames: []string{}}
+
+	for _, prefix := range collapsePrefixes(prefixes) {
+		if prefix.Addr().Is4() {
+			result.IPv4 = append(result.IPv4, prefix.String())
+		} else {
+			result.IPv6 = append(result.IPv6, prefix.String())
+		}
+	}
+
+	for _, hostname := range sortedKeys(hostnames) {
+		if _, parent, ok := strings.Cut(hostname, "."); ok && hostnames["*."+parent] && !strings.HasPrefix(hostname, "*.") {
+			continue
+		}
+		result.Hostnames = append(result.Hostnames, hostname)
+	}
+
+	return result, problems
+}
+
+// comparePrefixes orders prefixes by address, with shorter prefixes first.
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
+
+// collapsePrefixes sorts masked prefixes and removes duplicates and any
+// prefix contained in another.
+func collapsePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	sorted := append([]netip.Prefix(nil), prefixes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return comparePrefixes(sorted[i], sorted[j]) < 0
+	})
+
+	var result []netip.Prefix
+	for _, prefix := range sorted {
+		if n := len(result); n > 0 {
+			last := result[n-1]
+			if last.Addr().BitLen() == prefix.Addr().BitLen() && last.Bits() <= prefix.Bits() && last.Contains(prefix.Addr()) {
+				continue
+			}
+		}
+		result = append(result, prefix)
+	}
+	return result
+}
+
+// mergePrefixes collapses prefixes and then repeatedly joins adjacent
+// sibling blocks, such as two /25s into their /24, giving the smallest list
+// covering exactly the same addresses.
+func mergePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	result := collapsePrefixes(prefixes)
+	for {
+		merged := false
+		var next []netip.Prefix
+		for i := 0; i < len(result); i++ {
+			if i+1 < len(result) && result[i].Bits() == result[i+1].Bits() && result[i].Bits() > 0 {
+				parent, _ := result[i].Addr().Prefix(result[i].Bits() - 1)
+				if parent.Addr() == result[i].Addr() && parent.Contains(result[i+1].Addr()) {
+					next = append(next, parent)
+					merged = true
+					i++
+					continue
+				}
+			}
+			next = append(next, result[i])
+		}
+		result = collapsePrefixes(next)
+		if !merged {
+			return result
+		}
+	}
+}
+
+// Compile Allowlist Function
+var _ function.Function = &CompileAllowlistFunction{}
+
+type CompileAllowlistFunction struct{}
+
+func NewCompileAllowlistFunction() function.Function {
+	return &CompileAllowlistFunction{}
+}
+
+func (f *CompileAllowlistFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "compile_allowlist"
+}
+
+func (f *CompileAllowlistFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compiles a mixed allowlist into normalized lists",
+		Description: "Takes a list of hostnames, IP addresses and CIDR blocks, validates each entry and returns separate sorted lists " +
+			"of IPv4 CIDRs, IPv6 CIDRs and hostnames. Addresses become /32 or /128 blocks, host bits are cleared, and duplicates " +
+			"and entries covered by a broader block or wildcard hostname are removed.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "entries",
+				Description: "The allowlist entries; blank entries are ignored",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"ipv4":      types.ListType{ElemType: types.StringType},
+				"ipv6":      types.ListType{ElemType: types.StringType},
+				"hostnames": types.ListType{ElemType: types.StringType},
+			},
+		},
+	}
+}
+
+func (f *CompileAllowlistFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var entries []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &entries))
+	if resp.Error != nil {
+		return
+	}
+
+	result, problems := compileAllowlist(entries)
+	if len(problems) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "Invalid allowlist entries:\n  - "+strings.Join(problems, "\n  - ")))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// wafRegexEscape escapes regular expression metacharacters and writes
+// control characters as \xHH. Other UTF-8 text is left as-is, since RE2
+// engines read \xHH as a code point rather than a byte.
+func wafRegexEscape(input string) string {
+	var b strings.Builder
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case strings.IndexByte(`\.+*?()|[]{}^$/#-`, c) >= 0:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02X`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// WAF Regex Escape Function
+var _ function.Function = &WAFRegexEscapeFunction{}
+
+type WAFRegexEscapeFunction struct{}
+
+func NewWAFRegexEscapeFunction() function.Function {
+	return &WAFRegexEscapeFunction{}
+}
+
+func (f *WAFRegexEscapeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "waf_regex_escape"
+}
+
+func (f *WAFRegexEscapeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Escapes a string for use in a WAF regex",
+		Description: "Escapes regular expression metacharacters so the input matches literally in AWS WAF regex pattern sets, " +
+			"Cloudflare \"matches\" expressions and ModSecurity @rx operators. Control characters are written as \\xHH.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The literal string to escape",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *WAFRegexEscapeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, wafRegexEscape(input)))
+}
+
+// wafField describes how each engine refers to a part of the request.
+type wafField struct {
+	aws         map[string]any
+	cloudflare  string
+	modSecurity string
+}
+
+var wafFields = map[string]wafField{
+	"uri_path":     {map[string]any{"UriPath": map[string]any{}}, "http.request.uri.path", "REQUEST_FILENAME"},
+	"query_string": {map[string]any{"QueryString": map[string]any{}}, "http.request.uri.query", "QUERY_STRING"},
+	"method":       {map[string]any{"Method": map[string]any{}}, "http.request.method", "REQUEST_METHOD"},
+	"body":         {map[string]any{"Body": map[string]any{}}, "http.request.body.raw", "REQUEST_BODY"},
+}
+
+// wafMatches maps match types to AWS positional constraints and ModSecurity
+// operators; Cloudflare uses operators and functions built per match.
+var wafMatches = map[string][2]string{
+	"exact":         {"EXACTLY", "@streq"},
+	"starts_with":   {"STARTS_WITH", "@beginsWith"},
+	"ends_with":     {"ENDS_WITH", "@endsWith"},
+	"contains":      {"CONTAINS", "@contains"},
+	"contains_word": {"CONTAINS_WORD", "@rx"},
+}
+
+var wafTransforms = map[string][2]string{
+	"lowercase":  {"LOWERCASE", "lower"},
+	"url_decode": {"URL_DECODE", "url_decode"},
+}
+
+// wafByteMatch holds a parsed waf_byte_match specification.
+type wafByteMatch struct {
+	engine     string
+	fieldName  string
+	field      wafField
+	header     string
+	match      string
+	value      string
+	transforms []string
+}
+
+func parseWAFByteMatch(data any) (wafByteMatch, []string) {
+	spec, ok := data.(map[string]any)
+	if !ok {
+		return wafByteMatch{}, []string{fmt.Sprintf("specification must be an object, got %s", typeName(data))}
+	}
+
+	var m wafByteMatch
+	var problems []string
+	for _, key := range sortedKeys(spec) {
+		switch key {
+		case "engine", "field", "match", "value", "transforms":
+		default:
+			problems = append(problems, fmt.Sprintf("unknown attribute %q", key))
+		}
+	}
+
+	strs := map[string]string{}
+	for _, key := range []string{"engine", "field", "match", "value"} {
+		value, ok := spec[key].(string)
+		if _, set := spec[key]; set && !ok {
+			problems = append(problems, fmt.Sprintf("%s must be a string", key))
+		}
+		strs[key] = value
+	}
+	m.engine, m.fieldName, m.match, m.value = strs["engine"], strs["field"], strs["match"], strs["value"]
+
+	switch m.engine {
+	case "aws", "cloudflare", "modsecurity":
+	default:
+		problems = append(problems, fmt.Sprintf("engine must be aws, cloudflare or modsecurity, got %q", m.engine))
+	}
+
+	if name, ok := strings.CutPrefix(m.fieldName, "header:"); ok {
+		m.header = strings.ToLower(name)
+		if !hostnameLabel.MatchString(strings.ReplaceAll(m.header, "_", "-")) {
+			problems = append(problems, fmt.Sprintf("invalid header name %q", name))
+		}
+	} else if field, ok := wafFields[m.fieldName]; ok {
+		m.field = field
+	} else {
+		problems = append(problems, fmt.Sprintf("field must be one of %s or header:<name>, got %q", strings.Join(sortedKeys(wafFields), ", "), m.fieldName))
+	}
+
+	if _, ok := wafMatches[m.match]; !ok {
+		problems = append(problems, fmt.Sprintf("match must be one of %s, got %q", strings.Join(sortedKeys(wafMatches), ", "), m.match))
+	}
+	if m.value == "" {
+		problems = append(problems, "value must not be empty")
+	}
+
+	if transforms, ok := spec["transforms"]; ok && transforms != nil {
+		list, ok := transforms.([]any)
+		if !ok {
+			problems = append(problems, "transforms must be a list of strings")
+		}
+		for _, transform := range list {
+			name, _ := transform.(string)
+			if _, ok := wafTransforms[name]; !ok {
+				problems = append(problems, fmt.Sprintf("transform must be one of %s, got %v", strings.Join(sortedKeys(wafTransforms), ", "), transform))
+			}
+			m.transforms = append(m.transforms, name)
+		}
+		if m.engine == "modsecurity" && len(m.transforms) > 0 {
+			problems = append(problems, "transforms are not supported for modsecurity; add t: actions to the rule instead")
+		}
+	}
+
+	return m, problems
+}
+
+// wordPattern matches value as a whole word, as AWS CONTAINS_WORD does.
+func wordPattern(value string) string {
+	return `(?:^|\W)` + wafRegexEscape(value) + `(?:\W|$)`
+}
+
+// cloudflareString quotes a string literal for the Cloudflare rules language.
+func cloudflareString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func (m wafByteMatch) render() (string, error) {
+	match := wafMatches[m.match]
+
+	switch m.engine {
+	case "aws":
+		field := m.field.aws
+		if m.header != "" {
+			field = map[string]any{"SingleHeader": map[string]any{"Name": m.header}}
+		}
+		transformations := []any{}
+		for i, transform := range m.transforms {
+			transformations = append(transformations, map[string]any{"Priority": i, "Type": wafTransforms[transform][0]})
+		}
+		if len(transformations) == 0 {
+			transformations = append(transformations, map[string]any{"Priority": 0, "Type": "NONE"})
+		}
+		encoded, err := json.Marshal(map[string]any{
+			"ByteMatchStatement": map[string]any{
+				"FieldToMatch":         field,
+				"PositionalConstraint": match[0],
+				"SearchString":         m.value,
+				"TextTransformations":  transformations,
+			},
+		})
+		return string(encoded), err
+
+	case "cloudflare":
+		field := m.field.cloudflare
+		if m.header != "" {
+			field = `http.request.headers[` + cloudflareString(m.header) + `][*]`
+		}
+		for _, transform := range m.transforms {
+			field = wafTransforms[transform][1] + "(" + field + ")"
+		}
+		value := cloudflareString(m.value)
+
+		var expression string
+		switch m.match {
+		case "exact":
+			expression = field + " eq " + value
+		case "starts_with", "ends_with":
+			expression = m.match + "(" + field + ", " + value + ")"
+		case "contains":
+			expression = field + " contains " + value
+		case "contains_word":
+			expression = field + " matches " + cloudflareString(wordPattern(m.value))
+		}
+		if m.header != "" {
+			expression = "any(" + expression + ")"
+		}
+		return expression, nil
+	}
+
+	variable := m.field.modSecurity
+	if m.header != "" {
+		variable = "REQUEST_HEADERS:" + m.header
+	}
+	argument := m.value
+	if m.match == "contains_word" {
+		argument = wordPattern(m.value)
+	}
+	return variable + ` "` + match[1] + " " + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(argument) + `"`, nil
+}
+
+// WAF Byte Match Function
+var _ function.Function = &WAFByteMatchFunction{}
+
+type WAFByteMatchFunction struct{}
+
+func NewWAFByteMatchFunction() function.Function {
+	return &WAFByteMatchFunction{}
+}
+
+func (f *WAFByteMatchFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "waf_byte_match"
+}
+
+func (f *WAFByteMatchFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds a WAF string match expression",
+		Description: "Takes an object with engine (aws, cloudflare or modsecurity), field, match, value and optional transforms, " +
+			"and returns the equivalent match for that engine with the value correctly quoted: an AWS WAFv2 ByteMatchStatement " +
+			"as JSON, a Cloudflare rule expression, or a ModSecurity variable and operator.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "object",
+				Description: "The match specification",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *WAFByteMatchFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var object types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &object))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, object)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	match, problems := parseWAFByteMatch(data)
+	if len(problems) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "Invalid WAF match:\n  - "+strings.Join(problems, "\n  - ")))
+		return
+	}
+
+	expression, err := match.render()
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, expression))
+}
+
+// sgProtocols maps protocol names and numbers to the spelling used by the
+// AWS security group API.
+var sgProtocols = map[string]string{
+	"tcp": "tcp", "6": "tcp",
+	"udp": "udp", "17": "udp",
+	"icmp": "icmp", "1": "icmp",
+	"icmpv6": "58", "58": "58",
+	"all": "-1", "-1": "-1",
+}
+
+// sgRule is a normalized security group rule.
+type sgRule struct {
+	Type           string   `tfsdk:"type"`
+	Protocol       string   `tfsdk:"protocol"`
+	FromPort       int64    `tfsdk:"from_port"`
+	ToPort         int64    `tfsdk:"to_port"`
+	CIDRBlocks     []string `tfsdk:"cidr_blocks"`
+	IPv6CIDRBlocks []string `tfsdk:"ipv6_cidr_blocks"`
+	SecurityGroups []string `tfsdk:"security_groups"`
+	Self           bool     `tfsdk:"self"`
+	Description    *string  `tfsdk:"description"`
+}
+
+var sgRuleType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"type":             types.StringType,
+	"protocol":         types.StringType,
+	"from_port":        types.Int64Type,
+	"to_port":          types.Int64Type,
+	"cidr_blocks":      types.ListType{ElemType: types.StringType},
+	"ipv6_cidr_blocks": types.ListType{ElemType: types.StringType},
+	"security_groups":  types.ListType{ElemType: types.StringType},
+	"self":             types.BoolType,
+	"description":      types.StringType,
+}}
+
+// sgRuleInput is a rule after parsing, before rules are grouped.
+type sgRuleInput struct {
+	key            string
+	rule           sgRule
+	prefixes       []netip.Prefix
+	securityGroups []string
+}
+
+// toInt64 converts a whole number to int64.
+func toInt64(data any) (int64, bool) {
+	f, ok := data.(*big.Float)
+	if !ok || !f.IsInt() {
+		return 0, false
+	}
+	i, accuracy := f.Int64()
+	return i, accuracy == big.Exact
+}
+
+// parseSGPorts reads from_port/to_port, a single port, or a "from-to" ports
+// string, in that order of precedence.
+func parseSGPorts(spec map[string]any) (int64, int64, error) {
+	if ports, ok := spec["ports"]; ok && ports != nil {
+		text, ok := ports.(string)
+		if !ok {
+			return 0, 0, fmt.Errorf("ports must be a string such as \"443\" or \"8000-8100\"")
+		}
+		fromText, toText, isRange := strings.Cut(text, "-")
+		if !isRange {
+			toText = fromText
+		}
+		from, errFrom := strconv.ParseInt(strings.TrimSpace(fromText), 10, 64)
+		to, errTo := strconv.ParseInt(strings.TrimSpace(toText), 10, 64)
+		if errFrom != nil || errTo != nil {
+			return 0, 0, fmt.Errorf("invalid ports %q", text)
+		}
+		return from, to, nil
+	}
+
+	if port, ok := spec["port"]; ok && port != nil {
+		p, ok := toInt64(port)
+		if !ok {
+			return 0, 0, fmt.Errorf("port must be a whole number")
+		}
+		return p, p, nil
+	}
+
+	var ports [2]int64
+	for i, key := range []string{"from_port", "to_port"} {
+		value, set := spec[key]
+		if !set || value == nil {
+			return 0, 0, fmt.Errorf("one of from_port/to_port, port or ports is required")
+		}
+		p, ok := toInt64(value)
+		if !ok {
+			return 0, 0, fmt.Errorf("%s must be a whole number", key)
+		}
+		ports[i] = p
+	}
+	return ports[0], ports[1], nil
+}
+
+func parseSGRule(data any) (sgRuleInput, error) {
+	spec, ok := data.(map[string]any)
+	if !ok {
+		return sgRuleInput{}, fmt.Errorf("rule must be an object, got %s", typeName(data))
+	}
+	for _, key := range sortedKeys(spec) {
+		switch key {
+		case "type", "protocol", "from_port", "to_port", "port", "ports", "cidr_blocks", "ipv6_cidr_blocks", "security_groups", "self", "description":
+		default:
+			return sgRuleInput{}, fmt.Errorf("unknown attribute %q", key)
+		}
+	}
+
+	input := sgRuleInput{rule: sgRule{Type: "ingress"}}
+	rule := &input.rule
+
+	if value, ok := spec["type"].(string); ok {
+		rule.Type = strings.ToLower(value)
+	}
+	if rule.Type != "ingress" && rule.Type != "egress" {
+		return input, fmt.Errorf("type must be ingress or egress, got %q", rule.Type)
+	}
+
+	protocol, _ := spec["protocol"].(string)
+	if number, ok := toInt64(spec["protocol"]); ok {
+		protocol = strconv.FormatInt(number, 10)
+	}
+	rule.Protocol, ok = sgProtocols[strings.ToLower(protocol)]
+	if !ok {
+		number, err := strconv.Atoi(protocol)
+		if err != nil || number < 0 || number > 255 {
+			return input, fmt.Errorf("unknown protocol %q", protocol)
+		}
+		rule.Protocol = protocol
+	}
+
+	// Only TCP, UDP, ICMP and ICMPv6 have ports; AWS expects 0 for the rest.
+	switch rule.Protocol {
+	case "tcp", "udp", "icmp", "58":
+		from, to, err := parseSGPorts(spec)
+		if err != nil {
+			return input, err
+		}
+		if rule.Protocol == "icmp" || rule.Protocol == "58" {
+			// For ICMP, from_port is the type and to_port the code, not a range.
+			if from < -1 || from > 255 || to < -1 || to > 255 {
+				return input, fmt.Errorf("invalid ICMP type %d or code %d, expected -1 to 255", from, to)
+			}
+		} else if from < 0 || to > 65535 || from > to {
+			return input, fmt.Errorf("invalid port range %d-%d for protocol %s", from, to, rule.Protocol)
+		}
+		rule.FromPort, rule.ToPort = from, to
+	}
+
+	for _, key := range []string{"cidr_blocks", "ipv6_cidr_blocks", "security_groups"} {
+		value, set := spec[key]
+		if !set || value == nil {
+			continue
+		}
+		list, ok := value.([]any)
+		if !ok {
+			return input, fmt.Errorf("%s must be a list of strings", key)
+		}
+		for _, element := range list {
+			text, ok := element.(string)
+			if !ok {
+				return input, fmt.Errorf("%s must be a list of strings", key)
+			}
+			if key == "security_groups" {
+				input.securityGroups = append(input.securityGroups, text)
+				continue
+			}
+			prefix, err := netip.ParsePrefix(text)
+			if err != nil {
+				return input, fmt.Errorf("invalid CIDR %q", text)
+			}
+			if prefix.Addr().Is4() != (key == "cidr_blocks") {
+				return input, fmt.Errorf("%q is in %s, but cidr_blocks is for IPv4 and ipv6_cidr_blocks for IPv6", text, key)
+			}
+			input.prefixes = append(input.prefixes, prefix.Masked())
+		}
+	}
+
+	if self, ok := spec["self"].(bool); ok {
+		rule.Self = self
+	}
+	if description, ok := spec["description"].(string); ok && description != "" {
+		rule.Description = &description
+	}
+	if len(input.prefixes) == 0 && len(input.securityGroups) == 0 && !rule.Self {
+		return input, fmt.Errorf("rule has no cidr_blocks, ipv6_cidr_blocks, security_groups or self")
+	}
+
+	description := ""
+	if rule.Description != nil {
+		description = *rule.Description
+	}
+	input.key = fmt.Sprintf("%s\x00%s\x00%d\x00%d\x00%s", rule.Type, rule.Protocol, rule.FromPort, rule.ToPort, description)
+	return input, nil
+}
+
+// normalizeSGRules groups rules that share a type, protocol, port range and
+// description, merging their sources into minimal sorted lists.
+func normalizeSGRules(rules []any) ([]sgRule, []string) {
+	groups := map[string]*sgRuleInput{}
+	var problems []string
+
+	for i, data := range rules {
+		input, err := parseSGRule(data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("rule %d: %s", i, err))
+			continue
+		}
+		group, ok := groups[input.key]
+		if !ok {
+			groups[input.key] = &input
+			continue
+		}
+		group.prefixes = append(group.prefixes, input.prefixes...)
+		group.securityGroups = append(group.securityGroups, input.securityGroups...)
+		group.rule.Self = group.rule.Self || input.rule.Self
+	}
+
+	result := make([]sgRule, 0, len(groups))
+	for _, group := range groups {
+		rule := group.rule
+		rule.CIDRBlocks, rule.IPv6CIDRBlocks = []string{}, []string{}
+		for _, prefix := range mergePrefixes(group.prefixes) {
+			if prefix.Addr().Is4() {
+				rule.CIDRBlocks = append(rule.CIDRBlocks, prefix.String())
+			} else {
+				rule.IPv6CIDRBlocks = append(rule.IPv6CIDRBlocks, prefix.String())
+			}
+		}
+		securityGroups := map[string]bool{}
+		for _, id := range group.securityGroups {
+			securityGroups[id] = true
+		}
+		rule.SecurityGroups = sortedKeys(securityGroups)
+		result = append(result, rule)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Type != b.Type {
+			return a.Type > b.Type
+		}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.FromPort != b.FromPort {
+			return a.FromPort < b.FromPort
+		}
+		if a.ToPort != b.ToPort {
+			return a.ToPort < b.ToPort
+		}
+		if a.Description == nil || b.Description == nil {
+			return a.Description == nil && b.Description != nil
+		}
+		return *a.Description < *b.Description
+	})
+	return result, problems
+}
+
+// Normalize SG Rules Function
+var _ function.Function = &NormalizeSGRulesFunction{}
+
+type NormalizeSGRulesFunction struct{}
+
+func NewNormalizeSGRulesFunction() function.Function {
+	return &NormalizeSGRulesFunction{}
+}
+
+func (f *NormalizeSGRulesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_sg_rules"
+}
+
+func (f *NormalizeSGRulesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Canonicalizes a list of security group rules",
+		Description: "Takes security group rule objects, normalizes protocols and port ranges, merges rules that differ only in " +
+			"their sources, merges duplicate, overlapping and adjacent CIDR blocks, and returns the rules in a stable order.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "rules",
+				Description: "A list of rule objects",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: sgRuleType,
+		},
+	}
+}
+
+func (f *NormalizeSGRulesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var rulesValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &rulesValue))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, rulesValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	rules, ok := data.([]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("rules must be a list, got %s", typeName(data))))
+		return
+	}
+
+	result, problems := normalizeSGRules(rules)
+	if len(problems) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "Invalid security group rules:\n  - "+strings.Join(problems, "\n  - ")))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCompileAllowlist(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []string
+		expected string
+	}{
+		{
+			name:     "classify and normalize",
+			entries:  []string{"10.0.0.5/24", "192.168.1.1", " API.Example.com. ", "2001:db8::1", "", "::ffff:172.16.0.1"},
+			expected: `{"hostnames":["api.example.com"],"ipv4":["10.0.0.0/24","172.16.0.1/32","192.168.1.1/32"],"ipv6":["2001:db8::1/128"]}`,
+		},
+		{
+			name:     "duplicates and overlaps",
+			entries:  []string{"10.0.0.0/8", "10.1.0.0/16", "10.2.3.4", "10.0.0.0/8", "11.0.0.1", "2001:db8::/32", "2001:db8:1::/48"},
+			expected: `{"hostnames":[],"ipv4":["10.0.0.0/8","11.0.0.1/32"],"ipv6":["2001:db8::/32"]}`,
+		},
+		{
+			name:     "wildcard hostnames",
+			entries:  []string{"*.example.com", "www.example.com", "a.b.example.com", "example.com", "www.example.com"},
+			expected: `{"hostnames":["*.example.com","a.b.example.com","example.com"],"ipv4":[],"ipv6":[]}`,
+		},
+		{
+			name:     "ipv4-mapped prefixes",
+			entries:  []string{"::ffff:10.0.0.0/104", "::ffff:10.0.0.0/64"},
+			expected: `{"hostnames":[],"ipv4":["10.0.0.0/8"],"ipv6":["::/64"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewCompileAllowlistFunction(), stringList(tt.entries...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := jsonOf(t, result); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	for _, entry := range []string{"10.0.0.0/33", "bad_host", "fe80::1%eth0", "a.*.com", "-a.com"} {
+		if _, err := runFunction(t, NewCompileAllowlistFunction(), stringList(entry)); err == nil {
+			t.Errorf("expected error for %q", entry)
+		}
+	}
+}
+
+func TestWAFRegexEscape(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/admin/login.php", `\/admin\/login\.php`},
+		{"a+b*(c)?[d]{e}^$|\\", `a\+b\*\(c\)\?\[d\]\{e\}\^\$\|\\`},
+		{"tab\there\x00", `tab\x09here\x00`},
+		{"café", "café"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewWAFRegexEscapeFunction(), types.StringValue(tt.input))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%q: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestWAFByteMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected string
+	}{
+		{
+			name:     "aws",
+			spec:     `{"engine":"aws","field":"uri_path","match":"starts_with","value":"/admin","transforms":["url_decode","lowercase"]}`,
+			expected: `{"ByteMatchStatement":{"FieldToMatch":{"UriPath":{}},"PositionalConstraint":"STARTS_WITH","SearchString":"/admin","TextTransformations":[{"Priority":0,"Type":"URL_DECODE"},{"Priority":1,"Type":"LOWERCASE"}]}}`,
+		},
+		{
+			name:     "aws header",
+			spec:     `{"engine":"aws","field":"header:User-Agent","match":"contains","value":"sqlmap"}`,
+			expected: `{"ByteMatchStatement":{"FieldToMatch":{"SingleHeader":{"Name":"user-agent"}},"PositionalConstraint":"CONTAINS","SearchString":"sqlmap","TextTransformations":[{"Priority":0,"Type":"NONE"}]}}`,
+		},
+		{
+			name:     "cloudflare",
+			spec:     `{"engine":"cloudflare","field":"uri_path","match":"exact","value":"/say \"hi\"","transforms":["lowercase"]}`,
+			expected: `lower(http.request.uri.path) eq "/say \"hi\""`,
+		},
+		{
+			name:     "cloudflare header function",
+			spec:     `{"engine":"cloudflare","field":"header:X-Env","match":"ends_with","value":"prod"}`,
+			expected: `any(ends_with(http.request.headers["x-env"][*], "prod"))`,
+		},
+		{
+			name:     "cloudflare word",
+			spec:     `{"engine":"cloudflare","field":"query_string","match":"contains_word","value":"a.b"}`,
+			expected: `http.request.uri.query matches "(?:^|\\W)a\\.b(?:\\W|$)"`,
+		},
+		{
+			name:     "modsecurity",
+			spec:     `{"engine":"modsecurity","field":"method","match":"exact","value":"DELETE"}`,
+			expected: `REQUEST_METHOD "@streq DELETE"`,
+		},
+		{
+			name:     "modsecurity word",
+			spec:     `{"engine":"modsecurity","field":"header:Referer","match":"contains_word","value":"evil\"site"}`,
+			expected: `REQUEST_HEADERS:referer "@rx (?:^|\\W)evil\"site(?:\\W|$)"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewWAFByteMatchFunction(), dynamicOf(t, mustJSON(t, tt.spec)))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.(types.String).ValueString(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWAFByteMatchErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"not an object", `"x"`},
+		{"unknown engine", `{"engine":"nginx","field":"uri_path","match":"exact","value":"/"}`},
+		{"unknown field", `{"engine":"aws","field":"cookie","match":"exact","value":"/"}`},
+		{"unknown match", `{"engine":"aws","field":"uri_path","match":"regex","value":"/"}`},
+		{"empty value", `{"engine":"aws","field":"uri_path","match":"exact","value":""}`},
+		{"missing engine", `{"field":"uri_path","match":"exact","value":"/"}`},
+		{"unknown attribute", `{"engine":"aws","field":"uri_path","match":"exact","value":"/","negate":true}`},
+		{"modsecurity transforms", `{"engine":"modsecurity","field":"uri_path","match":"exact","value":"/","transforms":["lowercase"]}`},
+		{"bad header", `{"engine":"aws","field":"header:bad header","match":"exact","value":"/"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFunction(t, NewWAFByteMatchFunction(), dynamicOf(t, mustJSON(t, tt.spec))); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNormalizeSGRules(t *testing.T) {
+	rules := `[
+		{"protocol": "TCP", "port": 443, "cidr_blocks": ["10.0.1.0/25"], "ipv6_cidr_blocks": ["2001:db8::/64"]},
+		{"protocol": "6", "from_port": 443, "to_port": 443, "cidr_blocks": ["10.0.1.128/25", "10.0.1.5/32"]},
+		{"protocol": "tcp", "ports": "8000-8100", "security_groups": ["sg-b", "sg-a", "sg-b"]},
+		{"type": "egress", "protocol": "all", "from_port": 0, "to_port": 65535, "cidr_blocks": ["0.0.0.0/0"]},
+		{"protocol": "icmp", "from_port": -1, "to_port": -1, "self": true, "description": "ping"},
+		{"protocol": "icmp", "from_port": 8, "to_port": 0, "cidr_blocks": ["10.0.0.0/8"]},
+		{"protocol": "icmpv6", "from_port": 3, "to_port": 4, "ipv6_cidr_blocks": ["2001:db8::/32"]},
+		{"protocol": 50, "cidr_blocks": ["192.0.2.0/24"], "description": "esp"}
+	]`
+	expected := `[` +
+		`{"cidr_blocks":["192.0.2.0/24"],"description":"esp","from_port":0,"ipv6_cidr_blocks":[],"protocol":"50","security_groups":[],"self":false,"to_port":0,"type":"ingress"},` +
+		`{"cidr_blocks":[],"description":null,"from_port":3,"ipv6_cidr_blocks":["2001:db8::/32"],"protocol":"58","security_groups":[],"self":false,"to_port":4,"type":"ingress"},` +
+		`{"cidr_blocks":[],"description":"ping","from_port":-1,"ipv6_cidr_blocks":[],"protocol":"icmp","security_groups":[],"self":true,"to_port":-1,"type":"ingress"},` +
+		`{"cidr_blocks":["10.0.0.0/8"],"description":null,"from_port":8,"ipv6_cidr_blocks":[],"protocol":"icmp","security_groups":[],"self":false,"to_port":0,"type":"ingress"},` +
+		`{"cidr_blocks":["10.0.1.0/24"],"description":null,"from_port":443,"ipv6_cidr_blocks":["2001:db8::/64"],"protocol":"tcp","security_groups":[],"self":false,"to_port":443,"type":"ingress"},` +
+		`{"cidr_blocks":[],"description":null,"from_port":8000,"ipv6_cidr_blocks":[],"protocol":"tcp","security_groups":["sg-a","sg-b"],"self":false,"to_port":8100,"type":"ingress"},` +
+		`{"cidr_blocks":["0.0.0.0/0"],"description":null,"from_port":0,"ipv6_cidr_blocks":[],"protocol":"-1","security_groups":[],"self":false,"to_port":0,"type":"egress"}]`
+
+	result, err := runFunction(t, NewNormalizeSGRulesFunction(), dynamicOf(t, mustJSON(t, rules)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestNormalizeSGRulesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{"not a list", `{"protocol": "tcp"}`},
+		{"unknown protocol", `[{"protocol": "gre", "port": 1, "self": true}]`},
+		{"missing ports", `[{"protocol": "tcp", "self": true}]`},
+		{"reversed range", `[{"protocol": "tcp", "ports": "90-80", "self": true}]`},
+		{"port out of range", `[{"protocol": "udp", "port": 70000, "self": true}]`},
+		{"icmp type out of range", `[{"protocol": "icmp", "from_port": 256, "to_port": 0, "self": true}]`},
+		{"icmp code out of range", `[{"protocol": "icmp", "from_port": 3, "to_port": -2, "self": true}]`},
+		{"bad cidr", `[{"protocol": "tcp", "port": 22, "cidr_blocks": ["10.0.0.0/40"]}]`},
+		{"ipv6 in cidr_blocks", `[{"protocol": "tcp", "port": 22, "cidr_blocks": ["2001:db8::/64"]}]`},
+		{"ipv4 in ipv6_cidr_blocks", `[{"protocol": "tcp", "port": 22, "ipv6_cidr_blocks": ["10.0.0.0/8"]}]`},
+		{"no sources", `[{"protocol": "tcp", "port": 22}]`},
+		{"bad type", `[{"type": "inbound", "protocol": "tcp", "port": 22, "self": true}]`},
+		{"unknown attribute", `[{"protocol": "tcp", "port": 22, "self": true, "cidr": "x"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFunction(t, NewNormalizeSGRulesFunction(), dynamicOf(t, mustJSON(t, tt.rules))); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
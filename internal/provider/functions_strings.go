@@ -0,0 +1,452 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	markdownhtml "github.com/yuin/goldmark/renderer/html"
+	"golang.org/x/net/html"
+)
+
+// markdownDefaults lists the options accepted by markdown_to_html.
+var markdownDefaults = map[string]any{
+	"sanitize": true,
+	"gfm":      false,
+}
+
+// renderMarkdown converts CommonMark to HTML. When sanitizing, raw HTML is
+// dropped and links with dangerous schemes such as javascript: are emptied.
+func renderMarkdown(input string, options map[string]any) (string, error) {
+	var rendererOptions []goldmark.Option
+	if options["gfm"].(bool) {
+		rendererOptions = append(rendererOptions, goldmark.WithExtensions(extension.GFM))
+	}
+	if !options["sanitize"].(bool) {
+		rendererOptions = append(rendererOptions, goldmark.WithRendererOptions(markdownhtml.WithUnsafe()))
+	}
+
+	var b bytes.Buffer
+	if err := goldmark.New(rendererOptions...).Convert([]byte(input), &b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Markdown To HTML Function
+var _ function.Function = &MarkdownToHTMLFunction{}
+
+type MarkdownToHTMLFunction struct{}
+
+func NewMarkdownToHTMLFunction() function.Function {
+	return &MarkdownToHTMLFunction{}
+}
+
+func (f *MarkdownToHTMLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "markdown_to_html"
+}
+
+func (f *MarkdownToHTMLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Renders Markdown as HTML",
+		Description: "Renders CommonMark Markdown as HTML. Output is sanitized by default, dropping raw HTML and dangerous link " +
+			"URLs. Options: sanitize (default true) and gfm (default false) to enable GitHub Flavored Markdown tables, " +
+			"strikethrough, task lists and autolinks.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The Markdown to render",
+			},
+			function.DynamicParameter{
+				Name:           "options",
+				Description:    "An object of options, or null for the defaults",
+				AllowNullValue: true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *MarkdownToHTMLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	var optionsValue types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &optionsValue))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, optionsValue)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	options, err := parseOptions(data, markdownDefaults)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	result, err := renderMarkdown(input, options)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("Unable to render Markdown: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// stripTags returns the text content of an HTML fragment with entities
+// decoded. The contents of script and style elements and comments are
+// dropped.
+func stripTags(input string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	skip := ""
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return b.String()
+		case html.StartTagToken:
+			if name, _ := tokenizer.TagName(); skip == "" && (string(name) == "script" || string(name) == "style") {
+				skip = string(name)
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == skip {
+				skip = ""
+			}
+		case html.TextToken:
+			if skip == "" {
+				b.Write(tokenizer.Text())
+			}
+		}
+	}
+}
+
+// HTML Escape Function
+var _ function.Function = &HTMLEscapeFunction{}
+
+type HTMLEscapeFunction struct{}
+
+func NewHTMLEscapeFunction() function.Function {
+	return &HTMLEscapeFunction{}
+}
+
+func (f *HTMLEscapeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "html_escape"
+}
+
+func (f *HTMLEscapeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Escapes a string for HTML",
+		Description: "Escapes <, >, &, ' and \" so the string can be placed safely in HTML text or a quoted attribute value.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The string to escape",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *HTMLEscapeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, html.EscapeString(input)))
+}
+
+// HTML Unescape Function
+var _ function.Function = &HTMLUnescapeFunction{}
+
+type HTMLUnescapeFunction struct{}
+
+func NewHTMLUnescapeFunction() function.Function {
+	return &HTMLUnescapeFunction{}
+}
+
+func (f *HTMLUnescapeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "html_unescape"
+}
+
+func (f *HTMLUnescapeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Unescapes HTML entities",
+		Description: "Decodes named entities such as &amp; and numeric entities such as &#39; and &#x27; in a string.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The string to unescape",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *HTMLUnescapeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, html.UnescapeString(input)))
+}
+
+// HTML Strip Tags Function
+var _ function.Function = &HTMLStripTagsFunction{}
+
+type HTMLStripTagsFunction struct{}
+
+func NewHTMLStripTagsFunction() function.Function {
+	return &HTMLStripTagsFunction{}
+}
+
+func (f *HTMLStripTagsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "html_strip_tags"
+}
+
+func (f *HTMLStripTagsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Removes HTML tags from a string",
+		Description: "Returns the text content of an HTML fragment with tags and comments removed and entities decoded. The " +
+			"contents of script and style elements are removed as well.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The HTML to strip",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *HTMLStripTagsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, stripTags(input)))
+}
+
+// shellSafe matches words that need no quoting in a POSIX shell.
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9@%+=:,./_-]+$`)
+
+// shellQuote quotes a word for a POSIX shell, using single quotes unless
+// the word is made only of safe characters.
+func shellQuote(input string) (string, error) {
+	if strings.ContainsRune(input, 0) {
+		return "", fmt.Errorf("shell words cannot contain NUL characters")
+	}
+	if shellSafe.MatchString(input) {
+		return input, nil
+	}
+	return "'" + strings.ReplaceAll(input, "'", `'"'"'`) + "'", nil
+}
+
+// powershellQuotes are the characters PowerShell accepts as single quotes,
+// including the typographic variants.
+var powershellQuotes = strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019", "\u201A", "\u201A\u201A", "\u201B", "\u201B\u201B")
+
+// powershellQuote wraps a string in PowerShell single quotes, in which no
+// expansion takes place.
+func powershellQuote(input string) string {
+	return "'" + powershellQuotes.Replace(input) + "'"
+}
+
+// Shell Quote Function
+var _ function.Function = &ShellQuoteFunction{}
+
+type ShellQuoteFunction struct{}
+
+func NewShellQuoteFunction() function.Function {
+	return &ShellQuoteFunction{}
+}
+
+func (f *ShellQuoteFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "shell_quote"
+}
+
+func (f *ShellQuoteFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Quotes a string for a POSIX shell",
+		Description: "Quotes a string so a POSIX shell such as sh or bash reads it as a single word with no expansion. Strings " +
+			"made only of letters, digits and @%+=:,./_- are returned unchanged.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The string to quote",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ShellQuoteFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	result, err := shellQuote(input)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Shell Quote List Function
+var _ function.Function = &ShellQuoteListFunction{}
+
+type ShellQuoteListFunction struct{}
+
+func NewShellQuoteListFunction() function.Function {
+	return &ShellQuoteListFunction{}
+}
+
+func (f *ShellQuoteListFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "shell_quote_list"
+}
+
+func (f *ShellQuoteListFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Builds a POSIX shell command line",
+		Description: "Quotes each element with shell_quote and joins them with spaces, producing a command line that runs the first element with the rest as its arguments.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "words",
+				Description: "The command and its arguments",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ShellQuoteListFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var words []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &words))
+	if resp.Error != nil {
+		return
+	}
+
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		var err error
+		if quoted[i], err = shellQuote(word); err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Element %d: %s", i, err)))
+			return
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, strings.Join(quoted, " ")))
+}
+
+// PowerShell Quote Function
+var _ function.Function = &PowerShellQuoteFunction{}
+
+type PowerShellQuoteFunction struct{}
+
+func NewPowerShellQuoteFunction() function.Function {
+	return &PowerShellQuoteFunction{}
+}
+
+func (f *PowerShellQuoteFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "powershell_quote"
+}
+
+func (f *PowerShellQuoteFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Quotes a string for PowerShell",
+		Description: "Wraps a string in PowerShell single quotes, in which no variable or subexpression expansion takes place, " +
+			"doubling any embedded single quotes including the typographic variants PowerShell also accepts.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The string to quote",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PowerShellQuoteFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, powershellQuote(input)))
+}
+
+// PowerShell Quote List Function
+var _ function.Function = &PowerShellQuoteListFunction{}
+
+type PowerShellQuoteListFunction struct{}
+
+func NewPowerShellQuoteListFunction() function.Function {
+	return &PowerShellQuoteListFunction{}
+}
+
+func (f *PowerShellQuoteListFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "powershell_quote_list"
+}
+
+func (f *PowerShellQuoteListFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds a PowerShell command line",
+		Description: "Quotes each element with powershell_quote and joins them with spaces after the & call operator, producing " +
+			"a command line that runs the first element with the rest as its arguments.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "words",
+				Description: "The command and its arguments",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PowerShellQuoteListFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var words []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &words))
+	if resp.Error != nil {
+		return
+	}
+
+	if len(words) == 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "words must contain at least the command"))
+		return
+	}
+
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = powershellQuote(word)
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, "& "+strings.Join(quoted, " ")))
+}
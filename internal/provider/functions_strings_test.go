@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	input := "# Runbook\n\nRestart **web** with `systemctl`.\n\n<script>alert(1)</script>\n\n[bad](javascript:alert(1)) [docs](https://example.com)\n\n| a | b |\n|---|---|\n| 1 | ~~2~~ |\n"
+
+	tests := []struct {
+		name     string
+		options  string
+		expected string
+	}{
+		{
+			"sanitized commonmark",
+			`null`,
+			"<h1>Runbook</h1>\n<p>Restart <strong>web</strong> with <code>systemctl</code>.</p>\n<!-- raw HTML omitted -->\n" +
+				"<p><a href=\"\">bad</a> <a href=\"https://example.com\">docs</a></p>\n<p>| a | b |\n|---|---|\n| 1 | ~~2~~ |</p>\n",
+		},
+		{
+			"unsanitized gfm",
+			`{"sanitize": false, "gfm": true}`,
+			"<h1>Runbook</h1>\n<p>Restart <strong>web</strong> with <code>systemctl</code>.</p>\n<script>alert(1)</script>\n" +
+				"<p><a href=\"javascript:alert(1)\">bad</a> <a href=\"https://example.com\">docs</a></p>\n" +
+				"<table>\n<thead>\n<tr>\n<th>a</th>\n<th>b</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>1</td>\n<td><del>2</del></td>\n</tr>\n</tbody>\n</table>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runFunction(t, NewMarkdownToHTMLFunction(), types.StringValue(input), dynamicOf(t, mustJSON(t, tt.options)))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.(types.String).ValueString(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := runFunction(t, NewMarkdownToHTMLFunction(), types.StringValue(input), dynamicOf(t, mustJSON(t, `{"unsafe": true}`))); err == nil {
+		t.Error("expected error for unknown option")
+	}
+}
+
+func TestHTMLEscape(t *testing.T) {
+	tests := []struct {
+		input   string
+		escaped string
+	}{
+		{`<img src=x onerror="alert('hi')">`, `&lt;img src=x onerror=&#34;alert(&#39;hi&#39;)&#34;&gt;`},
+		{"Fish & Chips", "Fish &amp; Chips"},
+		{"café", "café"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewHTMLEscapeFunction(), types.StringValue(tt.input))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.escaped {
+			t.Errorf("escape %q: expected %q, got %q", tt.input, tt.escaped, got)
+		}
+
+		result, err = runFunction(t, NewHTMLUnescapeFunction(), types.StringValue(tt.escaped))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.input {
+			t.Errorf("unescape %q: expected %q, got %q", tt.escaped, tt.input, got)
+		}
+	}
+
+	result, err := runFunction(t, NewHTMLUnescapeFunction(), types.StringValue("&copy; 2024 &#x2603; &eacute &bogus;"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := result.(types.String).ValueString(); got != "© 2024 ☃ é &bogus;" {
+		t.Errorf("unexpected unescape result %q", got)
+	}
+}
+
+func TestHTMLStripTags(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`<p>Hello <b>world</b> &amp; <a href="#">friends</a></p>`, "Hello world & friends"},
+		{`<style>p{color:red}</style>Visible<script>alert("<b>x</b>")</script><!-- hidden --> text`, "Visible text"},
+		{"plain 1 < 2", "plain 1 < 2"},
+		{"unclosed <b", "unclosed "},
+		{"literal &amp;lt;b&amp;gt;", "literal &lt;b&gt;"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewHTMLStripTagsFunction(), types.StringValue(tt.input))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/usr/bin/env", "/usr/bin/env"},
+		{"key=value,a@b:1%", "key=value,a@b:1%"},
+		{"", "''"},
+		{"hello world", "'hello world'"},
+		{"it's $(rm -rf /) `x` \"$HOME\"", `'it'"'"'s $(rm -rf /) ` + "`x`" + ` "$HOME"'`},
+		{"line\nbreak", "'line\nbreak'"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewShellQuoteFunction(), types.StringValue(tt.input))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", tt.input, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewShellQuoteFunction(), types.StringValue("a\x00b")); err == nil {
+		t.Error("expected error for NUL")
+	}
+
+	result, err := runFunction(t, NewShellQuoteListFunction(), stringList("aws", "s3", "cp", "my file.txt", "s3://bucket/it's"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := result.(types.String).ValueString(); got != `aws s3 cp 'my file.txt' 's3://bucket/it'"'"'s'` {
+		t.Errorf("unexpected command line %q", got)
+	}
+	if _, err := runFunction(t, NewShellQuoteListFunction(), stringList("ok", "a\x00")); err == nil {
+		t.Error("expected error for NUL in list")
+	}
+}
+
+func TestPowerShellQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "''"},
+		{"C:\\Program Files\\App", "'C:\\Program Files\\App'"},
+		{"$env:PATH; $(Remove-Item x) `n", "'$env:PATH; $(Remove-Item x) `n'"},
+		{"it's", "'it''s'"},
+		{"it\u2019s \u2018x\u201B\u201A", "'it\u2019\u2019s \u2018\u2018x\u201B\u201B\u201A\u201A'"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewPowerShellQuoteFunction(), types.StringValue(tt.input))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", tt.input, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewPowerShellQuoteListFunction(), stringList("C:\\Tools\\setup.exe", "/name", "O'Brien"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := result.(types.String).ValueString(); got != `& 'C:\Tools\setup.exe' '/name' 'O''Brien'` {
+		t.Errorf("unexpected command line %q", got)
+	}
+	if _, err := runFunction(t, NewPowerShellQuoteListFunction(), stringList()); err == nil {
+		t.Error("expected error for empty list")
+	}
+}
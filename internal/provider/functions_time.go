@@ -0,0 +1,1893 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // IANA timezones must not depend on the host's zoneinfo
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// namedTimeLayouts maps the named formats accepted for layouts to the Go
+// layouts they try, in order. The unix format is handled separately.
+var namedTimeLayouts = map[string][]string{
+	"rfc3339":       {time.RFC3339Nano},
+	"rfc1123":       {time.RFC1123, time.RFC1123Z},
+	"iso8601-basic": {"20060102T150405.999999999Z0700", "20060102T150405Z0700", "20060102T1504Z0700", "20060102T150405", "20060102"},
+}
+
+// loadLocation loads an IANA timezone. An empty name means UTC; "Local" is
+// rejected because it depends on the machine running Terraform.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	if name == "Local" {
+		return nil, fmt.Errorf("timezone %q depends on the machine running Terraform, use an IANA name such as Europe/Berlin", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q, expected an IANA name such as America/New_York", name)
+	}
+	return loc, nil
+}
+
+// parseUnixSeconds parses decimal seconds since the Unix epoch, such as
+// 1700000000 or -1.5, keeping nanosecond precision.
+func parseUnixSeconds(text string) (time.Time, error) {
+	whole, frac, hasFrac := strings.Cut(text, ".")
+	if whole == "" || whole == "-" || (hasFrac && (frac == "" || len(frac) > 9)) {
+		return time.Time{}, fmt.Errorf("invalid unix timestamp %q", text)
+	}
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid unix timestamp %q", text)
+	}
+	var nanos int64
+	if hasFrac {
+		if nanos, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64); err != nil || nanos < 0 {
+			return time.Time{}, fmt.Errorf("invalid unix timestamp %q", text)
+		}
+		if strings.HasPrefix(whole, "-") {
+			nanos = -nanos
+		}
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+// checkZoneAbbreviation rejects times whose zone abbreviation Go did not
+// recognise. Go reads an unknown abbreviation such as PST as offset zero, so
+// only UTC, GMT, numeric offsets and abbreviations defined by loc are
+// trusted.
+func checkZoneAbbreviation(t time.Time, loc *time.Location) error {
+	name, offset := t.Zone()
+	if name == "" || offset != 0 || t.Location() == loc || t.Location() == time.UTC {
+		return nil
+	}
+	if name == "UTC" || name == "GMT" || name == "Z" {
+		return nil
+	}
+	return fmt.Errorf("zone abbreviation %q is not defined by timezone %q; use a numeric offset or the timezone that defines it", name, loc)
+}
+
+// parseTime parses input with a named format or a Go layout. Inputs without
+// a UTC offset are read in loc.
+func parseTime(input, layout string, loc *time.Location) (time.Time, error) {
+	if layout == "unix" {
+		return parseUnixSeconds(input)
+	}
+	layouts, named := namedTimeLayouts[layout]
+	if !named {
+		layouts = []string{layout}
+	}
+	var err error
+	for _, candidate := range layouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(candidate, input, loc); err == nil {
+			return t, checkZoneAbbreviation(t, loc)
+		}
+	}
+	if named {
+		return time.Time{}, fmt.Errorf("%q is not a valid %s timestamp", input, layout)
+	}
+	return time.Time{}, fmt.Errorf("%q does not match layout %q: %s", input, layout, strings.TrimPrefix(err.Error(), "parsing time "))
+}
+
+// parseTimestamp parses an RFC 3339 timestamp argument, the format produced
+// by timestamp() and by the functions in this file.
+func parseTimestamp(text string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid RFC 3339 timestamp %q", text)
+	}
+	return t, nil
+}
+
+// formatTimestamp renders t as RFC 3339, with fractional seconds only when
+// they are set.
+func formatTimestamp(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// timeComponents are the calendar fields of a time in its location.
+type timeComponents struct {
+	Year       int64  `tfsdk:"year"`
+	Month      int64  `tfsdk:"month"`
+	Day        int64  `tfsdk:"day"`
+	Hour       int64  `tfsdk:"hour"`
+	Minute     int64  `tfsdk:"minute"`
+	Second     int64  `tfsdk:"second"`
+	Nanosecond int64  `tfsdk:"nanosecond"`
+	Weekday    string `tfsdk:"weekday"`
+	DayOfYear  int64  `tfsdk:"day_of_year"`
+	ISOWeek    int64  `tfsdk:"iso_week"`
+	Unix       int64  `tfsdk:"unix"`
+	Offset     string `tfsdk:"offset"`
+	Timezone   string `tfsdk:"timezone"`
+}
+
+var timeComponentsType = map[string]attr.Type{
+	"year":        types.Int64Type,
+	"month":       types.Int64Type,
+	"day":         types.Int64Type,
+	"hour":        types.Int64Type,
+	"minute":      types.Int64Type,
+	"second":      types.Int64Type,
+	"nanosecond":  types.Int64Type,
+	"weekday":     types.StringType,
+	"day_of_year": types.Int64Type,
+	"iso_week":    types.Int64Type,
+	"unix":        types.Int64Type,
+	"offset":      types.StringType,
+	"timezone":    types.StringType,
+}
+
+// describeTime returns the components of t in its location.
+func describeTime(t time.Time) timeComponents {
+	_, week := t.ISOWeek()
+	return timeComponents{
+		Year:       int64(t.Year()),
+		Month:      int64(t.Month()),
+		Day:        int64(t.Day()),
+		Hour:       int64(t.Hour()),
+		Minute:     int64(t.Minute()),
+		Second:     int64(t.Second()),
+		Nanosecond: int64(t.Nanosecond()),
+		Weekday:    strings.ToLower(t.Weekday().String()),
+		DayOfYear:  int64(t.YearDay()),
+		ISOWeek:    int64(week),
+		Unix:       t.Unix(),
+		Offset:     t.Format("-07:00"),
+		Timezone:   t.Location().String(),
+	}
+}
+
+// parsedTime is the result of time_parse.
+type parsedTime struct {
+	RFC3339    string         `tfsdk:"rfc3339"`
+	Components timeComponents `tfsdk:"components"`
+}
+
+var parsedTimeType = map[string]attr.Type{
+	"rfc3339":    types.StringType,
+	"components": types.ObjectType{AttrTypes: timeComponentsType},
+}
+
+// Time Parse Function
+var _ function.Function = &TimeParseFunction{}
+
+type TimeParseFunction struct{}
+
+func NewTimeParseFunction() function.Function {
+	return &TimeParseFunction{}
+}
+
+func (f *TimeParseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "time_parse"
+}
+
+func (f *TimeParseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a timestamp with a named format or Go layout",
+		Description: "Parses input with one of the named formats rfc3339, rfc1123, unix or iso8601-basic, or with a Go " +
+			"layout such as \"02/01/2006 15:04\". Returns an object with the time as RFC 3339 in the given timezone and " +
+			"its components. Inputs without a UTC offset are read in the timezone.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "input",
+				Description: "The timestamp to parse",
+			},
+			function.StringParameter{
+				Name:        "layout",
+				Description: "A named format (rfc3339, rfc1123, unix, iso8601-basic) or a Go layout",
+			},
+			function.StringParameter{
+				Name:        "timezone",
+				Description: "An IANA timezone such as Europe/Berlin, or an empty string for UTC",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: parsedTimeType,
+		},
+	}
+}
+
+func (f *TimeParseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input, layout, timezone string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &input, &layout, &timezone))
+	if resp.Error != nil {
+		return
+	}
+
+	loc, err := loadLocation(timezone)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+	if layout == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "layout must not be empty"))
+		return
+	}
+	t, err := parseTime(input, layout, loc)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	t = t.In(loc)
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, parsedTime{RFC3339: formatTimestamp(t), Components: describeTime(t)}))
+}
+
+// strftimeLayouts maps strftime directives to the equivalent Go layouts.
+var strftimeLayouts = map[byte]string{
+	'a': "Mon", 'A': "Monday", 'b': "Jan", 'B': "January", 'd': "02", 'e': "_2", 'F': "2006-01-02",
+	'H': "15", 'I': "03", 'j': "002", 'm': "01", 'M': "04", 'p': "PM", 'S': "05", 'T': "15:04:05",
+	'D': "01/02/06", 'R': "15:04", 'y': "06", 'Y': "2006", 'z': "-0700", 'Z': "MST",
+}
+
+// formatStrftime formats t with a strftime layout. Besides the directives in
+// strftimeLayouts it supports %f (microseconds), %s (Unix seconds), %u and
+// %w (weekday numbers), %G and %V (ISO year and week), %:z, %n, %t and %%.
+func formatStrftime(t time.Time, layout string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' {
+			b.WriteByte(layout[i])
+			continue
+		}
+		if i++; i == len(layout) {
+			return "", fmt.Errorf("layout %q ends with an incomplete directive", layout)
+		}
+		if goLayout, ok := strftimeLayouts[layout[i]]; ok {
+			b.WriteString(t.Format(goLayout))
+			continue
+		}
+		isoYear, isoWeek := t.ISOWeek()
+		switch layout[i] {
+		case 'f':
+			fmt.Fprintf(&b, "%06d", t.Nanosecond()/1000)
+		case 's':
+			fmt.Fprint(&b, t.Unix())
+		case 'u':
+			fmt.Fprint(&b, (int(t.Weekday())+6)%7+1)
+		case 'w':
+			fmt.Fprint(&b, int(t.Weekday()))
+		case 'G':
+			fmt.Fprintf(&b, "%04d", isoYear)
+		case 'V':
+			fmt.Fprintf(&b, "%02d", isoWeek)
+		case ':':
+			if i+1 == len(layout) || layout[i+1] != 'z' {
+				return "", fmt.Errorf("unsupported directive %%: in layout %q", layout)
+			}
+			i++
+			b.WriteString(t.Format("-07:00"))
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case '%':
+			b.WriteByte('%')
+		default:
+			return "", fmt.Errorf("unsupported directive %%%c in layout %q", layout[i], layout)
+		}
+	}
+	return b.String(), nil
+}
+
+// formatTime formats t with a named format, a strftime layout (any layout
+// containing %) or a Go layout.
+func formatTime(t time.Time, layout string) (string, error) {
+	switch {
+	case layout == "rfc3339":
+		return formatTimestamp(t), nil
+	case layout == "rfc1123":
+		if _, offset := t.Zone(); offset == 0 {
+			return t.Format("Mon, 02 Jan 2006 15:04:05 GMT"), nil
+		}
+		return t.Format(time.RFC1123Z), nil
+	case layout == "unix":
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case layout == "iso8601-basic":
+		return t.Format("20060102T150405Z0700"), nil
+	case strings.Contains(layout, "%"):
+		return formatStrftime(t, layout)
+	}
+	return t.Format(layout), nil
+}
+
+// Time Format Function
+var _ function.Function = &TimeFormatFunction{}
+
+type TimeFormatFunction struct{}
+
+func NewTimeFormatFunction() function.Function {
+	return &TimeFormatFunction{}
+}
+
+func (f *TimeFormatFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "time_format"
+}
+
+func (f *TimeFormatFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Formats a timestamp in a timezone",
+		Description: "Converts an RFC 3339 timestamp to an IANA timezone and formats it with a named format (rfc3339, " +
+			"rfc1123, unix, iso8601-basic), a strftime layout such as \"%Y-%m-%d %H:%M\" or a Go layout such as " +
+			"\"2006-01-02 15:04\". Layouts containing % are treated as strftime layouts.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "timestamp",
+				Description: "The RFC 3339 timestamp, such as the result of timestamp()",
+			},
+			function.StringParameter{
+				Name:        "layout",
+				Description: "A named format, a strftime layout or a Go layout",
+			},
+			function.StringParameter{
+				Name:        "timezone",
+				Description: "An IANA timezone such as Asia/Tokyo, or an empty string for UTC",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *TimeFormatFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var timestamp, layout, timezone string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &timestamp, &layout, &timezone))
+	if resp.Error != nil {
+		return
+	}
+
+	t, err := parseTimestamp(timestamp)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	loc, err := loadLocation(timezone)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+	formatted, err := formatTime(t.In(loc), layout)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatted))
+}
+
+// calendarDuration is a duration with calendar parts, which depend on the
+// date they are added to, and a fixed clock part.
+type calendarDuration struct {
+	years, months, days int
+	clock               time.Duration
+}
+
+// durationUnits maps the units of compact durations to their fixed length;
+// calendar units have no fixed length and map to zero.
+var durationUnits = map[string]time.Duration{
+	"y": 0, "mo": 0, "w": 0, "d": 0,
+	"h": time.Hour, "m": time.Minute, "s": time.Second,
+	"ms": time.Millisecond, "us": time.Microsecond, "ns": time.Nanosecond,
+}
+
+// isoDurationPattern matches ISO 8601 durations such as P1Y2M10DT2H30M.
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// compactDurationPattern matches one number and unit of a compact duration
+// such as 1y2mo3w4d5h30m.
+var compactDurationPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)(mo|ms|us|ns|[ywdhms])`)
+
+// maxCalendarTerm bounds a single year, month, week or day term. Any larger
+// term leaves the years 0000 to 9999, and the bound keeps sums from
+// overflowing an int.
+const maxCalendarTerm = 10000 * 366
+
+// parseCalendarDuration parses an ISO 8601 duration (P1M, PT12H) or a
+// compact duration (1mo, 90d, 1h30m), either with an optional leading
+// minus sign. Years, months, weeks and days must be whole numbers.
+func parseCalendarDuration(text string) (calendarDuration, error) {
+	var d calendarDuration
+	body, negative := strings.CutPrefix(text, "-")
+	if body == "" || body == "P" || strings.HasSuffix(body, "T") {
+		return d, fmt.Errorf("invalid duration %q", text)
+	}
+
+	add := func(value, unit string) error {
+		if durationUnits[unit] != 0 {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+			// Terms are never negative, so d.clock only grows towards the
+			// largest time.Duration, about 292 years.
+			term := n * float64(durationUnits[unit])
+			if term >= float64(math.MaxInt64-d.clock) {
+				return fmt.Errorf("the hours, minutes and seconds add up to more than %s", time.Duration(math.MaxInt64))
+			}
+			d.clock += time.Duration(term)
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s%s must be a whole number", value, unit)
+		}
+		if n > maxCalendarTerm {
+			return fmt.Errorf("%s%s is outside the years 0000 to 9999", value, unit)
+		}
+		switch unit {
+		case "y":
+			d.years += n
+		case "mo":
+			d.months += n
+		case "w":
+			d.days += 7 * n
+		case "d":
+			d.days += n
+		}
+		return nil
+	}
+
+	if m := isoDurationPattern.FindStringSubmatch(body); m != nil {
+		for i, unit := range []string{"y", "mo", "w", "d", "h", "m", "s"} {
+			if m[i+1] == "" {
+				continue
+			}
+			if err := add(m[i+1], unit); err != nil {
+				return d, fmt.Errorf("invalid duration %q: %s", text, err)
+			}
+		}
+	} else {
+		for rest := body; rest != ""; {
+			m := compactDurationPattern.FindStringSubmatch(rest)
+			if m == nil {
+				return d, fmt.Errorf("invalid duration %q, expected ISO 8601 such as P1M or units such as 1y2mo3w4d5h6m7s", text)
+			}
+			if err := add(m[1], m[2]); err != nil {
+				return d, fmt.Errorf("invalid duration %q: %s", text, err)
+			}
+			rest = rest[len(m[0]):]
+		}
+	}
+
+	if negative {
+		d = d.negate()
+	}
+	return d, nil
+}
+
+func (d calendarDuration) negate() calendarDuration {
+	return calendarDuration{years: -d.years, months: -d.months, days: -d.days, clock: -d.clock}
+}
+
+// addTo adds d to t: years and months first, keeping the day of the month
+// but clamping it to the length of the target month (so January 31 plus one
+// month is the last day of February), then days, then the clock part.
+func (d calendarDuration) addTo(t time.Time) time.Time {
+	if d.years != 0 || d.months != 0 {
+		month := int(t.Month()) - 1 + d.months + 12*d.years
+		year := t.Year() + month/12
+		if month %= 12; month < 0 {
+			month += 12
+			year--
+		}
+		lastDay := time.Date(year, time.Month(month+2), 0, 0, 0, 0, 0, time.UTC).Day()
+		hour, minute, second := t.Clock()
+		t = time.Date(year, time.Month(month+1), min(t.Day(), lastDay), hour, minute, second, t.Nanosecond(), t.Location())
+	}
+	return t.AddDate(0, 0, d.days).Add(d.clock)
+}
+
+var timeArithmeticParameters = []function.Parameter{
+	function.StringParameter{
+		Name:        "timestamp",
+		Description: "The RFC 3339 timestamp",
+	},
+	function.StringParameter{
+		Name:        "duration",
+		Description: "An ISO 8601 duration such as P1M or PT36H, or units such as 90d, 1y6mo or 1w2d12h",
+	},
+}
+
+// runTimeArithmetic implements time_add and time_subtract.
+func runTimeArithmetic(ctx context.Context, req function.RunRequest, resp *function.RunResponse, subtract bool) {
+	var timestamp, text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &timestamp, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	t, err := parseTimestamp(timestamp)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	d, err := parseCalendarDuration(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	if subtract {
+		d = d.negate()
+	}
+	result := d.addTo(t)
+	if result.Year() < 0 || result.Year() > 9999 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("%s from %s is outside the years 0000 to 9999", text, timestamp)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatTimestamp(result)))
+}
+
+// Time Add Function
+var _ function.Function = &TimeAddFunction{}
+
+type TimeAddFunction struct{}
+
+func NewTimeAddFunction() function.Function {
+	return &TimeAddFunction{}
+}
+
+func (f *TimeAddFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "time_add"
+}
+
+func (f *TimeAddFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Adds a calendar-aware duration to a timestamp",
+		Description: "Adds a duration that may include years, months, weeks and days to an RFC 3339 timestamp. Months " +
+			"keep the day of the month, clamped to the last day of shorter months, so 2024-01-31 plus 1mo is 2024-02-29.",
+		Parameters: timeArithmeticParameters,
+		Return:     function.StringReturn{},
+	}
+}
+
+func (f *TimeAddFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runTimeArithmetic(ctx, req, resp, false)
+}
+
+// Time Subtract Function
+var _ function.Function = &TimeSubtractFunction{}
+
+type TimeSubtractFunction struct{}
+
+func NewTimeSubtractFunction() function.Function {
+	return &TimeSubtractFunction{}
+}
+
+func (f *TimeSubtractFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "time_subtract"
+}
+
+func (f *TimeSubtractFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Subtracts a calendar-aware duration from a timestamp",
+		Description: "Subtracts a duration that may include years, months, weeks and days from an RFC 3339 timestamp, " +
+			"with the same month clamping as time_add, so 2024-03-31 minus 1mo is 2024-02-29.",
+		Parameters: timeArithmeticParameters,
+		Return:     function.StringReturn{},
+	}
+}
+
+func (f *TimeSubtractFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runTimeArithmetic(ctx, req, resp, true)
+}
+
+// nanosNumber returns nanos divided by unit (nanoseconds per unit) as a
+// number, exact to the nanosecond.
+func nanosNumber(nanos *big.Int, unit int64) *big.Float {
+	number, _, _ := big.ParseFloat(new(big.Rat).SetFrac(nanos, big.NewInt(unit)).FloatString(9), 10, 512, big.ToNearestEven)
+	return number
+}
+
+// secondsNumber returns seconds plus nanoseconds as a number.
+func secondsNumber(seconds, nanos int64) *big.Float {
+	total := new(big.Int).Mul(big.NewInt(seconds), big.NewInt(1e9))
+	return nanosNumber(total.Add(total, big.NewInt(nanos)), 1e9)
+}
+
+// isoDuration renders an elapsed time as an ISO 8601 duration using days and
+// clock units, such as P1DT2H30M or -PT0.5S.
+func isoDuration(days, hours, minutes, seconds, nanos int64) string {
+	var b strings.Builder
+	if days < 0 || hours < 0 || minutes < 0 || seconds < 0 || nanos < 0 {
+		b.WriteString("-")
+	}
+	abs := func(n int64) int64 { return max(n, -n) }
+	b.WriteString("P")
+	if days != 0 {
+		fmt.Fprintf(&b, "%dD", abs(days))
+	}
+	if hours == 0 && minutes == 0 && seconds == 0 && nanos == 0 {
+		if days == 0 {
+			b.WriteString("T0S")
+		}
+		return b.String()
+	}
+	b.WriteString("T")
+	if hours != 0 {
+		fmt.Fprintf(&b, "%dH", abs(hours))
+	}
+	if minutes != 0 {
+		fmt.Fprintf(&b, "%dM", abs(minutes))
+	}
+	if seconds != 0 || nanos != 0 {
+		b.WriteString(strconv.FormatInt(abs(seconds), 10))
+		if nanos != 0 {
+			b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", abs(nanos)), "0"))
+		}
+		b.WriteString("S")
+	}
+	return b.String()
+}
+
+// timeDiffComponents is the difference between two times split into days
+// of 24 hours and clock units, all with the sign of the difference.
+type timeDiffComponents struct {
+	Days    int64      `tfsdk:"days"`
+	Hours   int64      `tfsdk:"hours"`
+	Minutes int64      `tfsdk:"minutes"`
+	Seconds *big.Float `tfsdk:"seconds"`
+}
+
+var timeDiffComponentsType = map[string]attr.Type{
+	"days":    types.Int64Type,
+	"hours":   types.Int64Type,
+	"minutes": types.Int64Type,
+	"seconds": types.NumberType,
+}
+
+// timeDiff is the result of time_diff.
+type timeDiff struct {
+	Seconds    *big.Float         `tfsdk:"seconds"`
+	Components timeDiffComponents `tfsdk:"components"`
+	Duration   string             `tfsdk:"duration"`
+}
+
+var timeDiffType = map[string]attr.Type{
+	"seconds":    types.NumberType,
+	"components": types.ObjectType{AttrTypes: timeDiffComponentsType},
+	"duration":   types.StringType,
+}
+
+// diffTimes returns the elapsed time from a to b. It works from Unix
+// seconds rather than time.Duration, which saturates after 292 years.
+func diffTimes(a, b time.Time) timeDiff {
+	seconds := b.Unix() - a.Unix()
+	nanos := int64(b.Nanosecond() - a.Nanosecond())
+	if seconds > 0 && nanos < 0 {
+		seconds, nanos = seconds-1, nanos+1e9
+	} else if seconds < 0 && nanos > 0 {
+		seconds, nanos = seconds+1, nanos-1e9
+	}
+
+	days, rest := seconds/86400, seconds%86400
+	hours, rest := rest/3600, rest%3600
+	minutes, rest := rest/60, rest%60
+	return timeDiff{
+		Seconds: secondsNumber(seconds, nanos),
+		Components: timeDiffComponents{
+			Days:    days,
+			Hours:   hours,
+			Minutes: minutes,
+			Seconds: secondsNumber(rest, nanos),
+		},
+		Duration: isoDuration(days, hours, minutes, rest, nanos),
+	}
+}
+
+// Time Diff Function
+var _ function.Function = &TimeDiffFunction{}
+
+type TimeDiffFunction struct{}
+
+func NewTimeDiffFunction() function.Function {
+	return &TimeDiffFunction{}
+}
+
+func (f *TimeDiffFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "time_diff"
+}
+
+func (f *TimeDiffFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the time elapsed between two timestamps",
+		Description: "Returns the time from a to b as total seconds, as days, hours, minutes and seconds, and as an " +
+			"ISO 8601 duration. The result is negative when b is before a.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "a",
+				Description: "The RFC 3339 start timestamp",
+			},
+			function.StringParameter{
+				Name:        "b",
+				Description: "The RFC 3339 end timestamp",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: timeDiffType},
+	}
+}
+
+func (f *TimeDiffFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &a, &b))
+	if resp.Error != nil {
+		return
+	}
+
+	start, err := parseTimestamp(a)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	end, err := parseTimestamp(b)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, diffTimes(start, end)))
+}
+
+// epochUnits maps the units accepted for Unix epoch values to their length in
+// nanoseconds.
+var epochUnits = map[string]int64{
+	"seconds":      1e9,
+	"milliseconds": 1e6,
+	"microseconds": 1e3,
+	"nanoseconds":  1,
+}
+
+// minRFC3339Unix and maxRFC3339Unix bound the Unix seconds of timestamps
+// with four-digit years, the only years RFC 3339 can represent.
+var (
+	minRFC3339Unix = time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	maxRFC3339Unix = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC).Unix()
+)
+
+// epochUnit looks up the length of an epoch unit in nanoseconds.
+func epochUnit(name string) (int64, error) {
+	unit, ok := epochUnits[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q, expected one of: %s", name, strings.Join(sortedKeys(epochUnits), ", "))
+	}
+	return unit, nil
+}
+
+// Unix To RFC3339 Function
+var _ function.Function = &UnixToRFC3339Function{}
+
+type UnixToRFC3339Function struct{}
+
+func NewUnixToRFC3339Function() function.Function {
+	return &UnixToRFC3339Function{}
+}
+
+func (f *UnixToRFC3339Function) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "unix_to_rfc3339"
+}
+
+func (f *UnixToRFC3339Function) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a Unix epoch value to an RFC 3339 timestamp",
+		Description: "Converts a number of seconds, milliseconds, microseconds or nanoseconds since the Unix epoch to an " +
+			"RFC 3339 timestamp in UTC. Fractional values are kept down to the nanosecond.",
+		Parameters: []function.Parameter{
+			function.NumberParameter{
+				Name:        "epoch",
+				Description: "The time since 1970-01-01T00:00:00Z",
+			},
+			function.StringParameter{
+				Name:        "unit",
+				Description: "The unit of epoch: seconds, milliseconds, microseconds or nanoseconds",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *UnixToRFC3339Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var epoch *big.Float
+	var unitName string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &epoch, &unitName))
+	if resp.Error != nil {
+		return
+	}
+
+	unit, err := epochUnit(unitName)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	// Numbers arrive as binary floats; their shortest decimal form is the
+	// value as written in the configuration, which converts exactly.
+	nanos, _ := new(big.Rat).SetString(epoch.Text('f', -1))
+	nanos.Mul(nanos, new(big.Rat).SetInt64(unit))
+	if !nanos.IsInt() {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("%s %s is more precise than a nanosecond", epoch.Text('f', -1), unitName)))
+		return
+	}
+
+	seconds, rest := new(big.Int).DivMod(nanos.Num(), big.NewInt(1e9), new(big.Int))
+	if !seconds.IsInt64() || seconds.Int64() < minRFC3339Unix || seconds.Int64() > maxRFC3339Unix {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("%s %s is outside the years 0000 to 9999", epoch.Text('f', -1), unitName)))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatTimestamp(time.Unix(seconds.Int64(), rest.Int64()).UTC())))
+}
+
+// RFC3339 To Unix Function
+var _ function.Function = &RFC3339ToUnixFunction{}
+
+type RFC3339ToUnixFunction struct{}
+
+func NewRFC3339ToUnixFunction() function.Function {
+	return &RFC3339ToUnixFunction{}
+}
+
+func (f *RFC3339ToUnixFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "rfc3339_to_unix"
+}
+
+func (f *RFC3339ToUnixFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts an RFC 3339 timestamp to a Unix epoch value",
+		Description: "Converts an RFC 3339 timestamp to the number of seconds, milliseconds, microseconds or nanoseconds " +
+			"since the Unix epoch. The result is fractional when the timestamp is more precise than the unit.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "timestamp",
+				Description: "The RFC 3339 timestamp",
+			},
+			function.StringParameter{
+				Name:        "unit",
+				Description: "The unit of the result: seconds, milliseconds, microseconds or nanoseconds",
+			},
+		},
+		Return: function.NumberReturn{},
+	}
+}
+
+func (f *RFC3339ToUnixFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var timestamp, unitName string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &timestamp, &unitName))
+	if resp.Error != nil {
+		return
+	}
+
+	t, err := parseTimestamp(timestamp)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	unit, err := epochUnit(unitName)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	nanos := new(big.Int).Mul(big.NewInt(t.Unix()), big.NewInt(1e9))
+	nanos.Add(nanos, big.NewInt(int64(t.Nanosecond())))
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, nanosNumber(nanos, unit)))
+}
+
+// timeIntervals are the intervals accepted by time_floor and time_ceil.
+var timeIntervals = []string{"hour", "day", "week", "month"}
+
+// floorTime returns the start of the interval containing t, in the location
+// of t. Weeks start on Monday, as in ISO 8601.
+func floorTime(t time.Time, interval string) time.Time {
+	year, month, day := t.Date()
+	switch interval {
+	case "hour":
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+	case "day":
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	case "week":
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// ceilTime returns the start of the next interval after t, or t itself when
+// it starts an interval.
+func ceilTime(t time.Time, interval string) time.Time {
+	floor := floorTime(t, interval)
+	if floor.Equal(t) {
+		return t
+	}
+	switch interval {
+	case "hour":
+		return floor.Add(time.Hour)
+	case "day":
+		return floor.AddDate(0, 0, 1)
+	case "week":
+		return floor.AddDate(0, 0, 7)
+	}
+	return floor.AddDate(0, 1, 0)
+}
+
+var timeBucketParameters = []function.Parameter{
+	function.StringParameter{
+		Name:        "timestamp",
+		Description: "The RFC 3339 timestamp",
+	},
+	function.StringParameter{
+		Name:        "interval",
+		Description: "The interval: hour, day, week or month",
+	},
+}
+
+// runTimeBucket implements time_floor and time_ceil.
+func runTimeBucket(ctx context.Context, req function.RunRequest, resp *function.RunResponse, ceil bool) {
+	var timestamp, interval string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &timestamp, &interval))
+	if resp.Error != nil {
+		return
+	}
+
+	t, err := parseTimestamp(timestamp)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if !slices.Contains(timeIntervals, interval) {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("unknown interval %q, expected one of: %s", interval, strings.Join(timeIntervals, ", "))))
+		return
+	}
+	if ceil {
+		t = ceilTime(t, interval)
+	} else {
+		t = floorTime(t, interval)
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatTimestamp(t)))
+}
+
+// Time Floor Function
+var _ function.Function = &TimeFloorFunction{}
+
+type TimeFloorFunction struct{}
+
+func NewTimeFloorFunction() function.Function {
+	return &TimeFloorFunction{}
+}
+
+func (f *TimeFloorFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "time_floor"
+}
+
+func (f *TimeFloorFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Rounds a timestamp down to the start of an interval",
+		Description: "Returns the start of the hour, day, week or month containing a timestamp, in its UTC offset. " +
+			"Weeks start on Monday.",
+		Parameters: timeBucketParameters,
+		Return:     function.StringReturn{},
+	}
+}
+
+func (f *TimeFloorFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runTimeBucket(ctx, req, resp, false)
+}
+
+// Time Ceil Function
+var _ function.Function = &TimeCeilFunction{}
+
+type TimeCeilFunction struct{}
+
+func NewTimeCeilFunction() function.Function {
+	return &TimeCeilFunction{}
+}
+
+func (f *TimeCeilFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "time_ceil"
+}
+
+func (f *TimeCeilFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Rounds a timestamp up to the start of the next interval",
+		Description: "Returns the start of the next hour, day, week or month after a timestamp, in its UTC offset, or " +
+			"the timestamp itself when it already starts one. Weeks start on Monday.",
+		Parameters: timeBucketParameters,
+		Return:     function.StringReturn{},
+	}
+}
+
+func (f *TimeCeilFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	runTimeBucket(ctx, req, resp, true)
+}
+
+// cronFieldSpec describes one field of a cron dialect.
+type cronFieldSpec struct {
+	name     string
+	min, max int
+	names    []string // names for min, min+1, ...
+}
+
+var (
+	cronMonthNames = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	cronDayNames   = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+	cronSecond = cronFieldSpec{"second", 0, 59, nil}
+	cronMinute = cronFieldSpec{"minute", 0, 59, nil}
+	cronHour   = cronFieldSpec{"hour", 0, 23, nil}
+	cronDay    = cronFieldSpec{"day-of-month", 1, 31, nil}
+	cronMonth  = cronFieldSpec{"month", 1, 12, cronMonthNames}
+)
+
+// cronFlavors maps each cron dialect to its fields. Standard cron numbers
+// weekdays from 0 (with 7 also meaning Sunday); AWS and Quartz from 1.
+var cronFlavors = map[string][]cronFieldSpec{
+	"standard": {cronMinute, cronHour, cronDay, cronMonth, {"day-of-week", 0, 7, cronDayNames}},
+	"aws":      {cronMinute, cronHour, cronDay, cronMonth, {"day-of-week", 1, 7, cronDayNames}, {"year", 1970, 2199, nil}},
+	"quartz":   {cronSecond, cronMinute, cronHour, cronDay, cronMonth, {"day-of-week", 1, 7, cronDayNames}, {"year", 1970, 2099, nil}},
+}
+
+// cronMacros are the shorthands of standard cron that describe a schedule.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronRatePattern matches AWS rate expressions such as rate(5 minutes).
+var cronRatePattern = regexp.MustCompile(`^rate\((\d+) (minute|hour|day)(s?)\)$`)
+
+var cronRateUnits = map[string]time.Duration{"minute": time.Minute, "hour": time.Hour, "day": 24 * time.Hour}
+
+// cronValues is the set of values a field matches, indexed by value. A nil
+// set matches every value.
+type cronValues []bool
+
+func (v cronValues) has(n int) bool {
+	return v == nil || (n < len(v) && v[n])
+}
+
+// cronDays matches the day-of-month or day-of-week field: plain values plus
+// the L, W and # rules, which depend on the month.
+type cronDays struct {
+	values cronValues
+	rules  []func(t time.Time) bool
+	star   bool // the field is *, matching every day
+	skip   bool // the field is ?, leaving the choice to the other field
+}
+
+// cronSchedule is a parsed cron or rate expression.
+type cronSchedule struct {
+	seconds, minutes, hours, months, years cronValues
+	dom, dow                               cronDays
+	rate                                   time.Duration
+}
+
+// daysIn returns the number of days in the month of t.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func (d cronDays) match(t time.Time, value int) bool {
+	if d.values[value] {
+		return true
+	}
+	for _, rule := range d.rules {
+		if rule(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDay reports whether the schedule runs on the day of t. Like Vixie
+// cron, a day matches either restricted field when both are restricted, and
+// both fields otherwise.
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dom, dow := s.dom.match(t, t.Day()), s.dow.match(t, int(t.Weekday()))
+	switch {
+	case s.dom.skip:
+		return dow
+	case s.dow.skip:
+		return dom
+	case s.dom.star || s.dow.star:
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first time after t that the schedule runs, searching up
+// to 100 years ahead, in the location of t.
+func (s *cronSchedule) next(t time.Time) (time.Time, bool) {
+	if s.rate != 0 {
+		return t.Add(s.rate), true
+	}
+	loc := t.Location()
+	limit := t.Year() + 100
+	t = t.Truncate(time.Second).Add(time.Second)
+	for t.Year() <= limit {
+		year, month, day := t.Date()
+		hour, minute, second := t.Clock()
+		switch {
+		case !s.years.has(year):
+			t = time.Date(year+1, 1, 1, 0, 0, 0, 0, loc)
+		case !s.months.has(int(month)):
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case !s.hours.has(hour):
+			t = time.Date(year, month, day, hour+1, 0, 0, 0, loc)
+		case !s.minutes.has(minute):
+			t = time.Date(year, month, day, hour, minute+1, 0, 0, loc)
+		case !s.seconds.has(second):
+			t = t.Add(time.Second)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseCronValue parses a number or, for fields with names, a
+// case-insensitive name such as JAN or MON.
+func parseCronValue(text string, spec cronFieldSpec) (int, error) {
+	for i, name := range spec.names {
+		if strings.EqualFold(text, name) {
+			return spec.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || strings.HasPrefix(text, "+") {
+		return 0, fmt.Errorf("%s field: invalid value %q", spec.name, text)
+	}
+	if n < spec.min || n > spec.max {
+		return 0, fmt.Errorf("%s field: %d is outside %d-%d", spec.name, n, spec.min, spec.max)
+	}
+	return n, nil
+}
+
+// parseCronRange parses *, a value or a range a-b, optionally followed by a
+// step, and adds the values it matches to values.
+func parseCronRange(term string, spec cronFieldSpec, values cronValues) error {
+	rangeText, stepText, hasStep := strings.Cut(term, "/")
+	low, high := spec.min, spec.max
+	if rangeText != "*" {
+		lowText, highText, isRange := strings.Cut(rangeText, "-")
+		var err error
+		if low, err = parseCronValue(lowText, spec); err != nil {
+			return err
+		}
+		if high = low; isRange {
+			if high, err = parseCronValue(highText, spec); err != nil {
+				return err
+			}
+			if high < low {
+				return fmt.Errorf("%s field: range %q runs backwards", spec.name, rangeText)
+			}
+		} else if hasStep {
+			high = spec.max
+		}
+	}
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepText)
+		if err != nil || n < 1 || n > spec.max-spec.min+1 {
+			return fmt.Errorf("%s field: invalid step %q", spec.name, stepText)
+		}
+		step = n
+	}
+	for n := low; n <= high; n += step {
+		values[n] = true
+	}
+	return nil
+}
+
+// nearestWeekday returns the weekday closest to day in the month of t
+// without leaving the month, as for the Quartz nW rule.
+func nearestWeekday(t time.Time, day int) int {
+	last := daysIn(t)
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return 3
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
+// parseCronDays parses the day-of-month or day-of-week field. AWS and Quartz
+// also accept ? and the rules L (last), W (nearest weekday) and # (nth
+// weekday of the month); weekdays are stored as time.Weekday values.
+func parseCronDays(field string, spec cronFieldSpec, flavor string) (cronDays, error) {
+	days := cronDays{values: make(cronValues, spec.max+1), star: strings.HasPrefix(field, "*")}
+	extended := flavor != "standard"
+	if field == "?" {
+		if !extended {
+			return days, fmt.Errorf("%s field: ? is not supported by standard cron", spec.name)
+		}
+		days.skip = true
+		return days, nil
+	}
+	weekdays := spec.name == "day-of-week"
+	toWeekday := func(n int) int {
+		if flavor == "standard" {
+			return n % 7
+		}
+		return n - 1
+	}
+	weekday := func(text string) (int, error) {
+		n, err := parseCronValue(text, spec)
+		return toWeekday(n), err
+	}
+
+	for _, term := range strings.Split(field, ",") {
+		switch {
+		case extended && weekdays && term == "L":
+			days.values[time.Saturday] = true
+		case extended && weekdays && strings.HasSuffix(term, "L"):
+			day, err := weekday(strings.TrimSuffix(term, "L"))
+			if err != nil {
+				return days, err
+			}
+			days.rules = append(days.rules, func(t time.Time) bool {
+				return int(t.Weekday()) == day && t.Day()+7 > daysIn(t)
+			})
+		case extended && weekdays && strings.Contains(term, "#"):
+			dayText, nthText, _ := strings.Cut(term, "#")
+			day, err := weekday(dayText)
+			if err != nil {
+				return days, err
+			}
+			nth, err := strconv.Atoi(nthText)
+			if err != nil || nth < 1 || nth > 5 {
+				return days, fmt.Errorf("%s field: invalid occurrence %q in %q, expected 1-5", spec.name, nthText, term)
+			}
+			days.rules = append(days.rules, func(t time.Time) bool {
+				return int(t.Weekday()) == day && (t.Day()-1)/7+1 == nth
+			})
+		case extended && !weekdays && term == "L":
+			days.rules = append(days.rules, func(t time.Time) bool { return t.Day() == daysIn(t) })
+		case extended && !weekdays && strings.HasPrefix(term, "L-"):
+			offset, err := strconv.Atoi(term[2:])
+			if err != nil || offset < 1 || offset > 30 {
+				return days, fmt.Errorf("%s field: invalid offset in %q, expected L-1 to L-30", spec.name, term)
+			}
+			days.rules = append(days.rules, func(t time.Time) bool { return t.Day() == daysIn(t)-offset })
+		case extended && !weekdays && term == "LW":
+			days.rules = append(days.rules, func(t time.Time) bool {
+				return t.Day() == nearestWeekday(t, daysIn(t))
+			})
+		case extended && !weekdays && strings.HasSuffix(term, "W"):
+			day, err := parseCronValue(strings.TrimSuffix(term, "W"), spec)
+			if err != nil {
+				return days, err
+			}
+			days.rules = append(days.rules, func(t time.Time) bool {
+				return day <= daysIn(t) && t.Day() == nearestWeekday(t, day)
+			})
+		default:
+			values := make(cronValues, spec.max+1)
+			if err := parseCronRange(term, spec, values); err != nil {
+				return days, err
+			}
+			for n, ok := range values {
+				if ok && weekdays {
+					days.values[toWeekday(n)] = true
+				} else if ok {
+					days.values[n] = true
+				}
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseCron parses a cron expression of the given flavor: five fields or an
+// @ macro for standard cron, cron() with six fields or rate() for AWS, and
+// six or seven fields, starting with seconds, for Quartz.
+func parseCron(expression, flavor string) (*cronSchedule, error) {
+	specs := cronFlavors[flavor]
+	text := strings.TrimSpace(expression)
+	switch flavor {
+	case "standard":
+		if strings.HasPrefix(text, "@") {
+			macro, ok := cronMacros[strings.ToLower(text)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported macro %q, expected one of: %s", text, strings.Join(sortedKeys(cronMacros), ", "))
+			}
+			text = macro
+		}
+	case "aws":
+		if m := cronRatePattern.FindStringSubmatch(text); m != nil {
+			n, err := strconv.Atoi(m[1])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("rate value must be a positive whole number")
+			}
+			if (n == 1) != (m[3] == "") {
+				return nil, fmt.Errorf("rate unit must be %q for a value of 1 and plural otherwise", m[2])
+			}
+			return &cronSchedule{rate: time.Duration(n) * cronRateUnits[m[2]]}, nil
+		}
+		if !strings.HasPrefix(text, "cron(") || !strings.HasSuffix(text, ")") {
+			return nil, fmt.Errorf("AWS schedules must be cron(minutes hours day-of-month month day-of-week year) or rate(value unit)")
+		}
+		text = text[len("cron(") : len(text)-1]
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) == len(specs)-1 && flavor == "quartz" {
+		fields = append(fields, "*")
+	}
+	if len(fields) != len(specs) {
+		names := make([]string, len(specs))
+		for i, spec := range specs {
+			names[i] = spec.name
+		}
+		return nil, fmt.Errorf("expected %d fields (%s), got %d", len(specs), strings.Join(names, " "), len(fields))
+	}
+
+	schedule := &cronSchedule{seconds: cronValues{true}}
+	targets := map[string]*cronValues{"second": &schedule.seconds, "minute": &schedule.minutes, "hour": &schedule.hours, "month": &schedule.months, "year": &schedule.years}
+	for i, spec := range specs {
+		var err error
+		switch spec.name {
+		case "day-of-month":
+			schedule.dom, err = parseCronDays(fields[i], spec, flavor)
+		case "day-of-week":
+			schedule.dow, err = parseCronDays(fields[i], spec, flavor)
+		default:
+			values := make(cronValues, spec.max+1)
+			for _, term := range strings.Split(fields[i], ",") {
+				if err = parseCronRange(term, spec, values); err != nil {
+					break
+				}
+			}
+			*targets[spec.name] = values
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if flavor != "standard" && schedule.dom.skip == schedule.dow.skip {
+		return nil, fmt.Errorf("exactly one of the day-of-month and day-of-week fields must be ?")
+	}
+	return schedule, nil
+}
+
+// detectCronFlavor picks the flavor of an expression from its shape.
+func detectCronFlavor(expression string) (string, error) {
+	text := strings.TrimSpace(expression)
+	switch n := len(strings.Fields(text)); {
+	case strings.HasPrefix(text, "cron(") || strings.HasPrefix(text, "rate("):
+		return "aws", nil
+	case strings.HasPrefix(text, "@") || n == 5:
+		return "standard", nil
+	case n == 6 || n == 7:
+		return "quartz", nil
+	}
+	return "", fmt.Errorf("invalid cron expression %q, expected 5 fields (standard), 6 or 7 fields (Quartz) or cron() or rate() (AWS)", expression)
+}
+
+// cronValidation is the result of cron_validate.
+type cronValidation struct {
+	Valid  bool   `tfsdk:"valid"`
+	Reason string `tfsdk:"reason"`
+}
+
+var cronValidationType = map[string]attr.Type{
+	"valid":  types.BoolType,
+	"reason": types.StringType,
+}
+
+// Cron Validate Function
+var _ function.Function = &CronValidateFunction{}
+
+type CronValidateFunction struct{}
+
+func NewCronValidateFunction() function.Function {
+	return &CronValidateFunction{}
+}
+
+func (f *CronValidateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cron_validate"
+}
+
+func (f *CronValidateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates a cron expression",
+		Description: "Checks a standard, AWS or Quartz cron expression and returns whether it is valid and, if not, why. " +
+			"Invalid expressions are not an error, so the result can be used in validation conditions.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "expression",
+				Description: "The cron expression, such as \"0 3 * * 1-5\" or \"cron(0 3 ? * MON-FRI *)\"",
+			},
+			function.StringParameter{
+				Name:        "flavor",
+				Description: "The cron dialect: standard, aws or quartz",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: cronValidationType},
+	}
+}
+
+func (f *CronValidateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var expression, flavor string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &expression, &flavor))
+	if resp.Error != nil {
+		return
+	}
+
+	if _, ok := cronFlavors[flavor]; !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("unknown flavor %q, expected one of: %s", flavor, strings.Join(sortedKeys(cronFlavors), ", "))))
+		return
+	}
+	result := cronValidation{Valid: true}
+	if _, err := parseCron(expression, flavor); err != nil {
+		result = cronValidation{Reason: err.Error()}
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// Cron Next Function
+var _ function.Function = &CronNextFunction{}
+
+type CronNextFunction struct{}
+
+func NewCronNextFunction() function.Function {
+	return &CronNextFunction{}
+}
+
+func (f *CronNextFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cron_next"
+}
+
+func (f *CronNextFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Lists the next run times of a cron schedule",
+		Description: "Returns the next run times of a cron expression after a timestamp. The flavor is detected from the " +
+			"expression: five fields or an @ macro for standard cron, cron() or rate() for AWS, six or seven fields for Quartz. " +
+			"Schedules are evaluated in the UTC offset of from.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "expression",
+				Description: "The cron expression",
+			},
+			function.StringParameter{
+				Name:        "from",
+				Description: "The RFC 3339 timestamp to start after, such as timestamp()",
+			},
+			function.Int64Parameter{
+				Name:        "count",
+				Description: "The number of run times to return, between 1 and 100",
+			},
+		},
+		Return: function.ListReturn{ElementType: types.StringType},
+	}
+}
+
+func (f *CronNextFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var expression, from string
+	var count int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &expression, &from, &count))
+	if resp.Error != nil {
+		return
+	}
+
+	flavor, err := detectCronFlavor(expression)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	schedule, err := parseCron(expression, flavor)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("invalid %s cron expression: %s", flavor, err)))
+		return
+	}
+	t, err := parseTimestamp(from)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	if count < 1 || count > 100 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, "count must be between 1 and 100"))
+		return
+	}
+
+	runs := []string{}
+	for len(runs) < int(count) {
+		var ok bool
+		if t, ok = schedule.next(t); !ok {
+			break
+		}
+		runs = append(runs, formatTimestamp(t))
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, runs))
+}
+
+// maxBusinessDays bounds add_business_days to about 40 years.
+const maxBusinessDays = 10000
+
+var businessDayHolidaysParameter = function.ListParameter{
+	Name:           "holidays",
+	Description:    "Dates that are not business days, as YYYY-MM-DD, or null",
+	ElementType:    types.StringType,
+	AllowNullValue: true,
+}
+
+// parseHolidays reads a list of YYYY-MM-DD dates into a set.
+func parseHolidays(ctx context.Context, list types.List) (map[string]bool, error) {
+	var dates []string
+	if !list.IsNull() {
+		if diags := list.ElementsAs(ctx, &dates, false); diags.HasError() {
+			return nil, fmt.Errorf("holidays must be a list of YYYY-MM-DD dates")
+		}
+	}
+	holidays := make(map[string]bool, len(dates))
+	for _, date := range dates {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return nil, fmt.Errorf("invalid holiday %q, expected a date such as 2024-12-25", date)
+		}
+		holidays[date] = true
+	}
+	return holidays, nil
+}
+
+// isBusinessDay reports whether t falls on a weekday that is not a holiday,
+// using the date in the UTC offset of t.
+func isBusinessDay(t time.Time, holidays map[string]bool) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return !holidays[t.Format(time.DateOnly)]
+}
+
+// Add Business Days Function
+var _ function.Function = &AddBusinessDaysFunction{}
+
+type AddBusinessDaysFunction struct{}
+
+func NewAddBusinessDaysFunction() function.Function {
+	return &AddBusinessDaysFunction{}
+}
+
+func (f *AddBusinessDaysFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "add_business_days"
+}
+
+func (f *AddBusinessDaysFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Adds a number of business days to a timestamp",
+		Description: "Moves a timestamp forward, or backward for negative n, by n days that are neither weekends nor " +
+			"holidays, keeping the time of day.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "timestamp",
+				Description: "The RFC 3339 timestamp",
+			},
+			function.Int64Parameter{
+				Name:        "n",
+				Description: "The number of business days to add, negative to go back",
+			},
+			businessDayHolidaysParameter,
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *AddBusinessDaysFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var timestamp string
+	var n int64
+	var holidayList types.List
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &timestamp, &n, &holidayList))
+	if resp.Error != nil {
+		return
+	}
+
+	t, err := parseTimestamp(timestamp)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if n < -maxBusinessDays || n > maxBusinessDays {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("n must be between %d and %d", -maxBusinessDays, maxBusinessDays)))
+		return
+	}
+	holidays, err := parseHolidays(ctx, holidayList)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+		return
+	}
+
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for ; n > 0; n-- {
+		t = t.AddDate(0, 0, step)
+		for !isBusinessDay(t, holidays) {
+			t = t.AddDate(0, 0, step)
+		}
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatTimestamp(t)))
+}
+
+// Is Business Day Function
+var _ function.Function = &IsBusinessDayFunction{}
+
+type IsBusinessDayFunction struct{}
+
+func NewIsBusinessDayFunction() function.Function {
+	return &IsBusinessDayFunction{}
+}
+
+func (f *IsBusinessDayFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_business_day"
+}
+
+func (f *IsBusinessDayFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Checks whether a timestamp falls on a business day",
+		Description: "Returns true when the date of a timestamp, in its UTC offset, is a Monday to Friday that is not a holiday.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "timestamp",
+				Description: "The RFC 3339 timestamp",
+			},
+			businessDayHolidaysParameter,
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *IsBusinessDayFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var timestamp string
+	var holidayList types.List
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &timestamp, &holidayList))
+	if resp.Error != nil {
+		return
+	}
+
+	t, err := parseTimestamp(timestamp)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	holidays, err := parseHolidays(ctx, holidayList)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, isBusinessDay(t, holidays)))
+}
+
+// windowReferenceYear is the year whose weeks windows_overlap compares, so
+// that results are stable while still covering daylight saving changes.
+const windowReferenceYear = 2025
+
+// windowDefaults are the attributes of a maintenance window.
+var windowDefaults = map[string]any{
+	"day":      "",
+	"start":    "",
+	"end":      "",
+	"timezone": "",
+}
+
+var windowClockPattern = regexp.MustCompile(`^([01]\d|2[0-3]):([0-5]\d)$`)
+
+// maintenanceWindow is a daily or weekly window in local time. Windows that
+// end before they start run past midnight.
+type maintenanceWindow struct {
+	days          []time.Weekday
+	start, length time.Duration
+	loc           *time.Location
+}
+
+// parseWindow reads a window object with a day (a weekday name or daily),
+// start and end times as HH:MM and an optional IANA timezone.
+func parseWindow(data any) (maintenanceWindow, error) {
+	var window maintenanceWindow
+	if data == nil {
+		return window, fmt.Errorf("window must be an object, got null")
+	}
+	attrs, err := parseOptions(data, windowDefaults)
+	if err != nil {
+		return window, err
+	}
+
+	day := strings.ToLower(attrs["day"].(string))
+	if day == "daily" {
+		window.days = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if name := strings.ToLower(weekday.String()); day == name || day == name[:3] {
+			window.days = []time.Weekday{weekday}
+		}
+	}
+	if window.days == nil {
+		return window, fmt.Errorf("invalid day %q, expected a weekday such as mon or monday, or daily", attrs["day"])
+	}
+
+	clock := func(key string) (time.Duration, error) {
+		m := windowClockPattern.FindStringSubmatch(attrs[key].(string))
+		if m == nil {
+			return 0, fmt.Errorf("invalid %s %q, expected a time such as 03:30", key, attrs[key])
+		}
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+	}
+	if window.start, err = clock("start"); err != nil {
+		return window, err
+	}
+	end, err := clock("end")
+	if err != nil {
+		return window, err
+	}
+	if end == window.start {
+		return window, fmt.Errorf("window starts and ends at %s", attrs["start"])
+	}
+	if window.length = end - window.start; window.length < 0 {
+		window.length += 24 * time.Hour
+	}
+	window.loc, err = loadLocation(attrs["timezone"].(string))
+	return window, err
+}
+
+// timeSpan is a half-open interval of time.
+type timeSpan struct {
+	start, end time.Time
+}
+
+// occurrences returns the times the window runs during the reference year,
+// starting from the last days of the year before so that windows running
+// into the new year are included.
+func (w maintenanceWindow) occurrences() []timeSpan {
+	var spans []timeSpan
+	for day := time.Date(windowReferenceYear-1, 12, 25, 0, 0, 0, 0, w.loc); day.Year() <= windowReferenceYear; day = day.AddDate(0, 0, 1) {
+		if !slices.Contains(w.days, day.Weekday()) {
+			continue
+		}
+		year, month, date := day.Date()
+		start := time.Date(year, month, date, int(w.start.Hours()), int(w.start.Minutes())%60, 0, 0, w.loc)
+		end := time.Date(year, month, date, int(w.start.Hours()), int(w.start.Minutes())%60+int(w.length.Minutes()), 0, 0, w.loc)
+		spans = append(spans, timeSpan{start, end})
+	}
+	return spans
+}
+
+// windowOverlap is the result of windows_overlap.
+type windowOverlap struct {
+	Overlaps       bool  `tfsdk:"overlaps"`
+	OverlapMinutes int64 `tfsdk:"overlap_minutes"`
+}
+
+var windowOverlapType = map[string]attr.Type{
+	"overlaps":        types.BoolType,
+	"overlap_minutes": types.Int64Type,
+}
+
+// overlapWindows compares every occurrence of a with every occurrence of b
+// and returns the longest overlap between two of them.
+func overlapWindows(a, b maintenanceWindow) windowOverlap {
+	var longest time.Duration
+	spansA, spansB := a.occurrences(), b.occurrences()
+	for i, j := 0, 0; i < len(spansA) && j < len(spansB); {
+		start := spansA[i].start
+		if spansB[j].start.After(start) {
+			start = spansB[j].start
+		}
+		end := spansA[i].end
+		if spansB[j].end.Before(end) {
+			end = spansB[j].end
+		}
+		longest = max(longest, end.Sub(start))
+		if spansA[i].end.Before(spansB[j].end) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return windowOverlap{Overlaps: longest > 0, OverlapMinutes: int64(longest.Minutes())}
+}
+
+// Windows Overlap Function
+var _ function.Function = &WindowsOverlapFunction{}
+
+type WindowsOverlapFunction struct{}
+
+func NewWindowsOverlapFunction() function.Function {
+	return &WindowsOverlapFunction{}
+}
+
+func (f *WindowsOverlapFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "windows_overlap"
+}
+
+func (f *WindowsOverlapFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether two maintenance windows overlap",
+		Description: "Compares two daily or weekly windows, each in its own timezone, across every week of a year so " +
+			"that daylight saving changes are taken into account. Returns whether they overlap and the longest overlap in minutes.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "window_a",
+				Description: "A window such as { day = \"sun\", start = \"02:00\", end = \"04:00\", timezone = \"Europe/Berlin\" }",
+			},
+			function.DynamicParameter{
+				Name:        "window_b",
+				Description: "The window to compare with, in the same form",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: windowOverlapType},
+	}
+}
+
+func (f *WindowsOverlapFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var valueA, valueB types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &valueA, &valueB))
+	if resp.Error != nil {
+		return
+	}
+
+	windows := make([]maintenanceWindow, 2)
+	for i, value := range []types.Dynamic{valueA, valueB} {
+		data, err := fromValue(ctx, value)
+		if err == nil {
+			windows[i], err = parseWindow(data)
+		}
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i), err.Error()))
+			return
+		}
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, overlapWindows(windows[0], windows[1])))
+}
@@ -0,0 +1,525 @@
+package provider
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTimeParse(t *testing.T) {
+	tests := []struct {
+		input, layout, timezone string
+		expected                string
+	}{
+		{"2024-03-10T14:30:00Z", "rfc3339", "", "2024-03-10T14:30:00Z"},
+		{"2024-03-10T14:30:00.250+02:00", "rfc3339", "", "2024-03-10T12:30:00.25Z"},
+		{"2024-03-10T14:30:00Z", "rfc3339", "America/New_York", "2024-03-10T10:30:00-04:00"},
+		{"Sun, 10 Mar 2024 14:30:00 GMT", "rfc1123", "", "2024-03-10T14:30:00Z"},
+		{"Sun, 10 Mar 2024 14:30:00 -0500", "rfc1123", "", "2024-03-10T19:30:00Z"},
+		{"Mon, 02 Jan 2006 15:04:05 PST", "rfc1123", "America/Los_Angeles", "2006-01-02T15:04:05-08:00"},
+		{"Mon, 03 Jul 2006 15:04:05 BST", "rfc1123", "Europe/London", "2006-07-03T15:04:05+01:00"},
+		{"Mon, 03 Jul 2006 15:04:05 UTC", "rfc1123", "Europe/London", "2006-07-03T16:04:05+01:00"},
+		{"1710081000", "unix", "", "2024-03-10T14:30:00Z"},
+		{"1710081000.5", "unix", "Europe/Berlin", "2024-03-10T15:30:00.5+01:00"},
+		{"-1.5", "unix", "", "1969-12-31T23:59:58.5Z"},
+		{"20240310T143000Z", "iso8601-basic", "", "2024-03-10T14:30:00Z"},
+		{"20240310T143000+0530", "iso8601-basic", "", "2024-03-10T09:00:00Z"},
+		{"20240310T143000", "iso8601-basic", "Asia/Tokyo", "2024-03-10T14:30:00+09:00"},
+		{"20240310", "iso8601-basic", "", "2024-03-10T00:00:00Z"},
+		{"10/03/2024 14:30", "02/01/2006 15:04", "Europe/London", "2024-03-10T14:30:00Z"},
+		{"2024-07-01 09:00", "2006-01-02 15:04", "Europe/London", "2024-07-01T09:00:00+01:00"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewTimeParseFunction(), types.StringValue(tt.input), types.StringValue(tt.layout), types.StringValue(tt.timezone))
+		if err != nil {
+			t.Fatalf("%s (%s): unexpected error: %s", tt.input, tt.layout, err)
+		}
+		if got := jsonOf(t, result); !strings.Contains(got, `"rfc3339":"`+tt.expected+`"`) {
+			t.Errorf("%s (%s, %q): expected %s, got %s", tt.input, tt.layout, tt.timezone, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewTimeParseFunction(), types.StringValue("2024-12-30T23:15:07.5-08:00"), types.StringValue("rfc3339"), types.StringValue("America/Los_Angeles"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := `{"components":{"day":30,"day_of_year":365,"hour":23,"iso_week":1,"minute":15,"month":12,"nanosecond":500000000,"offset":"-08:00","second":7,"timezone":"America/Los_Angeles","unix":1735629307,"weekday":"monday","year":2024},"rfc3339":"2024-12-30T23:15:07.5-08:00"}`
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s\ngot      %s", expected, got)
+	}
+
+	errorCases := []struct {
+		input, layout, timezone string
+	}{
+		{"2024-03-10", "rfc3339", ""},
+		{"yesterday", "unix", ""},
+		{"1.1234567891", "unix", ""},
+		{"2024-03-10T14:30:00Z", "rfc3339", "Mars/Olympus"},
+		{"2024-03-10T14:30:00Z", "rfc3339", "Local"},
+		{"2024-03-10", "", ""},
+		{"10/03/2024", "2006-01-02", ""},
+		{"Mon, 02 Jan 2006 15:04:05 PST", "rfc1123", ""},
+		{"Mon, 02 Jan 2006 15:04:05 PST", "rfc1123", "Europe/Berlin"},
+		{"2006-01-02 15:04 EST", "2006-01-02 15:04 MST", ""},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewTimeParseFunction(), types.StringValue(tt.input), types.StringValue(tt.layout), types.StringValue(tt.timezone)); err == nil {
+			t.Errorf("expected error for %q (%q, %q)", tt.input, tt.layout, tt.timezone)
+		}
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	const ts = "2024-03-10T14:30:05.123456789Z"
+	tests := []struct {
+		layout, timezone, expected string
+	}{
+		{"rfc3339", "", ts},
+		{"rfc3339", "Asia/Tokyo", "2024-03-10T23:30:05.123456789+09:00"},
+		{"rfc1123", "", "Sun, 10 Mar 2024 14:30:05 GMT"},
+		{"rfc1123", "America/New_York", "Sun, 10 Mar 2024 10:30:05 -0400"},
+		{"unix", "Europe/Berlin", "1710081005"},
+		{"iso8601-basic", "", "20240310T143005Z"},
+		{"iso8601-basic", "Asia/Kolkata", "20240310T200005+0530"},
+		{"2006-01-02 15:04 MST", "Europe/Berlin", "2024-03-10 15:30 CET"},
+		{"%Y-%m-%d %H:%M:%S %Z", "America/Los_Angeles", "2024-03-10 07:30:05 PDT"},
+		{"%a %A %b %B %e %j %I%p %y", "", "Sun Sunday Mar March 10 070 02PM 24"},
+		{"%F %T.%f %z %:z", "Australia/Adelaide", "2024-03-11 01:00:05.123456 +1030 +10:30"},
+		{"%D %R %s %u %w %G-W%V %%", "", "03/10/24 14:30 1710081005 7 0 2024-W10 %"},
+		{"backup-%Y%m%d", "Europe/London", "backup-20240310"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewTimeFormatFunction(), types.StringValue(ts), types.StringValue(tt.layout), types.StringValue(tt.timezone))
+		if err != nil {
+			t.Fatalf("%s (%s): unexpected error: %s", tt.layout, tt.timezone, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%s (%s): expected %q, got %q", tt.layout, tt.timezone, tt.expected, got)
+		}
+	}
+
+	errorCases := []struct {
+		timestamp, layout, timezone string
+	}{
+		{"2024-03-10", "rfc3339", ""},
+		{ts, "%Q", ""},
+		{ts, "%Y-%", ""},
+		{ts, "%:y", ""},
+		{ts, "rfc3339", "Nowhere/City"},
+	}
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewTimeFormatFunction(), types.StringValue(tt.timestamp), types.StringValue(tt.layout), types.StringValue(tt.timezone)); err == nil {
+			t.Errorf("expected error for %q (%q, %q)", tt.timestamp, tt.layout, tt.timezone)
+		}
+	}
+}
+
+func TestTimeArithmetic(t *testing.T) {
+	tests := []struct {
+		timestamp, duration, added, subtracted string
+	}{
+		{"2024-01-31T10:00:00Z", "1mo", "2024-02-29T10:00:00Z", "2023-12-31T10:00:00Z"},
+		{"2024-01-31T10:00:00Z", "P1M", "2024-02-29T10:00:00Z", "2023-12-31T10:00:00Z"},
+		{"2024-03-31T00:00:00+02:00", "1mo", "2024-04-30T00:00:00+02:00", "2024-02-29T00:00:00+02:00"},
+		{"2024-02-29T12:00:00Z", "1y", "2025-02-28T12:00:00Z", "2023-02-28T12:00:00Z"},
+		{"2024-05-15T08:00:00Z", "13mo", "2025-06-15T08:00:00Z", "2023-04-15T08:00:00Z"},
+		{"2024-05-15T08:00:00Z", "90d", "2024-08-13T08:00:00Z", "2024-02-15T08:00:00Z"},
+		{"2024-05-15T08:00:00Z", "2w", "2024-05-29T08:00:00Z", "2024-05-01T08:00:00Z"},
+		{"2024-05-15T08:00:00Z", "1w2d12h30m", "2024-05-24T20:30:00Z", "2024-05-05T19:30:00Z"},
+		{"2024-05-15T08:00:00Z", "PT36H", "2024-05-16T20:00:00Z", "2024-05-13T20:00:00Z"},
+		{"2024-05-15T08:00:00Z", "P1Y2M3W4DT5H6M7.5S", "2025-08-09T13:06:07.5Z", "2023-02-18T02:53:52.5Z"},
+		{"2024-05-15T08:00:00Z", "1.5h", "2024-05-15T09:30:00Z", "2024-05-15T06:30:00Z"},
+		{"2024-05-15T08:00:00Z", "500ms", "2024-05-15T08:00:00.5Z", "2024-05-15T07:59:59.5Z"},
+		{"2024-05-15T08:00:00Z", "-1d", "2024-05-14T08:00:00Z", "2024-05-16T08:00:00Z"},
+		{"2024-05-15T08:00:00Z", "-P1M", "2024-04-15T08:00:00Z", "2024-06-15T08:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewTimeAddFunction(), types.StringValue(tt.timestamp), types.StringValue(tt.duration))
+		if err != nil {
+			t.Fatalf("time_add(%s, %s): unexpected error: %s", tt.timestamp, tt.duration, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.added {
+			t.Errorf("time_add(%s, %s): expected %s, got %s", tt.timestamp, tt.duration, tt.added, got)
+		}
+
+		result, err = runFunction(t, NewTimeSubtractFunction(), types.StringValue(tt.timestamp), types.StringValue(tt.duration))
+		if err != nil {
+			t.Fatalf("time_subtract(%s, %s): unexpected error: %s", tt.timestamp, tt.duration, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.subtracted {
+			t.Errorf("time_subtract(%s, %s): expected %s, got %s", tt.timestamp, tt.duration, tt.subtracted, got)
+		}
+	}
+
+	for _, duration := range []string{"", "-", "P", "PT", "1.5d", "P1.5M", "10", "1x", "1h30", "d1", "P1H", "1 d"} {
+		if _, err := runFunction(t, NewTimeAddFunction(), types.StringValue("2024-05-15T08:00:00Z"), types.StringValue(duration)); err == nil {
+			t.Errorf("expected error for duration %q", duration)
+		}
+	}
+	for _, duration := range []string{"999999999999h", "2000000h2000000h", "PT9999999999999999999S", "99999999999999999999d", "8000y", "P7999Y12M"} {
+		if _, err := runFunction(t, NewTimeAddFunction(), types.StringValue("2024-03-31T10:00:00Z"), types.StringValue(duration)); err == nil {
+			t.Errorf("expected error for out of range duration %q", duration)
+		}
+	}
+	if _, err := runFunction(t, NewTimeSubtractFunction(), types.StringValue("0001-01-01T00:00:00Z"), types.StringValue("2y")); err == nil {
+		t.Error("expected error for a result before year 0000")
+	}
+	if _, err := runFunction(t, NewTimeAddFunction(), types.StringValue("2024-05-15"), types.StringValue("1d")); err == nil {
+		t.Error("expected error for invalid timestamp")
+	}
+}
+
+func TestTimeDiff(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected string
+	}{
+		{"2024-03-10T00:00:00Z", "2024-03-11T02:30:15Z", `{"components":{"days":1,"hours":2,"minutes":30,"seconds":15},"duration":"P1DT2H30M15S","seconds":95415}`},
+		{"2024-03-10T00:00:00Z", "2024-03-10T00:00:00+00:00", `{"components":{"days":0,"hours":0,"minutes":0,"seconds":0},"duration":"PT0S","seconds":0}`},
+		{"2024-03-10T12:00:00Z", "2024-03-10T12:00:00+02:00", `{"components":{"days":0,"hours":-2,"minutes":0,"seconds":0},"duration":"-PT2H","seconds":-7200}`},
+		{"2024-03-10T00:00:00.75Z", "2024-03-10T00:00:02.5Z", `{"components":{"days":0,"hours":0,"minutes":0,"seconds":1.75},"duration":"PT1.75S","seconds":1.75}`},
+		{"2024-03-10T00:00:01Z", "2024-03-10T00:00:00.5Z", `{"components":{"days":0,"hours":0,"minutes":0,"seconds":-0.5},"duration":"-PT0.5S","seconds":-0.5}`},
+		{"2024-01-01T00:00:00Z", "2024-03-01T00:00:00Z", `{"components":{"days":60,"hours":0,"minutes":0,"seconds":0},"duration":"P60D","seconds":5184000}`},
+		{"1700-01-01T00:00:00Z", "2100-01-01T00:00:00Z", `{"components":{"days":146097,"hours":0,"minutes":0,"seconds":0},"duration":"P146097D","seconds":12622780800}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewTimeDiffFunction(), types.StringValue(tt.a), types.StringValue(tt.b))
+		if err != nil {
+			t.Fatalf("%s to %s: unexpected error: %s", tt.a, tt.b, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s to %s: expected %s, got %s", tt.a, tt.b, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewTimeDiffFunction(), types.StringValue("2024-03-10"), types.StringValue("2024-03-11T00:00:00Z")); err == nil {
+		t.Error("expected error for a date without a time")
+	}
+}
+
+func TestUnixToRFC3339(t *testing.T) {
+	tests := []struct {
+		epoch, unit string
+		expected    string
+	}{
+		{"1710081000", "seconds", "2024-03-10T14:30:00Z"},
+		{"1710081000.123", "seconds", "2024-03-10T14:30:00.123Z"},
+		{"1710081000123", "milliseconds", "2024-03-10T14:30:00.123Z"},
+		{"1710081000123456", "microseconds", "2024-03-10T14:30:00.123456Z"},
+		{"1710081000123456789", "nanoseconds", "2024-03-10T14:30:00.123456789Z"},
+		{"-1500", "milliseconds", "1969-12-31T23:59:58.5Z"},
+		{"0", "seconds", "1970-01-01T00:00:00Z"},
+		{"253402300799", "seconds", "9999-12-31T23:59:59Z"},
+	}
+
+	for _, tt := range tests {
+		epoch, _, _ := big.ParseFloat(tt.epoch, 10, 512, big.ToNearestEven)
+		result, err := runFunction(t, NewUnixToRFC3339Function(), types.NumberValue(epoch), types.StringValue(tt.unit))
+		if err != nil {
+			t.Fatalf("%s %s: unexpected error: %s", tt.epoch, tt.unit, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%s %s: expected %s, got %s", tt.epoch, tt.unit, tt.expected, got)
+		}
+	}
+
+	errorCases := []struct {
+		epoch, unit string
+	}{
+		{"1710081000", "days"},
+		{"1.5", "nanoseconds"},
+		{"1710081000.0000000001", "seconds"},
+		{"253402300800", "seconds"},
+		{"1e30", "seconds"},
+	}
+
+	for _, tt := range errorCases {
+		epoch, _, _ := big.ParseFloat(tt.epoch, 10, 512, big.ToNearestEven)
+		if _, err := runFunction(t, NewUnixToRFC3339Function(), types.NumberValue(epoch), types.StringValue(tt.unit)); err == nil {
+			t.Errorf("%s %s: expected error", tt.epoch, tt.unit)
+		}
+	}
+}
+
+func TestRFC3339ToUnix(t *testing.T) {
+	tests := []struct {
+		timestamp, unit string
+		expected        string
+	}{
+		{"2024-03-10T14:30:00Z", "seconds", "1710081000"},
+		{"2024-03-10T16:30:00+02:00", "milliseconds", "1710081000000"},
+		{"2024-03-10T14:30:00.123456789Z", "nanoseconds", "1710081000123456789"},
+		{"2024-03-10T14:30:00.5Z", "seconds", "1710081000.5"},
+		{"2024-03-10T14:30:00.1234Z", "milliseconds", "1710081000123.4"},
+		{"1969-12-31T23:59:58.5Z", "milliseconds", "-1500"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewRFC3339ToUnixFunction(), types.StringValue(tt.timestamp), types.StringValue(tt.unit))
+		if err != nil {
+			t.Fatalf("%s %s: unexpected error: %s", tt.timestamp, tt.unit, err)
+		}
+		if got := result.(types.Number).ValueBigFloat().Text('f', -1); got != tt.expected {
+			t.Errorf("%s %s: expected %s, got %s", tt.timestamp, tt.unit, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewRFC3339ToUnixFunction(), types.StringValue("2024-03-10T14:30:00Z"), types.StringValue("ms")); err == nil {
+		t.Error("expected error for an unknown unit")
+	}
+}
+
+func TestCronValidate(t *testing.T) {
+	tests := []struct {
+		expression, flavor string
+		reason             string
+	}{
+		{"0 9 * * 1-5", "standard", ""},
+		{"*/15 0-6,22,23 1,15 JAN-MAR sun", "standard", ""},
+		{"@daily", "standard", ""},
+		{"60 * * * *", "standard", "minute field: 60 is outside 0-59"},
+		{"0 9 * *", "standard", "expected 5 fields (minute hour day-of-month month day-of-week), got 4"},
+		{"0 9 ? * *", "standard", "day-of-month field: ? is not supported by standard cron"},
+		{"0 9 L * *", "standard", `day-of-month field: invalid value "L"`},
+		{"0 9 * * 5-1", "standard", `day-of-week field: range "5-1" runs backwards`},
+		{"*/0 * * * *", "standard", `minute field: invalid step "0"`},
+		{"@reboot", "standard", "unsupported macro \"@reboot\", expected one of: @annually, @daily, @hourly, @midnight, @monthly, @weekly, @yearly"},
+		{"cron(0 12 ? * MON-FRI *)", "aws", ""},
+		{"cron(0 18 L * ? 2024-2030)", "aws", ""},
+		{"rate(5 minutes)", "aws", ""},
+		{"rate(1 day)", "aws", ""},
+		{"rate(1 days)", "aws", `rate unit must be "day" for a value of 1 and plural otherwise`},
+		{"cron(0 12 * * * *)", "aws", "exactly one of the day-of-month and day-of-week fields must be ?"},
+		{"cron(0 12 ? * 0 *)", "aws", "day-of-week field: 0 is outside 1-7"},
+		{"0 12 ? * MON *", "aws", "AWS schedules must be cron(minutes hours day-of-month month day-of-week year) or rate(value unit)"},
+		{"0 0 12 ? * WED", "quartz", ""},
+		{"0 0 12 15W,L-2 * ? 2030", "quartz", ""},
+		{"0 0 12 ? * 6#3,2L", "quartz", ""},
+		{"0 0 12 * * MON", "quartz", "exactly one of the day-of-month and day-of-week fields must be ?"},
+		{"0 0 12 ? * 2#6", "quartz", `day-of-week field: invalid occurrence "6" in "2#6", expected 1-5`},
+		{"0 0 12 * *", "quartz", "expected 7 fields (second minute hour day-of-month month day-of-week year), got 5"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewCronValidateFunction(), types.StringValue(tt.expression), types.StringValue(tt.flavor))
+		if err != nil {
+			t.Fatalf("%s (%s): unexpected error: %s", tt.expression, tt.flavor, err)
+		}
+		expected := fmt.Sprintf(`{"reason":%q,"valid":%t}`, tt.reason, tt.reason == "")
+		if got := jsonOf(t, result); got != expected {
+			t.Errorf("%s (%s): expected %s, got %s", tt.expression, tt.flavor, expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewCronValidateFunction(), types.StringValue("0 9 * * *"), types.StringValue("vixie")); err == nil {
+		t.Error("expected error for an unknown flavor")
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		expression, from string
+		count            int64
+		expected         []string
+	}{
+		{"*/15 * * * *", "2024-03-10T14:30:00Z", 3, []string{"2024-03-10T14:45:00Z", "2024-03-10T15:00:00Z", "2024-03-10T15:15:00Z"}},
+		{"0 9 * * 1-5", "2024-03-10T14:30:00Z", 2, []string{"2024-03-11T09:00:00Z", "2024-03-12T09:00:00Z"}},
+		{"0 0 13 * 5", "2024-03-10T14:30:00Z", 2, []string{"2024-03-13T00:00:00Z", "2024-03-15T00:00:00Z"}},
+		{"@monthly", "2024-03-10T14:30:00Z", 1, []string{"2024-04-01T00:00:00Z"}},
+		{"0 15 * * *", "2024-03-10T14:30:00+02:00", 1, []string{"2024-03-10T15:00:00+02:00"}},
+		{"0 0 30 2 *", "2024-03-10T14:30:00Z", 1, []string{}},
+		{"cron(0 12 ? * MON-FRI *)", "2024-03-10T14:30:00Z", 2, []string{"2024-03-11T12:00:00Z", "2024-03-12T12:00:00Z"}},
+		{"rate(6 hours)", "2024-03-10T14:30:00Z", 2, []string{"2024-03-10T20:30:00Z", "2024-03-11T02:30:00Z"}},
+		{"0 0 10 L * ?", "2024-03-10T14:30:00Z", 2, []string{"2024-03-31T10:00:00Z", "2024-04-30T10:00:00Z"}},
+		{"0 0 10 ? * 6#3", "2024-03-10T14:30:00Z", 2, []string{"2024-03-15T10:00:00Z", "2024-04-19T10:00:00Z"}},
+		{"0 0 10 ? * 2L", "2024-03-10T14:30:00Z", 2, []string{"2024-03-25T10:00:00Z", "2024-04-29T10:00:00Z"}},
+		{"0 0 9 15W * ?", "2024-03-10T14:30:00Z", 4, []string{"2024-03-15T09:00:00Z", "2024-04-15T09:00:00Z", "2024-05-15T09:00:00Z", "2024-06-14T09:00:00Z"}},
+		{"0 0 0 LW * ?", "2024-03-10T14:30:00Z", 2, []string{"2024-03-29T00:00:00Z", "2024-04-30T00:00:00Z"}},
+		{"30 0 0 1 1 ? 2025", "2024-03-10T14:30:00Z", 2, []string{"2025-01-01T00:00:30Z"}},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewCronNextFunction(), types.StringValue(tt.expression), types.StringValue(tt.from), types.Int64Value(tt.count))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.expression, err)
+		}
+		if got, expected := jsonOf(t, result), jsonOf(t, stringList(tt.expected...)); got != expected {
+			t.Errorf("%s: expected %s, got %s", tt.expression, expected, got)
+		}
+	}
+
+	errorCases := []struct {
+		expression, from string
+		count            int64
+	}{
+		{"0 9 * *", "2024-03-10T14:30:00Z", 1},
+		{"0 0 12 * * MON", "2024-03-10T14:30:00Z", 1},
+		{"0 9 * * *", "2024-03-10", 1},
+		{"0 9 * * *", "2024-03-10T14:30:00Z", 0},
+		{"0 9 * * *", "2024-03-10T14:30:00Z", 101},
+	}
+
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewCronNextFunction(), types.StringValue(tt.expression), types.StringValue(tt.from), types.Int64Value(tt.count)); err == nil {
+			t.Errorf("%s from %s (%d): expected error", tt.expression, tt.from, tt.count)
+		}
+	}
+}
+
+func TestTimeBucket(t *testing.T) {
+	tests := []struct {
+		timestamp, interval string
+		floor, ceil         string
+	}{
+		{"2024-03-10T14:30:15.5Z", "hour", "2024-03-10T14:00:00Z", "2024-03-10T15:00:00Z"},
+		{"2024-03-10T14:30:00Z", "day", "2024-03-10T00:00:00Z", "2024-03-11T00:00:00Z"},
+		{"2024-03-10T14:30:00Z", "week", "2024-03-04T00:00:00Z", "2024-03-11T00:00:00Z"},
+		{"2024-03-11T00:00:00Z", "week", "2024-03-11T00:00:00Z", "2024-03-11T00:00:00Z"},
+		{"2024-12-31T23:59:59Z", "month", "2024-12-01T00:00:00Z", "2025-01-01T00:00:00Z"},
+		{"2024-03-01T00:00:00Z", "month", "2024-03-01T00:00:00Z", "2024-03-01T00:00:00Z"},
+		{"2024-03-10T01:30:00+05:30", "day", "2024-03-10T00:00:00+05:30", "2024-03-11T00:00:00+05:30"},
+	}
+
+	for _, tt := range tests {
+		for _, c := range []struct {
+			f        func() function.Function
+			expected string
+		}{{NewTimeFloorFunction, tt.floor}, {NewTimeCeilFunction, tt.ceil}} {
+			result, err := runFunction(t, c.f(), types.StringValue(tt.timestamp), types.StringValue(tt.interval))
+			if err != nil {
+				t.Fatalf("%s (%s): unexpected error: %s", tt.timestamp, tt.interval, err)
+			}
+			if got := result.(types.String).ValueString(); got != c.expected {
+				t.Errorf("%s (%s): expected %s, got %s", tt.timestamp, tt.interval, c.expected, got)
+			}
+		}
+	}
+
+	if _, err := runFunction(t, NewTimeFloorFunction(), types.StringValue("2024-03-10T14:30:00Z"), types.StringValue("quarter")); err == nil {
+		t.Error("expected error for an unknown interval")
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	tests := []struct {
+		timestamp string
+		n         int64
+		holidays  []string
+		expected  string
+	}{
+		{"2024-03-08T17:00:00Z", 1, nil, "2024-03-11T17:00:00Z"},
+		{"2024-03-08T17:00:00Z", 1, []string{"2024-03-11"}, "2024-03-12T17:00:00Z"},
+		{"2024-03-11T09:00:00Z", -1, nil, "2024-03-08T09:00:00Z"},
+		{"2024-12-18T09:00:00-05:00", 5, []string{"2024-12-25", "2024-12-26"}, "2024-12-27T09:00:00-05:00"},
+		{"2024-03-09T12:00:00Z", 0, nil, "2024-03-09T12:00:00Z"},
+		{"2024-03-09T12:00:00Z", 10, nil, "2024-03-22T12:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		holidays := types.ListNull(types.StringType)
+		if tt.holidays != nil {
+			holidays = stringList(tt.holidays...)
+		}
+		result, err := runFunction(t, NewAddBusinessDaysFunction(), types.StringValue(tt.timestamp), types.Int64Value(tt.n), holidays)
+		if err != nil {
+			t.Fatalf("%s + %d: unexpected error: %s", tt.timestamp, tt.n, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%s + %d: expected %s, got %s", tt.timestamp, tt.n, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewAddBusinessDaysFunction(), types.StringValue("2024-03-08T17:00:00Z"), types.Int64Value(1), stringList("25/12/2024")); err == nil {
+		t.Error("expected error for an invalid holiday")
+	}
+	if _, err := runFunction(t, NewAddBusinessDaysFunction(), types.StringValue("2024-03-08T17:00:00Z"), types.Int64Value(10001), stringList()); err == nil {
+		t.Error("expected error for too many days")
+	}
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	tests := []struct {
+		timestamp string
+		holidays  []string
+		expected  bool
+	}{
+		{"2024-03-08T17:00:00Z", nil, true},
+		{"2024-03-09T17:00:00Z", nil, false},
+		{"2024-03-10T17:00:00Z", nil, false},
+		{"2024-12-25T09:00:00Z", []string{"2024-12-25"}, false},
+		{"2024-03-09T01:00:00+10:00", nil, false},
+		{"2024-03-08T20:00:00-08:00", nil, true},
+	}
+
+	for _, tt := range tests {
+		holidays := types.ListNull(types.StringType)
+		if tt.holidays != nil {
+			holidays = stringList(tt.holidays...)
+		}
+		result, err := runFunction(t, NewIsBusinessDayFunction(), types.StringValue(tt.timestamp), holidays)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.timestamp, err)
+		}
+		if got := result.(types.Bool).ValueBool(); got != tt.expected {
+			t.Errorf("%s: expected %t, got %t", tt.timestamp, tt.expected, got)
+		}
+	}
+}
+
+func TestWindowsOverlap(t *testing.T) {
+	window := func(day, start, end, timezone string) map[string]any {
+		w := map[string]any{"day": day, "start": start, "end": end}
+		if timezone != "" {
+			w["timezone"] = timezone
+		}
+		return w
+	}
+	tests := []struct {
+		a, b     map[string]any
+		expected string
+	}{
+		{window("sun", "02:00", "04:00", ""), window("sun", "03:00", "05:00", ""), `{"overlap_minutes":60,"overlaps":true}`},
+		{window("sun", "02:00", "04:00", ""), window("sunday", "04:00", "05:00", ""), `{"overlap_minutes":0,"overlaps":false}`},
+		{window("sat", "23:00", "01:00", ""), window("Sun", "00:30", "02:00", ""), `{"overlap_minutes":30,"overlaps":true}`},
+		{window("daily", "03:00", "04:00", ""), window("tue", "03:30", "06:00", "UTC"), `{"overlap_minutes":30,"overlaps":true}`},
+		{window("sun", "02:00", "03:00", "Europe/Berlin"), window("sun", "00:00", "01:00", ""), `{"overlap_minutes":60,"overlaps":true}`},
+		{window("mon", "09:00", "10:00", "America/New_York"), window("mon", "14:30", "15:30", ""), `{"overlap_minutes":30,"overlaps":true}`},
+		{window("mon", "09:00", "10:00", "America/New_York"), window("tue", "09:00", "10:00", "America/New_York"), `{"overlap_minutes":0,"overlaps":false}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewWindowsOverlapFunction(), dynamicOf(t, tt.a), dynamicOf(t, tt.b))
+		if err != nil {
+			t.Fatalf("%v and %v: unexpected error: %s", tt.a, tt.b, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%v and %v: expected %s, got %s", tt.a, tt.b, tt.expected, got)
+		}
+	}
+
+	valid := window("sun", "02:00", "04:00", "")
+	errorCases := []any{
+		nil,
+		window("funday", "02:00", "04:00", ""),
+		window("sun", "24:00", "04:00", ""),
+		window("sun", "2:00", "04:00", ""),
+		window("sun", "02:00", "02:00", ""),
+		window("sun", "02:00", "04:00", "Mars/Olympus"),
+		map[string]any{"day": "sun", "start": "02:00", "end": "04:00", "zone": "UTC"},
+		map[string]any{"day": "sun", "start": "02:00"},
+	}
+
+	for _, w := range errorCases {
+		if _, err := runFunction(t, NewWindowsOverlapFunction(), dynamicOf(t, valid), dynamicOf(t, w)); err == nil {
+			t.Errorf("%v: expected error", w)
+		}
+	}
+}
@@ -0,0 +1,652 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"mime"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// escapeQueryComponent percent-encodes everything except the RFC 3986
+// unreserved characters, so the result reads back the same whether the
+// receiver treats "+" as a space or not.
+func escapeQueryComponent(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// encodeQuery builds a query string from an object whose values are scalars
+// or lists of scalars. Keys are sorted, list values repeat the key and null
+// values are omitted.
+func encodeQuery(params map[string]any) (string, error) {
+	var pairs []string
+	for _, key := range sortedKeys(params) {
+		values, ok := params[key].([]any)
+		if !ok {
+			values = []any{params[key]}
+		}
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+			rendered, err := csvField(value)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", key, err)
+			}
+			pairs = append(pairs, escapeQueryComponent(key)+"="+escapeQueryComponent(rendered))
+		}
+	}
+	return strings.Join(pairs, "&"), nil
+}
+
+// URL Query Decode Function
+var _ function.Function = &URLQueryDecodeFunction{}
+
+type URLQueryDecodeFunction struct{}
+
+func NewURLQueryDecodeFunction() function.Function {
+	return &URLQueryDecodeFunction{}
+}
+
+func (f *URLQueryDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "url_query_decode"
+}
+
+func (f *URLQueryDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decodes a URL query string",
+		Description: "Parses a URL query string into a map of parameter names to the list of their values, in the order they " +
+			"appear. A leading \"?\" is ignored and \"+\" decodes to a space.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "query",
+				Description: "The query string to decode",
+			},
+		},
+		Return: function.MapReturn{
+			ElementType: types.ListType{ElemType: types.StringType},
+		},
+	}
+}
+
+func (f *URLQueryDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var query string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &query))
+	if resp.Error != nil {
+		return
+	}
+
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid query string: %s", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, map[string][]string(values)))
+}
+
+// URL Query Encode Function
+var _ function.Function = &URLQueryEncodeFunction{}
+
+type URLQueryEncodeFunction struct{}
+
+func NewURLQueryEncodeFunction() function.Function {
+	return &URLQueryEncodeFunction{}
+}
+
+func (f *URLQueryEncodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "url_query_encode"
+}
+
+func (f *URLQueryEncodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encodes a URL query string",
+		Description: "Builds a query string from a map of parameter names to a value or a list of values. Keys are sorted, list " +
+			"values repeat the key, null values are omitted and everything except unreserved characters is percent-encoded.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "params",
+				Description: "An object or map of parameter names to strings, numbers, bools or lists of them",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *URLQueryEncodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	params, ok := data.(map[string]any)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("params must be an object or map, got %s", typeName(data))))
+		return
+	}
+
+	query, err := encodeQuery(params)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, query))
+}
+
+// parsedURL is the object returned by url_parse.
+type parsedURL struct {
+	Scheme   string              `tfsdk:"scheme"`
+	Userinfo string              `tfsdk:"userinfo"`
+	Username string              `tfsdk:"username"`
+	Password string              `tfsdk:"password"`
+	Host     string              `tfsdk:"host"`
+	Port     types.Int64         `tfsdk:"port"`
+	Path     string              `tfsdk:"path"`
+	Query    map[string][]string `tfsdk:"query"`
+	Fragment string              `tfsdk:"fragment"`
+}
+
+var parsedURLType = map[string]attr.Type{
+	"scheme":   types.StringType,
+	"userinfo": types.StringType,
+	"username": types.StringType,
+	"password": types.StringType,
+	"host":     types.StringType,
+	"port":     types.Int64Type,
+	"path":     types.StringType,
+	"query":    types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+	"fragment": types.StringType,
+}
+
+// parseURL splits an absolute URL into its components, decoding the path,
+// query and fragment.
+func parseURL(raw string) (parsedURL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return parsedURL{}, errors.Unwrap(err)
+	}
+	if u.Scheme == "" {
+		return parsedURL{}, fmt.Errorf("missing scheme, expected an absolute URL such as https://example.com/path")
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return parsedURL{}, fmt.Errorf("invalid query: %w", err)
+	}
+
+	result := parsedURL{
+		Scheme:   strings.ToLower(u.Scheme),
+		Host:     u.Hostname(),
+		Port:     types.Int64Null(),
+		Path:     u.Path,
+		Query:    query,
+		Fragment: u.Fragment,
+	}
+	if u.Opaque != "" {
+		result.Path = u.Opaque
+	}
+	if port := u.Port(); port != "" {
+		number, err := strconv.ParseInt(port, 10, 64)
+		if err != nil || number > 65535 {
+			return parsedURL{}, fmt.Errorf("invalid port %q", port)
+		}
+		result.Port = types.Int64Value(number)
+	}
+	if u.User != nil {
+		result.Userinfo = u.User.String()
+		result.Username = u.User.Username()
+		result.Password, _ = u.User.Password()
+	}
+	return result, nil
+}
+
+// URL Parse Function
+var _ function.Function = &URLParseFunction{}
+
+type URLParseFunction struct{}
+
+func NewURLParseFunction() function.Function {
+	return &URLParseFunction{}
+}
+
+func (f *URLParseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "url_parse"
+}
+
+func (f *URLParseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a URL into its components",
+		Description: "Splits an absolute URL into scheme, userinfo, host, port, path, query and fragment. The path, query and " +
+			"fragment are percent-decoded, and port is null when the URL does not specify one.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "url",
+				Description: "The absolute URL to parse",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: parsedURLType,
+		},
+	}
+}
+
+func (f *URLParseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var raw string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &raw))
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := parseURL(raw)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid URL %q: %s", raw, err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, parsed))
+}
+
+// urlScheme matches a valid RFC 3986 scheme.
+var urlScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*$`)
+
+// urlBuildDefaults lists the components accepted by url_build. They match
+// the attributes returned by url_parse.
+var urlBuildDefaults = map[string]any{
+	"scheme":   "",
+	"userinfo": "",
+	"username": "",
+	"password": "",
+	"host":     "",
+	"port":     new(big.Float),
+	"path":     "",
+	"query":    map[string]any{},
+	"fragment": "",
+}
+
+// buildURL assembles a URL from decoded components, escaping each one for
+// its position.
+func buildURL(components map[string]any) (string, error) {
+	scheme := components["scheme"].(string)
+	if !urlScheme.MatchString(scheme) {
+		return "", fmt.Errorf("scheme must be set to a valid URL scheme, got %q", scheme)
+	}
+
+	u := &url.URL{
+		Scheme:   strings.ToLower(scheme),
+		Host:     components["host"].(string),
+		Path:     components["path"].(string),
+		Fragment: components["fragment"].(string),
+	}
+	if strings.Contains(u.Host, ":") {
+		u.Host = "[" + u.Host + "]"
+	}
+	if u.Path != "" && !strings.HasPrefix(u.Path, "/") {
+		if u.Host == "" {
+			// A rootless path without a host, such as mailto:, is opaque.
+			u.Opaque, u.Path = (&url.URL{Path: u.Path}).EscapedPath(), ""
+		} else {
+			u.Path = "/" + u.Path
+		}
+	}
+
+	if port, ok := toInt64(components["port"]); !ok || port < 0 || port > 65535 {
+		return "", fmt.Errorf("port must be an integer between 1 and 65535")
+	} else if port > 0 {
+		if u.Host == "" {
+			return "", fmt.Errorf("port requires a host")
+		}
+		u.Host += ":" + strconv.FormatInt(port, 10)
+	}
+
+	username, password := components["username"].(string), components["password"].(string)
+	if userinfo := components["userinfo"].(string); userinfo != "" && username == "" && password == "" {
+		rawUsername, rawPassword, hasPassword := strings.Cut(userinfo, ":")
+		var err error
+		if username, err = url.PathUnescape(rawUsername); err != nil {
+			return "", fmt.Errorf("invalid userinfo: %w", err)
+		}
+		if password, err = url.PathUnescape(rawPassword); err != nil {
+			return "", fmt.Errorf("invalid userinfo: %w", err)
+		}
+		if hasPassword {
+			u.User = url.UserPassword(username, password)
+		} else {
+			u.User = url.User(username)
+		}
+	} else if password != "" {
+		u.User = url.UserPassword(username, password)
+	} else if username != "" {
+		u.User = url.User(username)
+	}
+	if u.User != nil && u.Host == "" {
+		return "", fmt.Errorf("userinfo requires a host")
+	}
+
+	query, err := encodeQuery(components["query"].(map[string]any))
+	if err != nil {
+		return "", fmt.Errorf("query: %w", err)
+	}
+	u.RawQuery = query
+
+	return u.String(), nil
+}
+
+// parseAbsoluteURL parses a base URL, requiring a scheme.
+func parseAbsoluteURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, errors.Unwrap(err)
+	}
+	if u.Scheme == "" || u.Opaque != "" {
+		return nil, fmt.Errorf("expected an absolute URL such as https://example.com/path")
+	}
+	return u, nil
+}
+
+// joinURL appends path segments to the path of base, escaping each segment
+// and collapsing the slashes between them. A trailing slash on the last part
+// is kept.
+func joinURL(base string, parts []string) (string, error) {
+	u, err := parseAbsoluteURL(base)
+	if err != nil {
+		return "", err
+	}
+
+	escaped := strings.TrimRight(u.EscapedPath(), "/")
+	trailing := false
+	for _, part := range parts {
+		for _, segment := range strings.Split(part, "/") {
+			if segment == "." || segment == ".." {
+				return "", fmt.Errorf("part %q contains a %q segment, use url_resolve for relative references", part, segment)
+			}
+			if segment != "" {
+				escaped += "/" + url.PathEscape(segment)
+			}
+		}
+		if part != "" {
+			trailing = strings.HasSuffix(part, "/")
+		}
+	}
+	if trailing || escaped == "" {
+		escaped += "/"
+	}
+
+	if u.Path, err = url.PathUnescape(escaped); err != nil {
+		return "", err
+	}
+	u.RawPath = escaped
+	return u.String(), nil
+}
+
+// URL Build Function
+var _ function.Function = &URLBuildFunction{}
+
+type URLBuildFunction struct{}
+
+func NewURLBuildFunction() function.Function {
+	return &URLBuildFunction{}
+}
+
+func (f *URLBuildFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "url_build"
+}
+
+func (f *URLBuildFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds a URL from its components",
+		Description: "Assembles a URL from an object with the same attributes url_parse returns: scheme, userinfo or " +
+			"username and password, host, port, path, query and fragment. Only scheme is required, and each component is " +
+			"escaped for its position.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "components",
+				Description: "An object of decoded URL components",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *URLBuildFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := fromValue(ctx, value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if data == nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "components must be an object, got null"))
+		return
+	}
+	components, err := parseOptions(data, urlBuildDefaults)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	result, err := buildURL(components)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// URL Join Function
+var _ function.Function = &URLJoinFunction{}
+
+type URLJoinFunction struct{}
+
+func NewURLJoinFunction() function.Function {
+	return &URLJoinFunction{}
+}
+
+func (f *URLJoinFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "url_join"
+}
+
+func (f *URLJoinFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Appends path segments to a URL",
+		Description: "Appends parts to the path of an absolute URL, collapsing slashes so no double slashes are produced. " +
+			"Slashes inside a part separate segments, and each segment is percent-escaped. The query and fragment of the " +
+			"base URL are kept.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "base",
+				Description: "The absolute URL to append to",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:        "parts",
+			Description: "Unescaped path parts to append",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *URLJoinFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var base string
+	var parts []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &base, &parts))
+	if resp.Error != nil {
+		return
+	}
+
+	result, err := joinURL(base, parts)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewFuncError(fmt.Sprintf("Unable to join URL %q: %s", base, err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// URL Resolve Function
+var _ function.Function = &URLResolveFunction{}
+
+type URLResolveFunction struct{}
+
+func NewURLResolveFunction() function.Function {
+	return &URLResolveFunction{}
+}
+
+func (f *URLResolveFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "url_resolve"
+}
+
+func (f *URLResolveFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Resolves a reference against a base URL",
+		Description: "Resolves a relative or absolute reference against an absolute base URL as a browser would (RFC 3986), " +
+			"handling \".\" and \"..\" segments, root-relative paths and scheme-relative references.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "base",
+				Description: "The absolute base URL",
+			},
+			function.StringParameter{
+				Name:        "reference",
+				Description: "The reference to resolve, such as \"../v2/items\" or \"/health\"",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *URLResolveFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var base, reference string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &base, &reference))
+	if resp.Error != nil {
+		return
+	}
+
+	baseURL, err := parseAbsoluteURL(base)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("Invalid base URL %q: %s", base, err)))
+		return
+	}
+	referenceURL, err := url.Parse(reference)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("Invalid reference %q: %s", reference, errors.Unwrap(err))))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, baseURL.ResolveReference(referenceURL).String()))
+}
+
+// buildDataURI builds an RFC 2397 data URI. An empty media type is omitted,
+// which readers interpret as text/plain;charset=US-ASCII.
+func buildDataURI(content, mediaType string, encode bool) (string, error) {
+	if mediaType != "" {
+		parsed, params, err := mime.ParseMediaType(mediaType)
+		if err != nil {
+			return "", err
+		}
+		if !strings.Contains(parsed, "/") {
+			return "", fmt.Errorf("expected type/subtype")
+		}
+		// FormatMediaType separates parameters with "; ", but spaces are not
+		// allowed in a URI, so quoted parameter values are rejected.
+		mediaType = strings.ReplaceAll(mime.FormatMediaType(parsed, params), "; ", ";")
+		if mediaType == "" || strings.ContainsAny(mediaType, "\" ,") {
+			return "", fmt.Errorf("parameter values must be tokens")
+		}
+	}
+
+	if encode {
+		return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString([]byte(content)), nil
+	}
+	return "data:" + mediaType + "," + escapeQueryComponent(content), nil
+}
+
+// Data URI Function
+var _ function.Function = &DataURIFunction{}
+
+type DataURIFunction struct{}
+
+func NewDataURIFunction() function.Function {
+	return &DataURIFunction{}
+}
+
+func (f *DataURIFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "data_uri"
+}
+
+func (f *DataURIFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds an RFC 2397 data URI",
+		Description: "Builds a data: URI that inlines content with the given media type, either base64-encoded or " +
+			"percent-encoded. The media type is validated and normalized.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "content",
+				Description: "The content to inline",
+			},
+			function.StringParameter{
+				Name:        "mime_type",
+				Description: "The media type, such as \"image/svg+xml\" or \"text/html;charset=utf-8\", or \"\" to omit it",
+			},
+			function.BoolParameter{
+				Name:        "base64",
+				Description: "Base64-encode the content instead of percent-encoding it",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *DataURIFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content, mediaType string
+	var encode bool
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content, &mediaType, &encode))
+	if resp.Error != nil {
+		return
+	}
+
+	uri, err := buildDataURI(content, mediaType, encode)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("Invalid media type %q: %s", mediaType, err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, uri))
+}
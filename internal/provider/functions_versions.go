@@ -0,0 +1,758 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// semver is a parsed Semantic Versioning 2.0.0 version.
+type semver struct {
+	major, minor, patch int64
+	prerelease          []string
+	build               string
+}
+
+// String returns the canonical form of v, without a leading "v".
+func (v semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if len(v.prerelease) > 0 {
+		s += "-" + strings.Join(v.prerelease, ".")
+	}
+	if v.build != "" {
+		s += "+" + v.build
+	}
+	return s
+}
+
+// splitSemverIdentifiers splits a prerelease or build part into its
+// dot-separated identifiers. Identifiers must be non-empty and use only ASCII
+// letters, digits and hyphens; numeric prerelease identifiers must not have
+// leading zeros.
+func splitSemverIdentifiers(part, kind string, numeric bool) ([]string, error) {
+	identifiers := strings.Split(part, ".")
+	for _, id := range identifiers {
+		if id == "" {
+			return nil, fmt.Errorf("empty %s identifier", kind)
+		}
+		for _, c := range id {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return nil, fmt.Errorf("%s identifier %q contains invalid character %q", kind, id, c)
+			}
+		}
+		if numeric && len(id) > 1 && id[0] == '0' && strings.Trim(id, "0123456789") == "" {
+			return nil, fmt.Errorf("numeric %s identifier %q has a leading zero", kind, id)
+		}
+	}
+	return identifiers, nil
+}
+
+// parseSemver parses a Semantic Versioning 2.0.0 version such as
+// 1.2.3-rc.1+build.5, with an optional leading "v".
+func parseSemver(text string) (semver, error) {
+	var v semver
+	rest := text
+	if strings.HasPrefix(rest, "v") || strings.HasPrefix(rest, "V") {
+		rest = rest[1:]
+	}
+	rest, build, hasBuild := strings.Cut(rest, "+")
+	core, prerelease, hasPrerelease := strings.Cut(rest, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version %q, expected MAJOR.MINOR.PATCH such as 1.2.3", text)
+	}
+	numbers := make([]int64, 3)
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || (len(part) > 1 && part[0] == '0') {
+			return v, fmt.Errorf("invalid version %q: %q is not a number without leading zeros", text, part)
+		}
+		numbers[i] = n
+	}
+	v.major, v.minor, v.patch = numbers[0], numbers[1], numbers[2]
+
+	if hasPrerelease {
+		identifiers, err := splitSemverIdentifiers(prerelease, "prerelease", true)
+		if err != nil {
+			return v, fmt.Errorf("invalid version %q: %s", text, err)
+		}
+		v.prerelease = identifiers
+	}
+	if hasBuild {
+		if _, err := splitSemverIdentifiers(build, "build", false); err != nil {
+			return v, fmt.Errorf("invalid version %q: %s", text, err)
+		}
+		v.build = build
+	}
+	return v, nil
+}
+
+// compareSemver orders versions by SemVer precedence: numbers first, then a
+// prerelease sorts before the release, with prerelease identifiers compared
+// numerically or in ASCII order and numeric identifiers before others. Build
+// metadata is ignored.
+func compareSemver(a, b semver) int {
+	if c := cmp.Compare(a.major, b.major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.minor, b.minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.patch, b.patch); c != 0 {
+		return c
+	}
+	switch {
+	case len(a.prerelease) == 0 && len(b.prerelease) == 0:
+		return 0
+	case len(a.prerelease) == 0:
+		return 1
+	case len(b.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.prerelease) && i < len(b.prerelease); i++ {
+		x, y := a.prerelease[i], b.prerelease[i]
+		xNumeric, yNumeric := strings.Trim(x, "0123456789") == "", strings.Trim(y, "0123456789") == ""
+		var c int
+		switch {
+		case xNumeric && yNumeric:
+			c = cmp.Or(cmp.Compare(len(x), len(y)), strings.Compare(x, y))
+		case xNumeric:
+			c = -1
+		case yNumeric:
+			c = 1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a.prerelease), len(b.prerelease))
+}
+
+// parsedSemver is the result of semver_parse.
+type parsedSemver struct {
+	Version    string `tfsdk:"version"`
+	Major      int64  `tfsdk:"major"`
+	Minor      int64  `tfsdk:"minor"`
+	Patch      int64  `tfsdk:"patch"`
+	Prerelease string `tfsdk:"prerelease"`
+	Build      string `tfsdk:"build"`
+}
+
+var parsedSemverType = map[string]attr.Type{
+	"version":    types.StringType,
+	"major":      types.Int64Type,
+	"minor":      types.Int64Type,
+	"patch":      types.Int64Type,
+	"prerelease": types.StringType,
+	"build":      types.StringType,
+}
+
+// Semver Parse Function
+var _ function.Function = &SemverParseFunction{}
+
+type SemverParseFunction struct{}
+
+func NewSemverParseFunction() function.Function {
+	return &SemverParseFunction{}
+}
+
+func (f *SemverParseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "semver_parse"
+}
+
+func (f *SemverParseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a semantic version",
+		Description: "Parses a Semantic Versioning 2.0.0 version, with an optional leading \"v\", into its major, minor " +
+			"and patch numbers, prerelease and build metadata.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "version",
+				Description: "The version, such as \"1.2.3\" or \"v1.28.0-rc.1+build.5\"",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: parsedSemverType},
+	}
+}
+
+func (f *SemverParseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	v, err := parseSemver(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, parsedSemver{
+		Version:    v.String(),
+		Major:      v.major,
+		Minor:      v.minor,
+		Patch:      v.patch,
+		Prerelease: strings.Join(v.prerelease, "."),
+		Build:      v.build,
+	}))
+}
+
+// Semver Compare Function
+var _ function.Function = &SemverCompareFunction{}
+
+type SemverCompareFunction struct{}
+
+func NewSemverCompareFunction() function.Function {
+	return &SemverCompareFunction{}
+}
+
+func (f *SemverCompareFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "semver_compare"
+}
+
+func (f *SemverCompareFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compares two semantic versions",
+		Description: "Returns -1 when a has lower precedence than b, 0 when they are equal and 1 when a is higher, following " +
+			"SemVer 2.0.0: prereleases sort before the release and build metadata is ignored.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "a",
+				Description: "The first version",
+			},
+			function.StringParameter{
+				Name:        "b",
+				Description: "The second version",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *SemverCompareFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &a, &b))
+	if resp.Error != nil {
+		return
+	}
+
+	x, err := parseSemver(a)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	y, err := parseSemver(b)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, int64(compareSemver(x, y))))
+}
+
+// Semver Sort Function
+var _ function.Function = &SemverSortFunction{}
+
+type SemverSortFunction struct{}
+
+func NewSemverSortFunction() function.Function {
+	return &SemverSortFunction{}
+}
+
+func (f *SemverSortFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "semver_sort"
+}
+
+func (f *SemverSortFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Sorts a list of semantic versions",
+		Description: "Sorts versions by SemVer precedence, so 1.10.0 sorts after 1.9.0 and 2.0.0-rc.1 before 2.0.0. " +
+			"Versions are returned as given and versions of equal precedence keep their order.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "versions",
+				Description: "The versions to sort",
+				ElementType: types.StringType,
+			},
+			function.StringParameter{
+				Name:        "direction",
+				Description: "asc for lowest first or desc for highest first",
+			},
+		},
+		Return: function.ListReturn{ElementType: types.StringType},
+	}
+}
+
+func (f *SemverSortFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var versions []string
+	var direction string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &versions, &direction))
+	if resp.Error != nil {
+		return
+	}
+
+	if direction != "asc" && direction != "desc" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("direction must be \"asc\" or \"desc\", got %q", direction)))
+		return
+	}
+	parsed := make(map[string]semver, len(versions))
+	for i, text := range versions {
+		v, err := parseSemver(text)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("element %d: %s", i, err)))
+			return
+		}
+		parsed[text] = v
+	}
+
+	sorted := slices.Clone(versions)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		if direction == "desc" {
+			return compareSemver(parsed[b], parsed[a])
+		}
+		return compareSemver(parsed[a], parsed[b])
+	})
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, sorted))
+}
+
+// semverComparator is a single comparison such as >=1.27.0.
+type semverComparator struct {
+	op      string
+	version semver
+}
+
+func (c semverComparator) match(v semver) bool {
+	n := compareSemver(v, c.version)
+	switch c.op {
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	case "!=":
+		return n != 0
+	}
+	return n == 0
+}
+
+// semverConstraint is a set of alternatives joined by ||, each of which is a
+// set of comparators that must all match.
+type semverConstraint [][]semverComparator
+
+// match reports whether v satisfies the constraint. As in npm, a prerelease
+// only matches an alternative that names a prerelease of the same
+// major.minor.patch, so ^1.2.3 does not match 2.0.0-rc.1.
+func (c semverConstraint) match(v semver) bool {
+	for _, comparators := range c {
+		matched := true
+		allowed := len(v.prerelease) == 0
+		for _, comparator := range comparators {
+			matched = matched && comparator.match(v)
+			cv := comparator.version
+			if len(cv.prerelease) > 0 && cv.major == v.major && cv.minor == v.minor && cv.patch == v.patch {
+				allowed = true
+			}
+		}
+		if matched && allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// semverOperatorPattern splits a comparator into its operator and version.
+var semverOperatorPattern = regexp.MustCompile(`^(~>|>=|<=|!=|>|<|=|~|\^)?(.*)$`)
+
+// partialSemver is a version in a constraint, where trailing numbers may be
+// missing or wildcards (x, X or *).
+type partialSemver struct {
+	version  semver
+	numbers  int // how many of major, minor and patch are numbers
+	wildcard bool
+}
+
+// parsePartialSemver parses a version such as 1, 1.2, 1.2.x or 1.2.3-rc.1.
+func parsePartialSemver(text string) (partialSemver, error) {
+	var p partialSemver
+	rest := text
+	if strings.HasPrefix(rest, "v") || strings.HasPrefix(rest, "V") {
+		rest = rest[1:]
+	}
+	core, _, _ := strings.Cut(rest, "+")
+	core, _, hasPrerelease := strings.Cut(core, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return p, fmt.Errorf("invalid version %q", text)
+	}
+	for _, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			p.wildcard = true
+			continue
+		}
+		if p.wildcard {
+			return p, fmt.Errorf("invalid version %q: numbers cannot follow a wildcard", text)
+		}
+		p.numbers++
+	}
+	if hasPrerelease && p.numbers < 3 {
+		return p, fmt.Errorf("invalid version %q: a prerelease needs major, minor and patch", text)
+	}
+	full := text
+	if p.numbers < 3 {
+		numbers := parts[:p.numbers]
+		for len(numbers) < 3 {
+			numbers = append(numbers, "0")
+		}
+		full = strings.Join(numbers, ".")
+	}
+	v, err := parseSemver(full)
+	if err != nil {
+		return p, fmt.Errorf("invalid version %q", text)
+	}
+	p.version = v
+	return p, nil
+}
+
+// bump returns the lowest version above every version starting with the
+// first n numbers of v.
+func (p partialSemver) bump(n int) semver {
+	v := p.version
+	switch n {
+	case 0:
+		return semver{major: math.MaxInt64}
+	case 1:
+		return semver{major: v.major + 1}
+	case 2:
+		return semver{major: v.major, minor: v.minor + 1}
+	}
+	return semver{major: v.major, minor: v.minor, patch: v.patch + 1}
+}
+
+// expandComparator turns one comparator of a constraint into plain
+// comparisons. Missing numbers are zero, except that wildcards such as 1.2.x
+// match every version with that prefix.
+func expandComparator(op string, p partialSemver) ([]semverComparator, error) {
+	v := p.version
+	between := func(low, high semver) []semverComparator {
+		return []semverComparator{{">=", low}, {"<", high}}
+	}
+	if p.wildcard && op != "" && op != "=" {
+		return nil, fmt.Errorf("wildcard versions cannot be used with %s", op)
+	}
+	switch op {
+	case "", "=":
+		if p.wildcard {
+			return between(v, p.bump(p.numbers)), nil
+		}
+		return []semverComparator{{"=", v}}, nil
+	case "~>":
+		if p.numbers == 3 {
+			return between(v, p.bump(2)), nil
+		}
+		return between(v, p.bump(1)), nil
+	case "~":
+		if p.numbers == 1 {
+			return between(v, p.bump(1)), nil
+		}
+		return between(v, p.bump(2)), nil
+	case "^":
+		switch {
+		case v.major > 0 || p.numbers == 1:
+			return between(v, p.bump(1)), nil
+		case v.minor > 0 || p.numbers == 2:
+			return between(v, p.bump(2)), nil
+		}
+		return between(v, p.bump(3)), nil
+	}
+	return []semverComparator{{op, v}}, nil
+}
+
+// parseSemverConstraint parses a constraint such as ">=1.27, <1.30",
+// "~> 1.2", "^0.4.1", "1.2.3 - 1.4" or "1.x || >=2.1.0". Comparators are
+// separated by commas or spaces and alternatives by ||.
+func parseSemverConstraint(text string) (semverConstraint, error) {
+	var constraint semverConstraint
+	for _, alternative := range strings.Split(text, "||") {
+		fields := strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q: empty alternative", text)
+		}
+
+		var comparators []semverComparator
+		if len(fields) == 3 && fields[1] == "-" {
+			low, err := parsePartialSemver(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %s", text, err)
+			}
+			high, err := parsePartialSemver(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %s", text, err)
+			}
+			if low.wildcard || high.wildcard {
+				return nil, fmt.Errorf("invalid constraint %q: hyphen ranges cannot use wildcards", text)
+			}
+			comparators = []semverComparator{{">=", low.version}, {"<=", high.version}}
+			if high.numbers < 3 {
+				comparators[1] = semverComparator{"<", high.bump(high.numbers)}
+			}
+			constraint = append(constraint, comparators)
+			continue
+		}
+
+		for i := 0; i < len(fields); i++ {
+			m := semverOperatorPattern.FindStringSubmatch(fields[i])
+			op, version := m[1], m[2]
+			if version == "" && op != "" && i+1 < len(fields) {
+				i++
+				version = fields[i]
+			}
+			if version == "" {
+				return nil, fmt.Errorf("invalid constraint %q: %s has no version", text, op)
+			}
+			p, err := parsePartialSemver(version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %s", text, err)
+			}
+			expanded, err := expandComparator(op, p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %s", text, err)
+			}
+			comparators = append(comparators, expanded...)
+		}
+		constraint = append(constraint, comparators)
+	}
+	return constraint, nil
+}
+
+// Semver Satisfies Function
+var _ function.Function = &SemverSatisfiesFunction{}
+
+type SemverSatisfiesFunction struct{}
+
+func NewSemverSatisfiesFunction() function.Function {
+	return &SemverSatisfiesFunction{}
+}
+
+func (f *SemverSatisfiesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "semver_satisfies"
+}
+
+func (f *SemverSatisfiesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether a semantic version satisfies a constraint",
+		Description: "Returns true when a version matches a constraint built from =, !=, >, >=, <, <=, ~> (pessimistic), " +
+			"~ (tilde), ^ (caret), hyphen ranges and wildcards, with comparators joined by commas or spaces and alternatives by ||.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "version",
+				Description: "The version to check",
+			},
+			function.StringParameter{
+				Name:        "constraint",
+				Description: "The constraint, such as \">=1.27, <1.30\" or \"~> 1.2\"",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *SemverSatisfiesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var version, text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &version, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	v, err := parseSemver(version)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	constraint, err := parseSemverConstraint(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, constraint.match(v)))
+}
+
+// semverLevels are the levels accepted by semver_bump.
+var semverLevels = []string{"major", "minor", "patch", "prerelease"}
+
+// bumpSemver increments v at level, dropping build metadata. A prerelease is
+// released by the level it leads up to, so 2.0.0-rc.1 bumps to 2.0.0 for
+// major; a prerelease bump increments the last numeric identifier, adding .0
+// if there is none, and starts 1.2.3 at 1.2.4-0.
+func bumpSemver(v semver, level string) semver {
+	pre := len(v.prerelease) > 0
+	switch level {
+	case "major":
+		if !pre || v.minor != 0 || v.patch != 0 {
+			v.major++
+		}
+		return semver{major: v.major}
+	case "minor":
+		if !pre || v.patch != 0 {
+			v.minor++
+		}
+		return semver{major: v.major, minor: v.minor}
+	case "patch":
+		if !pre {
+			v.patch++
+		}
+		return semver{major: v.major, minor: v.minor, patch: v.patch}
+	}
+
+	if !pre {
+		return semver{major: v.major, minor: v.minor, patch: v.patch + 1, prerelease: []string{"0"}}
+	}
+	identifiers := slices.Clone(v.prerelease)
+	for i := len(identifiers) - 1; i >= 0; i-- {
+		if n, err := strconv.ParseInt(identifiers[i], 10, 64); err == nil {
+			identifiers[i] = strconv.FormatInt(n+1, 10)
+			return semver{major: v.major, minor: v.minor, patch: v.patch, prerelease: identifiers}
+		}
+	}
+	return semver{major: v.major, minor: v.minor, patch: v.patch, prerelease: append(identifiers, "0")}
+}
+
+// Semver Bump Function
+var _ function.Function = &SemverBumpFunction{}
+
+type SemverBumpFunction struct{}
+
+func NewSemverBumpFunction() function.Function {
+	return &SemverBumpFunction{}
+}
+
+func (f *SemverBumpFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "semver_bump"
+}
+
+func (f *SemverBumpFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Increments a semantic version",
+		Description: "Bumps the major, minor or patch number or the prerelease of a version, resetting lower numbers and " +
+			"dropping build metadata. A leading \"v\" is kept.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "version",
+				Description: "The version to bump",
+			},
+			function.StringParameter{
+				Name:        "level",
+				Description: "The part to bump: major, minor, patch or prerelease",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SemverBumpFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var version, level string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &version, &level))
+	if resp.Error != nil {
+		return
+	}
+
+	v, err := parseSemver(version)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	if !slices.Contains(semverLevels, level) {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("unknown level %q, expected one of: %s", level, strings.Join(semverLevels, ", "))))
+		return
+	}
+	prefix := ""
+	if strings.HasPrefix(version, "v") || strings.HasPrefix(version, "V") {
+		prefix = version[:1]
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, prefix+bumpSemver(v, level).String()))
+}
+
+// Semver Latest Function
+var _ function.Function = &SemverLatestFunction{}
+
+type SemverLatestFunction struct{}
+
+func NewSemverLatestFunction() function.Function {
+	return &SemverLatestFunction{}
+}
+
+func (f *SemverLatestFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "semver_latest"
+}
+
+func (f *SemverLatestFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the highest semantic version in a list",
+		Description: "Returns the highest version in a list that satisfies a constraint, as given, or null when none " +
+			"does. An empty constraint matches every release; prereleases only match constraints that name one.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "versions",
+				Description: "The versions to choose from",
+				ElementType: types.StringType,
+			},
+			function.StringParameter{
+				Name:        "constraint",
+				Description: "A constraint as for semver_satisfies, such as \"~> 1.28\", or \"\" for any release",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SemverLatestFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var versions []string
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &versions, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	if strings.TrimSpace(text) == "" {
+		text = "*"
+	}
+	constraint, err := parseSemverConstraint(text)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, err.Error()))
+		return
+	}
+
+	latest := types.StringNull()
+	var best semver
+	for i, version := range versions {
+		v, err := parseSemver(version)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("element %d: %s", i, err)))
+			return
+		}
+		if constraint.match(v) && (latest.IsNull() || compareSemver(v, best) > 0) {
+			latest, best = types.StringValue(version), v
+		}
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, latest))
+}
@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSemverParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.2.3", `{"build":"","major":1,"minor":2,"patch":3,"prerelease":"","version":"1.2.3"}`},
+		{"v1.28.0-rc.1+build.5", `{"build":"build.5","major":1,"minor":28,"patch":0,"prerelease":"rc.1","version":"1.28.0-rc.1+build.5"}`},
+		{"V0.0.0-alpha-1.0", `{"build":"","major":0,"minor":0,"patch":0,"prerelease":"alpha-1.0","version":"0.0.0-alpha-1.0"}`},
+		{"10.20.30+001", `{"build":"001","major":10,"minor":20,"patch":30,"prerelease":"","version":"10.20.30+001"}`},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewSemverParseFunction(), types.StringValue(tt.input))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.input, err)
+		}
+		if got := jsonOf(t, result); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	for _, input := range []string{"", "1.2", "1.2.3.4", "01.2.3", "1.2.3-", "1.2.3-01", "1.2.3-rc..1", "1.2.3+", "1.2.3-rc_1", "vv1.2.3", " 1.2.3", "1.2.x", "99999999999999999999.0.0"} {
+		if _, err := runFunction(t, NewSemverParseFunction(), types.StringValue(input)); err == nil {
+			t.Errorf("%q: expected error", input)
+		}
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int64
+	}{
+		{"1.9.0", "1.10.0", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3+build.1", "1.2.3+build.2", 0},
+		{"2.0.0-rc.1", "2.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0-beta.11", 1},
+		{"2.0.0", "10.0.0", -1},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewSemverCompareFunction(), types.StringValue(tt.a), types.StringValue(tt.b))
+		if err != nil {
+			t.Fatalf("%s <=> %s: unexpected error: %s", tt.a, tt.b, err)
+		}
+		if got := result.(types.Int64).ValueInt64(); got != tt.expected {
+			t.Errorf("%s <=> %s: expected %d, got %d", tt.a, tt.b, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewSemverCompareFunction(), types.StringValue("1.2.3"), types.StringValue("latest")); err == nil {
+		t.Error("expected error for an invalid version")
+	}
+}
+
+func TestSemverSort(t *testing.T) {
+	versions := stringList("1.0.0", "1.0.0-beta.11", "v1.0.0-alpha", "1.0.0-rc.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-alpha.1", "1.0.0-beta", "0.9.10", "0.10.0")
+
+	result, err := runFunction(t, NewSemverSortFunction(), versions, types.StringValue("asc"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := `["0.9.10","0.10.0","v1.0.0-alpha","1.0.0-alpha.1","1.0.0-alpha.beta","1.0.0-beta","1.0.0-beta.2","1.0.0-beta.11","1.0.0-rc.1","1.0.0"]`
+	if got := jsonOf(t, result); got != expected {
+		t.Errorf("expected %s\ngot      %s", expected, got)
+	}
+
+	result, err = runFunction(t, NewSemverSortFunction(), stringList("1.9.0", "1.10.0+b", "1.10.0+a", "1.2.0"), types.StringValue("desc"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, expected := jsonOf(t, result), `["1.10.0+b","1.10.0+a","1.9.0","1.2.0"]`; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	if _, err := runFunction(t, NewSemverSortFunction(), stringList("1.2.3", "1.2"), types.StringValue("asc")); err == nil {
+		t.Error("expected error for an invalid version")
+	}
+	if _, err := runFunction(t, NewSemverSortFunction(), stringList("1.2.3"), types.StringValue("newest")); err == nil {
+		t.Error("expected error for an invalid direction")
+	}
+}
+
+func TestSemverSatisfies(t *testing.T) {
+	tests := []struct {
+		version, constraint string
+		expected            bool
+	}{
+		{"1.28.4", ">=1.27,<1.30", true},
+		{"1.30.0", ">=1.27,<1.30", false},
+		{"1.26.9", ">= 1.27, < 1.30", false},
+		{"1.29.0", ">=1.27 <1.30", true},
+		{"1.2.9", "~> 1.2.3", true},
+		{"1.3.0", "~> 1.2.3", false},
+		{"1.9.0", "~> 1.2", true},
+		{"2.0.0", "~> 1.2", false},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2", false},
+		{"1.9.0", "^1.2.3", true},
+		{"2.0.0", "^1.2.3", false},
+		{"0.2.9", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.4", "^0.0.3", false},
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3+build.7", "=1.2.3", true},
+		{"1.2.4", "1.2.3", false},
+		{"1.2.0", "1.2", true},
+		{"1.2.5", "1.2", false},
+		{"1.2.5", "1.2.x", true},
+		{"1.3.0", "1.2.x", false},
+		{"7.0.0", "*", true},
+		{"1.2.4", "!=1.2.3", true},
+		{"1.2.3", "!= 1.2.3", false},
+		{"2.3.9", "1.2.3 - 2.3", true},
+		{"2.4.0", "1.2.3 - 2.3", false},
+		{"2.3.4", "1.2.3 - 2.3.4", true},
+		{"1.2.2", "1.2.3 - 2.3.4", false},
+		{"2.1.0", "1.x || >=2.1.0", true},
+		{"2.0.0", "1.x || >=2.1.0", false},
+		{"v1.5.0", ">1.4", true},
+		{"2.0.0-rc.1", "^1.2.3", false},
+		{"2.0.0-rc.1", ">=1.0.0", false},
+		{"1.2.3-rc.2", ">=1.2.3-rc.1", true},
+		{"1.2.3-alpha", ">=1.2.3-rc.1", false},
+		{"1.2.3", ">=1.2.3-rc.1", true},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewSemverSatisfiesFunction(), types.StringValue(tt.version), types.StringValue(tt.constraint))
+		if err != nil {
+			t.Fatalf("%s %s: unexpected error: %s", tt.version, tt.constraint, err)
+		}
+		if got := result.(types.Bool).ValueBool(); got != tt.expected {
+			t.Errorf("%s %s: expected %t, got %t", tt.version, tt.constraint, tt.expected, got)
+		}
+	}
+
+	errorCases := []struct {
+		version, constraint string
+	}{
+		{"1.2", ">=1.0.0"},
+		{"1.2.3", ""},
+		{"1.2.3", ">=1.0 ||"},
+		{"1.2.3", ">="},
+		{"1.2.3", ">=1.x"},
+		{"1.2.3", "1.x.3"},
+		{"1.2.3", "1.2-rc.1"},
+		{"1.2.3", "1.2.3.4"},
+		{"1.2.3", "=> 1.2"},
+		{"1.2.3", "1.x - 2"},
+	}
+
+	for _, tt := range errorCases {
+		if _, err := runFunction(t, NewSemverSatisfiesFunction(), types.StringValue(tt.version), types.StringValue(tt.constraint)); err == nil {
+			t.Errorf("%s %q: expected error", tt.version, tt.constraint)
+		}
+	}
+}
+
+func TestSemverBump(t *testing.T) {
+	tests := []struct {
+		version, level string
+		expected       string
+	}{
+		{"1.2.3", "major", "2.0.0"},
+		{"1.2.3", "minor", "1.3.0"},
+		{"1.2.3", "patch", "1.2.4"},
+		{"1.2.3", "prerelease", "1.2.4-0"},
+		{"v1.2.3+build.5", "patch", "v1.2.4"},
+		{"2.0.0-rc.1", "major", "2.0.0"},
+		{"2.1.0-rc.1", "major", "3.0.0"},
+		{"1.3.0-beta", "minor", "1.3.0"},
+		{"1.3.1-beta", "minor", "1.4.0"},
+		{"1.2.4-0", "patch", "1.2.4"},
+		{"1.2.4-rc.1", "prerelease", "1.2.4-rc.2"},
+		{"1.2.4-rc.1.beta", "prerelease", "1.2.4-rc.2.beta"},
+		{"1.2.4-alpha", "prerelease", "1.2.4-alpha.0"},
+		{"V1.2.4-9+meta", "prerelease", "V1.2.4-10"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewSemverBumpFunction(), types.StringValue(tt.version), types.StringValue(tt.level))
+		if err != nil {
+			t.Fatalf("%s (%s): unexpected error: %s", tt.version, tt.level, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%s (%s): expected %s, got %s", tt.version, tt.level, tt.expected, got)
+		}
+	}
+
+	if _, err := runFunction(t, NewSemverBumpFunction(), types.StringValue("1.2"), types.StringValue("patch")); err == nil {
+		t.Error("expected error for an invalid version")
+	}
+	if _, err := runFunction(t, NewSemverBumpFunction(), types.StringValue("1.2.3"), types.StringValue("build")); err == nil {
+		t.Error("expected error for an unknown level")
+	}
+}
+
+func TestSemverLatest(t *testing.T) {
+	versions := stringList("1.27.9", "v1.28.3-eksbuild.1", "1.28.10", "1.29.0-rc.1", "1.28.2", "1.9.0")
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"", "1.28.10"},
+		{"~> 1.27.0", "1.27.9"},
+		{"<1.28", "1.27.9"},
+		{">=1.29.0-rc.1", "1.29.0-rc.1"},
+		{"1.28.x", "1.28.10"},
+	}
+
+	for _, tt := range tests {
+		result, err := runFunction(t, NewSemverLatestFunction(), versions, types.StringValue(tt.constraint))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", tt.constraint, err)
+		}
+		if got := result.(types.String).ValueString(); got != tt.expected {
+			t.Errorf("%q: expected %s, got %s", tt.constraint, tt.expected, got)
+		}
+	}
+
+	result, err := runFunction(t, NewSemverLatestFunction(), versions, types.StringValue(">=2.0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !result.IsNull() {
+		t.Errorf("expected null, got %s", result)
+	}
+
+	if _, err := runFunction(t, NewSemverLatestFunction(), stringList("1.2.3", "latest"), types.StringValue("")); err == nil {
+		t.Error("expected error for an invalid version")
+	}
+	if _, err := runFunction(t, NewSemverLatestFunction(), versions, types.StringValue(">=")); err == nil {
+		t.Error("expected error for an invalid constraint")
+	}
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ datasource.DataSource = &linesDataSource{}
+
+// linesDataSource reads a line-oriented file such as an ops-maintained
+// allowlist or key=value settings file.
+type linesDataSource struct{}
+
+// linesDataSourceModel maps the data source schema data.
+type linesDataSourceModel struct {
+	Path          types.String `tfsdk:"path"`
+	CommentPrefix types.String `tfsdk:"comment_prefix"`
+	Trim          types.Bool   `tfsdk:"trim"`
+	SkipEmpty     types.Bool   `tfsdk:"skip_empty"`
+	Separator     types.String `tfsdk:"separator"`
+	Lines         types.List   `tfsdk:"lines"`
+	Entries       types.Map    `tfsdk:"entries"`
+}
+
+// NewLinesDataSource is a helper function to simplify the provider implementation.
+func NewLinesDataSource() datasource.DataSource {
+	return &linesDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *linesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_lines"
+}
+
+// Schema defines the schema for the data source.
+func (d *linesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads a file and returns its lines, with optional comment stripping, trimming and key/value splitting.",
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				Description: "Path of the file to read, relative to the working directory.",
+				Required:    true,
+			},
+			"comment_prefix": schema.StringAttribute{
+				Description: "Marks comments, e.g. \"#\". Lines starting with it are dropped and text after it is removed when it follows whitespace.",
+				Optional:    true,
+			},
+			"trim": schema.BoolAttribute{
+				Description: "Remove leading and trailing whitespace from each line, and from keys and values. Defaults to false.",
+				Optional:    true,
+			},
+			"skip_empty": schema.BoolAttribute{
+				Description: "Drop lines that are empty after comment stripping and trimming. Defaults to false.",
+				Optional:    true,
+			},
+			"separator": schema.StringAttribute{
+				Description: "Splits each line into a key and value at the first occurrence, populating entries.",
+				Optional:    true,
+			},
+			"lines": schema.ListAttribute{
+				Description: "The processed lines, in file order.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"entries": schema.MapAttribute{
+				Description: "The key/value pairs when separator is set, otherwise empty.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *linesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state linesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, err := os.ReadFile(state.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Unable to read file", err.Error())
+		return
+	}
+
+	lines := processLines(string(content), state.CommentPrefix.ValueString(), state.Trim.ValueBool(), state.SkipEmpty.ValueBool())
+
+	entries := map[string]string{}
+	if separator := state.Separator.ValueString(); separator != "" {
+		entries, err = splitLines(lines, separator, state.Trim.ValueBool())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("separator"), "Unable to split lines", err.Error())
+			return
+		}
+	}
+
+	var diags diag.Diagnostics
+	state.Lines, diags = types.ListValueFrom(ctx, types.StringType, lines)
+	resp.Diagnostics.Append(diags...)
+	state.Entries, diags = types.MapValueFrom(ctx, types.StringType, entries)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// processLines splits content into lines, handling CRLF line endings and a
+// trailing newline, then applies comment stripping and trimming.
+func processLines(content, commentPrefix string, trim, skipEmpty bool) []string {
+	content = strings.TrimSuffix(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if content == "" {
+		return []string{}
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		if commentPrefix != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), commentPrefix) {
+				continue
+			}
+			line = stripInlineComment(line, commentPrefix)
+		}
+		if trim {
+			line = strings.TrimSpace(line)
+		}
+		if skipEmpty && strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// stripInlineComment removes a trailing comment that is preceded by
+// whitespace, so values such as URL fragments are left alone.
+func stripInlineComment(line, prefix string) string {
+	for i := 1; i < len(line); i++ {
+		if (line[i-1] == ' ' || line[i-1] == '\t') && strings.HasPrefix(line[i:], prefix) {
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return line
+}
+
+// splitLines builds a map from lines of the form key<separator>value.
+func splitLines(lines []string, separator string, trim bool) (map[string]string, error) {
+	entries := make(map[string]string, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, separator)
+		if !ok {
+			return nil, fmt.Errorf("line %q does not contain %q", line, separator)
+		}
+		if trim {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		}
+		if _, exists := entries[key]; exists {
+			return nil, fmt.Errorf("duplicate key %q", key)
+		}
+		entries[key] = value
+	}
+	return entries, nil
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// readDataSource runs a data source's Read with the given configuration,
+// leaving unset attributes null, and returns the resulting state.
+func readDataSource(t *testing.T, ds datasource.DataSource, config map[string]tftypes.Value) (tfsdk.State, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &datasource.SchemaResponse{}
+	ds.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema error: %v", schemaResp.Diagnostics)
+	}
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range schemaType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := config[name]; ok {
+			values[name] = value
+		}
+	}
+
+	req := datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, values)}}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, nil)}}
+	ds.Read(ctx, req, resp)
+	return resp.State, resp.Diagnostics
+}
+
+func TestLinesDataSource(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "hosts.txt")
+	content := "# maintained by ops\r\nweb01 = 10.0.0.1  # primary\r\n\r\n  db01=10.0.0.2\r\nurl=https://example.com/#anchor\r\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  map[string]tftypes.Value
+		lines   []string
+		entries map[string]string
+	}{
+		{
+			name:    "raw",
+			config:  map[string]tftypes.Value{},
+			lines:   []string{"# maintained by ops", "web01 = 10.0.0.1  # primary", "", "  db01=10.0.0.2", "url=https://example.com/#anchor"},
+			entries: map[string]string{},
+		},
+		{
+			name: "comments and trimming",
+			config: map[string]tftypes.Value{
+				"comment_prefix": tftypes.NewValue(tftypes.String, "#"),
+				"trim":           tftypes.NewValue(tftypes.Bool, true),
+				"skip_empty":     tftypes.NewValue(tftypes.Bool, true),
+			},
+			lines:   []string{"web01 = 10.0.0.1", "db01=10.0.0.2", "url=https://example.com/#anchor"},
+			entries: map[string]string{},
+		},
+		{
+			name: "key value",
+			config: map[string]tftypes.Value{
+				"comment_prefix": tftypes.NewValue(tftypes.String, "#"),
+				"trim":           tftypes.NewValue(tftypes.Bool, true),
+				"separator":      tftypes.NewValue(tftypes.String, "="),
+			},
+			lines:   []string{"web01 = 10.0.0.1", "", "db01=10.0.0.2", "url=https://example.com/#anchor"},
+			entries: map[string]string{"web01": "10.0.0.1", "db01": "10.0.0.2", "url": "https://example.com/#anchor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["path"] = tftypes.NewValue(tftypes.String, file)
+			state, diags := readDataSource(t, NewLinesDataSource(), tt.config)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			var model linesDataSourceModel
+			if diags := state.Get(context.Background(), &model); diags.HasError() {
+				t.Fatalf("unexpected error reading state: %v", diags)
+			}
+
+			var lines []string
+			var entries map[string]string
+			model.Lines.ElementsAs(context.Background(), &lines, false)
+			model.Entries.ElementsAs(context.Background(), &entries, false)
+			if jsonOf(t, model.Lines) != jsonOf(t, stringList(tt.lines...)) {
+				t.Errorf("expected lines %q, got %q", tt.lines, lines)
+			}
+			if len(entries) != len(tt.entries) {
+				t.Errorf("expected entries %v, got %v", tt.entries, entries)
+			}
+			for key, value := range tt.entries {
+				if entries[key] != value {
+					t.Errorf("expected %s=%q, got %q", key, value, entries[key])
+				}
+			}
+		})
+	}
+}
+
+func TestLinesDataSourceErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "settings.txt")
+	if err := os.WriteFile(file, []byte("a=1\nb\na=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]tftypes.Value
+	}{
+		{"missing file", map[string]tftypes.Value{"path": tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "missing"))}},
+		{"missing separator", map[string]tftypes.Value{"path": tftypes.NewValue(tftypes.String, file), "separator": tftypes.NewValue(tftypes.String, "=")}},
+		{"duplicate key", map[string]tftypes.Value{"path": tftypes.NewValue(tftypes.String, file), "separator": tftypes.NewValue(tftypes.String, "="), "comment_prefix": tftypes.NewValue(tftypes.String, "b")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, diags := readDataSource(t, NewLinesDataSource(), tt.config); !diags.HasError() {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ datasource.DataSource = &preflightDataSource{}
+
+// preflightDataSource evaluates a list of named checks and fails the plan
+// with a single consolidated report when any error-severity check fails.
+type preflightDataSource struct{}
+
+// preflightDataSourceModel maps the data source schema data.
+type preflightDataSourceModel struct {
+	Checks   []preflightCheckModel `tfsdk:"checks"`
+	Passed   types.Bool            `tfsdk:"passed"`
+	Failures types.List            `tfsdk:"failures"`
+	Warnings types.List            `tfsdk:"warnings"`
+	Report   types.String          `tfsdk:"report"`
+}
+
+// preflightCheckModel maps a single check.
+type preflightCheckModel struct {
+	Name      types.String `tfsdk:"name"`
+	Condition types.Bool   `tfsdk:"condition"`
+	Message   types.String `tfsdk:"message"`
+	Severity  types.String `tfsdk:"severity"`
+}
+
+// NewPreflightDataSource is a helper function to simplify the provider implementation.
+func NewPreflightDataSource() datasource.DataSource {
+	return &preflightDataSource{}
+}
+
+// Metadata returns the data source type name.
+func (d *preflightDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_preflight"
+}
+
+// Schema defines the schema for the data source.
+func (d *preflightDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Evaluates a list of named checks and fails with a consolidated report when any error-severity check fails.",
+		Attributes: map[string]schema.Attribute{
+			"checks": schema.ListNestedAttribute{
+				Description: "The checks to evaluate.",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "A unique name for the check, shown in the report.",
+							Required:    true,
+						},
+						"condition": schema.BoolAttribute{
+							Description: "The check passes when this is true.",
+							Required:    true,
+						},
+						"message": schema.StringAttribute{
+							Description: "Explains the failure, shown in the report.",
+							Required:    true,
+						},
+						"severity": schema.StringAttribute{
+							Description: "\"error\" fails the plan, \"warning\" only reports. Defaults to \"error\".",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"passed": schema.BoolAttribute{
+				Description: "Whether every check passed, including warnings.",
+				Computed:    true,
+			},
+			"failures": schema.ListAttribute{
+				Description: "Names of the failed error-severity checks.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"warnings": schema.ListAttribute{
+				Description: "Names of the failed warning-severity checks.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"report": schema.StringAttribute{
+				Description: "The formatted report of failed checks, empty when all checks pass.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *preflightDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state preflightDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failures, warnings := []string{}, []string{}
+	var errorLines, warningLines []string
+	names := map[string]bool{}
+
+	for i, check := range state.Checks {
+		name := check.Name.ValueString()
+		if names[name] {
+			resp.Diagnostics.AddAttributeError(path.Root("checks").AtListIndex(i).AtName("name"), "Duplicate check name", fmt.Sprintf("The check name %q is used more than once.", name))
+			continue
+		}
+		names[name] = true
+
+		severity := check.Severity.ValueString()
+		if severity == "" {
+			severity = "error"
+		}
+		if severity != "error" && severity != "warning" {
+			resp.Diagnostics.AddAttributeError(path.Root("checks").AtListIndex(i).AtName("severity"), "Invalid severity", fmt.Sprintf("Severity must be \"error\" or \"warning\", got %q.", severity))
+			continue
+		}
+
+		if check.Condition.ValueBool() {
+			continue
+		}
+		line := fmt.Sprintf("  - [%s] %s: %s", severity, name, check.Message.ValueString())
+		if severity == "error" {
+			failures = append(failures, name)
+			errorLines = append(errorLines, line)
+		} else {
+			warnings = append(warnings, name)
+			warningLines = append(warningLines, line)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	report := ""
+	if failed := len(failures) + len(warnings); failed > 0 {
+		report = fmt.Sprintf("%d of %d preflight checks failed:\n%s", failed, len(state.Checks), strings.Join(append(errorLines, warningLines...), "\n"))
+	}
+
+	if len(failures) > 0 {
+		resp.Diagnostics.AddError("Preflight checks failed", report)
+		return
+	}
+	if len(warnings) > 0 {
+		resp.Diagnostics.AddWarning("Preflight checks reported warnings", report)
+	}
+
+	var diags diag.Diagnostics
+	state.Passed = types.BoolValue(len(warnings) == 0)
+	state.Report = types.StringValue(report)
+	state.Failures, diags = types.ListValueFrom(ctx, types.StringType, failures)
+	resp.Diagnostics.Append(diags...)
+	state.Warnings, diags = types.ListValueFrom(ctx, types.StringType, warnings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
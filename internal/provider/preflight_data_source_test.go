@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var preflightCheckType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"name":      tftypes.String,
+	"condition": tftypes.Bool,
+	"message":   tftypes.String,
+	"severity":  tftypes.String,
+}}
+
+// preflightChecks builds the checks attribute from name, condition, message
+// and severity tuples; an empty severity is left null.
+func preflightChecks(checks ...[4]any) map[string]tftypes.Value {
+	values := make([]tftypes.Value, len(checks))
+	for i, check := range checks {
+		severity := tftypes.NewValue(tftypes.String, nil)
+		if check[3] != "" {
+			severity = tftypes.NewValue(tftypes.String, check[3])
+		}
+		values[i] = tftypes.NewValue(preflightCheckType, map[string]tftypes.Value{
+			"name":      tftypes.NewValue(tftypes.String, check[0]),
+			"condition": tftypes.NewValue(tftypes.Bool, check[1]),
+			"message":   tftypes.NewValue(tftypes.String, check[2]),
+			"severity":  severity,
+		})
+	}
+	return map[string]tftypes.Value{"checks": tftypes.NewValue(tftypes.List{ElementType: preflightCheckType}, values)}
+}
+
+func TestPreflightDataSource(t *testing.T) {
+	state, diags := readDataSource(t, NewPreflightDataSource(), preflightChecks(
+		[4]any{"region", true, "Region must be allowed", ""},
+		[4]any{"tags", false, "Cost center tag is missing", "warning"},
+	))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail(), "[warning] tags: Cost center tag is missing") {
+		t.Errorf("expected a single warning with the report, got %v", diags)
+	}
+
+	var model preflightDataSourceModel
+	if diags := state.Get(context.Background(), &model); diags.HasError() {
+		t.Fatalf("unexpected error reading state: %v", diags)
+	}
+	if model.Passed.ValueBool() {
+		t.Error("expected passed to be false when a warning fails")
+	}
+	if got := jsonOf(t, model.Warnings); got != `["tags"]` {
+		t.Errorf("unexpected warnings: %s", got)
+	}
+	if got := jsonOf(t, model.Failures); got != `[]` {
+		t.Errorf("unexpected failures: %s", got)
+	}
+
+	state, diags = readDataSource(t, NewPreflightDataSource(), preflightChecks([4]any{"ok", true, "never shown", "error"}))
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	state.Get(context.Background(), &model)
+	if !model.Passed.ValueBool() || model.Report.ValueString() != "" {
+		t.Errorf("expected all checks to pass, got passed=%v report=%q", model.Passed, model.Report.ValueString())
+	}
+}
+
+func TestPreflightDataSourceFailures(t *testing.T) {
+	_, diags := readDataSource(t, NewPreflightDataSource(), preflightChecks(
+		[4]any{"cidr", false, "VPC CIDR must be a /16", ""},
+		[4]any{"tags", false, "Cost center tag is missing", "warning"},
+		[4]any{"region", true, "Region must be allowed", ""},
+		[4]any{"azs", false, "At least two AZs are required", "error"},
+	))
+	if !diags.HasError() {
+		t.Fatal("expected error")
+	}
+
+	expected := "3 of 4 preflight checks failed:\n" +
+		"  - [error] cidr: VPC CIDR must be a /16\n" +
+		"  - [error] azs: At least two AZs are required\n" +
+		"  - [warning] tags: Cost center tag is missing"
+	if diags[0].Detail() != expected {
+		t.Errorf("expected report:\n%s\ngot:\n%s", expected, diags[0].Detail())
+	}
+
+	for _, checks := range []map[string]tftypes.Value{
+		preflightChecks([4]any{"a", true, "x", ""}, [4]any{"a", true, "y", ""}),
+		preflightChecks([4]any{"a", true, "x", "fatal"}),
+	} {
+		if _, diags := readDataSource(t, NewPreflightDataSource(), checks); !diags.HasError() {
+			t.Error("expected error")
+		}
+	}
+}
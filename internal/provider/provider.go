@@ -47,8 +47,10 @@ func (p *utilsProvider) Configure(ctx context.Context, req provider.ConfigureReq
 
 // DataSources defines the data sources implemented in the provider.
 func (p *utilsProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	// This is a function-only provider, so no data sources
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewLinesDataSource,
+		NewPreflightDataSource,
+	}
 }
 
 // Resources defines the resources implemented in the provider.
@@ -73,5 +75,161 @@ func (p *utilsProvider) Functions(_ context.Context) []func() function.Function
 		NewTrimFunction,
 		NewJoinFunction,
 		NewSplitFunction,
+		NewDeepMergeFunction,
+		NewProvenanceExtractFunction,
+		NewFieldMaskPathsFunction,
+		NewApplyFieldMaskFunction,
+		NewGRPCServiceConfigFunction,
+		NewOpenAPIValidateFunction,
+		NewOpenAPIMergeFunction,
+		NewOpenAPIExtractPathsFunction,
+		NewQueryFunction,
+		NewJSONCanonicalFunction,
+		NewGraphQLValidateSchemaFunction,
+		NewGraphQLValidateQueryFunction,
+		NewSchemaCompatibleFunction,
+		NewProtoDescriptorInfoFunction,
+		NewYAMLToJSONFunction,
+		NewJSONToYAMLFunction,
+		NewTOMLDecodeFunction,
+		NewTOMLEncodeFunction,
+		NewCompileAllowlistFunction,
+		NewINIDecodeFunction,
+		NewWAFRegexEscapeFunction,
+		NewWAFByteMatchFunction,
+		NewCSVDecodeFunction,
+		NewNormalizeSGRulesFunction,
+		NewCSVEncodeFunction,
+		NewConditionCompileFunction,
+		NewXMLDecodeFunction,
+		NewXMLEncodeFunction,
+		NewHCLEncodeFunction,
+		NewPropertiesDecodeFunction,
+		NewPropertiesEncodeFunction,
+		NewURLQueryDecodeFunction,
+		NewURLQueryEncodeFunction,
+		NewURLParseFunction,
+		NewURLBuildFunction,
+		NewURLJoinFunction,
+		NewURLResolveFunction,
+		NewDataURIFunction,
+		NewMarkdownToHTMLFunction,
+		NewHTMLEscapeFunction,
+		NewHTMLUnescapeFunction,
+		NewHTMLStripTagsFunction,
+		NewShellQuoteFunction,
+		NewShellQuoteListFunction,
+		NewPowerShellQuoteFunction,
+		NewPowerShellQuoteListFunction,
+		NewMergeDeepFunction,
+		NewFlattenMapFunction,
+		NewUnflattenMapFunction,
+		NewIndexByFunction,
+		NewPluckFunction,
+		NewSortByFunction,
+		NewDedupeByFunction,
+		NewObjectDiffFunction,
+		NewPickFunction,
+		NewOmitFunction,
+		NewRemapKeysFunction,
+		NewZipFunction,
+		NewUnzipFunction,
+		NewChunkFunction,
+		NewUnionByFunction,
+		NewIntersectByFunction,
+		NewDifferenceByFunction,
+		NewJoinByFunction,
+		NewTopologicalSortFunction,
+		NewTransposeMapFunction,
+		NewInvertMapFunction,
+		NewMinByFunction,
+		NewMaxByFunction,
+		NewSumByFunction,
+		NewAvgByFunction,
+		NewCountByFunction,
+		NewShuffleSeededFunction,
+		NewSampleSeededFunction,
+		NewWindowsFunction,
+		NewDefaultsDeepFunction,
+		NewCompactDeepFunction,
+		NewInterleaveFunction,
+		NewCoalesceObjectsFunction,
+		NewCIDRPlanFunction,
+		NewCIDRContainsFunction,
+		NewCIDROverlapsFunction,
+		NewNextAvailableCIDRFunction,
+		NewCIDRSplitFunction,
+		NewRangeToCIDRsFunction,
+		NewCIDRInfoFunction,
+		NewPrefixToNetmaskFunction,
+		NewPrefixToWildcardFunction,
+		NewNetmaskToPrefixFunction,
+		NewIPv6ExpandFunction,
+		NewIPv6CompressFunction,
+		NewIPv6ValidFunction,
+		NewPTRNameFunction,
+		NewMACNormalizeFunction,
+		NewMACValidFunction,
+		NewMACToEUI64Function,
+		NewMACGenerateFunction,
+		NewIPIsPrivateFunction,
+		NewIPIsPublicFunction,
+		NewIPIsLoopbackFunction,
+		NewIPVersionFunction,
+		NewCIDRSortFunction,
+		NewVLSMAllocateFunction,
+		NewValidateHostnameFunction,
+		NewIDNToASCIIFunction,
+		NewIDNToUnicodeFunction,
+		NewDomainParseFunction,
+		NewEmailParseFunction,
+		NewDNSLabelFunction,
+		NewCIDRToWildcardFunction,
+		NewNoProxyNormalizeFunction,
+		NewSPFRecordFunction,
+		NewDMARCRecordFunction,
+		NewTimeParseFunction,
+		NewTimeFormatFunction,
+		NewTimeAddFunction,
+		NewTimeSubtractFunction,
+		NewTimeDiffFunction,
+		NewUnixToRFC3339Function,
+		NewRFC3339ToUnixFunction,
+		NewCronValidateFunction,
+		NewCronNextFunction,
+		NewTimeFloorFunction,
+		NewTimeCeilFunction,
+		NewAddBusinessDaysFunction,
+		NewIsBusinessDayFunction,
+		NewWindowsOverlapFunction,
+		NewSemverParseFunction,
+		NewSemverCompareFunction,
+		NewSemverSortFunction,
+		NewSemverSatisfiesFunction,
+		NewSemverBumpFunction,
+		NewSemverLatestFunction,
+		NewFormatBytesFunction,
+		NewParseBytesFunction,
+		NewFormatNumberFunction,
+		NewParseIntBaseFunction,
+		NewToHexFunction,
+		NewToOctalFunction,
+		NewToBinaryFunction,
+		NewBitAndFunction,
+		NewBitOrFunction,
+		NewBitXorFunction,
+		NewBitNotFunction,
+		NewBitShiftLeftFunction,
+		NewBitShiftRightFunction,
+		NewClampFunction,
+		NewRoundToFunction,
+		NewPercentOfFunction,
+		NewToRomanFunction,
+		NewFromRomanFunction,
+		NewOrdinalFunction,
+		NewSeededIntFunction,
+		NewSeededChoiceFunction,
+		NewIAMPolicyMergeFunction,
+		NewIAMPolicyValidateFunction,
 	}
 }
@@ -1,7 +1,11 @@
 package provider
 
 import (
+	"context"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 )
 
 func TestProvider(t *testing.T) {
@@ -10,3 +14,29 @@ func TestProvider(t *testing.T) {
 		t.Fatal("Expected provider to be non-nil")
 	}
 }
+
+func TestProviderFunctionDefinitions(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")().(provider.ProviderWithFunctions)
+	names := map[string]bool{}
+
+	for _, newFunction := range p.Functions(ctx) {
+		fn := newFunction()
+
+		metaResp := &function.MetadataResponse{}
+		fn.Metadata(ctx, function.MetadataRequest{}, metaResp)
+		if names[metaResp.Name] {
+			t.Errorf("Duplicate function name %q", metaResp.Name)
+		}
+		names[metaResp.Name] = true
+
+		defResp := &function.DefinitionResponse{}
+		fn.Definition(ctx, function.DefinitionRequest{}, defResp)
+
+		validateResp := &function.DefinitionValidateResponse{}
+		defResp.Definition.ValidateImplementation(ctx, function.DefinitionValidateRequest{FuncName: metaResp.Name}, validateResp)
+		if validateResp.Diagnostics.HasError() {
+			t.Errorf("Invalid definition for %q: %v", metaResp.Name, validateResp.Diagnostics)
+		}
+	}
+}
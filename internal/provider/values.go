@@ -0,0 +1,491 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"gopkg.in/yaml.v3"
+)
+
+// fromValue converts a framework value into plain Go data so that functions
+// which accept arbitrary (dynamic) arguments can work with it directly.
+//
+// The mapping is: null → nil, string → string, number → *big.Float,
+// bool → bool, list/set/tuple → []any and map/object → map[string]any.
+func fromValue(ctx context.Context, value attr.Value) (any, error) {
+	if value == nil || value.IsNull() {
+		return nil, nil
+	}
+
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("value is not yet known")
+	}
+
+	switch v := value.(type) {
+	case basetypes.DynamicValue:
+		return fromValue(ctx, v.UnderlyingValue())
+	case basetypes.StringValue:
+		return v.ValueString(), nil
+	case basetypes.BoolValue:
+		return v.ValueBool(), nil
+	case basetypes.NumberValue:
+		return v.ValueBigFloat(), nil
+	case basetypes.Int64Value:
+		return new(big.Float).SetInt64(v.ValueInt64()), nil
+	case basetypes.Float64Value:
+		return big.NewFloat(v.ValueFloat64()), nil
+	case basetypes.ListValue:
+		return fromElements(ctx, v.Elements())
+	case basetypes.SetValue:
+		return fromElements(ctx, v.Elements())
+	case basetypes.TupleValue:
+		return fromElements(ctx, v.Elements())
+	case basetypes.MapValue:
+		return fromAttributes(ctx, v.Elements())
+	case basetypes.ObjectValue:
+		return fromAttributes(ctx, v.Attributes())
+	}
+
+	return nil, fmt.Errorf("unsupported value type %T", value)
+}
+
+func fromElements(ctx context.Context, elements []attr.Value) (any, error) {
+	result := make([]any, len(elements))
+	for i, element := range elements {
+		converted, err := fromValue(ctx, element)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		result[i] = converted
+	}
+	return result, nil
+}
+
+func fromAttributes(ctx context.Context, attributes map[string]attr.Value) (any, error) {
+	result := make(map[string]any, len(attributes))
+	for key, attribute := range attributes {
+		converted, err := fromValue(ctx, attribute)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		result[key] = converted
+	}
+	return result, nil
+}
+
+// toValue converts plain Go data back into a framework value suitable for a
+// dynamic function result. Maps become objects and slices become tuples so
+// that elements are free to have differing types, mirroring jsondecode().
+func toValue(data any) (attr.Value, error) {
+	switch v := data.(type) {
+	case nil:
+		return types.DynamicNull(), nil
+	case attr.Value:
+		return v, nil
+	case string:
+		return types.StringValue(v), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case *big.Float:
+		return types.NumberValue(v), nil
+	case int:
+		return types.NumberValue(new(big.Float).SetInt64(int64(v))), nil
+	case int64:
+		return types.NumberValue(new(big.Float).SetInt64(v)), nil
+	case float64:
+		return types.NumberValue(big.NewFloat(v)), nil
+	case []string:
+		elements := make([]any, len(v))
+		for i, s := range v {
+			elements[i] = s
+		}
+		return toValue(elements)
+	case []any:
+		elementTypes := make([]attr.Type, len(v))
+		elements := make([]attr.Value, len(v))
+		for i, element := range v {
+			converted, err := toValue(element)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			elements[i] = converted
+			elementTypes[i] = converted.Type(context.Background())
+		}
+		tuple, diags := types.TupleValue(elementTypes, elements)
+		if diags.HasError() {
+			return nil, fmt.Errorf("building tuple: %v", diags)
+		}
+		return tuple, nil
+	case map[string]string:
+		attributes := make(map[string]any, len(v))
+		for key, s := range v {
+			attributes[key] = s
+		}
+		return toValue(attributes)
+	case map[string]any:
+		attributeTypes := make(map[string]attr.Type, len(v))
+		attributes := make(map[string]attr.Value, len(v))
+		for key, attribute := range v {
+			converted, err := toValue(attribute)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			attributes[key] = converted
+			attributeTypes[key] = converted.Type(context.Background())
+		}
+		object, diags := types.ObjectValue(attributeTypes, attributes)
+		if diags.HasError() {
+			return nil, fmt.Errorf("building object: %v", diags)
+		}
+		return object, nil
+	}
+
+	return nil, fmt.Errorf("unsupported data type %T", data)
+}
+
+// toDynamic wraps converted Go data in a types.Dynamic for use as a result.
+func toDynamic(data any) (types.Dynamic, error) {
+	value, err := toValue(data)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	if dynamic, ok := value.(types.Dynamic); ok {
+		return dynamic, nil
+	}
+	return types.DynamicValue(value), nil
+}
+
+// decodeJSON parses a JSON document into the same plain Go data produced by
+// fromValue, keeping numbers exact as *big.Float.
+func decodeJSON(input string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(input))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return fromJSON(data)
+}
+
+func fromJSON(data any) (any, error) {
+	switch v := data.(type) {
+	case json.Number:
+		number, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s: %w", v, err)
+		}
+		return number, nil
+	case []any:
+		for i, element := range v {
+			converted, err := fromJSON(element)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+	case map[string]any:
+		for key, element := range v {
+			converted, err := fromJSON(element)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+	}
+	return data, nil
+}
+
+// decodeYAML parses a single YAML document into plain Go data, resolving
+// aliases and merge keys. Integers and floats both become *big.Float.
+func decodeYAML(input string) (any, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(input), &document); err != nil {
+		return nil, err
+	}
+	if document.Kind == 0 {
+		return nil, nil
+	}
+	return fromYAMLNode(&document)
+}
+
+func fromYAMLNode(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return fromYAMLNode(node.Content[0])
+	case yaml.AliasNode:
+		return fromYAMLNode(node.Alias)
+	case yaml.SequenceNode:
+		result := make([]any, len(node.Content))
+		for i, element := range node.Content {
+			converted, err := fromYAMLNode(element)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case yaml.MappingNode:
+		result := map[string]any{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			converted, err := fromYAMLNode(value)
+			if err != nil {
+				return nil, err
+			}
+			if key.Tag == "!!merge" {
+				if err := mergeYAMLKeys(result, converted); err != nil {
+					return nil, fmt.Errorf("line %d: %w", key.Line, err)
+				}
+				continue
+			}
+			result[key.Value] = converted
+		}
+		return result, nil
+	case yaml.ScalarNode:
+		return fromYAMLScalar(node)
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// mergeYAMLKeys applies a "<<" merge key, where explicitly set keys win.
+func mergeYAMLKeys(target map[string]any, merged any) error {
+	sources, ok := merged.([]any)
+	if !ok {
+		sources = []any{merged}
+	}
+	for _, source := range sources {
+		m, ok := source.(map[string]any)
+		if !ok {
+			return fmt.Errorf("merge key value must be a mapping")
+		}
+		for key, value := range m {
+			if _, exists := target[key]; !exists {
+				target[key] = value
+			}
+		}
+	}
+	return nil
+}
+
+func fromYAMLScalar(node *yaml.Node) (any, error) {
+	switch node.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	case "!!int":
+		i, ok := new(big.Int).SetString(strings.ReplaceAll(node.Value, "_", ""), 0)
+		if !ok {
+			var n int64
+			if err := node.Decode(&n); err != nil {
+				return nil, fmt.Errorf("line %d: invalid integer %q", node.Line, node.Value)
+			}
+			i = big.NewInt(n)
+		}
+		return new(big.Float).SetInt(i), nil
+	case "!!float":
+		f, _, err := big.ParseFloat(strings.ReplaceAll(node.Value, "_", ""), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: unsupported float %q", node.Line, node.Value)
+		}
+		return f, nil
+	}
+	return node.Value, nil
+}
+
+// decodeDocument parses input as JSON, falling back to YAML.
+func decodeDocument(input string) (any, error) {
+	if data, err := decodeJSON(input); err == nil {
+		return data, nil
+	}
+	return decodeYAML(input)
+}
+
+// encodeJSON serialises plain Go data as compact JSON with object keys in
+// sorted order, so equal inputs always render identically.
+func encodeJSON(data any) (string, error) {
+	var buf strings.Builder
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(toJSON(data)); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func toJSON(data any) any {
+	switch v := data.(type) {
+	case *big.Float:
+		return json.Number(formatBigFloat(v))
+	case []any:
+		result := make([]any, len(v))
+		for i, element := range v {
+			result[i] = toJSON(element)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, element := range v {
+			result[key] = toJSON(element)
+		}
+		return result
+	}
+	return data
+}
+
+// formatBigFloat renders whole numbers without a fraction or exponent and
+// everything else in the shortest form that round-trips through a float64.
+func formatBigFloat(f *big.Float) string {
+	if f.IsInt() {
+		i, _ := f.Int(nil)
+		return i.String()
+	}
+	value, _ := f.Float64()
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// lookupPath walks a dot-separated path such as "spec.containers.0.name"
+// through nested objects and lists, returning false when any segment is
+// missing.
+func lookupPath(data any, path string) (any, bool) {
+	if path == "" {
+		return data, true
+	}
+
+	current := data
+	for _, segment := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// sortedKeys returns the keys of a map in lexical order so that results built
+// from maps are deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// typeName returns the Terraform-facing name of a converted Go value, for use
+// in error messages.
+func typeName(data any) string {
+	switch data.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case *big.Float:
+		return "number"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", data)
+}
+
+// valuesEqual reports whether two values built by fromValue are deeply
+// equal, comparing numbers by value.
+func valuesEqual(a, b any) bool {
+	switch x := a.(type) {
+	case *big.Float:
+		y, ok := b.(*big.Float)
+		return ok && x.Cmp(y) == 0
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !valuesEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, exists := y[key]
+			if !exists || !valuesEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// parseOptions validates an options object against defaults, returning the
+// defaults overlaid with any attributes that were set. Unknown attributes and
+// values whose type differs from the default are errors; null options or
+// null attributes leave the defaults in place.
+func parseOptions(data any, defaults map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(defaults))
+	for key, value := range defaults {
+		result[key] = value
+	}
+	if data == nil {
+		return result, nil
+	}
+
+	options, ok := data.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("options must be an object, got %s", typeName(data))
+	}
+	for _, key := range sortedKeys(options) {
+		value := options[key]
+		def, known := defaults[key]
+		if !known {
+			return nil, fmt.Errorf("unknown option %q, expected one of: %s", key, strings.Join(sortedKeys(defaults), ", "))
+		}
+		if value == nil {
+			continue
+		}
+		if typeName(value) != typeName(def) {
+			return nil, fmt.Errorf("option %q must be a %s, got %s", key, typeName(def), typeName(value))
+		}
+		result[key] = value
+	}
+	return result, nil
+}
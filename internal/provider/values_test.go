@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// runFunction calls a function the way the framework would and returns the
+// result value along with any function error.
+func runFunction(t *testing.T, fn function.Function, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+
+	defResp := &function.DefinitionResponse{}
+	fn.Definition(ctx, function.DefinitionRequest{}, defResp)
+
+	result, funcErr := defResp.Definition.Return.NewResultData(ctx)
+	if funcErr != nil {
+		t.Fatalf("unexpected error creating result data: %s", funcErr)
+	}
+
+	resp := &function.RunResponse{Result: result}
+	fn.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+	return resp.Result.Value(), resp.Error
+}
+
+// dynamicOf builds a dynamic argument from plain Go data.
+func dynamicOf(t *testing.T, data any) types.Dynamic {
+	t.Helper()
+	value, err := toDynamic(data)
+	if err != nil {
+		t.Fatalf("unexpected error building value: %s", err)
+	}
+	return value
+}
+
+// variadicOf builds the tuple the framework passes for variadic arguments.
+func variadicOf(values ...attr.Value) types.Tuple {
+	elementTypes := make([]attr.Type, len(values))
+	for i, value := range values {
+		elementTypes[i] = value.Type(context.Background())
+	}
+	return types.TupleValueMust(elementTypes, values)
+}
+
+// stringList builds a list(string) argument.
+func stringList(values ...string) types.List {
+	elements := make([]attr.Value, len(values))
+	for i, value := range values {
+		elements[i] = types.StringValue(value)
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// jsonOf renders a framework value as JSON with sorted keys so results can be
+// compared against readable expectations.
+func jsonOf(t *testing.T, value attr.Value) string {
+	t.Helper()
+	data, err := fromValue(context.Background(), value)
+	if err != nil {
+		t.Fatalf("unexpected error converting value: %s", err)
+	}
+	encoded, err := encodeJSON(data)
+	if err != nil {
+		t.Fatalf("unexpected error encoding value: %s", err)
+	}
+	return encoded
+}
+
+// mustJSON decodes a JSON literal into the plain Go data used by fromValue.
+func mustJSON(t *testing.T, input string) any {
+	t.Helper()
+	data, err := decodeJSON(input)
+	if err != nil {
+		t.Fatalf("invalid test JSON %q: %s", input, err)
+	}
+	return data
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"null", `null`},
+		{"string", `"hello"`},
+		{"number", `1.5`},
+		{"bool", `true`},
+		{"list", `[1,"two",null]`},
+		{"object", `{"a":{"b":[1,2]},"c":null}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := dynamicOf(t, mustJSON(t, tt.input))
+			if got := jsonOf(t, value); got != tt.input {
+				t.Errorf("expected %s, got %s", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestFromValueCollections(t *testing.T) {
+	list := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringValue("b")})
+	if got := jsonOf(t, list); got != `["a","b"]` {
+		t.Errorf("unexpected list conversion: %s", got)
+	}
+
+	m := types.MapValueMust(types.Int64Type, map[string]attr.Value{"x": types.Int64Value(3)})
+	if got := jsonOf(t, m); got != `{"x":3}` {
+		t.Errorf("unexpected map conversion: %s", got)
+	}
+
+	if _, err := fromValue(context.Background(), types.StringUnknown()); err == nil {
+		t.Error("expected error for unknown value")
+	}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	input := `
+defaults: &defaults
+  replicas: 2
+  ratio: 0.5
+service:
+  <<: *defaults
+  replicas: 3
+  name: web
+  enabled: yes_not_bool
+  ports: [80, 0x1BB]
+  empty: ~
+`
+	data, err := decodeYAML(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	service, _ := lookupPath(data, "service")
+	encoded, _ := encodeJSON(service)
+	expected := `{"empty":null,"enabled":"yes_not_bool","name":"web","ports":[80,443],"ratio":0.5,"replicas":3}`
+	if encoded != expected {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+
+	if _, err := decodeYAML("a: [1"); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	defaults := map[string]any{"delimiter": ",", "header": true, "columns": []any{}}
+
+	options, err := parseOptions(mustJSON(t, `{"delimiter": ";", "header": null}`), defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if options["delimiter"] != ";" || options["header"] != true {
+		t.Errorf("unexpected options: %v", options)
+	}
+
+	if options, err := parseOptions(nil, defaults); err != nil || options["delimiter"] != "," {
+		t.Errorf("expected defaults for null options, got %v, %v", options, err)
+	}
+
+	for _, input := range []string{`"x"`, `{"nope": 1}`, `{"header": "yes"}`, `{"columns": "a"}`} {
+		if _, err := parseOptions(mustJSON(t, input), defaults); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}